@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"html/template"
 	"log/slog"
-	"math"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,9 +17,13 @@ import (
 
 	"sun-booking-tours/internal/config"
 	"sun-booking-tours/internal/database"
+	"sun-booking-tours/internal/jobs"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/routes"
+	"sun-booking-tours/internal/storage"
+	"sun-booking-tours/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/render"
@@ -83,15 +89,29 @@ func main() {
 
 	r.Static("/static", "./static")
 
+	store, err := storage.New(cfg)
+	if err != nil {
+		slog.Error(messages.LogStorageInitFailed, "error", err)
+		os.Exit(1)
+	}
+	// Local uploads are served by the app itself; S3 objects are fetched
+	// straight from the bucket or CDN.
+	if local, ok := store.(*storage.LocalStorage); ok && strings.HasPrefix(local.PublicURL(), "/") {
+		r.Static(local.PublicURL(), local.Dir())
+	}
+
 	middleware.SetupSession(r, cfg.SessionSecret)
 	r.Use(middleware.CSRFMiddleware(cfg.SessionSecret))
+	r.Use(middleware.Locale())
 
-	routes.SetupRoutes(r, db, cfg)
+	routes.SetupRoutes(r, db, cfg, store)
+	jobs.Start(context.Background(), db, cfg)
 
 	// Start server
 	addr := ":" + cfg.Port
 	slog.Info(messages.LogStartingServer, "addr", addr)
-	if err := r.Run(addr); err != nil {
+	// Locale prefixes (/en/...) are stripped before gin matches routes.
+	if err := http.ListenAndServe(addr, middleware.StripLocalePrefix(r)); err != nil {
 		slog.Error(messages.LogServerStartFailed, "error", err)
 		os.Exit(1)
 	}
@@ -174,25 +194,7 @@ func loadTemplates(baseDir string) render.HTMLRender {
 				}
 				return *p
 			},
-			"formatPrice": func(p float64) string {
-				whole := int64(math.Round(p))
-				s := fmt.Sprintf("%d", whole)
-				n := len(s)
-				if n <= 3 {
-					return s + " ₫"
-				}
-				var parts []string
-				for n > 0 {
-					end := n
-					start := n - 3
-					if start < 0 {
-						start = 0
-					}
-					parts = append([]string{s[start:end]}, parts...)
-					n = start
-				}
-				return strings.Join(parts, ".") + " ₫"
-			},
+			"formatPrice": utils.FormatPrice,
 			"formatDate": func(t time.Time) string {
 				return t.Format("02/01/2006")
 			},
@@ -207,27 +209,14 @@ func loadTemplates(baseDir string) render.HTMLRender {
 				return s
 			},
 			"jsonArray": func(data any) []string {
-				var raw []byte
-				switch v := data.(type) {
-				case json.RawMessage:
-					raw = v
-				case []byte:
-					raw = v
-				default:
-					s := fmt.Sprintf("%s", v)
-					if s == "" || s == "<nil>" || strings.HasPrefix(s, "%!") {
-						return nil
-					}
-					raw = []byte(s)
-				}
-				if len(raw) == 0 || string(raw) == "null" {
-					return nil
-				}
-				var arr []string
-				if err := json.Unmarshal(raw, &arr); err != nil {
+				assets := models.ParseImageAssets(templateJSON(data))
+				if len(assets) == 0 {
 					return nil
 				}
-				return arr
+				return models.ImageAssetURLs(assets)
+			},
+			"imageAssets": func(data any) []models.ImageAsset {
+				return models.ParseImageAssets(templateJSON(data))
 			},
 			"derefFloat": func(p *float64) float64 {
 				if p == nil {
@@ -253,27 +242,31 @@ func loadTemplates(baseDir string) render.HTMLRender {
 				return false
 			},
 			"thumbnail": func(data any) string {
-				var raw []byte
-				switch v := data.(type) {
-				case json.RawMessage:
-					raw = v
-				case []byte:
-					raw = v
-				default:
-					s := fmt.Sprintf("%s", v)
-					if s == "" || s == "<nil>" || strings.HasPrefix(s, "%!") {
-						return "/static/images/placeholder.svg"
-					}
-					raw = []byte(s)
-				}
-				if len(raw) == 0 || string(raw) == "null" {
+				assets := models.ParseImageAssets(templateJSON(data))
+				if len(assets) == 0 {
 					return "/static/images/placeholder.svg"
 				}
-				var urls []string
-				if err := json.Unmarshal(raw, &urls); err != nil || len(urls) == 0 {
-					return "/static/images/placeholder.svg"
+				return assets[0].ThumbnailURL()
+			},
+			// dict builds the data for a nested {{template}} call, such as
+			// the recursive comment thread, from key/value pairs.
+			"dict": func(pairs ...any) (map[string]any, error) {
+				if len(pairs)%2 != 0 {
+					return nil, errors.New("dict: odd number of arguments")
+				}
+				m := make(map[string]any, len(pairs)/2)
+				for i := 0; i < len(pairs); i += 2 {
+					key, ok := pairs[i].(string)
+					if !ok {
+						return nil, errors.New("dict: keys must be strings")
+					}
+					m[key] = pairs[i+1]
 				}
-				return urls[0]
+				return m, nil
+			},
+			// highlight escapes its input itself before adding <mark> tags.
+			"highlight": func(text, query string) template.HTML {
+				return utils.Highlight(text, utils.SearchTerms(query))
 			},
 		})
 
@@ -311,3 +304,20 @@ func loadTemplates(baseDir string) render.HTMLRender {
 
 	return &multiRenderer{sets: sets}
 }
+
+// templateJSON extracts raw JSON bytes from a datatypes.JSON-like value
+// passed into a template func.
+func templateJSON(data any) []byte {
+	switch v := data.(type) {
+	case json.RawMessage:
+		return v
+	case []byte:
+		return v
+	default:
+		s := fmt.Sprintf("%s", v)
+		if s == "" || s == "<nil>" || strings.HasPrefix(s, "%!") {
+			return nil
+		}
+		return []byte(s)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -36,6 +37,40 @@ type Config struct {
 	SMTPUser     string
 	SMTPPassword string
 	SMTPFrom     string
+
+	// DepartureCutoff is how long before departure under-subscribed
+	// schedules are cancelled; DepartureCheckInterval is how often the
+	// cutoff job runs.
+	DepartureCutoff        time.Duration
+	DepartureCheckInterval time.Duration
+
+	// RecommendationInterval is how often related tours and per-user
+	// recommendations are recomputed.
+	RecommendationInterval time.Duration
+
+	// WishlistNotifyInterval is how often saved tours are checked for new
+	// schedules and price drops.
+	WishlistNotifyInterval time.Duration
+
+	// TrashRetention is how long deleted tours, categories and reviews stay
+	// in the trash before TrashPurgeInterval's job removes them for good.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// StorageDriver selects where uploads are kept: "local" or "s3".
+	StorageDriver    string
+	StorageLocalDir  string
+	StoragePublicURL string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+
+	UploadMaxBytes   int64
+	ImageWebPEncoder string
 }
 
 func (c *Config) DSN() string {
@@ -84,6 +119,27 @@ func LoadConfig() *Config {
 		SMTPUser:     getEnv("SMTP_USER", ""),
 		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 		SMTPFrom:     getEnv("SMTP_FROM", ""),
+
+		DepartureCutoff:        time.Duration(getEnvInt("DEPARTURE_CUTOFF_HOURS", 72)) * time.Hour,
+		DepartureCheckInterval: time.Duration(getEnvInt("DEPARTURE_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+		RecommendationInterval: time.Duration(getEnvInt("RECOMMENDATION_INTERVAL_MINUTES", 360)) * time.Minute,
+		WishlistNotifyInterval: time.Duration(getEnvInt("WISHLIST_NOTIFY_INTERVAL_MINUTES", 30)) * time.Minute,
+		TrashRetention:         time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		TrashPurgeInterval:     time.Duration(getEnvInt("TRASH_PURGE_INTERVAL_MINUTES", 720)) * time.Minute,
+
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalDir:  getEnv("STORAGE_LOCAL_DIR", "uploads"),
+		StoragePublicURL: getEnv("STORAGE_PUBLIC_URL", ""),
+
+		S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", ""),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		S3PathStyle: getEnv("S3_PATH_STYLE", "true") == "true",
+
+		UploadMaxBytes:   int64(getEnvInt("UPLOAD_MAX_SIZE_MB", 5)) << 20,
+		ImageWebPEncoder: getEnv("IMAGE_WEBP_ENCODER", "cwebp"),
 	}
 }
 
@@ -94,3 +150,13 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt returns a positive integer environment variable or a default value
+// when it is unset or malformed.
+func getEnvInt(key string, defaultValue int) int {
+	v, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || v <= 0 {
+		return defaultValue
+	}
+	return v
+}
@@ -2,6 +2,7 @@ package constants
 
 const (
 	RoleAdmin = "admin"
+	RoleGuide = "guide"
 	RoleUser  = "user"
 )
 
@@ -25,6 +26,7 @@ const (
 
 const (
 	RouteMyBookings = "/my/bookings"
+	RouteMyWishlist = "/my/wishlist"
 )
 
 const (
@@ -46,6 +48,8 @@ const (
 	RouteAdminCategoryCreate = "/admin/categories/create"
 	RouteAdminCategoryEdit   = "/admin/categories/%d/edit"
 	RouteAdminCategoryDelete = "/admin/categories/%d/delete"
+	RouteAdminCategoryMove   = "/admin/categories/%d/move"
+	RouteAdminCategoryMerge  = "/admin/categories/%d/merge"
 )
 
 const (
@@ -53,6 +57,9 @@ const (
 	RouteAdminTourCreate = "/admin/tours/create"
 	RouteAdminTourEdit   = "/admin/tours/%d/edit"
 	RouteAdminTourDelete = "/admin/tours/%d/delete"
+	RouteAdminTourClone  = "/admin/tours/%d/clone"
+	RouteAdminTourImport = "/admin/tours/import"
+	RouteAdminTourExport = "/admin/tours/export"
 )
 
 const (
@@ -60,12 +67,29 @@ const (
 	RouteAdminTourScheduleCreate = "/admin/tours/%d/schedules/create"
 	RouteAdminScheduleEdit       = "/admin/schedules/%d/edit"
 	RouteAdminScheduleDelete     = "/admin/schedules/%d/delete"
+	RouteAdminScheduleGuides     = "/admin/schedules/%d/guides"
+)
+
+const (
+	RouteGuideSchedules      = "/guide/schedules"
+	RouteGuideScheduleDetail = "/guide/schedules/%d"
 )
 
 const (
 	RouteAdminBookings = "/admin/bookings"
 )
 
+const (
+	RouteAdminTrash = "/admin/trash"
+)
+
+const (
+	RouteAdminRatings = "/admin/ratings"
+	// RatingViewAll lists every rating on the admin ratings page instead of
+	// the unanswered low-score queue.
+	RatingViewAll = "all"
+)
+
 const (
 	RouteAdminUsers      = "/admin/users"
 	RouteAdminUserDetail = "/admin/users/%d"
@@ -79,6 +103,12 @@ const (
 const (
 	RatingMinScore = 1
 	RatingMaxScore = 5
+	// RatingPriorWeight is how many site-average ratings the Bayesian rating
+	// sort blends into every tour.
+	RatingPriorWeight = 10
+	// RatingLowScoreMax is the highest score that lands an unanswered rating
+	// in the admin reply queue.
+	RatingLowScoreMax = 3
 )
 
 const (
@@ -86,12 +116,86 @@ const (
 	RoutePublicReviewCreate = "/reviews/create"
 	RouteMyReviews          = "/my/reviews"
 	RouteAdminReviews       = "/admin/reviews"
+	RouteAdminComments      = "/admin/comments"
+	RouteAdminReports       = "/admin/reports"
 )
 
 const (
 	ReviewStatusPending  = "pending"
 	ReviewStatusApproved = "approved"
 	ReviewStatusRejected = "rejected"
+	// ReviewStatusHidden is content taken down after user reports.
+	ReviewStatusHidden = "hidden"
+)
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusHidden   = "hidden"
+	// CommentStatusAll lists comments of every status in the admin queue.
+	CommentStatusAll = "all"
+)
+
+// CommentMaxDepth is how many levels a comment thread can have, counting
+// the top-level comment; replies to the deepest level join that level.
+const CommentMaxDepth = 3
+
+// CommentPageLimit is how many top-level comments, with their replies, a
+// review page shows at a time.
+const CommentPageLimit = 10
+
+// Content users can report.
+const (
+	ReportTargetReview  = "review"
+	ReportTargetComment = "comment"
+)
+
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonAbusive       = "abusive"
+	ReportReasonMisleading    = "misleading"
+	ReportReasonInappropriate = "inappropriate"
+	ReportReasonOther         = "other"
+)
+
+// ReportReasons lists the report categories in display order.
+var ReportReasons = []string{
+	ReportReasonSpam,
+	ReportReasonAbusive,
+	ReportReasonMisleading,
+	ReportReasonInappropriate,
+	ReportReasonOther,
+}
+
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+)
+
+// Admin resolutions of a reported item; each closes all its open reports.
+const (
+	ReportActionDismiss = "dismiss"
+	ReportActionHide    = "hide"
+	ReportActionWarn    = "warn"
+	ReportActionBan     = "ban"
+)
+
+// ReportAutoHideThreshold is how many open reports from different users
+// hide a review or comment until an admin looks at it.
+const ReportAutoHideThreshold = 3
+
+// Kinds of content checked by the moderation pipeline.
+const (
+	ModerationKindReview  = "review"
+	ModerationKindComment = "comment"
+)
+
+// A moderation score at or above ModerationFlagScore holds content for an
+// admin; at or above ModerationRejectScore the content is rejected outright.
+const (
+	ModerationFlagScore   = 30
+	ModerationRejectScore = 80
 )
 
 const (
@@ -106,6 +210,45 @@ const (
 	TourStatusInactive = "inactive"
 )
 
+const (
+	SlugEntityTour     = "tour"
+	SlugEntityCategory = "category"
+)
+
+// Kinds of soft-deleted records listed in the admin trash.
+const (
+	TrashKindTours      = "tours"
+	TrashKindCategories = "categories"
+	TrashKindReviews    = "reviews"
+)
+
+// Content is written in DefaultLocale; the other supported locales are
+// stored as translations. The first entry of SupportedLocales is the default.
+const (
+	LocaleVI      = "vi"
+	LocaleEN      = "en"
+	DefaultLocale = LocaleVI
+)
+
+var SupportedLocales = []string{LocaleVI, LocaleEN}
+
+const (
+	RecommendationKindRelated    = "related"
+	RecommendationKindAlsoBooked = "also_booked"
+)
+
+const (
+	InclusionKindIncluded = "included"
+	InclusionKindExcluded = "excluded"
+	InclusionKindBring    = "bring"
+)
+
+const (
+	MealBreakfast = "breakfast"
+	MealLunch     = "lunch"
+	MealDinner    = "dinner"
+)
+
 const (
 	ScheduleStatusOpen      = "open"
 	ScheduleStatusFull      = "full"
@@ -129,6 +272,7 @@ const (
 const DefaultPageLimit = 10
 
 const (
-	HomeFeaturedLimit = 6
-	HomeLatestLimit   = 8
+	HomeFeaturedLimit    = 6
+	HomeLatestLimit      = 8
+	HomeRecommendedLimit = 6
 )
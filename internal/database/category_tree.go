@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// backfillCategoryPaths fills in Path and Depth for categories created
+// before the hierarchy was stored as materialized paths. A parent chain that
+// is broken or loops is cut where it goes wrong. It is a no-op once every
+// row has a path.
+func backfillCategoryPaths(db *gorm.DB) error {
+	var missing int64
+	if err := db.Unscoped().Model(&models.Category{}).Where("path = ''").Count(&missing).Error; err != nil {
+		return err
+	}
+	if missing == 0 {
+		return nil
+	}
+
+	var cats []models.Category
+	if err := db.Unscoped().Select("id", "parent_id").Order("id ASC").Find(&cats).Error; err != nil {
+		return err
+	}
+	parents := make(map[uint]*uint, len(cats))
+	for _, c := range cats {
+		parents[c.ID] = c.ParentID
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, c := range cats {
+			path, depth := fmt.Sprintf("%d/", c.ID), 0
+			seen := map[uint]bool{c.ID: true}
+			for p := parents[c.ID]; p != nil; p = parents[*p] {
+				if _, ok := parents[*p]; !ok || seen[*p] {
+					break
+				}
+				seen[*p] = true
+				path = fmt.Sprintf("%d/%s", *p, path)
+				depth++
+			}
+			if err := tx.Unscoped().Model(&models.Category{}).Where("id = ?", c.ID).
+				Updates(map[string]any{"path": "/" + path, "depth": depth}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
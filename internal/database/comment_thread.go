@@ -0,0 +1,56 @@
+package database
+
+import (
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// backfillCommentThreads fills in RootID and Depth for replies created
+// before threads were stored with them. A parent chain that is broken or
+// loops is cut where it goes wrong. It is a no-op once every reply has a
+// root.
+func backfillCommentThreads(db *gorm.DB) error {
+	var missing int64
+	if err := db.Model(&models.Comment{}).Where("parent_id IS NOT NULL AND root_id IS NULL").Count(&missing).Error; err != nil {
+		return err
+	}
+	if missing == 0 {
+		return nil
+	}
+
+	var comments []models.Comment
+	if err := db.Select("id", "parent_id").Order("id ASC").Find(&comments).Error; err != nil {
+		return err
+	}
+	parents := make(map[uint]*uint, len(comments))
+	for _, c := range comments {
+		parents[c.ID] = c.ParentID
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, c := range comments {
+			if c.ParentID == nil {
+				continue
+			}
+			root, depth := c.ID, 0
+			seen := map[uint]bool{c.ID: true}
+			for p := parents[c.ID]; p != nil; p = parents[*p] {
+				if _, ok := parents[*p]; !ok || seen[*p] {
+					break
+				}
+				seen[*p] = true
+				root = *p
+				depth++
+			}
+			if depth == 0 {
+				continue
+			}
+			if err := tx.Model(&models.Comment{}).Where("id = ?", c.ID).
+				Updates(map[string]any{"root_id": root, "depth": depth}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
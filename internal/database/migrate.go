@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"log/slog"
 
 	"sun-booking-tours/internal/models"
@@ -15,14 +16,30 @@ func AllModels() []interface{} {
 		&models.SocialAccount{},
 		&models.BankAccount{},
 		&models.Tour{},
+		&models.TourItineraryDay{},
+		&models.TourInclusion{},
+		&models.TourMeetingPoint{},
+		&models.TourFAQ{},
+		&models.TourTranslation{},
+		&models.TourItineraryTranslation{},
 		&models.Category{},
+		&models.CategoryTranslation{},
+		&models.SlugHistory{},
 		&models.TourSchedule{},
+		&models.ScheduleGuide{},
 		&models.Booking{},
 		&models.Payment{},
 		&models.Rating{},
+		&models.RatingReply{},
+		&models.RatingGrant{},
+		&models.TourRecommendation{},
+		&models.UserRecommendation{},
+		&models.Wishlist{},
 		&models.Review{},
 		&models.ReviewLike{},
 		&models.Comment{},
+		&models.CommentRevision{},
+		&models.Report{},
 		&models.ActivityLog{},
 	}
 }
@@ -36,6 +53,64 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	if err := dropLegacySlugIndexes(db); err != nil {
+		slog.Error("dropping legacy slug indexes failed", "error", err)
+		return err
+	}
+
+	if err := backfillCategoryPaths(db); err != nil {
+		slog.Error("backfilling category paths failed", "error", err)
+		return err
+	}
+
+	if err := backfillRatingCounts(db); err != nil {
+		slog.Error("backfilling tour rating counts failed", "error", err)
+		return err
+	}
+
+	if err := backfillCommentThreads(db); err != nil {
+		slog.Error("backfilling comment threads failed", "error", err)
+		return err
+	}
+
+	if err := setupFullTextSearch(db); err != nil {
+		slog.Error("full-text search setup failed", "error", err)
+		return err
+	}
+
 	slog.Info("database migration completed successfully")
 	return nil
 }
+
+// legacySlugIndexes made slugs unique across deleted rows too; they are
+// replaced by indexes covering only live rows so a trashed tour or category
+// does not hold on to its slug.
+var legacySlugIndexes = []struct {
+	model any
+	name  string
+}{
+	{&models.Tour{}, "idx_tours_slug"},
+	{&models.Category{}, "idx_categories_slug"},
+}
+
+func dropLegacySlugIndexes(db *gorm.DB) error {
+	for _, idx := range legacySlugIndexes {
+		if !db.Migrator().HasIndex(idx.model, idx.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(idx.model, idx.name); err != nil {
+			return fmt.Errorf("drop %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// backfillRatingCounts fills tours.rating_count for tours rated before the
+// column existed. Rating writes keep it current afterwards.
+func backfillRatingCounts(db *gorm.DB) error {
+	return db.Exec(`UPDATE tours SET rating_count = (
+		SELECT COUNT(*) FROM ratings WHERE ratings.tour_id = tours.id
+	) WHERE rating_count = 0 AND EXISTS (
+		SELECT 1 FROM ratings WHERE ratings.tour_id = tours.id
+	)`).Error
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"sun-booking-tours/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// searchColumns lists the weighted source columns of each table's
+// search_vector. Weight A ranks highest.
+var searchColumns = map[string][][2]string{
+	"tours": {
+		{"title", "A"},
+		{"location", "B"},
+		{"description", "C"},
+	},
+	"reviews": {
+		{"title", "A"},
+		{"content", "B"},
+	},
+}
+
+// setupFullTextSearch creates the vn_normalize() SQL function plus a
+// generated tsvector column and GIN index on every searchable table.
+// vn_normalize mirrors utils.FoldVietnamese, so "ha long" matches "Hạ Long".
+// Only PostgreSQL is supported; other dialects are left untouched.
+func setupFullTextSearch(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	from, to := utils.VietnameseFoldPairs()
+	fn := fmt.Sprintf(`CREATE OR REPLACE FUNCTION vn_normalize(input text) RETURNS text
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE
+		AS $$ SELECT lower(translate(input, '%s', '%s')) $$`, from, to)
+	if err := db.Exec(fn).Error; err != nil {
+		return fmt.Errorf("create vn_normalize: %w", err)
+	}
+
+	for _, table := range []string{"tours", "reviews"} {
+		parts := make([]string, 0, len(searchColumns[table]))
+		for _, col := range searchColumns[table] {
+			parts = append(parts, fmt.Sprintf(
+				"setweight(to_tsvector('simple', vn_normalize(coalesce(%s, ''))), '%s')", col[0], col[1]))
+		}
+
+		column := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (%s) STORED`, table, strings.Join(parts, " || "))
+		if err := db.Exec(column).Error; err != nil {
+			return fmt.Errorf("add %s.search_vector: %w", table, err)
+		}
+
+		index := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_search_vector ON %s USING GIN (search_vector)`, table, table)
+		if err := db.Exec(index).Error; err != nil {
+			return fmt.Errorf("index %s.search_vector: %w", table, err)
+		}
+	}
+	return nil
+}
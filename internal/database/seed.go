@@ -95,5 +95,5 @@ func seedCategories(db *gorm.DB) error {
 		}
 	}
 
-	return nil
+	return backfillCategoryPaths(db)
 }
@@ -49,23 +49,36 @@ var (
 )
 
 const (
-	ErrCtxTourFindAll            = "find all tours"
-	ErrCtxTourCount              = "count tours"
-	ErrCtxTourFindByID           = "find tour by id"
-	ErrCtxTourFindBySlug         = "find tour by slug"
-	ErrCtxTourCheckSlugExists    = "check tour slug exists"
-	ErrCtxTourCheckSlugExcluding = "check tour slug exists excluding"
-	ErrCtxTourCreate             = "create tour"
-	ErrCtxTourUpdate             = "update tour"
-	ErrCtxTourDelete             = "delete tour"
-	ErrCtxTourHasActiveBookings  = "check tour has active bookings"
-	ErrCtxTourReplaceCategories  = "replace tour categories"
-	ErrCtxTourFindFeatured       = "find featured tours"
-	ErrCtxTourFindLatest         = "find latest tours"
+	ErrCtxTourFindAll             = "find all tours"
+	ErrCtxTourCount               = "count tours"
+	ErrCtxTourCountFacets         = "count tour facets"
+	ErrCtxTourFindPins            = "find tour map pins"
+	ErrCtxTourFindByID            = "find tour by id"
+	ErrCtxTourFindBySlug          = "find tour by slug"
+	ErrCtxTourCheckSlugExists     = "check tour slug exists"
+	ErrCtxTourCheckSlugExcluding  = "check tour slug exists excluding"
+	ErrCtxTourCreate              = "create tour"
+	ErrCtxTourUpdate              = "update tour"
+	ErrCtxTourDelete              = "delete tour"
+	ErrCtxTourHasActiveBookings   = "check tour has active bookings"
+	ErrCtxTourReplaceCategories   = "replace tour categories"
+	ErrCtxTourAddCategories       = "add tour categories"
+	ErrCtxTourRemoveCategories    = "remove tour categories"
+	ErrCtxTourFindFeatured        = "find featured tours"
+	ErrCtxTourFindLatest          = "find latest tours"
+	ErrCtxTourReplaceItinerary    = "replace tour itinerary"
+	ErrCtxTourReplaceDetails      = "replace tour inclusions, meeting points and faqs"
+	ErrCtxTourReplaceTranslations = "replace tour translations"
+	ErrCtxTourFindForExport       = "find tours for export"
+	ErrCtxTourCreateClone         = "create tour clone"
+	ErrCtxTourCountBooked         = "count booked participants by schedule"
+	ErrCtxTourCountImageRefs      = "count tours using image"
 )
 
 const (
 	ErrCtxTourServiceList               = "list tours"
+	ErrCtxTourServiceFacets             = "list tour facets"
+	ErrCtxTourServicePins               = "list tour map pins"
 	ErrCtxTourServiceGet                = "get tour"
 	ErrCtxTourServiceCreateCheckSlug    = "create tour check slug"
 	ErrCtxTourServiceCreate             = "create tour"
@@ -78,17 +91,56 @@ const (
 	ErrCtxTourServiceValidateCategories = "validate category ids"
 	ErrCtxTourServiceFeatured           = "get featured tours"
 	ErrCtxTourServiceLatest             = "get latest tours"
+	ErrCtxTourServiceSlugHistory        = "tour slug history"
+	ErrCtxTourServiceTranslations       = "save tour translations"
+	ErrCtxTourServiceImport             = "import tours"
+	ErrCtxTourServiceImportRead         = "read tour import file"
+	ErrCtxTourServiceExport             = "export tours"
+	ErrCtxTourServiceClone              = "clone tour"
+	ErrCtxTourServiceBulkCategories     = "bulk update tour categories"
 )
 
 const (
 	ErrMsgTourTitleRequired       = "Tên tour là bắt buộc."
 	ErrMsgTourTitleDuplicate      = "Tour với tên tương tự đã tồn tại."
+	ErrMsgTourSlugInvalid         = "Slug tùy chỉnh không hợp lệ."
+	ErrMsgTourSlugDuplicate       = "Slug này đã được tour khác sử dụng."
 	ErrMsgTourPricePositive       = "Giá tour phải lớn hơn 0."
 	ErrMsgTourDurationPositive    = "Số ngày tour phải lớn hơn 0."
 	ErrMsgTourMaxParticipants     = "Số người tham gia tối đa phải lớn hơn 0."
+	ErrMsgTourMinParticipants     = "Số người tối thiểu phải từ 0 đến số người tối đa."
 	ErrMsgTourInvalidStatus       = "Trạng thái tour không hợp lệ."
 	ErrMsgTourCannotDeleteBooking = "Không thể xóa tour đang có booking."
 	ErrMsgTourCategoryNotFound    = "Một hoặc nhiều danh mục không tồn tại."
+	ErrMsgTourItineraryInvalid    = "Dữ liệu lịch trình theo ngày không hợp lệ."
+	ErrMsgTourItineraryDayCount   = "Lịch trình phải có đúng %d ngày, bằng số ngày của tour."
+	ErrMsgTourItineraryDayTitle   = "Ngày %d của lịch trình chưa có tiêu đề."
+	ErrMsgTourCoordinates         = "Tọa độ tour không hợp lệ: cần cả vĩ độ (-90 đến 90) và kinh độ (-180 đến 180)."
+	ErrMsgTourMeetingCoordinates  = "Tọa độ điểm hẹn không hợp lệ: cần cả vĩ độ (-90 đến 90) và kinh độ (-180 đến 180)."
+	ErrMsgTourMapBBox             = "Khung bản đồ (bbox) không hợp lệ."
+	ErrMsgTourInclusionsInvalid   = "Dữ liệu mục bao gồm / không bao gồm không hợp lệ."
+	ErrMsgTourMeetingInvalid      = "Dữ liệu điểm đón không hợp lệ."
+	ErrMsgTourMeetingName         = "Điểm đón thứ %d chưa có tên."
+	ErrMsgTourMeetingTime         = "Giờ đón của điểm thứ %d phải có dạng HH:MM."
+	ErrMsgTourFAQInvalid          = "Dữ liệu câu hỏi thường gặp không hợp lệ."
+	ErrMsgTourFAQIncomplete       = "Câu hỏi thường gặp thứ %d cần có cả câu hỏi và câu trả lời."
+	ErrMsgTourTranslationTitle    = "Bản dịch %s cần có tên tour."
+	ErrMsgTourTranslationSlug     = "Slug của bản dịch %s không hợp lệ."
+	ErrMsgTourTranslationSlugUsed = "Slug của bản dịch %s đã được tour khác sử dụng."
+	ErrMsgTourTranslationInvalid  = "Dữ liệu bản dịch %s không hợp lệ."
+	ErrMsgTourImportFormat        = "Tệp nhập phải có định dạng .csv hoặc .json."
+	ErrMsgTourImportFile          = "Không đọc được tệp nhập, hãy kiểm tra định dạng."
+	ErrMsgTourImportTitleColumn   = "Tệp CSV thiếu cột title."
+	ErrMsgTourImportEmpty         = "Tệp nhập không có tour nào."
+	ErrMsgTourImportTooMany       = "Mỗi lần chỉ nhập được tối đa %d tour."
+	ErrMsgTourImportValue         = "Giá trị cột %s không hợp lệ."
+	ErrMsgTourImportCategory      = "Danh mục %q không tồn tại."
+	ErrMsgTourImportSchedule      = "Lịch khởi hành thứ %d: %s"
+	ErrMsgTourCloneOffset         = "Số ngày dời lịch phải từ -%d đến %d."
+	ErrMsgTourBulkNoTours         = "Hãy chọn ít nhất một tour."
+	ErrMsgTourBulkNoCategories    = "Hãy chọn ít nhất một danh mục."
+	ErrMsgTourBulkAction          = "Thao tác hàng loạt không hợp lệ."
+	ErrMsgBookingPickupRequired   = "Vui lòng chọn điểm đón."
 )
 
 const (
@@ -98,6 +150,10 @@ const (
 	ErrCtxScheduleUpdate      = "update schedule"
 	ErrCtxScheduleDelete      = "delete schedule"
 	ErrCtxScheduleHasBookings = "check schedule has bookings"
+
+	ErrCtxScheduleCountConfirmed   = "count confirmed schedule participants"
+	ErrCtxScheduleSetGuaranteed    = "set schedule guaranteed"
+	ErrCtxScheduleFindDueForCutoff = "find schedules due for cutoff"
 )
 
 const (
@@ -129,6 +185,35 @@ const (
 	ErrMsgScheduleReturnDateReq         = "Ngày về là bắt buộc."
 )
 
+// Guide assignment
+const (
+	ErrCtxScheduleGuideFindBySchedule = "find guides by schedule"
+	ErrCtxScheduleGuideIsAssigned     = "check guide assigned"
+	ErrCtxScheduleGuideHasConflict    = "check guide schedule conflict"
+	ErrCtxScheduleGuideAssign         = "assign guide"
+	ErrCtxScheduleGuideUnassign       = "unassign guide"
+	ErrCtxScheduleGuideFindByGuide    = "find schedules by guide"
+	ErrCtxScheduleGuideUnassignGuide  = "unassign guide from all schedules"
+)
+
+const (
+	ErrCtxGuideServiceList          = "guide service list guides"
+	ErrCtxGuideServiceAssign        = "guide service assign"
+	ErrCtxGuideServiceUnassign      = "guide service unassign"
+	ErrCtxGuideServiceAssignments   = "guide service list assignments"
+	ErrCtxGuideServiceMySchedules   = "guide service my schedules"
+	ErrCtxGuideServiceScheduleView  = "guide service schedule detail"
+	ErrCtxScheduleServiceGuideCheck = "update schedule check guide conflicts"
+)
+
+const (
+	ErrMsgGuideNotFound          = "Hướng dẫn viên không tồn tại hoặc không hoạt động."
+	ErrMsgGuideAlreadyAssigned   = "Hướng dẫn viên đã được phân công cho lịch trình này."
+	ErrMsgGuideNotAssigned       = "Hướng dẫn viên chưa được phân công cho lịch trình này."
+	ErrMsgGuideScheduleConflict  = "Hướng dẫn viên %s đã có lịch dẫn tour trùng ngày."
+	ErrMsgGuideScheduleCancelled = "Không thể phân công hướng dẫn viên cho lịch trình đã hủy."
+)
+
 // Category
 var (
 	ErrCategoryNotFound = NewAppError(http.StatusNotFound, "category not found")
@@ -141,19 +226,25 @@ var (
 )
 
 const (
-	ErrCtxCategoryFindAll            = "find all categories"
-	ErrCtxCategoryFindAllParents     = "find parent categories"
-	ErrCtxCategoryFindByID           = "find category by id"
-	ErrCtxCategoryFindBySlug         = "find category by slug"
-	ErrCtxCategoryCheckSlugExists    = "check slug exists"
-	ErrCtxCategoryCheckSlugExcluding = "check slug exists excluding"
-	ErrCtxCategoryCreate             = "create category"
-	ErrCtxCategoryUpdate             = "update category"
-	ErrCtxCategoryDelete             = "delete category"
-	ErrCtxCategoryHasTours           = "check category has tours"
-	ErrCtxCategoryHasChildren        = "check category has children"
-	ErrCtxCategoryGetDescendantIDs   = "get descendant ids"
-	ErrCtxCategoryCountByIDs         = "count categories by ids"
+	ErrCtxCategoryFindAll             = "find all categories"
+	ErrCtxCategoryFindAllParents      = "find parent categories"
+	ErrCtxCategoryFindByID            = "find category by id"
+	ErrCtxCategoryFindBySlug          = "find category by slug"
+	ErrCtxCategoryCheckSlugExists     = "check slug exists"
+	ErrCtxCategoryCheckSlugExcluding  = "check slug exists excluding"
+	ErrCtxCategoryCreate              = "create category"
+	ErrCtxCategoryUpdate              = "update category"
+	ErrCtxCategoryDelete              = "delete category"
+	ErrCtxCategoryHasTours            = "check category has tours"
+	ErrCtxCategoryHasChildren         = "check category has children"
+	ErrCtxCategoryGetDescendantIDs    = "get descendant ids"
+	ErrCtxCategoryFindAncestors       = "find category ancestors"
+	ErrCtxCategoryChildIDs            = "find child category ids"
+	ErrCtxCategoryMove                = "move category subtree"
+	ErrCtxCategoryUpdatePositions     = "update category positions"
+	ErrCtxCategoryMerge               = "merge categories"
+	ErrCtxCategoryCountByIDs          = "count categories by ids"
+	ErrCtxCategoryReplaceTranslations = "replace category translations"
 )
 
 // Category Service error context (used in fmt.Errorf wrapping)
@@ -171,17 +262,32 @@ const (
 	ErrCtxCategoryServiceDeleteCheckChildren  = "delete category check children"
 	ErrCtxCategoryServiceDeleteCheckTours     = "delete category check tours"
 	ErrCtxCategoryServiceDelete               = "delete category"
+	ErrCtxCategoryServiceSlugHistory          = "category slug history"
+	ErrCtxCategoryServiceGetBySlug            = "get category by slug"
+	ErrCtxCategoryServiceTranslations         = "save category translations"
+	ErrCtxCategoryServiceMove                 = "move category"
+	ErrCtxCategoryServiceReorder              = "reorder categories"
+	ErrCtxCategoryServiceBreadcrumbs          = "category breadcrumbs"
+	ErrCtxCategoryServiceMerge                = "merge category"
 )
 
 // Category Service validation error messages (user-facing)
 const (
 	ErrMsgCategoryNameRequired             = "Tên danh mục là bắt buộc."
 	ErrMsgCategoryNameDuplicate            = "Danh mục với tên tương tự đã tồn tại."
+	ErrMsgCategorySlugInvalid              = "Slug tùy chỉnh không hợp lệ."
+	ErrMsgCategorySlugDuplicate            = "Slug này đã được danh mục khác sử dụng."
+	ErrMsgCategoryTranslationName          = "Bản dịch %s cần có tên danh mục."
+	ErrMsgCategoryTranslationSlug          = "Slug của bản dịch %s không hợp lệ."
+	ErrMsgCategoryTranslationSlugUsed      = "Slug của bản dịch %s đã được danh mục khác sử dụng."
 	ErrMsgCategoryParentNotFound           = "Danh mục cha không tồn tại."
-	ErrMsgCategoryParentMustBeRoot         = "Chỉ hỗ trợ danh mục con cấp 2 (danh mục cha phải là cấp gốc)."
 	ErrMsgCategorySelfParent               = "Danh mục không thể là cha của chính nó."
 	ErrMsgCategoryChildAsParent            = "Không thể chọn danh mục con làm danh mục cha."
 	ErrMsgCategoryCannotDeleteWithChildren = "Không thể xóa danh mục có danh mục con. Hãy xóa danh mục con trước."
+	ErrMsgCategoryReorderInvalid           = "Danh sách sắp xếp không khớp với các danh mục cùng cấp."
+	ErrMsgCategoryMergeSelf                = "Không thể gộp danh mục vào chính nó."
+	ErrMsgCategoryMergeIntoChild           = "Không thể gộp danh mục vào một danh mục con của nó."
+	ErrMsgCategoryMergeTargetNotFound      = "Danh mục đích không tồn tại."
 )
 
 // Bank Account error context (used in fmt.Errorf wrapping)
@@ -197,12 +303,15 @@ const (
 )
 
 const (
-	ErrCtxBookingCreate       = "create booking"
-	ErrCtxBookingFindByID     = "find booking by id"
-	ErrCtxBookingFindByUser   = "find bookings by user"
-	ErrCtxBookingCount        = "count bookings by user"
-	ErrCtxBookingUpdateStatus = "update booking status"
-	ErrCtxScheduleUpdateSlots = "update schedule available slots"
+	ErrCtxBookingCreate         = "create booking"
+	ErrCtxBookingFindByID       = "find booking by id"
+	ErrCtxBookingFindByUser     = "find bookings by user"
+	ErrCtxBookingCount          = "count bookings by user"
+	ErrCtxBookingUpdateStatus   = "update booking status"
+	ErrCtxBookingFindBySchedule = "find bookings by schedule"
+	ErrCtxBookingFindCompleted  = "find latest completed booking"
+	ErrCtxBookingFindReviewable = "find reviewable bookings"
+	ErrCtxScheduleUpdateSlots   = "update schedule available slots"
 )
 
 const (
@@ -214,6 +323,13 @@ const (
 	ErrCtxBookingServiceComplete = "booking service complete"
 )
 
+// Guaranteed departures
+const (
+	ErrCtxDepartureServiceRefresh = "refresh guaranteed departure"
+	ErrCtxDepartureServiceCutoff  = "departure cutoff"
+	ErrCtxDepartureServiceCancel  = "cancel under-subscribed schedule"
+)
+
 const (
 	ErrCtxBookingFindAll  = "find all bookings"
 	ErrCtxBookingCountAll = "count all bookings"
@@ -246,18 +362,26 @@ const (
 )
 
 var (
-	ErrReviewNotFound      = NewAppError(http.StatusNotFound, "review not found")
-	ErrReviewNotOwner      = NewAppError(http.StatusForbidden, "not review owner")
-	ErrReviewCannotApprove = NewAppError(http.StatusBadRequest, "review cannot be approved")
-	ErrReviewCannotReject  = NewAppError(http.StatusBadRequest, "review cannot be rejected")
-	ErrCommentNotFound     = NewAppError(http.StatusNotFound, "comment not found")
-	ErrCommentNotOwner     = NewAppError(http.StatusForbidden, "not comment owner")
+	ErrReviewNotFound       = NewAppError(http.StatusNotFound, "review not found")
+	ErrReviewNotOwner       = NewAppError(http.StatusForbidden, "not review owner")
+	ErrReviewCannotApprove  = NewAppError(http.StatusBadRequest, "review cannot be approved")
+	ErrReviewCannotReject   = NewAppError(http.StatusBadRequest, "review cannot be rejected")
+	ErrReviewTourNotBooked  = NewAppError(http.StatusForbidden, "review author has not booked this tour")
+	ErrCommentNotFound      = NewAppError(http.StatusNotFound, "comment not found")
+	ErrCommentNotOwner      = NewAppError(http.StatusForbidden, "not comment owner")
+	ErrCommentCannotApprove = NewAppError(http.StatusBadRequest, "comment cannot be approved")
+	ErrCommentCannotReject  = NewAppError(http.StatusBadRequest, "comment cannot be rejected")
 )
 
 var (
 	ErrRatingNotFound = NewAppError(http.StatusNotFound, "rating not found")
 	ErrAlreadyRated   = NewAppError(http.StatusConflict, "already rated this tour")
 	ErrInvalidScore   = NewAppError(http.StatusBadRequest, "score must be between 1 and 5")
+	ErrRatingNotTaken = NewAppError(http.StatusForbidden, "rating requires a completed booking")
+	ErrReplyEmpty     = NewAppError(http.StatusBadRequest, "reply content is required")
+
+	ErrRatingGrantExists   = NewAppError(http.StatusConflict, "user may already rate this tour")
+	ErrRatingGrantNotFound = NewAppError(http.StatusNotFound, "rating grant not found")
 )
 
 const (
@@ -273,6 +397,7 @@ const (
 	ErrCtxReviewDelete          = "delete review"
 	ErrCtxReviewUpdateStatus    = "update review status"
 	ErrCtxReviewUpdateLikeCount = "update review like count"
+	ErrCtxReviewCountImageRefs  = "count reviews using image"
 )
 
 const (
@@ -280,6 +405,40 @@ const (
 	ErrCtxCommentFindByReview = "find comments by review"
 	ErrCtxCommentFindByID     = "find comment by id"
 	ErrCtxCommentDelete       = "delete comment"
+	ErrCtxCommentFindAll      = "find all comments"
+	ErrCtxCommentCountAll     = "count all comments"
+	ErrCtxCommentUpdateStatus = "update comment status"
+	ErrCtxCommentCountRoots   = "count top-level comments"
+	ErrCtxCommentFindReplies  = "find comment replies"
+	ErrCtxCommentHasReplies   = "check comment replies"
+	ErrCtxCommentSoftDelete   = "soft delete comment"
+	ErrCtxCommentUpdate       = "update comment content"
+)
+
+var (
+	ErrReportInvalidReason  = NewAppError(http.StatusBadRequest, "invalid report reason")
+	ErrReportOwnContent     = NewAppError(http.StatusBadRequest, "cannot report own content")
+	ErrAlreadyReported      = NewAppError(http.StatusConflict, "already reported this content")
+	ErrReportTargetNotFound = NewAppError(http.StatusNotFound, "reported content not found")
+	ErrReportInvalidAction  = NewAppError(http.StatusBadRequest, "invalid report action")
+)
+
+const (
+	ErrCtxReportCreate         = "create report"
+	ErrCtxReportExists         = "check report exists"
+	ErrCtxReportCount          = "count open reports"
+	ErrCtxReportCountGroups    = "count reported items"
+	ErrCtxReportFindGroups     = "find reported items"
+	ErrCtxReportFindByTargets  = "find reports by targets"
+	ErrCtxReportResolve        = "resolve reports"
+	ErrCtxReportServiceReport  = "report service report"
+	ErrCtxReportServiceQueue   = "report service queue"
+	ErrCtxReportServiceResolve = "report service resolve"
+)
+
+const (
+	ErrCtxModerationRecent = "find recent content for moderation"
+	ErrCtxModerationCheck  = "run moderation rules"
 )
 
 const (
@@ -293,8 +452,22 @@ const (
 	ErrCtxRatingFindByTour     = "find ratings by tour"
 	ErrCtxRatingCountByTour    = "count ratings by tour"
 	ErrCtxRatingCalcAvg        = "calculate average rating"
+	ErrCtxRatingStats          = "aggregate rating stats"
+	ErrCtxRatingFindByID       = "find rating by id"
+	ErrCtxRatingFindAll        = "find all ratings"
+	ErrCtxRatingCountAll       = "count all ratings"
+	ErrCtxRatingSaveReply      = "save rating reply"
+	ErrCtxRatingHasGrant       = "check rating grant"
+	ErrCtxRatingCreateGrant    = "create rating grant"
+	ErrCtxRatingDeleteGrant    = "delete rating grant"
+	ErrCtxRatingFindGrants     = "find rating grants"
 	ErrCtxRatingServiceRate    = "rating service rate"
 	ErrCtxRatingServiceList    = "rating service list"
+	ErrCtxRatingServiceCanRate = "rating service can rate"
+	ErrCtxRatingServiceSummary = "rating service summary"
+	ErrCtxRatingServiceAdmin   = "rating service admin list"
+	ErrCtxRatingServiceReply   = "rating service reply"
+	ErrCtxRatingServiceGrant   = "rating service grant"
 	ErrCtxRatingUpdateTourAvg  = "update tour avg rating"
 )
 
@@ -310,13 +483,99 @@ const (
 	ErrCtxReviewServiceToggleLike = "review service toggle like"
 	ErrCtxReviewServiceAddComment = "review service add comment"
 	ErrCtxReviewServiceDelComment = "review service delete comment"
+	ErrCtxReviewServiceEdComment  = "review service edit comment"
 	ErrCtxReviewServiceAdminList  = "review service admin list"
+	ErrCtxReviewServiceTrip       = "review service resolve trip"
+	ErrCtxReviewServiceModerate   = "review service moderate"
+	ErrCtxReviewServiceComments   = "review service admin list comments"
+	ErrCtxReviewServiceCmtStatus  = "review service moderate comment"
+)
+
+// Slug history
+const (
+	ErrCtxSlugHistoryFind         = "find slug history"
+	ErrCtxSlugHistoryFindByEntity = "find slug history by entity"
+	ErrCtxSlugHistoryRecord       = "record slug history"
+)
+
+// Media
+const (
+	ErrCtxMediaRead   = "read uploaded image"
+	ErrCtxMediaEncode = "encode image"
+	ErrCtxMediaStore  = "store image"
+)
+
+const (
+	ErrMsgMediaTooMany         = "Chỉ được tải lên tối đa %d ảnh mỗi lần."
+	ErrMsgMediaTooLarge        = "Ảnh \"%s\" vượt quá dung lượng cho phép (%d MB)."
+	ErrMsgMediaUnsupportedType = "Ảnh \"%s\" không đúng định dạng. Chỉ chấp nhận JPEG, PNG, GIF hoặc WebP."
+	ErrMsgMediaInvalidImage    = "Không thể đọc ảnh \"%s\"."
+)
+
+// Recommendations
+const (
+	ErrCtxRecommendationCandidates   = "find recommendation candidate tours"
+	ErrCtxRecommendationCoBookings   = "count co-bookings"
+	ErrCtxRecommendationUserBookings = "find user booked tours"
+	ErrCtxRecommendationUserRatings  = "find user ratings"
+	ErrCtxRecommendationReplace      = "replace recommendations"
+	ErrCtxRecommendationFindForTour  = "find tour recommendations"
+	ErrCtxRecommendationFindForUser  = "find user recommendations"
+
+	ErrCtxRecommendationServiceRecompute = "recompute recommendations"
+	ErrCtxRecommendationServiceForTour   = "list tour recommendations"
+	ErrCtxRecommendationServiceForUser   = "list user recommendations"
+)
+
+// Wishlist
+const (
+	ErrCtxWishlistCheck       = "check wishlist"
+	ErrCtxWishlistCreate      = "add to wishlist"
+	ErrCtxWishlistDelete      = "remove from wishlist"
+	ErrCtxWishlistFindByUser  = "find wishlist by user"
+	ErrCtxWishlistCountByUser = "count wishlist by user"
+	ErrCtxWishlistSavedIDs    = "find saved tour ids"
+	ErrCtxWishlistPending     = "find pending wishlist notifications"
+	ErrCtxWishlistNewSchedule = "find new schedules for wishlist"
+	ErrCtxWishlistMarkNotify  = "mark wishlist notified"
+
+	ErrCtxWishlistServiceToggle = "wishlist service toggle"
+	ErrCtxWishlistServiceList   = "wishlist service list"
+	ErrCtxWishlistServiceNotify = "wishlist service notify"
+)
+
+// Trash
+var (
+	ErrTrashNotFound = NewAppError(http.StatusNotFound, "trashed record not found")
+	ErrTrashKind     = NewAppError(http.StatusBadRequest, "unknown trash kind")
+)
+
+const (
+	ErrCtxTrashFind        = "find trashed records"
+	ErrCtxTrashFindByID    = "find trashed record by id"
+	ErrCtxTrashRestore     = "restore trashed record"
+	ErrCtxTrashPurge       = "purge trashed record"
+	ErrCtxTrashExpired     = "find expired trashed records"
+	ErrCtxTrashHasBookings = "check trashed tour bookings"
+
+	ErrCtxTrashServiceList    = "trash service list"
+	ErrCtxTrashServiceRestore = "trash service restore"
+	ErrCtxTrashServicePurge   = "trash service purge"
+	ErrCtxTrashServiceExpired = "trash service purge expired"
+)
+
+const (
+	ErrMsgTrashTourHasBookings = "Tour đã có lượt đặt nên không thể xóa vĩnh viễn; tour vẫn nằm trong thùng rác."
 )
 
 // Admin — User Management
 var (
 	ErrCannotBanSelf  = NewAppError(http.StatusBadRequest, "cannot change own status")
 	ErrCannotBanAdmin = NewAppError(http.StatusBadRequest, "cannot change other admin status")
+	ErrInvalidRole    = NewAppError(http.StatusBadRequest, "invalid user role")
+
+	ErrCannotChangeOwnRole   = NewAppError(http.StatusBadRequest, "cannot change own role")
+	ErrCannotChangeAdminRole = NewAppError(http.StatusBadRequest, "cannot change other admin role")
 )
 
 const (
@@ -325,6 +584,7 @@ const (
 	ErrCtxUserFindByID              = "find user by id"
 	ErrCtxUserFindByIDWithRelations = "find user by id with relations"
 	ErrCtxUserUpdateStatus          = "update user status"
+	ErrCtxUserUpdateRole            = "update user role"
 )
 
 // Authorization
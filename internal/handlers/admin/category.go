@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
@@ -13,6 +14,7 @@ import (
 	"sun-booking-tours/internal/middleware"
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/services"
+	"sun-booking-tours/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -57,7 +59,7 @@ func (h *CategoryHandler) CreateForm(c *gin.Context) {
 		slog.Error(messages.LogAdminCategoryListFailed, "error", err)
 	}
 
-	rootCats := filterRootCategories(parents, 0)
+	options := parentOptions(parents, nil)
 	flashSuccess, flashError := middleware.GetFlash(c)
 
 	c.HTML(http.StatusOK, "admin/pages/category_form.html", gin.H{
@@ -69,9 +71,10 @@ func (h *CategoryHandler) CreateForm(c *gin.Context) {
 		"flash_success": flashSuccess,
 		"flash_error":   flashError,
 
-		"parents":  rootCats,
-		"is_edit":  false,
-		"form_url": constants.RouteAdminCategoryCreate,
+		"parents":            options,
+		"is_edit":            false,
+		"form_url":           constants.RouteAdminCategoryCreate,
+		"translated_locales": utils.TranslatedLocales(),
 	})
 }
 
@@ -82,6 +85,7 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 		c.Redirect(http.StatusFound, constants.RouteAdminCategoryCreate)
 		return
 	}
+	form.Translations = bindTranslations(c)
 
 	if err := h.service.CreateCategory(c.Request.Context(), &form); err != nil {
 		slog.Error(messages.LogAdminCategoryCreateFailed, "error", err)
@@ -119,7 +123,8 @@ func (h *CategoryHandler) EditForm(c *gin.Context) {
 	}
 
 	parents, _ := h.service.AllFlatCategories(c.Request.Context())
-	rootCats := filterRootCategories(parents, uint(id))
+	options := parentOptions(parents, cat)
+	slugHistory, _ := h.service.SlugHistory(c.Request.Context(), uint(id))
 
 	flashSuccess, flashError := middleware.GetFlash(c)
 
@@ -132,10 +137,12 @@ func (h *CategoryHandler) EditForm(c *gin.Context) {
 		"flash_success": flashSuccess,
 		"flash_error":   flashError,
 
-		"parents":  rootCats,
-		"is_edit":  true,
-		"category": cat,
-		"form_url": fmt.Sprintf(constants.RouteAdminCategoryEdit, id),
+		"parents":            options,
+		"is_edit":            true,
+		"category":           cat,
+		"form_url":           fmt.Sprintf(constants.RouteAdminCategoryEdit, id),
+		"translated_locales": utils.TranslatedLocales(),
+		"slug_history":       slugHistory,
 	})
 }
 
@@ -155,6 +162,7 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		c.Redirect(http.StatusFound, fmt.Sprintf(constants.RouteAdminCategoryEdit, id))
 		return
 	}
+	form.Translations = bindTranslations(c)
 
 	if err := h.service.UpdateCategory(c.Request.Context(), uint(id), &form); err != nil {
 		slog.Error(messages.LogAdminCategoryUpdateFailed, "error", err)
@@ -198,17 +206,230 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 	c.Redirect(http.StatusFound, constants.RouteAdminCategories)
 }
 
+// MoveForm lets the admin pick a new parent for a category and its subtree.
+func (h *CategoryHandler) MoveForm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	cat, err := h.service.GetCategory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "admin/pages/error.html", gin.H{
+			"status":  404,
+			"message": messages.ErrAdminCategoryNotFound,
+		})
+		return
+	}
+
+	parents, err := h.service.AllFlatCategories(c.Request.Context())
+	if err != nil {
+		slog.Error(messages.LogAdminCategoryListFailed, "error", err)
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/category_move.html", gin.H{
+		"title":       messages.TitleAdminCategoryMove,
+		"active_menu": "categories",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"category":      cat,
+		"parents":       parentOptions(parents, cat),
+		"subtree_count": subtreeSize(parents, cat),
+	})
+}
+
+func (h *CategoryHandler) Move(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+	moveURL := fmt.Sprintf(constants.RouteAdminCategoryMove, id)
+
+	parentID, err := strconv.ParseUint(c.DefaultPostForm("parent_id", "0"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, moveURL)
+		return
+	}
+
+	if err := h.service.MoveCategory(c.Request.Context(), uint(id), uint(parentID)); err != nil {
+		slog.Error(messages.LogAdminCategoryMoveFailed, "id", id, "parent_id", parentID, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminCategoryMoveFail)
+		}
+		c.Redirect(http.StatusFound, moveURL)
+		return
+	}
+
+	name := ""
+	if cat, err := h.service.GetCategory(c.Request.Context(), uint(id)); err == nil {
+		name = cat.Name
+	}
+	middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminCategoryMoved, name))
+	c.Redirect(http.StatusFound, constants.RouteAdminCategories)
+}
+
+// Reorder saves the order of one sibling list after a drag-and-drop. The
+// form carries parent_id (0 for the top level) and the ids in their new
+// order.
+func (h *CategoryHandler) Reorder(c *gin.Context) {
+	parentID, err := strconv.ParseUint(c.DefaultPostForm("parent_id", "0"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, constants.RouteAdminCategories)
+		return
+	}
+	var ids []uint
+	for _, raw := range c.PostFormArray("ids") {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			middleware.SetFlashError(c, messages.ErrInvalidForm)
+			c.Redirect(http.StatusFound, constants.RouteAdminCategories)
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+
+	if err := h.service.ReorderCategories(c.Request.Context(), uint(parentID), ids); err != nil {
+		slog.Error(messages.LogAdminCategoryReorderFailed, "parent_id", parentID, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminCategoryReorderFail)
+		}
+		c.Redirect(http.StatusFound, constants.RouteAdminCategories)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminCategoryReordered)
+	c.Redirect(http.StatusFound, constants.RouteAdminCategories)
+}
+
+// MergeForm lets the admin pick the category to fold this one into.
+func (h *CategoryHandler) MergeForm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	cat, err := h.service.GetCategory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "admin/pages/error.html", gin.H{
+			"status":  404,
+			"message": messages.ErrAdminCategoryNotFound,
+		})
+		return
+	}
+
+	cats, err := h.service.AllFlatCategories(c.Request.Context())
+	if err != nil {
+		slog.Error(messages.LogAdminCategoryListFailed, "error", err)
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/category_merge.html", gin.H{
+		"title":       messages.TitleAdminCategoryMerge,
+		"active_menu": "categories",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"category": cat,
+		"targets":  parentOptions(cats, cat),
+	})
+}
+
+func (h *CategoryHandler) Merge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+	mergeURL := fmt.Sprintf(constants.RouteAdminCategoryMerge, id)
+
+	targetID, err := strconv.ParseUint(c.PostForm("target_id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, mergeURL)
+		return
+	}
+
+	merged, err := h.service.MergeCategory(c.Request.Context(), uint(id), uint(targetID))
+	if err != nil {
+		slog.Error(messages.LogAdminCategoryMergeFailed, "id", id, "target_id", targetID, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminCategoryMergeFail)
+		}
+		c.Redirect(http.StatusFound, mergeURL)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminCategoryMerged, merged.Source, merged.Target, merged.Tours, merged.Children))
+	c.Redirect(http.StatusFound, constants.RouteAdminCategories)
+}
+
 type parentOption struct {
-	ID   uint
-	Name string
+	ID    uint
+	Name  string
+	Depth int
 }
 
-func filterRootCategories(cats []models.Category, excludeID uint) []parentOption {
+// parentOptions lists the categories, in tree order, that self may be put
+// under: everything except self and its subtree. A nil self (a new
+// category) may go anywhere.
+func parentOptions(cats []models.Category, self *models.Category) []parentOption {
 	var opts []parentOption
 	for _, c := range cats {
-		if c.ParentID == nil && c.ID != excludeID {
-			opts = append(opts, parentOption{ID: c.ID, Name: c.Name})
+		if self != nil && inSubtree(c, self) {
+			continue
 		}
+		opts = append(opts, parentOption{ID: c.ID, Name: c.Name, Depth: c.Depth})
 	}
 	return opts
 }
+
+// subtreeSize counts root's descendants among cats.
+func subtreeSize(cats []models.Category, root *models.Category) int {
+	n := 0
+	for _, c := range cats {
+		if c.ID != root.ID && inSubtree(c, root) {
+			n++
+		}
+	}
+	return n
+}
+
+func inSubtree(c models.Category, root *models.Category) bool {
+	return c.ID == root.ID || (root.Path != "" && strings.HasPrefix(c.Path, root.Path))
+}
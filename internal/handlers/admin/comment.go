@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentHandler is the admin moderation queue for review comments.
+type CommentHandler struct {
+	service *services.ReviewService
+}
+
+func NewCommentHandler(service *services.ReviewService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// List shows comments held by the moderation rules, or every comment of
+// the chosen status; status=all lists them all.
+func (h *CommentHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	status := c.DefaultQuery("status", constants.CommentStatusPending)
+	filter := repository.CommentFilter{Status: status, Page: page, Limit: constants.DefaultPageLimit}
+	if status == constants.CommentStatusAll {
+		filter.Status = ""
+	}
+	if rid, err := strconv.ParseUint(c.Query("review_id"), 10, 64); err == nil {
+		filter.ReviewID = uint(rid)
+	}
+	if uid, err := strconv.ParseUint(c.Query("user_id"), 10, 64); err == nil {
+		filter.UserID = uint(uid)
+	}
+
+	comments, total, err := h.service.AdminListComments(c.Request.Context(), filter)
+	if err != nil {
+		slog.Error(messages.LogAdminCommentListFailed, "error", err)
+		c.HTML(http.StatusInternalServerError, "admin/pages/error.html", gin.H{
+			"status":  500,
+			"message": messages.ErrInternalServer,
+		})
+		return
+	}
+
+	totalPages := int(total) / filter.Limit
+	if int(total)%filter.Limit > 0 {
+		totalPages++
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/comments_list.html", gin.H{
+		"title":       messages.TitleAdminComments,
+		"active_menu": "comments",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"comments":    comments,
+		"total":       total,
+		"page":        page,
+		"total_pages": totalPages,
+		"status":      status,
+		"filter":      filter,
+
+		"flag_score":   constants.ModerationFlagScore,
+		"reject_score": constants.ModerationRejectScore,
+	})
+}
+
+func (h *CommentHandler) Approve(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminCommentNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminComments)
+		return
+	}
+
+	if err := h.service.AdminApproveComment(c.Request.Context(), uint(id)); err != nil {
+		slog.Error(messages.LogAdminCommentApproveFailed, "comment_id", id, "error", err)
+		errMsg := messages.ErrAdminCommentApproveFail
+		if errors.Is(err, appErrors.ErrCommentNotFound) {
+			errMsg = messages.ErrAdminCommentNotFound
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, constants.RouteAdminComments)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminCommentApproved)
+	c.Redirect(http.StatusFound, constants.RouteAdminComments)
+}
+
+func (h *CommentHandler) Reject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminCommentNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminComments)
+		return
+	}
+
+	if err := h.service.AdminRejectComment(c.Request.Context(), uint(id), c.PostForm("reason")); err != nil {
+		slog.Error(messages.LogAdminCommentRejectFailed, "comment_id", id, "error", err)
+		errMsg := messages.ErrAdminCommentRejectFail
+		if errors.Is(err, appErrors.ErrCommentNotFound) {
+			errMsg = messages.ErrAdminCommentNotFound
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, constants.RouteAdminComments)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminCommentRejected)
+	c.Redirect(http.StatusFound, constants.RouteAdminComments)
+}
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatingHandler struct {
+	service *services.RatingService
+}
+
+func NewRatingHandler(service *services.RatingService) *RatingHandler {
+	return &RatingHandler{service: service}
+}
+
+// List shows the queue of unanswered low-score ratings, or every rating
+// with view=all.
+func (h *RatingHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	view := c.Query("view")
+	filter := repository.RatingFilter{Page: page, Limit: constants.DefaultPageLimit}
+	if view != constants.RatingViewAll {
+		view = ""
+		filter.Unanswered = true
+		filter.MaxScore = constants.RatingLowScoreMax
+	}
+
+	ratings, total, err := h.service.AdminListRatings(c.Request.Context(), filter)
+	if err != nil {
+		slog.Error(messages.LogAdminRatingListFailed, "error", err)
+		c.HTML(http.StatusInternalServerError, "admin/pages/error.html", gin.H{
+			"status":  500,
+			"message": messages.ErrInternalServer,
+		})
+		return
+	}
+
+	totalPages := int(total) / filter.Limit
+	if int(total)%filter.Limit > 0 {
+		totalPages++
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/ratings_list.html", gin.H{
+		"title":       messages.TitleAdminRatings,
+		"active_menu": "ratings",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"ratings":       ratings,
+		"total":         total,
+		"page":          page,
+		"total_pages":   totalPages,
+		"view":          view,
+		"low_score_max": constants.RatingLowScoreMax,
+	})
+}
+
+func (h *RatingHandler) Reply(c *gin.Context) {
+	redirectURL := constants.RouteAdminRatings
+	if c.PostForm("view") == constants.RatingViewAll {
+		redirectURL += "?view=" + constants.RatingViewAll
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminRatingNotFound)
+		c.Redirect(http.StatusFound, redirectURL)
+		return
+	}
+
+	admin := middleware.GetCurrentUser(c)
+	isNew, err := h.service.Reply(c.Request.Context(), uint(id), admin.ID, c.PostForm("content"))
+	if err != nil {
+		errMsg := messages.ErrAdminRatingReplyFail
+		switch {
+		case errors.Is(err, appErrors.ErrRatingNotFound):
+			errMsg = messages.ErrAdminRatingNotFound
+		case errors.Is(err, appErrors.ErrReplyEmpty):
+			errMsg = messages.ErrAdminRatingReplyEmpty
+		default:
+			slog.Error(messages.LogAdminRatingReplyFailed, "rating_id", id, "error", err)
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, redirectURL)
+		return
+	}
+
+	if isNew {
+		middleware.SetFlashSuccess(c, messages.MsgAdminRatingReplied)
+	} else {
+		middleware.SetFlashSuccess(c, messages.MsgAdminRatingReplyUpdated)
+	}
+	c.Redirect(http.StatusFound, redirectURL)
+}
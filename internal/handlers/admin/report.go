@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler is the admin queue of content reported by users.
+type ReportHandler struct {
+	service *services.ReportService
+}
+
+func NewReportHandler(service *services.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// List shows reported reviews and comments with open reports, grouped by
+// item, most reported first.
+func (h *ReportHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit := constants.DefaultPageLimit
+
+	groups, total, err := h.service.Queue(c.Request.Context(), page, limit)
+	if err != nil {
+		slog.Error(messages.LogAdminReportListFailed, "error", err)
+		c.HTML(http.StatusInternalServerError, "admin/pages/error.html", gin.H{
+			"status":  500,
+			"message": messages.ErrInternalServer,
+		})
+		return
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/reports_list.html", gin.H{
+		"title":       messages.TitleAdminReports,
+		"active_menu": "reports",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"groups":      groups,
+		"total":       total,
+		"page":        page,
+		"total_pages": totalPages,
+	})
+}
+
+// Resolve closes the open reports of an item with the posted action.
+func (h *ReportHandler) Resolve(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	targetType := c.Param("type")
+	if err != nil || (targetType != constants.ReportTargetReview && targetType != constants.ReportTargetComment) {
+		middleware.SetFlashError(c, messages.ErrAdminReportNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminReports)
+		return
+	}
+
+	target := repository.ReportTarget{Type: targetType, ID: uint(id)}
+	action := c.PostForm("action")
+	if err := h.service.Resolve(c.Request.Context(), middleware.GetCurrentUser(c), target, action); err != nil {
+		errMsg := messages.ErrAdminReportResolveFail
+		switch {
+		case errors.Is(err, appErrors.ErrReportInvalidAction):
+			errMsg = messages.ErrAdminReportInvalidAction
+		case errors.Is(err, appErrors.ErrReportTargetNotFound):
+			errMsg = messages.ErrAdminReportNotFound
+		case errors.Is(err, appErrors.ErrCannotBanSelf):
+			errMsg = messages.ErrAdminUserCannotBanSelf
+		case errors.Is(err, appErrors.ErrCannotBanAdmin):
+			errMsg = messages.ErrAdminUserCannotBanAdmin
+		default:
+			slog.Error(messages.LogAdminReportResolveFailed, "target_type", targetType, "target_id", id, "action", action, "error", err)
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, constants.RouteAdminReports)
+		return
+	}
+
+	var msg string
+	switch action {
+	case constants.ReportActionDismiss:
+		msg = messages.MsgAdminReportDismissed
+	case constants.ReportActionHide:
+		msg = messages.MsgAdminReportHidden
+	case constants.ReportActionWarn:
+		msg = messages.MsgAdminReportWarned
+	case constants.ReportActionBan:
+		msg = messages.MsgAdminReportBanned
+	}
+	middleware.SetFlashSuccess(c, msg)
+	c.Redirect(http.StatusFound, constants.RouteAdminReports)
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
@@ -31,15 +32,19 @@ func (h *ReviewHandler) List(c *gin.Context) {
 	}
 
 	filter := repository.ReviewFilter{
-		Status:  c.Query("status"),
-		Type:    c.Query("type"),
-		Keyword: c.Query("keyword"),
-		Page:    page,
-		Limit:   constants.DefaultPageLimit,
+		Status:   c.Query("status"),
+		Type:     c.Query("type"),
+		Location: strings.TrimSpace(c.Query("location")),
+		Keyword:  c.Query("keyword"),
+		Page:     page,
+		Limit:    constants.DefaultPageLimit,
 	}
 	if uid, err := strconv.ParseUint(c.Query("user_id"), 10, 64); err == nil {
 		filter.UserID = uint(uid)
 	}
+	if tid, err := strconv.ParseUint(c.Query("tour_id"), 10, 64); err == nil {
+		filter.TourID = uint(tid)
+	}
 
 	reviews, total, err := h.service.AdminListReviews(c.Request.Context(), filter)
 	if err != nil {
@@ -72,6 +77,9 @@ func (h *ReviewHandler) List(c *gin.Context) {
 		"page":        page,
 		"total_pages": totalPages,
 		"filter":      filter,
+
+		"flag_score":   constants.ModerationFlagScore,
+		"reject_score": constants.ModerationRejectScore,
 	})
 }
 
@@ -106,7 +114,7 @@ func (h *ReviewHandler) Reject(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.AdminRejectReview(c.Request.Context(), uint(id)); err != nil {
+	if err := h.service.AdminRejectReview(c.Request.Context(), uint(id), c.PostForm("reason")); err != nil {
 		slog.Error(messages.LogAdminReviewRejectFailed, "review_id", id, "error", err)
 		errMsg := messages.ErrAdminReviewRejectFail
 		if errors.Is(err, appErrors.ErrReviewNotFound) {
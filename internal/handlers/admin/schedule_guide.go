@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleGuideHandler struct {
+	service         *services.GuideService
+	scheduleService *services.ScheduleService
+}
+
+func NewScheduleGuideHandler(service *services.GuideService, scheduleService *services.ScheduleService) *ScheduleGuideHandler {
+	return &ScheduleGuideHandler{service: service, scheduleService: scheduleService}
+}
+
+func (h *ScheduleGuideHandler) List(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	schedule, err := h.scheduleService.GetSchedule(c.Request.Context(), uint(id))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "admin/pages/error.html", gin.H{
+			"status":  404,
+			"message": messages.ErrAdminScheduleNotFound,
+		})
+		return
+	}
+
+	assignments, err := h.service.ListAssignments(c.Request.Context(), schedule.ID)
+	if err != nil {
+		slog.Error(messages.LogAdminGuideListFailed, "schedule_id", id, "error", err)
+		c.HTML(http.StatusInternalServerError, "admin/pages/error.html", gin.H{
+			"status":  500,
+			"message": messages.ErrInternalServer,
+		})
+		return
+	}
+
+	guides, err := h.service.ListGuides(c.Request.Context())
+	if err != nil {
+		slog.Error(messages.LogAdminGuideListFailed, "schedule_id", id, "error", err)
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/schedule_guides.html", gin.H{
+		"title":       messages.TitleAdminScheduleGuides,
+		"active_menu": "tours",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"tour":        schedule.Tour,
+		"schedule":    schedule,
+		"assignments": assignments,
+		"guides":      guides,
+	})
+}
+
+func (h *ScheduleGuideHandler) Assign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+	backURL := fmt.Sprintf(constants.RouteAdminScheduleGuides, id)
+
+	guideID, err := strconv.ParseUint(c.PostForm("guide_id"), 10, 64)
+	if err != nil || guideID == 0 {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	if err := h.service.AssignGuide(c.Request.Context(), uint(id), uint(guideID)); err != nil {
+		slog.Error(messages.LogAdminGuideAssignFailed, "schedule_id", id, "guide_id", guideID, "error", err)
+		var appErr *appErrors.AppError
+		switch {
+		case errors.Is(err, appErrors.ErrScheduleNotFound):
+			middleware.SetFlashError(c, messages.ErrAdminScheduleNotFound)
+		case errors.As(err, &appErr):
+			middleware.SetFlashError(c, appErr.Message)
+		default:
+			middleware.SetFlashError(c, messages.ErrAdminGuideAssignFail)
+		}
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminGuideAssigned)
+	c.Redirect(http.StatusFound, backURL)
+}
+
+func (h *ScheduleGuideHandler) Unassign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+	backURL := fmt.Sprintf(constants.RouteAdminScheduleGuides, id)
+
+	guideID, err := strconv.ParseUint(c.Param("guide_id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	if err := h.service.UnassignGuide(c.Request.Context(), uint(id), uint(guideID)); err != nil {
+		slog.Error(messages.LogAdminGuideUnassignFailed, "schedule_id", id, "guide_id", guideID, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminGuideUnassignFail)
+		}
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminGuideUnassigned)
+	c.Redirect(http.StatusFound, backURL)
+}
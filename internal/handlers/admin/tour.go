@@ -1,19 +1,21 @@
 package admin
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 	"sun-booking-tours/internal/services"
+	"sun-booking-tours/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -43,7 +45,7 @@ func (h *TourHandler) List(c *gin.Context) {
 		filter.CategoryID = uint(catID)
 	}
 
-	tours, total, err := h.service.ListTours(c.Request.Context(), filter)
+	tours, total, _, err := h.service.ListTours(c.Request.Context(), filter)
 	if err != nil {
 		slog.Error(messages.LogAdminTourListFailed, "error", err)
 		c.HTML(http.StatusInternalServerError, "admin/pages/error.html", gin.H{
@@ -71,12 +73,13 @@ func (h *TourHandler) List(c *gin.Context) {
 		"flash_success": flashSuccess,
 		"flash_error":   flashError,
 
-		"tours":       tours,
-		"total":       total,
-		"page":        page,
-		"total_pages": totalPages,
-		"filter":      filter,
-		"categories":  categories,
+		"tours":        tours,
+		"total":        total,
+		"page":         page,
+		"total_pages":  totalPages,
+		"filter":       filter,
+		"categories":   categories,
+		"return_query": c.Request.URL.RawQuery,
 	})
 }
 
@@ -93,9 +96,10 @@ func (h *TourHandler) CreateForm(c *gin.Context) {
 		"flash_success": flashSuccess,
 		"flash_error":   flashError,
 
-		"categories": categories,
-		"is_edit":    false,
-		"form_url":   constants.RouteAdminTourCreate,
+		"categories":         categories,
+		"is_edit":            false,
+		"form_url":           constants.RouteAdminTourCreate,
+		"translated_locales": utils.TranslatedLocales(),
 	})
 }
 
@@ -106,6 +110,7 @@ func (h *TourHandler) Create(c *gin.Context) {
 		c.Redirect(http.StatusFound, constants.RouteAdminTourCreate)
 		return
 	}
+	form.Translations = bindTranslations(c)
 
 	if err := h.service.CreateTour(c.Request.Context(), &form); err != nil {
 		slog.Error(messages.LogAdminTourCreateFailed, "error", err)
@@ -149,10 +154,8 @@ func (h *TourHandler) EditForm(c *gin.Context) {
 		selectedCatIDs = append(selectedCatIDs, cat.ID)
 	}
 
-	var imageURLs []string
-	if len(tour.Images) > 0 {
-		_ = json.Unmarshal(tour.Images, &imageURLs)
-	}
+	imageURLs := models.ImageAssetURLs(models.ParseImageAssets(tour.Images))
+	slugHistory, _ := h.service.SlugHistory(c.Request.Context(), tour.ID)
 
 	flashSuccess, flashError := middleware.GetFlash(c)
 
@@ -165,12 +168,14 @@ func (h *TourHandler) EditForm(c *gin.Context) {
 		"flash_success": flashSuccess,
 		"flash_error":   flashError,
 
-		"categories":       categories,
-		"is_edit":          true,
-		"tour":             tour,
-		"form_url":         fmt.Sprintf(constants.RouteAdminTourEdit, id),
-		"selected_cat_ids": selectedCatIDs,
-		"image_urls":       imageURLs,
+		"categories":         categories,
+		"is_edit":            true,
+		"tour":               tour,
+		"form_url":           fmt.Sprintf(constants.RouteAdminTourEdit, id),
+		"translated_locales": utils.TranslatedLocales(),
+		"selected_cat_ids":   selectedCatIDs,
+		"image_urls":         imageURLs,
+		"slug_history":       slugHistory,
 	})
 }
 
@@ -190,6 +195,7 @@ func (h *TourHandler) Update(c *gin.Context) {
 		c.Redirect(http.StatusFound, fmt.Sprintf(constants.RouteAdminTourEdit, id))
 		return
 	}
+	form.Translations = bindTranslations(c)
 
 	if err := h.service.UpdateTour(c.Request.Context(), uint(id), &form); err != nil {
 		slog.Error(messages.LogAdminTourUpdateFailed, "error", err)
@@ -232,3 +238,109 @@ func (h *TourHandler) Delete(c *gin.Context) {
 	middleware.SetFlashSuccess(c, messages.MsgAdminTourDeleted)
 	c.Redirect(http.StatusFound, constants.RouteAdminTours)
 }
+
+func (h *TourHandler) CloneForm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	tour, err := h.service.GetTour(c.Request.Context(), uint(id))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "admin/pages/error.html", gin.H{
+			"status":  404,
+			"message": messages.ErrAdminTourNotFound,
+		})
+		return
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/tour_clone.html", gin.H{
+		"title":       messages.TitleAdminTourClone,
+		"active_menu": "tours",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"tour":     tour,
+		"form_url": fmt.Sprintf(constants.RouteAdminTourClone, id),
+	})
+}
+
+// Clone creates a draft copy of the tour and opens it for editing.
+func (h *TourHandler) Clone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	var form services.CloneForm
+	if err := c.ShouldBind(&form); err != nil {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, fmt.Sprintf(constants.RouteAdminTourClone, id))
+		return
+	}
+
+	clone, err := h.service.CloneTour(c.Request.Context(), uint(id), &form)
+	if err != nil {
+		slog.Error(messages.LogAdminTourCloneFailed, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminTourCloneFail)
+		}
+		c.Redirect(http.StatusFound, fmt.Sprintf(constants.RouteAdminTourClone, id))
+		return
+	}
+
+	middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTourCloned, clone.Title, len(clone.Schedules)))
+	c.Redirect(http.StatusFound, fmt.Sprintf(constants.RouteAdminTourEdit, clone.ID))
+}
+
+// BulkCategories adds or removes categories across the tours ticked on the
+// list page, then returns to the list with its filters.
+func (h *TourHandler) BulkCategories(c *gin.Context) {
+	back := constants.RouteAdminTours
+	if query, err := url.ParseQuery(c.PostForm("return_query")); err == nil && len(query) > 0 {
+		back += "?" + query.Encode()
+	}
+
+	var form services.BulkCategoryForm
+	if err := c.ShouldBind(&form); err != nil {
+		middleware.SetFlashError(c, messages.ErrInvalidForm)
+		c.Redirect(http.StatusFound, back)
+		return
+	}
+
+	changed, err := h.service.BulkUpdateCategories(c.Request.Context(), &form)
+	if err != nil {
+		slog.Error(messages.LogAdminTourBulkFailed, "action", form.Action, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminTourBulkFail)
+		}
+		c.Redirect(http.StatusFound, back)
+		return
+	}
+
+	if form.Action == services.BulkCategoryAdd {
+		middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTourBulkAdded, changed))
+	} else {
+		middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTourBulkRemoved, changed))
+	}
+	c.Redirect(http.StatusFound, back)
+}
@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tourImportMaxMB limits the size of an uploaded import file.
+const tourImportMaxMB = 5
+
+type TourImportHandler struct {
+	service *services.TourImportService
+}
+
+func NewTourImportHandler(service *services.TourImportService) *TourImportHandler {
+	return &TourImportHandler{service: service}
+}
+
+func (h *TourImportHandler) Form(c *gin.Context) {
+	h.render(c, nil)
+}
+
+// Import validates the uploaded file and, unless it is a dry run, creates
+// its tours. The row report is shown in place whenever something was not
+// imported.
+func (h *TourImportHandler) Import(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	format := ""
+	if err == nil {
+		format = services.TourFileFormat(fh.Filename)
+	}
+	if err != nil || format == "" || fh.Size > tourImportMaxMB<<20 {
+		middleware.SetFlashError(c, fmt.Sprintf(messages.ErrAdminTourImportFile, tourImportMaxMB))
+		c.Redirect(http.StatusFound, constants.RouteAdminTourImport)
+		return
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		slog.Error(messages.LogAdminTourImportFailed, "error", err)
+		middleware.SetFlashError(c, messages.ErrAdminTourImportFail)
+		c.Redirect(http.StatusFound, constants.RouteAdminTourImport)
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.PostForm("dry_run") == "true"
+	report, err := h.service.Import(c.Request.Context(), format, file, dryRun)
+	if err != nil {
+		slog.Error(messages.LogAdminTourImportFailed, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminTourImportFail)
+		}
+		c.Redirect(http.StatusFound, constants.RouteAdminTourImport)
+		return
+	}
+
+	if report.Imported > 0 {
+		middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTourImported, report.Imported))
+		c.Redirect(http.StatusFound, constants.RouteAdminTours)
+		return
+	}
+	h.render(c, report)
+}
+
+// Export downloads every tour as CSV or JSON (?format=json).
+func (h *TourImportHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", services.TourFileCSV)
+	if format != services.TourFileCSV && format != services.TourFileJSON {
+		format = services.TourFileCSV
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.Export(c.Request.Context(), format, &buf); err != nil {
+		slog.Error(messages.LogAdminTourExportFailed, "error", err)
+		middleware.SetFlashError(c, messages.ErrAdminTourExportFail)
+		c.Redirect(http.StatusFound, constants.RouteAdminTours)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == services.TourFileJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("tours-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+func (h *TourImportHandler) render(c *gin.Context, report *services.TourImportReport) {
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/tour_import.html", gin.H{
+		"title":       messages.TitleAdminTourImport,
+		"active_menu": "tours",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"report":   report,
+		"max_mb":   tourImportMaxMB,
+		"form_url": constants.RouteAdminTourImport,
+	})
+}
@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"fmt"
+
+	"sun-booking-tours/internal/services"
+	"sun-booking-tours/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindTranslations reads the language tabs of the tour and category forms.
+// Fields are named translations[<locale>][<field>]; the itinerary fields
+// repeat once per day.
+func bindTranslations(c *gin.Context) []services.TranslationForm {
+	var forms []services.TranslationForm
+	for _, locale := range utils.TranslatedLocales() {
+		field := func(name string) string {
+			return fmt.Sprintf("translations[%s][%s]", locale, name)
+		}
+		forms = append(forms, services.TranslationForm{
+			Locale:                  locale,
+			Name:                    c.PostForm(field("name")),
+			Slug:                    c.PostForm(field("slug")),
+			Description:             c.PostForm(field("description")),
+			ItineraryTitles:         c.PostFormArray(field("itinerary_title")),
+			ItineraryDescriptions:   c.PostFormArray(field("itinerary_description")),
+			ItineraryAccommodations: c.PostFormArray(field("itinerary_accommodation")),
+		})
+	}
+	return forms
+}
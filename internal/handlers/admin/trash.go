@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	service *services.TrashService
+}
+
+func NewTrashHandler(service *services.TrashService) *TrashHandler {
+	return &TrashHandler{service: service}
+}
+
+// List shows one kind of deleted record (?kind=tours|categories|reviews).
+func (h *TrashHandler) List(c *gin.Context) {
+	kind := c.DefaultQuery("kind", constants.TrashKindTours)
+	if !services.IsTrashKind(kind) {
+		kind = constants.TrashKindTours
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit := constants.DefaultPageLimit
+
+	items, total, err := h.service.List(c.Request.Context(), kind, page, limit)
+	if err != nil {
+		slog.Error(messages.LogAdminTrashListFailed, "error", err)
+		c.HTML(http.StatusInternalServerError, "admin/pages/error.html", gin.H{
+			"status":  500,
+			"message": messages.ErrInternalServer,
+		})
+		return
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "admin/pages/trash.html", gin.H{
+		"title":       messages.TitleAdminTrash,
+		"active_menu": "trash",
+		"user":        middleware.GetCurrentUser(c),
+		"csrf_token":  middleware.CSRFToken(c),
+
+		"flash_success": flashSuccess,
+		"flash_error":   flashError,
+
+		"kind":        kind,
+		"items":       items,
+		"total":       total,
+		"page":        page,
+		"total_pages": totalPages,
+	})
+}
+
+func (h *TrashHandler) Restore(c *gin.Context) {
+	kind := c.Param("kind")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || !services.IsTrashKind(kind) {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	restored, err := h.service.Restore(c.Request.Context(), kind, uint(id))
+	if err != nil {
+		slog.Error(messages.LogAdminTrashRestoreFailed, "kind", kind, "id", id, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminTrashRestoreFail)
+		}
+		c.Redirect(http.StatusFound, trashURL(kind))
+		return
+	}
+
+	if restored.SlugChanged {
+		middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTrashRestoredSlug, restored.Title, restored.Slug))
+	} else {
+		middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTrashRestored, restored.Title))
+	}
+	c.Redirect(http.StatusFound, trashURL(kind))
+}
+
+func (h *TrashHandler) Purge(c *gin.Context) {
+	kind := c.Param("kind")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || !services.IsTrashKind(kind) {
+		c.HTML(http.StatusBadRequest, "admin/pages/error.html", gin.H{
+			"status":  400,
+			"message": messages.ErrInvalidForm,
+		})
+		return
+	}
+
+	title, err := h.service.Purge(c.Request.Context(), kind, uint(id))
+	if err != nil {
+		slog.Error(messages.LogAdminTrashPurgeFailed, "kind", kind, "id", id, "error", err)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrAdminTrashPurgeFail)
+		}
+		c.Redirect(http.StatusFound, trashURL(kind))
+		return
+	}
+
+	middleware.SetFlashSuccess(c, fmt.Sprintf(messages.MsgAdminTrashPurged, title))
+	c.Redirect(http.StatusFound, trashURL(kind))
+}
+
+func trashURL(kind string) string {
+	return constants.RouteAdminTrash + "?kind=" + url.QueryEscape(kind)
+}
@@ -19,10 +19,11 @@ import (
 
 type UserHandler struct {
 	service *services.AdminUserService
+	ratings *services.RatingService
 }
 
-func NewUserHandler(service *services.AdminUserService) *UserHandler {
-	return &UserHandler{service: service}
+func NewUserHandler(service *services.AdminUserService, ratings *services.RatingService) *UserHandler {
+	return &UserHandler{service: service, ratings: ratings}
 }
 
 func (h *UserHandler) List(c *gin.Context) {
@@ -102,6 +103,11 @@ func (h *UserHandler) Detail(c *gin.Context) {
 		return
 	}
 
+	grants, err := h.ratings.ListRatingGrants(c.Request.Context(), target.ID)
+	if err != nil {
+		slog.Error(messages.LogAdminUserDetailFailed, "user_id", id, "error", err)
+	}
+
 	flashSuccess, flashError := middleware.GetFlash(c)
 
 	c.HTML(http.StatusOK, "admin/pages/user_detail.html", gin.H{
@@ -113,7 +119,8 @@ func (h *UserHandler) Detail(c *gin.Context) {
 		"flash_success": flashSuccess,
 		"flash_error":   flashError,
 
-		"target": target,
+		"target":        target,
+		"rating_grants": grants,
 	})
 }
 
@@ -161,3 +168,112 @@ func (h *UserHandler) UpdateStatus(c *gin.Context) {
 	middleware.SetFlashSuccess(c, messages.MsgAdminUserStatusUpdated)
 	c.Redirect(http.StatusFound, backURL)
 }
+
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminUserNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminUsers)
+		return
+	}
+
+	role := c.PostForm("role")
+	backURL := fmt.Sprintf(constants.RouteAdminUserDetail, id)
+
+	adminUser := middleware.GetCurrentUser(c)
+	if err := h.service.UpdateUserRole(c.Request.Context(), adminUser, uint(id), role); err != nil {
+		errMsg := messages.ErrAdminUserRoleUpdateFail
+		switch {
+		case errors.Is(err, appErrors.ErrInvalidRole):
+			errMsg = messages.ErrAdminUserInvalidRole
+		case errors.Is(err, appErrors.ErrCannotChangeOwnRole):
+			errMsg = messages.ErrAdminUserCannotChangeOwnRole
+		case errors.Is(err, appErrors.ErrCannotChangeAdminRole):
+			errMsg = messages.ErrAdminUserCannotChangeAdminRole
+		case errors.Is(err, appErrors.ErrUserNotFound):
+			errMsg = messages.ErrAdminUserNotFound
+		default:
+			slog.Error(messages.LogAdminUserRoleFailed, "user_id", id, "role", role, "error", err)
+		}
+
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminUserRoleUpdated)
+	c.Redirect(http.StatusFound, backURL)
+}
+
+// AllowRating lets the user rate a tour, given by slug, without a completed
+// booking.
+func (h *UserHandler) AllowRating(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminUserNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminUsers)
+		return
+	}
+	backURL := fmt.Sprintf(constants.RouteAdminUserDetail, id)
+
+	if _, err := h.service.GetUserDetail(c.Request.Context(), uint(id)); err != nil {
+		if !errors.Is(err, appErrors.ErrUserNotFound) {
+			slog.Error(messages.LogAdminUserRatingGrantFailed, "user_id", id, "error", err)
+		}
+		middleware.SetFlashError(c, messages.ErrAdminUserNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminUsers)
+		return
+	}
+
+	admin := middleware.GetCurrentUser(c)
+	if err := h.ratings.AllowRating(c.Request.Context(), admin.ID, uint(id), c.PostForm("tour_slug")); err != nil {
+		errMsg := messages.ErrAdminUserRatingGrantFail
+		switch {
+		case errors.Is(err, appErrors.ErrTourNotFound):
+			errMsg = messages.ErrAdminUserRatingGrantTour
+		case errors.Is(err, appErrors.ErrRatingGrantExists):
+			errMsg = messages.ErrAdminUserRatingGrantExists
+		default:
+			slog.Error(messages.LogAdminUserRatingGrantFailed, "user_id", id, "error", err)
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminUserRatingGranted)
+	c.Redirect(http.StatusFound, backURL)
+}
+
+// RevokeRating withdraws a rating grant; a rating already left stays.
+func (h *UserHandler) RevokeRating(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminUserNotFound)
+		c.Redirect(http.StatusFound, constants.RouteAdminUsers)
+		return
+	}
+	backURL := fmt.Sprintf(constants.RouteAdminUserDetail, id)
+
+	tourID, err := strconv.ParseUint(c.Param("tour_id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrAdminUserRatingGrantNotFound)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	if err := h.ratings.RevokeRatingGrant(c.Request.Context(), uint(id), uint(tourID)); err != nil {
+		errMsg := messages.ErrAdminUserRatingGrantFail
+		if errors.Is(err, appErrors.ErrRatingGrantNotFound) {
+			errMsg = messages.ErrAdminUserRatingGrantNotFound
+		} else {
+			slog.Error(messages.LogAdminUserRatingGrantFailed, "user_id", id, "tour_id", tourID, "error", err)
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	middleware.SetFlashSuccess(c, messages.MsgAdminUserRatingRevoked)
+	c.Redirect(http.StatusFound, backURL)
+}
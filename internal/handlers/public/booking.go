@@ -39,6 +39,13 @@ func (h *BookingHandler) Form(c *gin.Context) {
 		return
 	}
 
+	liveSlug := isLiveTourSlug(tour, slug)
+	tour.Localize(middleware.GetLocale(c))
+
+	if redirectToCanonicalSlug(c, slug, tour.Slug, middleware.LocalePath(c, fmt.Sprintf("/tours/%s/book", tour.Slug)), liveSlug) {
+		return
+	}
+
 	if len(tour.Schedules) == 0 {
 		middleware.SetFlashError(c, messages.ErrBookingNoSchedules)
 		c.Redirect(http.StatusFound, fmt.Sprintf("/tours/%s", tour.Slug))
@@ -85,6 +92,19 @@ func (h *BookingHandler) Create(c *gin.Context) {
 		return
 	}
 
+	meetingPointID, _ := strconv.ParseUint(c.PostForm("meeting_point_id"), 10, 64)
+	pickupPoint, err := services.PickupPoint(tour, uint(meetingPointID))
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			middleware.SetFlashError(c, appErr.Message)
+		} else {
+			middleware.SetFlashError(c, messages.ErrInvalidForm)
+		}
+		c.Redirect(http.StatusFound, fmt.Sprintf("/tours/%s/book?schedule_id=%d", slug, scheduleID))
+		return
+	}
+
 	booking, err := h.bookingService.CreateBooking(
 		c.Request.Context(),
 		user.ID,
@@ -93,6 +113,7 @@ func (h *BookingHandler) Create(c *gin.Context) {
 		numParticipants,
 		tour.Price,
 		note,
+		pickupPoint,
 	)
 	if err != nil {
 		slog.Error(messages.LogBookingCreateFailed, "slug", slug, "schedule_id", scheduleID, "error", err)
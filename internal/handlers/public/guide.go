@@ -0,0 +1,93 @@
+package public
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuideHandler serves the restricted portal where guides see the departures
+// they are assigned to and the passenger list for each one.
+type GuideHandler struct {
+	service *services.GuideService
+}
+
+func NewGuideHandler(service *services.GuideService) *GuideHandler {
+	return &GuideHandler{service: service}
+}
+
+func (h *GuideHandler) List(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+
+	schedules, err := h.service.ListMySchedules(c.Request.Context(), user.ID)
+	if err != nil {
+		slog.Error(messages.LogGuideScheduleListFailed, "user_id", user.ID, "error", err)
+		h.renderError(c, http.StatusInternalServerError, messages.ErrInternalServer)
+		return
+	}
+
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "public/pages/guide_schedules_list.html", gin.H{
+		"title":          messages.TitleGuideSchedules,
+		"user":           user,
+		"csrf_token":     middleware.CSRFToken(c),
+		"nav_categories": middleware.GetNavCategories(c),
+		"flash_success":  flashSuccess,
+		"flash_error":    flashError,
+		"schedules":      schedules,
+	})
+}
+
+func (h *GuideHandler) Detail(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	scheduleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.renderError(c, http.StatusBadRequest, messages.ErrGuideScheduleNotFound)
+		return
+	}
+
+	schedule, passengers, err := h.service.GetMySchedule(c.Request.Context(), user.ID, uint(scheduleID))
+	if err != nil {
+		if errors.Is(err, appErrors.ErrScheduleNotFound) {
+			h.renderError(c, http.StatusNotFound, messages.ErrGuideScheduleNotFound)
+			return
+		}
+		slog.Error(messages.LogGuideScheduleDetailFailed, "schedule_id", scheduleID, "error", err)
+		h.renderError(c, http.StatusInternalServerError, messages.ErrInternalServer)
+		return
+	}
+
+	totalPassengers := 0
+	for _, b := range passengers {
+		totalPassengers += b.NumParticipants
+	}
+
+	c.HTML(http.StatusOK, "public/pages/guide_schedule_detail.html", gin.H{
+		"title":            messages.TitleGuideScheduleDetail,
+		"user":             user,
+		"csrf_token":       middleware.CSRFToken(c),
+		"nav_categories":   middleware.GetNavCategories(c),
+		"schedule":         schedule,
+		"passengers":       passengers,
+		"total_passengers": totalPassengers,
+	})
+}
+
+func (h *GuideHandler) renderError(c *gin.Context, status int, message string) {
+	c.HTML(status, "public/pages/error.html", gin.H{
+		"title":          message,
+		"status":         status,
+		"message":        message,
+		"user":           middleware.GetCurrentUser(c),
+		"nav_categories": middleware.GetNavCategories(c),
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"sun-booking-tours/internal/constants"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -14,10 +15,11 @@ import (
 
 type HomeHandler struct {
 	tourService *services.TourService
+	recService  *services.RecommendationService
 }
 
-func NewHomeHandler(tourService *services.TourService) *HomeHandler {
-	return &HomeHandler{tourService: tourService}
+func NewHomeHandler(tourService *services.TourService, recService *services.RecommendationService) *HomeHandler {
+	return &HomeHandler{tourService: tourService, recService: recService}
 }
 
 func (h *HomeHandler) Index(c *gin.Context) {
@@ -33,15 +35,31 @@ func (h *HomeHandler) Index(c *gin.Context) {
 		slog.Error(messages.LogHomeLatestFailed, "error", err)
 	}
 
+	user := middleware.GetCurrentUser(c)
+	var recommended []models.Tour
+	if user != nil {
+		recommended, err = h.recService.ForUser(ctx, user.ID, constants.HomeRecommendedLimit)
+		if err != nil {
+			slog.Error(messages.LogRecommendationLoadFailed, "user_id", user.ID, "error", err)
+		}
+	}
+
+	locale := middleware.GetLocale(c)
+	models.LocalizeTours(featured, locale)
+	models.LocalizeTours(latest, locale)
+	models.LocalizeTours(recommended, locale)
+
 	flashSuccess, flashError := middleware.GetFlash(c)
 	c.HTML(http.StatusOK, "public/pages/home.html", gin.H{
 		"title":          messages.TitleHome,
-		"user":           middleware.GetCurrentUser(c),
+		"user":           user,
 		"csrf_token":     middleware.CSRFToken(c),
 		"flash_success":  flashSuccess,
 		"flash_error":    flashError,
 		"nav_categories": middleware.GetNavCategories(c),
 		"featured_tours": featured,
 		"new_tours":      latest,
+		"recommended":    recommended,
+		"locale":         locale,
 	})
 }
@@ -11,6 +11,7 @@ import (
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -49,17 +50,28 @@ func (h *RatingHandler) Rate(c *gin.Context) {
 		return
 	}
 
+	aspects, ok := ratingAspects(c)
+	if !ok {
+		middleware.SetFlashError(c, messages.ErrRatingInvalid)
+		c.Redirect(http.StatusFound, redirectURL)
+		return
+	}
+
 	input := services.RatingInput{
 		Score:   score,
 		Comment: c.PostForm("comment"),
+		Aspects: aspects,
 	}
 
 	isNew, err := h.ratingService.RateOrUpdate(c.Request.Context(), user.ID, tour.ID, input)
 	if err != nil {
 		slog.Error(messages.LogRatingFailed, "tour_id", tour.ID, "user_id", user.ID, "error", err)
 		errMsg := messages.ErrRatingFail
-		if errors.Is(err, appErrors.ErrInvalidScore) {
+		switch {
+		case errors.Is(err, appErrors.ErrInvalidScore):
 			errMsg = messages.ErrRatingInvalid
+		case errors.Is(err, appErrors.ErrRatingNotTaken):
+			errMsg = messages.ErrRatingNotTaken
 		}
 		middleware.SetFlashError(c, errMsg)
 		c.Redirect(http.StatusFound, redirectURL)
@@ -73,3 +85,20 @@ func (h *RatingHandler) Rate(c *gin.Context) {
 	}
 	c.Redirect(http.StatusFound, redirectURL)
 }
+
+// ratingAspects reads the optional sub-scores; a blank field leaves the
+// aspect unrated. ok is false when a field is not a valid score.
+func ratingAspects(c *gin.Context) (aspects models.RatingAspects, ok bool) {
+	for _, aspect := range models.RatingAspectKeys {
+		raw := c.PostForm(models.RatingAspectColumn(aspect))
+		if raw == "" {
+			continue
+		}
+		score, err := strconv.Atoi(raw)
+		if err != nil || score < constants.RatingMinScore || score > constants.RatingMaxScore {
+			return aspects, false
+		}
+		aspects.Set(aspect, &score)
+	}
+	return aspects, true
+}
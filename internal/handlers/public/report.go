@@ -0,0 +1,96 @@
+package public
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	service *services.ReportService
+}
+
+func NewReportHandler(service *services.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// ReportReview reports a review, then returns to it.
+func (h *ReportHandler) ReportReview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrReviewNotFound)
+		c.Redirect(http.StatusFound, constants.RoutePublicReviews)
+		return
+	}
+	target := repository.ReportTarget{Type: constants.ReportTargetReview, ID: uint(id)}
+	h.report(c, target, fmt.Sprintf("%s/%d", constants.RoutePublicReviews, id))
+}
+
+// ReportComment reports a comment, then returns to the review named by the
+// "review_id" form field.
+func (h *ReportHandler) ReportComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		middleware.SetFlashError(c, messages.ErrCommentNotFound)
+		c.Redirect(http.StatusFound, constants.RoutePublicReviews)
+		return
+	}
+	backURL := constants.RoutePublicReviews
+	if reviewID, err := strconv.ParseUint(c.PostForm("review_id"), 10, 64); err == nil && reviewID > 0 {
+		backURL = fmt.Sprintf("%s/%d", constants.RoutePublicReviews, reviewID)
+	}
+	target := repository.ReportTarget{Type: constants.ReportTargetComment, ID: uint(id)}
+	h.report(c, target, backURL)
+}
+
+func (h *ReportHandler) report(c *gin.Context, target repository.ReportTarget, backURL string) {
+	user := middleware.GetCurrentUser(c)
+	hidden, err := h.service.Report(c.Request.Context(), user.ID, services.ReportInput{
+		Target: target,
+		Reason: c.PostForm("reason"),
+		Note:   c.PostForm("note"),
+	})
+	if err != nil {
+		errMsg := messages.ErrReportFail
+		switch {
+		case errors.Is(err, appErrors.ErrReportInvalidReason):
+			errMsg = messages.ErrReportInvalidReason
+		case errors.Is(err, appErrors.ErrReportOwnContent):
+			errMsg = messages.ErrReportOwnContent
+		case errors.Is(err, appErrors.ErrAlreadyReported):
+			errMsg = messages.ErrAlreadyReported
+		case errors.Is(err, appErrors.ErrReportTargetNotFound):
+			errMsg = messages.ErrReviewNotFound
+			if target.Type == constants.ReportTargetComment {
+				errMsg = messages.ErrCommentNotFound
+			}
+		default:
+			slog.Error(messages.LogReportFailed, "target_type", target.Type, "target_id", target.ID, "user_id", user.ID, "error", err)
+		}
+		middleware.SetFlashError(c, errMsg)
+		c.Redirect(http.StatusFound, backURL)
+		return
+	}
+
+	if hidden {
+		middleware.SetFlashSuccess(c, messages.MsgReportHidden)
+		if target.Type == constants.ReportTargetReview {
+			// The review page is no longer public.
+			backURL = constants.RoutePublicReviews
+		}
+	} else {
+		middleware.SetFlashSuccess(c, messages.MsgReportSent)
+	}
+	c.Redirect(http.StatusFound, backURL)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 	"sun-booking-tours/internal/services"
 
@@ -33,11 +35,15 @@ func (h *ReviewHandler) List(c *gin.Context) {
 	}
 
 	filter := repository.ReviewFilter{
-		Type:    c.Query("type"),
-		Keyword: c.Query("q"),
-		Sort:    c.Query("sort"),
-		Page:    page,
-		Limit:   constants.DefaultPageLimit,
+		Type:     c.Query("type"),
+		Location: strings.TrimSpace(c.Query("location")),
+		Keyword:  c.Query("q"),
+		Sort:     c.Query("sort"),
+		Page:     page,
+		Limit:    constants.DefaultPageLimit,
+	}
+	if tid, err := strconv.ParseUint(c.Query("tour_id"), 10, 64); err == nil {
+		filter.TourID = uint(tid)
 	}
 
 	reviews, total, err := h.service.ListPublicReviews(c.Request.Context(), filter)
@@ -75,7 +81,18 @@ func (h *ReviewHandler) Detail(c *gin.Context) {
 		return
 	}
 
-	review, comments, err := h.service.GetPublicReview(c.Request.Context(), uint(id))
+	user := middleware.GetCurrentUser(c)
+	var userID uint
+	if user != nil {
+		userID = user.ID
+	}
+
+	commentPage, _ := strconv.Atoi(c.DefaultQuery("cpage", "1"))
+	if commentPage < 1 {
+		commentPage = 1
+	}
+
+	review, comments, commentTotal, err := h.service.GetPublicReview(c.Request.Context(), uint(id), userID, commentPage)
 	if err != nil {
 		if errors.Is(err, appErrors.ErrReviewNotFound) {
 			h.renderError(c, http.StatusNotFound, messages.ErrReviewNotFound)
@@ -86,12 +103,8 @@ func (h *ReviewHandler) Detail(c *gin.Context) {
 		return
 	}
 
-	user := middleware.GetCurrentUser(c)
-	var userID uint
-	if user != nil {
-		userID = user.ID
-	}
 	hasLiked := h.service.HasUserLiked(c.Request.Context(), userID, uint(id))
+	commentPages := max(1, (int(commentTotal)+constants.CommentPageLimit-1)/constants.CommentPageLimit)
 
 	flashSuccess, flashError := middleware.GetFlash(c)
 
@@ -104,20 +117,46 @@ func (h *ReviewHandler) Detail(c *gin.Context) {
 		"flash_error":    flashError,
 		"review":         review,
 		"comments":       comments,
+		"comment_total":  commentTotal,
+		"comment_pagination": map[string]any{
+			"Page":       commentPage,
+			"TotalPages": commentPages,
+			"PrevPage":   max(1, commentPage-1),
+			"NextPage":   min(commentPages, commentPage+1),
+			"Pages":      buildPageWindow(commentPage, commentPages),
+		},
 		"has_liked":      hasLiked,
+		"report_reasons": services.ReportReasonOptions(),
 	})
 }
 
 func (h *ReviewHandler) CreateForm(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	bookings := h.reviewableBookings(c, user.ID, 0)
+
+	// ?tour_id= preselects the latest trip on that tour, for the link on
+	// the tour page.
+	var selectedBooking uint
+	if tid, err := strconv.ParseUint(c.Query("tour_id"), 10, 64); err == nil {
+		for _, b := range bookings {
+			if b.TourID == uint(tid) {
+				selectedBooking = b.ID
+				break
+			}
+		}
+	}
+
 	flashSuccess, flashError := middleware.GetFlash(c)
 	c.HTML(http.StatusOK, "public/pages/review_form.html", gin.H{
-		"title":          messages.TitleReviewCreate,
-		"user":           middleware.GetCurrentUser(c),
-		"csrf_token":     middleware.CSRFToken(c),
-		"nav_categories": middleware.GetNavCategories(c),
-		"flash_success":  flashSuccess,
-		"flash_error":    flashError,
-		"is_edit":        false,
+		"title":            messages.TitleReviewCreate,
+		"user":             user,
+		"csrf_token":       middleware.CSRFToken(c),
+		"nav_categories":   middleware.GetNavCategories(c),
+		"flash_success":    flashSuccess,
+		"flash_error":      flashError,
+		"is_edit":          false,
+		"bookings":         bookings,
+		"selected_booking": selectedBooking,
 	})
 }
 
@@ -129,23 +168,27 @@ func (h *ReviewHandler) Create(c *gin.Context) {
 		Content: c.PostForm("content"),
 		Type:    c.PostForm("type"),
 		Images:  parseImageURLs(c.PostForm("images")),
+		Files:   formFiles(c, "image_files"),
 	}
+	input.TourID, input.BookingID = reviewTrip(c)
 
-	_, err := h.service.CreateReview(c.Request.Context(), user.ID, input)
+	review, err := h.service.CreateReview(c.Request.Context(), user.ID, input)
 	if err != nil {
 		slog.Error(messages.LogReviewCreateFailed, "user_id", user.ID, "error", err)
 		errMsg := messages.ErrReviewCreateFail
 		var appErr *appErrors.AppError
-		if errors.As(err, &appErr) {
-			errMsg = getReviewValidationMsg(input)
+		if errors.Is(err, appErrors.ErrReviewTourNotBooked) {
+			errMsg = messages.ErrReviewTourNotBooked
+		} else if errors.As(err, &appErr) {
+			errMsg = getReviewValidationMsg(input, appErr)
 		}
 		middleware.SetFlashError(c, errMsg)
 		c.Redirect(http.StatusFound, constants.RoutePublicReviewCreate)
 		return
 	}
 
-	slog.Info("review created", "user_id", user.ID)
-	middleware.SetFlashSuccess(c, messages.MsgReviewCreated)
+	slog.Info("review created", "user_id", user.ID, "status", review.Status, "moderation_score", review.ModerationScore)
+	setReviewModerationFlash(c, review, messages.MsgReviewApproved, messages.MsgReviewCreated)
 	c.Redirect(http.StatusFound, constants.RouteMyReviews)
 }
 
@@ -208,17 +251,24 @@ func (h *ReviewHandler) EditForm(c *gin.Context) {
 		return
 	}
 
+	var selectedBooking uint
+	if review.BookingID != nil {
+		selectedBooking = *review.BookingID
+	}
+
 	flashSuccess, flashError := middleware.GetFlash(c)
 
 	c.HTML(http.StatusOK, "public/pages/review_form.html", gin.H{
-		"title":          messages.TitleReviewEdit,
-		"user":           user,
-		"csrf_token":     middleware.CSRFToken(c),
-		"nav_categories": middleware.GetNavCategories(c),
-		"flash_success":  flashSuccess,
-		"flash_error":    flashError,
-		"review":         review,
-		"is_edit":        true,
+		"title":            messages.TitleReviewEdit,
+		"user":             user,
+		"csrf_token":       middleware.CSRFToken(c),
+		"nav_categories":   middleware.GetNavCategories(c),
+		"flash_success":    flashSuccess,
+		"flash_error":      flashError,
+		"review":           review,
+		"is_edit":          true,
+		"bookings":         h.reviewableBookings(c, user.ID, selectedBooking),
+		"selected_booking": selectedBooking,
 	})
 }
 
@@ -235,23 +285,31 @@ func (h *ReviewHandler) Update(c *gin.Context) {
 		Content: c.PostForm("content"),
 		Type:    c.PostForm("type"),
 		Images:  parseImageURLs(c.PostForm("images")),
+		Files:   formFiles(c, "image_files"),
 	}
+	input.TourID, input.BookingID = reviewTrip(c)
 
-	if err := h.service.UpdateReview(c.Request.Context(), uint(id), user.ID, input); err != nil {
+	review, err := h.service.UpdateReview(c.Request.Context(), uint(id), user.ID, input)
+	if err != nil {
 		slog.Error(messages.LogReviewUpdateFailed, "id", id, "user_id", user.ID, "error", err)
 		errMsg := messages.ErrReviewUpdateFail
+		var appErr *appErrors.AppError
 		if errors.Is(err, appErrors.ErrReviewNotFound) {
 			errMsg = messages.ErrReviewNotFound
 		} else if errors.Is(err, appErrors.ErrReviewNotOwner) {
 			errMsg = messages.ErrReviewNotOwner
+		} else if errors.Is(err, appErrors.ErrReviewTourNotBooked) {
+			errMsg = messages.ErrReviewTourNotBooked
+		} else if errors.As(err, &appErr) {
+			errMsg = getReviewValidationMsg(input, appErr)
 		}
 		middleware.SetFlashError(c, errMsg)
 		c.Redirect(http.StatusFound, fmt.Sprintf("%s/%d/edit", constants.RouteMyReviews, id))
 		return
 	}
 
-	slog.Info("review updated", "id", id, "user_id", user.ID)
-	middleware.SetFlashSuccess(c, messages.MsgReviewUpdated)
+	slog.Info("review updated", "id", id, "user_id", user.ID, "status", review.Status, "moderation_score", review.ModerationScore)
+	setReviewModerationFlash(c, review, messages.MsgReviewUpdated, messages.MsgReviewPending)
 	c.Redirect(http.StatusFound, constants.RouteMyReviews)
 }
 
@@ -319,14 +377,15 @@ func (h *ReviewHandler) AddComment(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.AddComment(c.Request.Context(), user.ID, uint(reviewID), nil, content); err != nil {
+	comment, err := h.service.AddComment(c.Request.Context(), user.ID, uint(reviewID), nil, content)
+	if err != nil {
 		slog.Error(messages.LogReviewCommentFailed, "review_id", reviewID, "user_id", user.ID, "error", err)
 		middleware.SetFlashError(c, messages.ErrCommentFail)
 		c.Redirect(http.StatusFound, fmt.Sprintf("%s/%d", constants.RoutePublicReviews, reviewID))
 		return
 	}
 
-	middleware.SetFlashSuccess(c, messages.MsgCommentAdded)
+	setCommentModerationFlash(c, comment, messages.MsgCommentAdded)
 	c.Redirect(http.StatusFound, fmt.Sprintf("%s/%d", constants.RoutePublicReviews, reviewID))
 }
 
@@ -352,17 +411,56 @@ func (h *ReviewHandler) ReplyComment(c *gin.Context) {
 	}
 
 	parentID := uint(commentID)
-	if err := h.service.AddComment(c.Request.Context(), user.ID, uint(reviewID), &parentID, content); err != nil {
+	comment, err := h.service.AddComment(c.Request.Context(), user.ID, uint(reviewID), &parentID, content)
+	if err != nil {
 		slog.Error(messages.LogReviewCommentFailed, "comment_id", commentID, "user_id", user.ID, "error", err)
 		middleware.SetFlashError(c, messages.ErrCommentFail)
 		c.Redirect(http.StatusFound, fmt.Sprintf("%s/%d", constants.RoutePublicReviews, reviewID))
 		return
 	}
 
-	middleware.SetFlashSuccess(c, messages.MsgCommentAdded)
+	setCommentModerationFlash(c, comment, messages.MsgCommentAdded)
 	c.Redirect(http.StatusFound, fmt.Sprintf("%s/%d", constants.RoutePublicReviews, reviewID))
 }
 
+func (h *ReviewHandler) EditComment(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.setFlashAndRedirect(c, messages.ErrCommentNotFound, constants.RoutePublicReviews)
+		return
+	}
+
+	redirectURL := constants.RoutePublicReviews
+	if reviewID, err := strconv.ParseUint(c.PostForm("review_id"), 10, 64); err == nil && reviewID > 0 {
+		redirectURL = fmt.Sprintf("%s/%d", constants.RoutePublicReviews, reviewID)
+	}
+
+	content := c.PostForm("content")
+	if strings.TrimSpace(content) == "" {
+		h.setFlashAndRedirect(c, messages.ErrCommentContentReq, redirectURL)
+		return
+	}
+
+	comment, err := h.service.EditComment(c.Request.Context(), uint(commentID), user.ID, content)
+	if err != nil {
+		errMsg := messages.ErrCommentEditFail
+		switch {
+		case errors.Is(err, appErrors.ErrCommentNotFound):
+			errMsg = messages.ErrCommentNotFound
+		case errors.Is(err, appErrors.ErrCommentNotOwner):
+			errMsg = messages.ErrCommentEditNotOwner
+		default:
+			slog.Error(messages.LogReviewEditCommentFailed, "comment_id", commentID, "user_id", user.ID, "error", err)
+		}
+		h.setFlashAndRedirect(c, errMsg, redirectURL)
+		return
+	}
+
+	setCommentModerationFlash(c, comment, messages.MsgCommentEdited)
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
 func (h *ReviewHandler) DeleteComment(c *gin.Context) {
 	user := middleware.GetCurrentUser(c)
 	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -411,6 +509,30 @@ func (h *ReviewHandler) renderError(c *gin.Context, status int, message string)
 	})
 }
 
+// setReviewModerationFlash tells the author what moderation decided about
+// the review they just saved.
+func setReviewModerationFlash(c *gin.Context, review *models.Review, approvedMsg, pendingMsg string) {
+	switch review.Status {
+	case constants.ReviewStatusApproved:
+		middleware.SetFlashSuccess(c, approvedMsg)
+	case constants.ReviewStatusRejected:
+		middleware.SetFlashError(c, fmt.Sprintf(messages.ErrReviewAutoRejected, review.RejectReason))
+	default:
+		middleware.SetFlashSuccess(c, pendingMsg)
+	}
+}
+
+func setCommentModerationFlash(c *gin.Context, comment *models.Comment, approvedMsg string) {
+	switch comment.Status {
+	case constants.CommentStatusApproved:
+		middleware.SetFlashSuccess(c, approvedMsg)
+	case constants.CommentStatusRejected:
+		middleware.SetFlashError(c, fmt.Sprintf(messages.ErrCommentAutoRejected, comment.RejectReason))
+	default:
+		middleware.SetFlashSuccess(c, messages.MsgCommentPending)
+	}
+}
+
 func (h *ReviewHandler) setFlashAndRedirect(c *gin.Context, msg, url string) {
 	middleware.SetFlashError(c, msg)
 	c.Redirect(http.StatusFound, url)
@@ -431,7 +553,20 @@ func parseImageURLs(raw string) []string {
 	return urls
 }
 
-func getReviewValidationMsg(input services.ReviewCreateInput) string {
+// formFiles returns the files uploaded under field, or nil when the request
+// is not multipart.
+func formFiles(c *gin.Context, field string) []*multipart.FileHeader {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil
+	}
+	return form.File[field]
+}
+
+// getReviewValidationMsg maps a rejected input to a user-facing message.
+// Errors not caused by the text fields, such as rejected uploads, already
+// carry their own message.
+func getReviewValidationMsg(input services.ReviewCreateInput, appErr *appErrors.AppError) string {
 	if strings.TrimSpace(input.Title) == "" {
 		return messages.ErrReviewTitleReq
 	}
@@ -446,7 +581,29 @@ func getReviewValidationMsg(input services.ReviewCreateInput) string {
 	default:
 		return messages.ErrReviewInvalidType
 	}
-	return messages.ErrReviewCreateFail
+	return appErr.Message
+}
+
+// reviewTrip reads the optional tour and booking a review is linked to.
+func reviewTrip(c *gin.Context) (tourID, bookingID uint) {
+	if id, err := strconv.ParseUint(c.PostForm("tour_id"), 10, 64); err == nil {
+		tourID = uint(id)
+	}
+	if id, err := strconv.ParseUint(c.PostForm("booking_id"), 10, 64); err == nil {
+		bookingID = uint(id)
+	}
+	return tourID, bookingID
+}
+
+// reviewableBookings loads the trips offered in the review form, plus keepID
+// when editing a linked review; on error the form is still shown, just
+// without them.
+func (h *ReviewHandler) reviewableBookings(c *gin.Context, userID, keepID uint) []models.Booking {
+	bookings, err := h.service.ReviewableBookings(c.Request.Context(), userID, keepID)
+	if err != nil {
+		slog.Error(messages.LogReviewTripsFailed, "user_id", userID, "error", err)
+	}
+	return bookings
 }
 
 func buildPageWindow(page, totalPages int) []int {
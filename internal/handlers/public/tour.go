@@ -1,32 +1,44 @@
 package public
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 	"sun-booking-tours/internal/services"
+	"sun-booking-tours/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxRadiusKm caps "tours near me" searches.
+const maxRadiusKm = 500
+
+// travellerStoryLimit is how many linked reviews the tour page shows.
+const travellerStoryLimit = 3
+
 type PublicTourHandler struct {
 	service       *services.TourService
 	catService    *services.CategoryService
 	ratingService *services.RatingService
+	reviewService *services.ReviewService
+	recService    *services.RecommendationService
+	wishlist      *services.WishlistService
 }
 
-func NewPublicTourHandler(service *services.TourService, catService *services.CategoryService, ratingService *services.RatingService) *PublicTourHandler {
-	return &PublicTourHandler{service: service, catService: catService, ratingService: ratingService}
+func NewPublicTourHandler(service *services.TourService, catService *services.CategoryService, ratingService *services.RatingService, reviewService *services.ReviewService, recService *services.RecommendationService, wishlist *services.WishlistService) *PublicTourHandler {
+	return &PublicTourHandler{service: service, catService: catService, ratingService: ratingService, reviewService: reviewService, recService: recService, wishlist: wishlist}
 }
 
 func (h *PublicTourHandler) List(c *gin.Context) {
@@ -35,30 +47,41 @@ func (h *PublicTourHandler) List(c *gin.Context) {
 		page = 1
 	}
 
-	minPrice, _ := strconv.ParseFloat(c.Query("min_price"), 64)
-	maxPrice, _ := strconv.ParseFloat(c.Query("max_price"), 64)
-	minDuration, _ := strconv.Atoi(c.Query("min_duration"))
-	maxDuration, _ := strconv.Atoi(c.Query("max_duration"))
+	filter := parseTourFilter(c)
+	filter.Page = page
+	filter.Limit = constants.DefaultPageLimit
+	filter.IncludeSchedules = true
+	filter.IncludeFacets = true
+	filter.Locale = middleware.GetLocale(c)
+	if user := middleware.GetCurrentUser(c); user != nil {
+		filter.SavedBy = user.ID
+	}
 
-	sortBy, sortOrder := parseSortParam(c.Query("sort"))
+	var category *models.Category
+	var breadcrumbs []models.Category
+	if len(filter.CategorySlugs) == 1 {
+		if cat, err := h.catService.GetCategoryBySlug(c.Request.Context(), filter.CategorySlugs[0]); err == nil {
+			liveSlug := isLiveCategorySlug(cat, filter.CategorySlugs[0])
+			cat.Localize(filter.Locale)
+			if cat.Slug != filter.CategorySlugs[0] {
+				query := c.Request.URL.Query()
+				query.Set("category", cat.Slug)
+				status := http.StatusMovedPermanently
+				if liveSlug {
+					status = middleware.RedirectStatus(c)
+				}
+				c.Redirect(status, middleware.LocalePath(c, constants.RoutePublicTours)+"?"+query.Encode())
+				return
+			}
+			category = cat
+			if breadcrumbs, err = h.catService.Breadcrumbs(c.Request.Context(), cat); err != nil {
+				slog.Error(messages.LogPublicCategoryBreadcrumbsFailed, "category_id", cat.ID, "error", err)
+			}
+			models.LocalizeCategories(breadcrumbs, filter.Locale)
+		}
+	}
 
-	filter := repository.TourFilter{
-		Status:           constants.TourStatusActive,
-		CategorySlug:     c.Query("category"),
-		Search:           c.Query("q"),
-		Location:         c.Query("location"),
-		MinPrice:         minPrice,
-		MaxPrice:         maxPrice,
-		MinDuration:      minDuration,
-		MaxDuration:      maxDuration,
-		SortBy:           sortBy,
-		SortOrder:        sortOrder,
-		Page:             page,
-		Limit:            constants.DefaultPageLimit,
-		IncludeSchedules: true,
-	}
-
-	tours, total, err := h.service.ListTours(c.Request.Context(), filter)
+	tours, total, facets, err := h.service.ListTours(c.Request.Context(), filter)
 	if err != nil {
 		slog.Error(messages.LogPublicTourListFailed, "error", err)
 		c.HTML(http.StatusInternalServerError, "public/pages/error.html", gin.H{
@@ -94,21 +117,43 @@ func (h *PublicTourHandler) List(c *gin.Context) {
 	}
 
 	categories, _ := h.catService.AllFlatCategories(c.Request.Context())
+	models.LocalizeCategories(categories, filter.Locale)
+
+	for _, opt := range facets.All() {
+		opt.URL = facetURL(filter, opt)
+	}
+
+	var distances map[uint]float64
+	if filter.RadiusKm > 0 {
+		distances = make(map[uint]float64, len(tours))
+		for _, t := range tours {
+			if t.HasCoordinates() {
+				distances[t.ID] = utils.HaversineKm(filter.NearLat, filter.NearLng, *t.Latitude, *t.Longitude)
+			}
+		}
+	}
 
 	flashSuccess, flashError := middleware.GetFlash(c)
 
 	c.HTML(http.StatusOK, "public/pages/tours_list.html", gin.H{
-		"title":          messages.TitlePublicTours,
-		"user":           middleware.GetCurrentUser(c),
-		"csrf_token":     middleware.CSRFToken(c),
-		"nav_categories": middleware.GetNavCategories(c),
-		"flash_success":  flashSuccess,
-		"flash_error":    flashError,
-		"tours":          tours,
-		"total":          total,
-		"filter":         filter,
-		"categories":     categories,
-		"base_url":       buildToursBaseURL(filter),
+		"title":               messages.TitlePublicTours,
+		"user":                middleware.GetCurrentUser(c),
+		"csrf_token":          middleware.CSRFToken(c),
+		"nav_categories":      middleware.GetNavCategories(c),
+		"flash_success":       flashSuccess,
+		"flash_error":         flashError,
+		"tours":               tours,
+		"total":               total,
+		"filter":              filter,
+		"categories":          categories,
+		"category":            category,
+		"selected_categories": selectedCategories(filter.CategorySlugs),
+		"breadcrumbs":         breadcrumbs,
+		"facets":              facets,
+		"distances":           distances,
+		"base_url":            buildToursBaseURL(filter),
+		"current_url":         c.Request.URL.RequestURI(),
+		"locale":              filter.Locale,
 		"pagination": map[string]any{
 			"Page":       page,
 			"TotalPages": totalPages,
@@ -145,11 +190,17 @@ func (h *PublicTourHandler) Detail(c *gin.Context) {
 		return
 	}
 
-	var imageURLs []string
-	if len(tour.Images) > 0 {
-		_ = json.Unmarshal(tour.Images, &imageURLs)
+	locale := middleware.GetLocale(c)
+	alternates := tourAlternates(tour)
+	liveSlug := isLiveTourSlug(tour, slug)
+	tour.Localize(locale)
+
+	if redirectToCanonicalSlug(c, slug, tour.Slug, middleware.LocalePath(c, fmt.Sprintf("/tours/%s", tour.Slug)), liveSlug) {
+		return
 	}
 
+	images := models.ParseImageAssets(tour.Images)
+
 	user := middleware.GetCurrentUser(c)
 	var userID uint
 	if user != nil {
@@ -161,10 +212,39 @@ func (h *PublicTourHandler) Detail(c *gin.Context) {
 		slog.Error("failed to get user rating", "err", err, "tour_id", tour.ID, "user_id", userID)
 	}
 
+	canRate, err := h.ratingService.CanRate(c.Request.Context(), userID, tour.ID)
+	if err != nil {
+		slog.Error("failed to check rating eligibility", "err", err, "tour_id", tour.ID, "user_id", userID)
+	}
+
 	ratings, _, err := h.ratingService.ListByTour(c.Request.Context(), tour.ID, 1, 20)
 	if err != nil {
 		slog.Error("failed to list ratings by tour", "err", err, "tour_id", tour.ID)
 	}
+	ratingSummary, err := h.ratingService.Summary(c.Request.Context(), tour.ID)
+	if err != nil {
+		slog.Error(messages.LogRatingSummaryFailed, "tour_id", tour.ID, "error", err)
+	}
+
+	stories, storyCount, err := h.reviewService.ListPublicReviews(c.Request.Context(), repository.ReviewFilter{
+		TourID: tour.ID,
+		Sort:   "most_liked",
+		Limit:  travellerStoryLimit,
+	})
+	if err != nil {
+		slog.Error(messages.LogPublicTourStoriesFailed, "tour_id", tour.ID, "error", err)
+	}
+
+	related, err := h.recService.RelatedTours(c.Request.Context(), tour.ID)
+	if err != nil {
+		slog.Error(messages.LogRecommendationLoadFailed, "tour_id", tour.ID, "error", err)
+	}
+	alsoBooked, err := h.recService.AlsoBooked(c.Request.Context(), tour.ID)
+	if err != nil {
+		slog.Error(messages.LogRecommendationLoadFailed, "tour_id", tour.ID, "error", err)
+	}
+	models.LocalizeTours(related, locale)
+	models.LocalizeTours(alsoBooked, locale)
 
 	flashSuccess, flashError := middleware.GetFlash(c)
 
@@ -177,14 +257,141 @@ func (h *PublicTourHandler) Detail(c *gin.Context) {
 		"flash_error":    flashError,
 		"tour":           tour,
 		"rating_count":   ratingCount,
-		"image_urls":     imageURLs,
+		"images":         images,
 		"user_rating":    userRating,
+		"can_rate":       canRate,
+		"rating_aspects": services.RatingAspectFields(userRating),
 		"ratings":        ratings,
+		"rating_summary": ratingSummary,
+		"stories":        stories,
+		"story_count":    storyCount,
+		"saved":          h.wishlist.IsSaved(c.Request.Context(), userID, tour.ID),
+		"related_tours":  related,
+		"also_booked":    alsoBooked,
+		"locale":         locale,
+		"alternates":     alternates,
 	})
 }
 
-func parseSortParam(sort string) (sortBy, sortOrder string) {
+// localeLink is a page's address in one locale.
+type localeLink struct {
+	Locale string
+	URL    string
+}
+
+// tourAlternates lists the tour page in every supported locale, for
+// hreflang links. Call it before localizing the tour.
+func tourAlternates(tour *models.Tour) []localeLink {
+	links := make([]localeLink, 0, len(constants.SupportedLocales))
+	for _, locale := range constants.SupportedLocales {
+		slug := tour.Slug
+		if tr := tour.Translation(locale); tr != nil {
+			slug = tr.Slug
+		}
+		links = append(links, localeLink{Locale: locale, URL: utils.LocalizedPath(locale, constants.RoutePublicTours+"/"+slug)})
+	}
+	return links
+}
+
+// GeoJSON serves the located tours matching the listing filters as an RFC
+// 7946 feature collection for the map view. An optional
+// bbox=minLng,minLat,maxLng,maxLat limits results to the visible map area.
+func (h *PublicTourHandler) GeoJSON(c *gin.Context) {
+	filter := parseTourFilter(c)
+
+	if raw := c.Query("bbox"); raw != "" {
+		bbox, err := services.ParseBBox(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErrors.ErrMsgTourMapBBox})
+			return
+		}
+		filter.BBox = bbox
+	}
+
+	pins, err := h.service.TourPins(c.Request.Context(), filter)
+	if err != nil {
+		slog.Error(messages.LogPublicTourGeoJSONFailed, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": messages.ErrInternalServer})
+		return
+	}
+
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, pins)
+}
+
+// parseTourFilter reads the public listing filters shared by the HTML list
+// and the GeoJSON feed. Malformed numbers are ignored, as is a radius
+// without a valid centre point.
+func parseTourFilter(c *gin.Context) repository.TourFilter {
+	minPrice, _ := strconv.ParseFloat(c.Query("min_price"), 64)
+	maxPrice, _ := strconv.ParseFloat(c.Query("max_price"), 64)
+	minDuration, _ := strconv.Atoi(c.Query("min_duration"))
+	maxDuration, _ := strconv.Atoi(c.Query("max_duration"))
+	minRating, _ := strconv.ParseFloat(c.Query("min_rating"), 64)
+
+	search := c.Query("q")
+	sortBy, sortOrder := parseSortParam(c.Query("sort"), search != "")
+
+	filter := repository.TourFilter{
+		Status:        constants.TourStatusActive,
+		CategorySlugs: categorySlugs(c.QueryArray("category")),
+		Search:        search,
+		Location:      c.Query("location"),
+		MinPrice:      minPrice,
+		MaxPrice:      maxPrice,
+		MinDuration:   minDuration,
+		MaxDuration:   maxDuration,
+		MinRating:     minRating,
+		SortBy:        sortBy,
+		SortOrder:     sortOrder,
+	}
+
+	if c.Query("match") == repository.CategoryMatchAll {
+		filter.CategoryMatch = repository.CategoryMatchAll
+	}
+
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	radius, _ := strconv.ParseFloat(c.Query("radius_km"), 64)
+	if errLat == nil && errLng == nil && radius > 0 && utils.ValidLatLng(lat, lng) {
+		filter.NearLat, filter.NearLng = lat, lng
+		filter.RadiusKm = min(radius, maxRadiusKm)
+	} else if filter.SortBy == repository.SortDistance {
+		filter.SortBy, filter.SortOrder = "created_at", "desc"
+	}
+	return filter
+}
+
+// selectedCategories indexes slugs for the category select.
+func selectedCategories(slugs []string) map[string]bool {
+	selected := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		selected[slug] = true
+	}
+	return selected
+}
+
+// categorySlugs drops blank and repeated values of the category parameter.
+func categorySlugs(values []string) []string {
+	var slugs []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !slices.Contains(slugs, v) {
+			slugs = append(slugs, v)
+		}
+	}
+	return slugs
+}
+
+// parseSortParam maps the public sort option to a column and direction.
+// Without an explicit choice a keyword search is ordered by relevance.
+func parseSortParam(sort string, searching bool) (sortBy, sortOrder string) {
 	switch sort {
+	case repository.SortRelevance:
+		return repository.SortRelevance, "desc"
+	case "newest":
+		return "created_at", "desc"
+	case repository.SortDistance:
+		return repository.SortDistance, "asc"
 	case "price_asc":
 		return "price", "asc"
 	case "price_desc":
@@ -192,17 +399,106 @@ func parseSortParam(sort string) (sortBy, sortOrder string) {
 	case "rating":
 		return "avg_rating", "desc"
 	default:
+		if searching {
+			return repository.SortRelevance, "desc"
+		}
 		return "created_at", "desc"
 	}
 }
 
+// redirectToCanonicalSlug redirects when the request reached the resource
+// through a former or other-locale slug. A former slug is gone for good and
+// redirects permanently; localeSlug marks a live slug of another locale,
+// where the status depends on how the locale was chosen (see
+// middleware.RedirectStatus). The query string is kept.
+func redirectToCanonicalSlug(c *gin.Context, requested, canonical, canonicalPath string, localeSlug bool) bool {
+	if requested == canonical {
+		return false
+	}
+	target := canonicalPath
+	if c.Request.URL.RawQuery != "" {
+		target += "?" + c.Request.URL.RawQuery
+	}
+	status := http.StatusMovedPermanently
+	if localeSlug {
+		status = middleware.RedirectStatus(c)
+	}
+	c.Redirect(status, target)
+	return true
+}
+
+// isLiveTourSlug reports whether slug is the tour's current slug in any
+// locale rather than a former one. Call it before Localize.
+func isLiveTourSlug(tour *models.Tour, slug string) bool {
+	if tour.Slug == slug {
+		return true
+	}
+	for _, tr := range tour.Translations {
+		if tr.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// isLiveCategorySlug is isLiveTourSlug for categories.
+func isLiveCategorySlug(cat *models.Category, slug string) bool {
+	if cat.Slug == slug {
+		return true
+	}
+	for _, tr := range cat.Translations {
+		if tr.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
 func buildToursBaseURL(filter repository.TourFilter) string {
+	encoded := toursQuery(filter).Encode()
+	if encoded == "" {
+		return "/tours?"
+	}
+	return "/tours?" + encoded + "&"
+}
+
+// facetURL links a filter chip: it applies the chip's parameters to the
+// current query, or removes them when the chip is already active. Paging
+// restarts from the first page.
+func facetURL(filter repository.TourFilter, opt *services.FacetOption) string {
+	v := toursQuery(filter)
+	for key, val := range opt.Params {
+		switch {
+		case opt.Toggle && opt.Active:
+			v[key] = slices.DeleteFunc(slices.Clone(v[key]), func(s string) bool { return s == val })
+		case opt.Toggle:
+			v.Add(key, val)
+		case opt.Active || val == "":
+			v.Del(key)
+		default:
+			v.Set(key, val)
+		}
+	}
+	if len(v["category"]) < 2 {
+		v.Del("match")
+	}
+	if encoded := v.Encode(); encoded != "" {
+		return constants.RoutePublicTours + "?" + encoded
+	}
+	return constants.RoutePublicTours
+}
+
+// toursQuery encodes filter back into /tours query parameters.
+func toursQuery(filter repository.TourFilter) url.Values {
 	v := url.Values{}
 	if filter.Search != "" {
 		v.Set("q", filter.Search)
 	}
-	if filter.CategorySlug != "" {
-		v.Set("category", filter.CategorySlug)
+	for _, slug := range filter.CategorySlugs {
+		v.Add("category", slug)
+	}
+	if len(filter.CategorySlugs) > 1 && filter.CategoryMatch == repository.CategoryMatchAll {
+		v.Set("match", repository.CategoryMatchAll)
 	}
 	if filter.MinPrice > 0 {
 		v.Set("min_price", fmt.Sprintf("%.0f", filter.MinPrice))
@@ -219,7 +515,18 @@ func buildToursBaseURL(filter repository.TourFilter) string {
 	if filter.MaxDuration > 0 {
 		v.Set("max_duration", strconv.Itoa(filter.MaxDuration))
 	}
-	if filter.SortBy != "" && filter.SortBy != "created_at" {
+	if filter.MinRating > 0 {
+		v.Set("min_rating", strconv.FormatFloat(filter.MinRating, 'f', -1, 64))
+	}
+	if filter.RadiusKm > 0 {
+		v.Set("lat", strconv.FormatFloat(filter.NearLat, 'f', -1, 64))
+		v.Set("lng", strconv.FormatFloat(filter.NearLng, 'f', -1, 64))
+		v.Set("radius_km", strconv.FormatFloat(filter.RadiusKm, 'f', -1, 64))
+	}
+	if filter.SortBy == "created_at" && filter.Search != "" {
+		v.Set("sort", "newest")
+	}
+	if filter.SortBy != "" && filter.SortBy != "created_at" && filter.SortBy != repository.SortRelevance {
 		switch filter.SortBy + "_" + filter.SortOrder {
 		case "price_asc":
 			v.Set("sort", "price_asc")
@@ -227,11 +534,9 @@ func buildToursBaseURL(filter repository.TourFilter) string {
 			v.Set("sort", "price_desc")
 		case "avg_rating_desc":
 			v.Set("sort", "rating")
+		case "distance_asc":
+			v.Set("sort", repository.SortDistance)
 		}
 	}
-	encoded := v.Encode()
-	if encoded == "" {
-		return "/tours?"
-	}
-	return "/tours?" + encoded + "&"
+	return v
 }
@@ -0,0 +1,57 @@
+package public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectToCanonicalSlug_Status(t *testing.T) {
+	tour := &models.Tour{
+		Slug:         "vinh-ha-long",
+		Translations: []models.TourTranslation{{Locale: "en", Slug: "ha-long-bay"}},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantURL  string
+	}{
+		{"former slug without prefix", "/tours/ha-long-cu", http.StatusMovedPermanently, "/tours/vinh-ha-long"},
+		{"former slug with prefix", "/en/tours/ha-long-cu", http.StatusMovedPermanently, "/en/tours/ha-long-bay"},
+		{"other-locale slug with prefix", "/en/tours/vinh-ha-long", http.StatusMovedPermanently, "/en/tours/ha-long-bay"},
+		{"other-locale slug without prefix", "/tours/ha-long-bay", http.StatusFound, "/tours/vinh-ha-long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testutil.SetupTestRouterNoCSRF()
+			r.Use(middleware.Locale())
+			r.GET("/tours/:slug", func(c *gin.Context) {
+				slug := c.Param("slug")
+				localized := *tour
+				liveSlug := isLiveTourSlug(&localized, slug)
+				localized.Localize(middleware.GetLocale(c))
+				if !redirectToCanonicalSlug(c, slug, localized.Slug, middleware.LocalePath(c, "/tours/"+localized.Slug), liveSlug) {
+					c.Status(http.StatusOK)
+				}
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Language", "vi")
+			middleware.StripLocalePrefix(r).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantURL, w.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package public
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/middleware"
+	"sun-booking-tours/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistHandler struct {
+	service     *services.WishlistService
+	tourService *services.TourService
+}
+
+func NewWishlistHandler(service *services.WishlistService, tourService *services.TourService) *WishlistHandler {
+	return &WishlistHandler{service: service, tourService: tourService}
+}
+
+// Toggle saves or unsaves a tour, then returns to the page named by the
+// "next" form field (the tour page when missing or not a local path).
+func (h *WishlistHandler) Toggle(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	slug := c.Param("slug")
+	redirectURL := localRedirect(c.PostForm("next"), constants.RoutePublicTours+"/"+slug)
+
+	tour, _, err := h.tourService.GetPublicTourBySlug(c.Request.Context(), slug)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrTourNotFound) {
+			middleware.SetFlashError(c, messages.ErrPublicTourNotFound)
+		} else {
+			slog.Error(messages.LogWishlistToggleFailed, "slug", slug, "user_id", user.ID, "error", err)
+			middleware.SetFlashError(c, messages.ErrWishlistFail)
+		}
+		c.Redirect(http.StatusFound, constants.RoutePublicTours)
+		return
+	}
+
+	saved, err := h.service.Toggle(c.Request.Context(), user.ID, tour)
+	if err != nil {
+		slog.Error(messages.LogWishlistToggleFailed, "tour_id", tour.ID, "user_id", user.ID, "error", err)
+		middleware.SetFlashError(c, messages.ErrWishlistFail)
+		c.Redirect(http.StatusFound, redirectURL)
+		return
+	}
+
+	if saved {
+		middleware.SetFlashSuccess(c, messages.MsgWishlistAdded)
+	} else {
+		middleware.SetFlashSuccess(c, messages.MsgWishlistRemoved)
+	}
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+func (h *WishlistHandler) MyList(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	items, total, err := h.service.ListMine(c.Request.Context(), user.ID, page, constants.DefaultPageLimit)
+	if err != nil {
+		slog.Error(messages.LogWishlistListFailed, "user_id", user.ID, "error", err)
+		c.HTML(http.StatusInternalServerError, "public/pages/error.html", gin.H{
+			"title":          messages.ErrInternalServer,
+			"message":        messages.ErrInternalServer,
+			"status":         http.StatusInternalServerError,
+			"user":           user,
+			"nav_categories": middleware.GetNavCategories(c),
+		})
+		return
+	}
+
+	locale := middleware.GetLocale(c)
+	for i := range items {
+		items[i].Tour.Localize(locale)
+	}
+
+	totalPages := max(1, (int(total)+constants.DefaultPageLimit-1)/constants.DefaultPageLimit)
+	flashSuccess, flashError := middleware.GetFlash(c)
+
+	c.HTML(http.StatusOK, "public/pages/my_wishlist.html", gin.H{
+		"title":          messages.TitleMyWishlist,
+		"user":           user,
+		"csrf_token":     middleware.CSRFToken(c),
+		"nav_categories": middleware.GetNavCategories(c),
+		"flash_success":  flashSuccess,
+		"flash_error":    flashError,
+		"items":          items,
+		"total":          total,
+		"pagination": map[string]any{
+			"Page":       page,
+			"TotalPages": totalPages,
+			"PrevPage":   max(1, page-1),
+			"NextPage":   min(totalPages, page+1),
+			"Pages":      buildPageWindow(page, totalPages),
+		},
+	})
+}
+
+// localRedirect returns next when it is a path on this site, otherwise
+// fallback. It rejects absolute and protocol-relative URLs.
+func localRedirect(next, fallback string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return fallback
+	}
+	return next
+}
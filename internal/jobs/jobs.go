@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/services"
+
+	"gorm.io/gorm"
+)
+
+// Start launches the background jobs in their own goroutines. They stop
+// when ctx is cancelled.
+func Start(ctx context.Context, db *gorm.DB, cfg *config.Config) {
+	scheduleRepo := repository.NewScheduleRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	emailService := services.NewEmailService(cfg)
+	departureService := services.NewDepartureService(db, scheduleRepo, bookingRepo, emailService, cfg.BaseURL, cfg.DepartureCutoff)
+
+	slog.Info(messages.LogDepartureJobStarted, "cutoff", cfg.DepartureCutoff, "interval", cfg.DepartureCheckInterval)
+	go runEvery(ctx, cfg.DepartureCheckInterval, func(ctx context.Context) {
+		cancelled, err := departureService.RunCutoff(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogDepartureCutoffFailed, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, messages.LogDepartureJobRun, "cancelled", cancelled)
+	})
+
+	recommendationService := services.NewRecommendationService(repository.NewRecommendationRepository(db))
+
+	slog.Info(messages.LogRecommendationJobStarted, "interval", cfg.RecommendationInterval)
+	go runEvery(ctx, cfg.RecommendationInterval, func(ctx context.Context) {
+		tourRows, userRows, err := recommendationService.Recompute(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogRecommendationRecomputeFailed, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, messages.LogRecommendationJobRun, "tour_rows", tourRows, "user_rows", userRows)
+	})
+
+	wishlistService := services.NewWishlistService(repository.NewWishlistRepository(db), emailService, cfg.BaseURL)
+
+	slog.Info(messages.LogWishlistJobStarted, "interval", cfg.WishlistNotifyInterval)
+	go runEvery(ctx, cfg.WishlistNotifyInterval, func(ctx context.Context) {
+		notified, err := wishlistService.NotifyChanges(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogWishlistJobFailed, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, messages.LogWishlistJobRun, "notified", notified)
+	})
+
+	trashService := services.NewTrashService(db, repository.NewTrashRepository(db), repository.NewTourRepository(db),
+		repository.NewCategoryRepository(db), repository.NewSlugHistoryRepository(db), cfg.TrashRetention)
+
+	slog.Info(messages.LogTrashJobStarted, "retention", cfg.TrashRetention, "interval", cfg.TrashPurgeInterval)
+	go runEvery(ctx, cfg.TrashPurgeInterval, func(ctx context.Context) {
+		purged, err := trashService.PurgeExpired(ctx, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogTrashJobFailed, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, messages.LogTrashJobRun, "purged", purged)
+	})
+}
+
+// runEvery calls fn immediately and then once per interval until ctx is done.
+func runEvery(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	fn(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn(ctx)
+		}
+	}
+}
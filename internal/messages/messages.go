@@ -151,6 +151,8 @@ const (
 	ErrCtxFindAllUsers              = "find all users"
 	ErrCtxFindUserByIDWithRelations = "find user by id with relations"
 	ErrCtxUpdateUserStatus          = "update user status"
+	ErrCtxUpdateUserRole            = "update user role"
+	ErrCtxFindUsersByRole           = "find users by role"
 	ErrCtxFindUserByVerifyToken     = "find user by verify token"
 )
 
@@ -159,40 +161,69 @@ const (
 	TitleAdminCategories     = "Quản lý danh mục"
 	TitleAdminCategoryCreate = "Thêm danh mục"
 	TitleAdminCategoryEdit   = "Chỉnh sửa danh mục"
-
-	MsgAdminCategoryCreated = "Thêm danh mục thành công."
-	MsgAdminCategoryUpdated = "Cập nhật danh mục thành công."
-	MsgAdminCategoryDeleted = "Xóa danh mục thành công."
-
-	ErrAdminCategoryNotFound   = "Không tìm thấy danh mục."
-	ErrAdminCategoryCreateFail = "Không thể thêm danh mục."
-	ErrAdminCategoryUpdateFail = "Không thể cập nhật danh mục."
-	ErrAdminCategoryDeleteFail = "Không thể xóa danh mục."
-
-	LogAdminCategoryListFailed   = "admin: list categories failed"
-	LogAdminCategoryCreateFailed = "admin: create category failed"
-	LogAdminCategoryUpdateFailed = "admin: update category failed"
-	LogAdminCategoryDeleteFailed = "admin: delete category failed"
+	TitleAdminCategoryMove   = "Di chuyển danh mục"
+	TitleAdminCategoryMerge  = "Gộp danh mục"
+
+	MsgAdminCategoryCreated   = "Thêm danh mục thành công."
+	MsgAdminCategoryUpdated   = "Cập nhật danh mục thành công."
+	MsgAdminCategoryDeleted   = "Xóa danh mục thành công."
+	MsgAdminCategoryMoved     = "Đã di chuyển danh mục «%s» cùng các danh mục con."
+	MsgAdminCategoryReordered = "Đã cập nhật thứ tự danh mục."
+	MsgAdminCategoryMerged    = "Đã gộp «%s» vào «%s»: thêm %d tour và chuyển %d danh mục con."
+
+	ErrAdminCategoryNotFound    = "Không tìm thấy danh mục."
+	ErrAdminCategoryCreateFail  = "Không thể thêm danh mục."
+	ErrAdminCategoryUpdateFail  = "Không thể cập nhật danh mục."
+	ErrAdminCategoryDeleteFail  = "Không thể xóa danh mục."
+	ErrAdminCategoryMoveFail    = "Không thể di chuyển danh mục."
+	ErrAdminCategoryReorderFail = "Không thể sắp xếp danh mục."
+	ErrAdminCategoryMergeFail   = "Không thể gộp danh mục."
+
+	LogAdminCategoryListFailed    = "admin: list categories failed"
+	LogAdminCategoryCreateFailed  = "admin: create category failed"
+	LogAdminCategoryUpdateFailed  = "admin: update category failed"
+	LogAdminCategoryDeleteFailed  = "admin: delete category failed"
+	LogAdminCategoryMoveFailed    = "admin: move category failed"
+	LogAdminCategoryReorderFailed = "admin: reorder categories failed"
+	LogAdminCategoryMergeFailed   = "admin: merge category failed"
 )
 
 const (
 	TitleAdminTours      = "Quản lý tour"
 	TitleAdminTourCreate = "Thêm tour mới"
 	TitleAdminTourEdit   = "Chỉnh sửa tour"
+	TitleAdminTourImport = "Nhập / xuất tour"
+	TitleAdminTourClone  = "Nhân bản tour"
 
-	MsgAdminTourCreated = "Thêm tour thành công."
-	MsgAdminTourUpdated = "Cập nhật tour thành công."
-	MsgAdminTourDeleted = "Xóa tour thành công."
+	// TourCloneTitle names a duplicated tour after its source.
+	TourCloneTitle = "%s (bản sao)"
+
+	MsgAdminTourCreated     = "Thêm tour thành công."
+	MsgAdminTourUpdated     = "Cập nhật tour thành công."
+	MsgAdminTourDeleted     = "Xóa tour thành công."
+	MsgAdminTourImported    = "Đã nhập %d tour."
+	MsgAdminTourCloned      = "Đã tạo bản nháp «%s» với %d lịch khởi hành."
+	MsgAdminTourBulkAdded   = "Đã gắn danh mục cho các tour đã chọn (%d liên kết mới)."
+	MsgAdminTourBulkRemoved = "Đã gỡ danh mục khỏi các tour đã chọn (%d liên kết)."
 
 	ErrAdminTourNotFound   = "Không tìm thấy tour."
 	ErrAdminTourCreateFail = "Không thể thêm tour."
 	ErrAdminTourUpdateFail = "Không thể cập nhật tour."
 	ErrAdminTourDeleteFail = "Không thể xóa tour."
+	ErrAdminTourImportFile = "Vui lòng chọn tệp .csv hoặc .json không quá %d MB."
+	ErrAdminTourImportFail = "Không thể nhập tour."
+	ErrAdminTourExportFail = "Không thể xuất danh sách tour."
+	ErrAdminTourCloneFail  = "Không thể nhân bản tour."
+	ErrAdminTourBulkFail   = "Không thể cập nhật danh mục cho các tour đã chọn."
 
 	LogAdminTourListFailed   = "admin: list tours failed"
 	LogAdminTourCreateFailed = "admin: create tour failed"
 	LogAdminTourUpdateFailed = "admin: update tour failed"
 	LogAdminTourDeleteFailed = "admin: delete tour failed"
+	LogAdminTourImportFailed = "admin: import tours failed"
+	LogAdminTourExportFailed = "admin: export tours failed"
+	LogAdminTourCloneFailed  = "admin: clone tour failed"
+	LogAdminTourBulkFailed   = "admin: bulk update tour categories failed"
 )
 
 const (
@@ -221,8 +252,25 @@ const (
 
 	ErrPublicTourNotFound = "Không tìm thấy tour hoặc tour không còn hoạt động."
 
-	LogPublicTourListFailed   = "public: list tours failed"
-	LogPublicTourDetailFailed = "public: get tour detail failed"
+	LogPublicTourListFailed    = "public: list tours failed"
+	LogPublicTourDetailFailed  = "public: get tour detail failed"
+	LogPublicTourStoriesFailed = "public: list traveller stories failed"
+	LogPublicTourGeoJSONFailed = "public: list tour map pins failed"
+
+	LogPublicCategoryBreadcrumbsFailed = "public: load category breadcrumbs failed"
+
+	// Facet chip labels (fmt.Sprintf).
+	FacetPriceMillions   = "%g triệu"
+	FacetPriceBelow      = "Dưới %s"
+	FacetPriceFrom       = "Từ %s"
+	FacetPriceBetween    = "%s – %s"
+	FacetDurationExact   = "%d ngày"
+	FacetDurationFrom    = "Từ %d ngày"
+	FacetDurationBetween = "%d – %d ngày"
+	FacetRatingFrom      = "Từ %g★"
+
+	FacetCategoryMatchAny = "Thuộc một trong các danh mục"
+	FacetCategoryMatchAll = "Thuộc tất cả danh mục"
 )
 
 // ── Admin — Booking
@@ -263,9 +311,17 @@ const (
 	ErrRatingInvalid      = "Điểm đánh giá phải từ 1 đến 5."
 	ErrRatingTourNotFound = "Không tìm thấy tour."
 	ErrRatingFail         = "Không thể gửi đánh giá. Vui lòng thử lại."
+	ErrRatingNotTaken     = "Chỉ khách đã hoàn thành tour mới có thể đánh giá."
+
+	RatingAspectGuide         = "Hướng dẫn viên"
+	RatingAspectItinerary     = "Lịch trình"
+	RatingAspectAccommodation = "Lưu trú"
+	RatingAspectTransport     = "Di chuyển"
+	RatingAspectValue         = "Đáng đồng tiền"
 
-	LogRatingFailed     = "public: submit rating failed"
-	LogRatingListFailed = "public: list ratings failed"
+	LogRatingFailed        = "public: submit rating failed"
+	LogRatingListFailed    = "public: list ratings failed"
+	LogRatingSummaryFailed = "public: load rating summary failed"
 )
 
 // ── Public — Booking
@@ -359,6 +415,7 @@ const (
 
 	LogConfigurationLoaded = "configuration loaded"
 	LogDatabaseConnFailed  = "database connection failed"
+	LogStorageInitFailed   = "storage initialization failed"
 	LogMigrationFailed     = "migration failed"
 	LogSeedingFailed       = "seeding failed"
 	LogStartingServer      = "starting server"
@@ -385,37 +442,48 @@ const (
 
 	MsgReviewCreated  = "Tạo bài đánh giá thành công! Bài viết đang chờ duyệt."
 	MsgReviewUpdated  = "Cập nhật bài đánh giá thành công."
+	MsgReviewApproved = "Bài đánh giá của bạn đã được đăng."
+	MsgReviewPending  = "Bài đánh giá đang chờ quản trị viên kiểm duyệt."
+	MsgCommentPending = "Bình luận đang chờ quản trị viên kiểm duyệt."
 	MsgReviewDeleted  = "Xóa bài đánh giá thành công."
 	MsgReviewLiked    = "Đã thích bài đánh giá."
 	MsgReviewUnliked  = "Đã bỏ thích bài đánh giá."
 	MsgCommentAdded   = "Thêm bình luận thành công."
 	MsgCommentDeleted = "Xóa bình luận thành công."
-
-	ErrReviewNotFound    = "Không tìm thấy bài đánh giá."
-	ErrReviewNotOwner    = "Bạn không có quyền thao tác bài đánh giá này."
-	ErrReviewCreateFail  = "Không thể tạo bài đánh giá. Vui lòng thử lại."
-	ErrReviewUpdateFail  = "Không thể cập nhật bài đánh giá. Vui lòng thử lại."
-	ErrReviewDeleteFail  = "Không thể xóa bài đánh giá. Vui lòng thử lại."
-	ErrReviewTitleReq    = "Tiêu đề bài đánh giá là bắt buộc."
-	ErrReviewContentReq  = "Nội dung bài đánh giá là bắt buộc."
-	ErrReviewContentMin  = "Nội dung phải có ít nhất 10 ký tự."
-	ErrReviewInvalidType = "Loại bài đánh giá không hợp lệ."
-	ErrLikeFail          = "Không thể thực hiện thao tác thích. Vui lòng thử lại."
-	ErrCommentContentReq = "Nội dung bình luận là bắt buộc."
-	ErrCommentNotFound   = "Không tìm thấy bình luận."
-	ErrCommentNotOwner   = "Bạn không có quyền xóa bình luận này."
-	ErrCommentDeleteFail = "Không thể xóa bình luận. Vui lòng thử lại."
-	ErrCommentFail       = "Không thể thêm bình luận. Vui lòng thử lại."
-
-	LogReviewListFailed       = "public: list reviews failed"
-	LogReviewDetailFailed     = "public: get review detail failed"
-	LogReviewCreateFailed     = "public: create review failed"
-	LogReviewUpdateFailed     = "public: update review failed"
-	LogReviewDeleteFailed     = "public: delete review failed"
-	LogReviewMyListFailed     = "public: list my reviews failed"
-	LogReviewLikeFailed       = "public: toggle review like failed"
-	LogReviewCommentFailed    = "public: add comment failed"
-	LogReviewDelCommentFailed = "public: delete comment failed"
+	MsgCommentEdited  = "Cập nhật bình luận thành công."
+
+	ErrReviewNotFound      = "Không tìm thấy bài đánh giá."
+	ErrReviewNotOwner      = "Bạn không có quyền thao tác bài đánh giá này."
+	ErrReviewCreateFail    = "Không thể tạo bài đánh giá. Vui lòng thử lại."
+	ErrReviewUpdateFail    = "Không thể cập nhật bài đánh giá. Vui lòng thử lại."
+	ErrReviewDeleteFail    = "Không thể xóa bài đánh giá. Vui lòng thử lại."
+	ErrReviewTitleReq      = "Tiêu đề bài đánh giá là bắt buộc."
+	ErrReviewContentReq    = "Nội dung bài đánh giá là bắt buộc."
+	ErrReviewContentMin    = "Nội dung phải có ít nhất 10 ký tự."
+	ErrReviewInvalidType   = "Loại bài đánh giá không hợp lệ."
+	ErrReviewTourNotBooked = "Bạn chỉ có thể gắn bài viết với tour mà bạn đã đặt."
+	ErrLikeFail            = "Không thể thực hiện thao tác thích. Vui lòng thử lại."
+	ErrCommentContentReq   = "Nội dung bình luận là bắt buộc."
+	ErrCommentNotFound     = "Không tìm thấy bình luận."
+	ErrCommentNotOwner     = "Bạn không có quyền xóa bình luận này."
+	ErrCommentDeleteFail   = "Không thể xóa bình luận. Vui lòng thử lại."
+	ErrCommentEditNotOwner = "Bạn không có quyền sửa bình luận này."
+	ErrCommentEditFail     = "Không thể cập nhật bình luận. Vui lòng thử lại."
+	ErrCommentFail         = "Không thể thêm bình luận. Vui lòng thử lại."
+	ErrReviewAutoRejected  = "Bài đánh giá không được đăng. %s"
+	ErrCommentAutoRejected = "Bình luận không được đăng. %s"
+
+	LogReviewListFailed        = "public: list reviews failed"
+	LogReviewDetailFailed      = "public: get review detail failed"
+	LogReviewCreateFailed      = "public: create review failed"
+	LogReviewTripsFailed       = "public: list reviewable bookings failed"
+	LogReviewUpdateFailed      = "public: update review failed"
+	LogReviewDeleteFailed      = "public: delete review failed"
+	LogReviewMyListFailed      = "public: list my reviews failed"
+	LogReviewLikeFailed        = "public: toggle review like failed"
+	LogReviewCommentFailed     = "public: add comment failed"
+	LogReviewDelCommentFailed  = "public: delete comment failed"
+	LogReviewEditCommentFailed = "public: edit comment failed"
 )
 
 // ── Admin — User Management
@@ -424,15 +492,31 @@ const (
 	TitleAdminUserDetail = "Chi tiết người dùng"
 
 	MsgAdminUserStatusUpdated = "Cập nhật trạng thái người dùng thành công."
+	MsgAdminUserRoleUpdated   = "Cập nhật vai trò người dùng thành công."
+	MsgAdminUserRatingGranted = "Đã cho phép người dùng đánh giá tour."
+	MsgAdminUserRatingRevoked = "Đã thu hồi quyền đánh giá tour."
 
 	ErrAdminUserNotFound       = "Không tìm thấy người dùng."
 	ErrAdminUserUpdateFail     = "Không thể cập nhật trạng thái người dùng."
 	ErrAdminUserCannotBanSelf  = "Không thể thay đổi trạng thái của chính mình."
 	ErrAdminUserCannotBanAdmin = "Không thể thay đổi trạng thái của quản trị viên khác."
+	ErrAdminUserRoleUpdateFail = "Không thể cập nhật vai trò người dùng."
+	ErrAdminUserInvalidRole    = "Vai trò không hợp lệ."
+
+	ErrAdminUserCannotChangeOwnRole   = "Không thể thay đổi vai trò của chính mình."
+	ErrAdminUserCannotChangeAdminRole = "Không thể thay đổi vai trò của quản trị viên khác."
+
+	ErrAdminUserRatingGrantTour     = "Không tìm thấy tour với slug này."
+	ErrAdminUserRatingGrantExists   = "Người dùng đã được phép đánh giá tour này."
+	ErrAdminUserRatingGrantNotFound = "Không tìm thấy quyền đánh giá."
+	ErrAdminUserRatingGrantFail     = "Không thể cập nhật quyền đánh giá."
 
 	LogAdminUserListFailed   = "admin: list users failed"
 	LogAdminUserDetailFailed = "admin: get user detail failed"
 	LogAdminUserStatusFailed = "admin: update user status failed"
+	LogAdminUserRoleFailed   = "admin: update user role failed"
+
+	LogAdminUserRatingGrantFailed = "admin: update rating grant failed"
 )
 
 // ── Admin — Review
@@ -450,3 +534,175 @@ const (
 	LogAdminReviewApproveFailed = "admin: approve review failed"
 	LogAdminReviewRejectFailed  = "admin: reject review failed"
 )
+
+// ── Admin — Comment moderation
+const (
+	TitleAdminComments = "Kiểm duyệt bình luận"
+
+	MsgAdminCommentApproved = "Duyệt bình luận thành công."
+	MsgAdminCommentRejected = "Từ chối bình luận thành công."
+
+	ErrAdminCommentNotFound    = "Không tìm thấy bình luận."
+	ErrAdminCommentApproveFail = "Không thể duyệt bình luận."
+	ErrAdminCommentRejectFail  = "Không thể từ chối bình luận."
+
+	LogAdminCommentListFailed    = "admin: list comments failed"
+	LogAdminCommentApproveFailed = "admin: approve comment failed"
+	LogAdminCommentRejectFailed  = "admin: reject comment failed"
+)
+
+// ── Reports
+const (
+	ReportReasonSpam          = "Spam, quảng cáo"
+	ReportReasonAbusive       = "Xúc phạm, quấy rối"
+	ReportReasonMisleading    = "Sai sự thật, gây hiểu nhầm"
+	ReportReasonInappropriate = "Nội dung không phù hợp"
+	ReportReasonOther         = "Lý do khác"
+
+	ReportHiddenReason  = "Nội dung tạm ẩn vì bị nhiều người dùng báo cáo, đang chờ quản trị viên xem xét."
+	ReportRemovedReason = "Nội dung bị gỡ sau khi quản trị viên xem xét báo cáo (%s)."
+
+	MsgReportSent   = "Cảm ơn bạn đã báo cáo. Quản trị viên sẽ xem xét nội dung này."
+	MsgReportHidden = "Cảm ơn bạn đã báo cáo. Nội dung đã được tạm ẩn để chờ xem xét."
+
+	ErrReportInvalidReason = "Vui lòng chọn lý do báo cáo."
+	ErrReportOwnContent    = "Bạn không thể báo cáo nội dung của chính mình."
+	ErrAlreadyReported     = "Bạn đã báo cáo nội dung này rồi."
+	ErrReportFail          = "Không thể gửi báo cáo. Vui lòng thử lại."
+
+	LogReportFailed              = "public: report content failed"
+	LogContentWarningEmailFailed = "send content warning email failed"
+)
+
+// ── Admin — Reports
+const (
+	TitleAdminReports = "Báo cáo vi phạm"
+
+	MsgAdminReportDismissed = "Đã bỏ qua các báo cáo."
+	MsgAdminReportHidden    = "Đã ẩn nội dung bị báo cáo."
+	MsgAdminReportWarned    = "Đã gửi cảnh cáo cho tác giả, nội dung vẫn được giữ."
+	MsgAdminReportBanned    = "Đã ẩn nội dung và khóa tài khoản tác giả."
+
+	ErrAdminReportNotFound      = "Không tìm thấy nội dung bị báo cáo."
+	ErrAdminReportInvalidAction = "Thao tác không hợp lệ."
+	ErrAdminReportResolveFail   = "Không thể xử lý báo cáo."
+
+	LogAdminReportListFailed    = "admin: list reports failed"
+	LogAdminReportResolveFailed = "admin: resolve reports failed"
+)
+
+// ── Moderation
+// Rule reasons are stored on the content and shown to admins; the
+// auto-reject reason is also shown to the author.
+const (
+	ModerationReasonBannedWords = "Chứa từ ngữ không phù hợp: %s"
+	ModerationReasonLinks       = "Chứa %d liên kết"
+	ModerationReasonPhone       = "Chứa số điện thoại"
+	ModerationReasonDuplicate   = "Trùng nội dung đã đăng gần đây"
+	ModerationReasonVelocity    = "Đã đăng %d lần trong %d phút"
+	ModerationAutoRejectReason  = "Nội dung vi phạm tiêu chuẩn cộng đồng (%s)."
+)
+
+// ── Admin — Rating replies
+const (
+	TitleAdminRatings = "Phản hồi đánh giá tour"
+
+	MsgAdminRatingReplied      = "Đã đăng phản hồi và thông báo cho khách."
+	MsgAdminRatingReplyUpdated = "Đã cập nhật phản hồi."
+
+	ErrAdminRatingNotFound   = "Không tìm thấy đánh giá."
+	ErrAdminRatingReplyEmpty = "Vui lòng nhập nội dung phản hồi."
+	ErrAdminRatingReplyFail  = "Không thể lưu phản hồi."
+
+	LogAdminRatingListFailed  = "admin: list ratings failed"
+	LogAdminRatingReplyFailed = "admin: reply to rating failed"
+	LogRatingReplyEmailFailed = "send rating reply email failed"
+)
+
+// ── Admin — Trash
+const (
+	TitleAdminTrash = "Thùng rác"
+
+	MsgAdminTrashRestored     = "Đã khôi phục «%s»."
+	MsgAdminTrashRestoredSlug = "Đã khôi phục «%s» với đường dẫn mới %s vì đường dẫn cũ đang được dùng."
+	MsgAdminTrashPurged       = "Đã xóa vĩnh viễn «%s»."
+
+	ErrAdminTrashRestoreFail = "Không thể khôi phục bản ghi."
+	ErrAdminTrashPurgeFail   = "Không thể xóa vĩnh viễn bản ghi."
+
+	LogAdminTrashListFailed    = "admin: list trash failed"
+	LogAdminTrashRestoreFailed = "admin: restore trashed record failed"
+	LogAdminTrashPurgeFailed   = "admin: purge trashed record failed"
+	LogTrashJobStarted         = "trash retention job started"
+	LogTrashJobRun             = "trash retention job run"
+	LogTrashJobFailed          = "purge expired trash failed"
+)
+
+// ── Admin — Schedule Guides
+const (
+	TitleAdminScheduleGuides = "Phân công hướng dẫn viên"
+
+	MsgAdminGuideAssigned   = "Phân công hướng dẫn viên thành công."
+	MsgAdminGuideUnassigned = "Đã gỡ hướng dẫn viên khỏi lịch trình."
+
+	ErrAdminGuideAssignFail   = "Không thể phân công hướng dẫn viên."
+	ErrAdminGuideUnassignFail = "Không thể gỡ hướng dẫn viên."
+
+	LogAdminGuideListFailed     = "admin: list schedule guides failed"
+	LogAdminGuideAssignFailed   = "admin: assign guide failed"
+	LogAdminGuideUnassignFailed = "admin: unassign guide failed"
+)
+
+// ── Guide portal
+const (
+	TitleGuideSchedules      = "Lịch dẫn tour của tôi"
+	TitleGuideScheduleDetail = "Chi tiết chuyến đi"
+
+	ErrGuideScheduleNotFound = "Không tìm thấy lịch trình hoặc bạn không được phân công."
+
+	LogGuideScheduleListFailed   = "guide: list schedules failed"
+	LogGuideScheduleDetailFailed = "guide: get schedule detail failed"
+)
+
+// ── Guaranteed departures
+const (
+	LogDepartureJobStarted        = "departure cutoff job started"
+	LogDepartureJobRun            = "departure cutoff job run"
+	LogDepartureCutoffFailed      = "departure cutoff failed"
+	LogDepartureRefreshFailed     = "refresh guaranteed departure failed"
+	LogDepartureScheduleCancelled = "under-subscribed schedule cancelled"
+	LogDepartureNotifyFailed      = "notify schedule cancellation failed"
+)
+
+// ── Recommendations
+const (
+	LogRecommendationJobStarted      = "recommendation job started"
+	LogRecommendationJobRun          = "recommendation job run"
+	LogRecommendationRecomputeFailed = "recompute recommendations failed"
+	LogRecommendationLoadFailed      = "load recommendations failed"
+)
+
+// ── Wishlist
+const (
+	TitleMyWishlist = "Tour yêu thích"
+
+	MsgWishlistAdded   = "Đã lưu tour vào danh sách yêu thích."
+	MsgWishlistRemoved = "Đã xóa tour khỏi danh sách yêu thích."
+
+	ErrWishlistFail = "Không thể cập nhật danh sách yêu thích. Vui lòng thử lại."
+
+	LogWishlistToggleFailed = "public: toggle wishlist failed"
+	LogWishlistListFailed   = "public: list wishlist failed"
+	LogWishlistJobStarted   = "wishlist notification job started"
+	LogWishlistJobRun       = "wishlist notification job run"
+	LogWishlistJobFailed    = "wishlist notification failed"
+	LogWishlistNotifyFailed = "notify wishlist update failed"
+)
+
+// ── Media uploads
+const (
+	LogMediaWebPUnavailable = "webp encoder not found, skipping webp variants"
+	LogMediaWebPFailed      = "webp encode failed"
+	LogMediaCleanupFailed   = "delete stored image after failed upload failed"
+	LogMediaUsageFailed     = "check stored image usage failed"
+)
@@ -112,3 +112,16 @@ func RequireAdmin() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireGuide restricts the guide portal to logged-in users with role=guide.
+func RequireGuide() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetCurrentUser(c)
+		if user == nil || user.Role != constants.RoleGuide {
+			c.Redirect(http.StatusFound, constants.RouteLogin)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -162,6 +162,41 @@ func TestRequireAdmin_AdminUser_Passes(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+// --- RequireGuide -----------------------------------------------------
+
+func TestRequireGuide_RegularUser_Redirects(t *testing.T) {
+	r := setupTestRouter()
+	r.GET("/guide/test", func(c *gin.Context) {
+		c.Set("current_user", &models.User{ID: 1, Role: constants.RoleUser})
+		c.Next()
+	}, RequireGuide(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/guide/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, constants.RouteLogin, w.Header().Get("Location"))
+}
+
+func TestRequireGuide_GuideUser_Passes(t *testing.T) {
+	r := setupTestRouter()
+	r.GET("/guide/test", func(c *gin.Context) {
+		c.Set("current_user", &models.User{ID: 1, Role: constants.RoleGuide})
+		c.Next()
+	}, RequireGuide(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/guide/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 // --- SetSessionUserID / ClearSession ----------------------------------
 
 func TestSetSessionUserID_And_ClearSession(t *testing.T) {
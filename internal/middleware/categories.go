@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +13,7 @@ func LoadCategories(catRepo repository.CategoryRepo) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		cats, err := catRepo.FindAllParents(c.Request.Context())
 		if err == nil {
+			models.LocalizeCategories(cats, GetLocale(c))
 			c.Set(contextKeyNavCategories, cats)
 		}
 		c.Next()
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/utils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	contextKeyLocale       = "locale"
+	contextKeyLocalePrefix = "locale_prefix"
+	sessionKeyLocale       = "locale"
+)
+
+type localePrefixKey struct{}
+
+// StripLocalePrefix serves /en/tours/... as /tours/..., remembering the
+// locale on the request context. It wraps the whole router because gin
+// matches routes before any middleware runs.
+func StripLocalePrefix(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if locale, rest, ok := splitLocalePrefix(r.URL.Path); ok {
+			r = r.WithContext(context.WithValue(r.Context(), localePrefixKey{}, locale))
+			u := *r.URL
+			u.Path, u.RawPath = rest, ""
+			r.URL = &u
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func splitLocalePrefix(path string) (locale, rest string, ok bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !utils.IsSupportedLocale(segment) {
+		return "", "", false
+	}
+	return segment, "/" + rest, true
+}
+
+// Locale picks the content locale: the URL prefix, then the one the visitor
+// last chose by prefix, then Accept-Language, then the default. A prefix
+// choice is kept in the session so unprefixed links stay in that language.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := sessions.Default(c)
+		locale, fromURL := c.Request.Context().Value(localePrefixKey{}).(string)
+		if fromURL {
+			c.Set(contextKeyLocalePrefix, true)
+			if session.Get(sessionKeyLocale) != locale {
+				session.Set(sessionKeyLocale, locale)
+				_ = session.Save()
+			}
+		} else if saved, ok := session.Get(sessionKeyLocale).(string); ok && utils.IsSupportedLocale(saved) {
+			locale = saved
+		} else {
+			c.Header("Vary", "Accept-Language")
+			locale = utils.MatchAcceptLanguage(c.GetHeader("Accept-Language"))
+			if locale == "" {
+				locale = constants.DefaultLocale
+			}
+		}
+
+		c.Set(contextKeyLocale, locale)
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
+
+// GetLocale returns the locale chosen by Locale, or the default locale.
+func GetLocale(c *gin.Context) string {
+	if locale := c.GetString(contextKeyLocale); locale != "" {
+		return locale
+	}
+	return constants.DefaultLocale
+}
+
+// RedirectStatus is the status for redirecting from a resource's slug in one
+// locale to its slug in the request locale. Only a locale prefix makes the
+// target independent of the visitor, so only then may the redirect be cached
+// as permanent; a locale taken from the session or Accept-Language gets a
+// temporary one. Redirects from a former slug are always permanent.
+func RedirectStatus(c *gin.Context) int {
+	if c.GetBool(contextKeyLocalePrefix) {
+		return http.StatusMovedPermanently
+	}
+	return http.StatusFound
+}
+
+// LocalePath returns path under the same locale prefix the request used, so
+// redirects keep a visitor on /en/... when they came that way.
+func LocalePath(c *gin.Context, path string) string {
+	if c.GetBool(contextKeyLocalePrefix) {
+		return utils.LocalizedPath(GetLocale(c), path)
+	}
+	return path
+}
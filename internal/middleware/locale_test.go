@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLocaleRouter() http.Handler {
+	r := setupTestRouter()
+	r.Use(Locale())
+	r.GET("/tours", func(c *gin.Context) {
+		c.String(http.StatusOK, GetLocale(c))
+	})
+	return StripLocalePrefix(r)
+}
+
+func TestLocale_PrefixWinsAndIsRemembered(t *testing.T) {
+	h := setupLocaleRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/en/tours", nil)
+	req.Header.Set("Accept-Language", "vi")
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "en", w.Body.String())
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest(http.MethodGet, "/tours", nil)
+	req2.Header.Set("Accept-Language", "vi")
+	for _, cookie := range w.Result().Cookies() {
+		req2.AddCookie(cookie)
+	}
+	h.ServeHTTP(w2, req2)
+	assert.Equal(t, "en", w2.Body.String())
+}
+
+func TestLocale_AcceptLanguageThenDefault(t *testing.T) {
+	h := setupLocaleRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/tours", nil)
+	req.Header.Set("Accept-Language", "en-GB,en;q=0.9")
+	h.ServeHTTP(w, req)
+	assert.Equal(t, "en", w.Body.String())
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest(http.MethodGet, "/tours", nil)
+	req2.Header.Set("Accept-Language", "ja")
+	h.ServeHTTP(w2, req2)
+	assert.Equal(t, "vi", w2.Body.String())
+}
+
+func TestStripLocalePrefix_IgnoresUnknownSegments(t *testing.T) {
+	h := setupLocaleRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/fr/tours", nil)
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLocalePath_KeepsRequestPrefix(t *testing.T) {
+	r := setupTestRouter()
+	r.Use(Locale())
+	r.GET("/tours", func(c *gin.Context) {
+		c.String(http.StatusOK, LocalePath(c, "/tours/ha-long-bay"))
+	})
+	h := StripLocalePrefix(r)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/en/tours", nil)
+	h.ServeHTTP(w, req)
+	assert.Equal(t, "/en/tours/ha-long-bay", w.Body.String())
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest(http.MethodGet, "/tours", nil)
+	req2.Header.Set("Accept-Language", "en")
+	h.ServeHTTP(w2, req2)
+	assert.Equal(t, "/tours/ha-long-bay", w2.Body.String())
+}
+
+func TestRedirectStatus_PermanentOnlyWithPrefix(t *testing.T) {
+	r := setupTestRouter()
+	r.Use(Locale())
+	r.GET("/tours", func(c *gin.Context) {
+		c.Redirect(RedirectStatus(c), LocalePath(c, "/tours/ha-long-bay"))
+	})
+	h := StripLocalePrefix(r)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/en/tours", nil)
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/en/tours/ha-long-bay", w.Header().Get("Location"))
+
+	w2 := httptest.NewRecorder()
+	req2, _ := http.NewRequest(http.MethodGet, "/tours", nil)
+	req2.Header.Set("Accept-Language", "en")
+	h.ServeHTTP(w2, req2)
+	assert.Equal(t, http.StatusFound, w2.Code)
+}
@@ -6,6 +6,8 @@ import (
 
 // Booking represents the bookings table.
 // Status: "pending", "confirmed", "cancelled", or "completed"
+// PickupPoint is a snapshot of the chosen TourMeetingPoint label, so it
+// survives later edits to the tour's meeting points.
 type Booking struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
 	UserID          uint      `gorm:"not null;index" json:"user_id"`
@@ -15,6 +17,7 @@ type Booking struct {
 	TotalPrice      float64   `gorm:"type:decimal(15,2);not null" json:"total_price"`
 	Status          string    `gorm:"size:20;default:'pending';not null" json:"status"`
 	Note            string    `gorm:"type:text" json:"note"`
+	PickupPoint     string    `gorm:"size:1000" json:"pickup_point"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 
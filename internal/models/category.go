@@ -1,19 +1,30 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 // Category represents the categories table.
-// Supports parent-child hierarchy via ParentID (self-referencing).
+// Categories nest to any depth via ParentID (self-referencing). Path lists
+// the IDs from the root down to the category itself, e.g. "/1/5/9/", so a
+// subtree is every row whose Path starts with its root's; Depth is 0 for
+// roots. Siblings are ordered by Position, then Name.
+// SlugPinned keeps a custom Slug when the name changes. Slug is unique among
+// categories that are not deleted.
 type Category struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Name        string         `gorm:"size:255;not null" json:"name"`
-	Slug        string         `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	Slug        string         `gorm:"size:255;not null;uniqueIndex:idx_categories_slug_live,where:deleted_at IS NULL" json:"slug"`
+	SlugPinned  bool           `gorm:"not null;default:false" json:"slug_pinned"`
 	Description string         `gorm:"type:text" json:"description"`
 	ParentID    *uint          `gorm:"index" json:"parent_id"`
+	Path        string         `gorm:"size:1000;not null;default:'';index" json:"path"`
+	Depth       int            `gorm:"not null;default:0" json:"depth"`
+	Position    int            `gorm:"not null;default:0" json:"position"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -22,4 +33,53 @@ type Category struct {
 	Parent   *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
 	Children []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
 	Tours    []Tour     `gorm:"many2many:tour_categories" json:"tours,omitempty"`
+
+	Translations []CategoryTranslation `gorm:"foreignKey:CategoryID" json:"translations,omitempty"`
+}
+
+// Translation returns the category's translation into locale, or nil.
+func (c *Category) Translation(locale string) *CategoryTranslation {
+	for i := range c.Translations {
+		if c.Translations[i].Locale == locale {
+			return &c.Translations[i]
+		}
+	}
+	return nil
+}
+
+// Localize swaps the category's name, slug and description, and those of
+// its loaded parent and children, for their translations into locale.
+func (c *Category) Localize(locale string) {
+	if tr := c.Translation(locale); tr != nil {
+		c.Name, c.Slug = tr.Name, tr.Slug
+		if tr.Description != "" {
+			c.Description = tr.Description
+		}
+	}
+	if c.Parent != nil {
+		c.Parent.Localize(locale)
+	}
+	for i := range c.Children {
+		c.Children[i].Localize(locale)
+	}
+}
+
+// AncestorIDs returns the IDs in Path above the category, root first.
+func (c *Category) AncestorIDs() []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(c.Path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || uint(id) == c.ID {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+// LocalizeCategories localizes every category in the slice.
+func LocalizeCategories(cats []Category, locale string) {
+	for i := range cats {
+		cats[i].Localize(locale)
+	}
 }
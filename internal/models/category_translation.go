@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// CategoryTranslation represents the category_translations table: the
+// name, slug and description of a category in a locale other than the
+// default.
+type CategoryTranslation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CategoryID  uint      `gorm:"not null;uniqueIndex:idx_category_translation_locale" json:"category_id"`
+	Locale      string    `gorm:"size:10;not null;uniqueIndex:idx_category_translation_locale;uniqueIndex:idx_category_translation_slug" json:"locale"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Slug        string    `gorm:"size:255;not null;uniqueIndex:idx_category_translation_slug" json:"slug"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationships
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
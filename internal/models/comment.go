@@ -5,19 +5,47 @@ import (
 )
 
 // Comment represents the comments table.
-// Supports nested replies via ParentID (self-referencing).
+// Supports nested replies via ParentID (self-referencing). RootID is the
+// top-level comment of the thread (nil for top-level comments) and Depth
+// its nesting level, 0 for top-level comments.
+// Status: "approved", "pending", "rejected" or "hidden"; only approved
+// comments are shown to other users.
+// A deleted comment that still has replies keeps its row as a "[deleted]"
+// placeholder: RemovedAt is set and Content is cleared.
 type Comment struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	UserID   uint   `gorm:"not null;index" json:"user_id"`
+	ReviewID uint   `gorm:"not null;index" json:"review_id"`
+	ParentID *uint  `gorm:"index" json:"parent_id"`
+	RootID   *uint  `gorm:"index" json:"root_id"`
+	Depth    int    `gorm:"default:0;not null" json:"depth"`
+	Content  string `gorm:"type:text;not null" json:"content"`
+	Status   string `gorm:"size:20;default:'approved';not null;index" json:"status"`
+	Moderation
+	EditedAt  *time.Time `json:"edited_at"`
+	RemovedAt *time.Time `json:"removed_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relationships
+	User      *User             `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Review    *Review           `gorm:"foreignKey:ReviewID" json:"review,omitempty"`
+	Parent    *Comment          `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Children  []Comment         `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Revisions []CommentRevision `gorm:"foreignKey:CommentID" json:"revisions,omitempty"`
+}
+
+// IsRemoved reports whether the comment was deleted and only remains as a
+// placeholder for its replies.
+func (c Comment) IsRemoved() bool {
+	return c.RemovedAt != nil
+}
+
+// CommentRevision represents the comment_revisions table: the content a
+// comment had before one of its edits.
+type CommentRevision struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `gorm:"not null;index" json:"user_id"`
-	ReviewID  uint      `gorm:"not null;index" json:"review_id"`
-	ParentID  *uint     `gorm:"index" json:"parent_id"`
+	CommentID uint      `gorm:"not null;index" json:"comment_id"`
 	Content   string    `gorm:"type:text;not null" json:"content"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-
-	// Relationships
-	User     *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Review   *Review   `gorm:"foreignKey:ReviewID" json:"review,omitempty"`
-	Parent   *Comment  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
-	Children []Comment `gorm:"foreignKey:ParentID" json:"children,omitempty"`
 }
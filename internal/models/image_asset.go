@@ -0,0 +1,119 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+// ImageAsset is one entry of an Images JSON column. Uploaded images carry
+// their resized and WebP variants; entries saved as bare URL strings are
+// read back as an asset with only URL set.
+type ImageAsset struct {
+	URL           string `json:"url"`
+	Thumbnail     string `json:"thumbnail,omitempty"`
+	WebP          string `json:"webp,omitempty"`
+	ThumbnailWebP string `json:"thumbnail_webp,omitempty"`
+	Width         int    `json:"width,omitempty"`
+	Height        int    `json:"height,omitempty"`
+}
+
+func (a *ImageAsset) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*a = ImageAsset{URL: url}
+		return nil
+	}
+	type plain ImageAsset
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*a = ImageAsset(p)
+	return nil
+}
+
+// ThumbnailURL falls back to the original for assets without a thumbnail.
+func (a ImageAsset) ThumbnailURL() string {
+	if a.Thumbnail != "" {
+		return a.Thumbnail
+	}
+	return a.URL
+}
+
+// ParseImageAssets decodes an Images column, skipping entries without a URL.
+func ParseImageAssets(raw []byte) []ImageAsset {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var assets []ImageAsset
+	if err := json.Unmarshal(raw, &assets); err != nil {
+		return nil
+	}
+	out := assets[:0]
+	for _, a := range assets {
+		if strings.TrimSpace(a.URL) != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// ImageAssetURLs returns the original URL of each asset.
+func ImageAssetURLs(assets []ImageAsset) []string {
+	urls := make([]string, len(assets))
+	for i, a := range assets {
+		urls[i] = a.URL
+	}
+	return urls
+}
+
+// MergeImageAssets keeps the entries of existing whose URL is listed in
+// keepURLs, in keepURLs order, and appends uploaded. URLs that are not
+// already stored become plain assets so manually entered links still work.
+func MergeImageAssets(existing []ImageAsset, keepURLs []string, uploaded []ImageAsset) []ImageAsset {
+	byURL := make(map[string]ImageAsset, len(existing))
+	for _, a := range existing {
+		byURL[a.URL] = a
+	}
+
+	merged := make([]ImageAsset, 0, len(keepURLs)+len(uploaded))
+	for _, url := range keepURLs {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		if a, ok := byURL[url]; ok {
+			merged = append(merged, a)
+			continue
+		}
+		merged = append(merged, ImageAsset{URL: url})
+	}
+	return append(merged, uploaded...)
+}
+
+// RemovedImageAssets returns the entries of before whose URL is missing
+// from after.
+func RemovedImageAssets(before, after []ImageAsset) []ImageAsset {
+	kept := make(map[string]bool, len(after))
+	for _, a := range after {
+		kept[a.URL] = true
+	}
+	var removed []ImageAsset
+	for _, a := range before {
+		if !kept[a.URL] {
+			removed = append(removed, a)
+		}
+	}
+	return removed
+}
+
+// MarshalImageAssets encodes assets for an Images column; empty yields nil.
+func MarshalImageAssets(assets []ImageAsset) datatypes.JSON {
+	if len(assets) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(assets)
+	return datatypes.JSON(b)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "strings"
+
+// ModerationFlagSeparator joins the reasons stored in ModerationFlags.
+const ModerationFlagSeparator = "; "
+
+// Moderation is embedded in user-generated content. ModerationScore and
+// ModerationFlags record what the automated rule chain found when the content
+// was last submitted; RejectReason is shown to the author once the content
+// is rejected, automatically or by an admin.
+type Moderation struct {
+	ModerationScore int    `gorm:"default:0;not null" json:"moderation_score"`
+	ModerationFlags string `gorm:"size:1000" json:"moderation_flags"`
+	RejectReason    string `gorm:"size:500" json:"reject_reason"`
+}
+
+// Flags splits ModerationFlags back into the individual rule reasons.
+func (m Moderation) Flags() []string {
+	if m.ModerationFlags == "" {
+		return nil
+	}
+	return strings.Split(m.ModerationFlags, ModerationFlagSeparator)
+}
@@ -7,16 +7,102 @@ import (
 // Rating represents the ratings table.
 // Each user can rate a tour only once (unique constraint on user_id + tour_id).
 // Score: 1-5
+// The embedded RatingAspects hold optional 1-5 sub-scores.
+// BookingID is the completed booking that entitles the user to rate; it is
+// nil for ratings allowed by a RatingGrant or left before bookings were
+// required.
 type Rating struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_tour" json:"user_id"`
-	TourID    uint      `gorm:"not null;uniqueIndex:idx_user_tour" json:"tour_id"`
-	Score     int       `gorm:"not null;check:score >= 1 AND score <= 5" json:"score"`
-	Comment   string    `gorm:"type:text" json:"comment"`
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_user_tour" json:"user_id"`
+	TourID    uint   `gorm:"not null;uniqueIndex:idx_user_tour" json:"tour_id"`
+	BookingID *uint  `gorm:"index" json:"booking_id"`
+	Score     int    `gorm:"not null;check:score >= 1 AND score <= 5" json:"score"`
+	Comment   string `gorm:"type:text" json:"comment"`
+	RatingAspects
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relationships
-	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+	User    *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Tour    *Tour        `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+	Booking *Booking     `gorm:"foreignKey:BookingID" json:"booking,omitempty"`
+	Reply   *RatingReply `gorm:"foreignKey:RatingID" json:"reply,omitempty"`
+}
+
+// Verified reports whether the rating comes from a completed booking.
+func (r Rating) Verified() bool {
+	return r.BookingID != nil
+}
+
+// TripDate is the departure date of the booked trip, or the zero time when
+// the rating is not verified.
+func (r Rating) TripDate() time.Time {
+	if r.Booking == nil || r.Booking.Schedule == nil {
+		return time.Time{}
+	}
+	return r.Booking.Schedule.DepartureDate
+}
+
+// Rating aspects, each stored in the "<aspect>_score" column.
+const (
+	RatingAspectGuide         = "guide"
+	RatingAspectItinerary     = "itinerary"
+	RatingAspectAccommodation = "accommodation"
+	RatingAspectTransport     = "transport"
+	RatingAspectValue         = "value"
+)
+
+// RatingAspectKeys lists the aspects in display order.
+var RatingAspectKeys = []string{
+	RatingAspectGuide,
+	RatingAspectItinerary,
+	RatingAspectAccommodation,
+	RatingAspectTransport,
+	RatingAspectValue,
+}
+
+// RatingAspects are the optional sub-scores of a rating; nil means the
+// aspect was not rated.
+type RatingAspects struct {
+	GuideScore         *int `gorm:"check:guide_score >= 1 AND guide_score <= 5" json:"guide_score"`
+	ItineraryScore     *int `gorm:"check:itinerary_score >= 1 AND itinerary_score <= 5" json:"itinerary_score"`
+	AccommodationScore *int `gorm:"check:accommodation_score >= 1 AND accommodation_score <= 5" json:"accommodation_score"`
+	TransportScore     *int `gorm:"check:transport_score >= 1 AND transport_score <= 5" json:"transport_score"`
+	ValueScore         *int `gorm:"check:value_score >= 1 AND value_score <= 5" json:"value_score"`
+}
+
+// Get returns the sub-score of aspect, or nil when it is unrated or unknown.
+func (a RatingAspects) Get(aspect string) *int {
+	if ref := a.ref(aspect); ref != nil {
+		return *ref
+	}
+	return nil
+}
+
+// Set stores the sub-score of aspect; unknown aspects are ignored.
+func (a *RatingAspects) Set(aspect string, score *int) {
+	if ref := a.ref(aspect); ref != nil {
+		*ref = score
+	}
+}
+
+func (a *RatingAspects) ref(aspect string) **int {
+	switch aspect {
+	case RatingAspectGuide:
+		return &a.GuideScore
+	case RatingAspectItinerary:
+		return &a.ItineraryScore
+	case RatingAspectAccommodation:
+		return &a.AccommodationScore
+	case RatingAspectTransport:
+		return &a.TransportScore
+	case RatingAspectValue:
+		return &a.ValueScore
+	}
+	return nil
+}
+
+// RatingAspectColumn is the ratings column holding aspect's sub-score.
+func RatingAspectColumn(aspect string) string {
+	return aspect + "_score"
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// RatingGrant represents the rating_grants table: an admin's permission for
+// a user to rate a tour without a completed booking, e.g. for a trip booked
+// offline. Composite primary key: (UserID, TourID)
+type RatingGrant struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	TourID    uint      `gorm:"primaryKey;index" json:"tour_id"`
+	AdminID   uint      `gorm:"not null" json:"admin_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// RatingReply represents the rating_replies table: the operator's public
+// answer to a rating. A rating has at most one reply (unique RatingID);
+// editing it moves UpdatedAt.
+type RatingReply struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RatingID  uint      `gorm:"not null;uniqueIndex" json:"rating_id"`
+	AdminID   uint      `gorm:"not null;index" json:"admin_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Admin *User `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
+
+// Edited reports whether the reply was changed after it was posted.
+func (r RatingReply) Edited() bool {
+	return r.UpdatedAt.Sub(r.CreatedAt) > time.Second
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// TourRecommendation is a precomputed suggestion shown on a tour's page,
+// rebuilt wholesale by the recommendation job.
+// Kind: "related" (shared categories, nearby location and co-bookings
+// combined) or "also_booked" (co-bookings only). Rank starts at 1.
+type TourRecommendation struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	TourID            uint      `gorm:"not null;uniqueIndex:idx_tour_recommendation,priority:1" json:"tour_id"`
+	Kind              string    `gorm:"size:20;not null;uniqueIndex:idx_tour_recommendation,priority:2" json:"kind"`
+	Rank              int       `gorm:"not null;uniqueIndex:idx_tour_recommendation,priority:3" json:"rank"`
+	RecommendedTourID uint      `gorm:"not null;index" json:"recommended_tour_id"`
+	Score             float64   `gorm:"not null" json:"score"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// Relationships
+	RecommendedTour *Tour `gorm:"foreignKey:RecommendedTourID" json:"recommended_tour,omitempty"`
+}
+
+// UserRecommendation is a precomputed "recommended for you" tour derived
+// from the user's bookings and ratings. Rank starts at 1.
+type UserRecommendation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_recommendation,priority:1" json:"user_id"`
+	Rank      int       `gorm:"not null;uniqueIndex:idx_user_recommendation,priority:2" json:"rank"`
+	TourID    uint      `gorm:"not null;index" json:"tour_id"`
+	Score     float64   `gorm:"not null" json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
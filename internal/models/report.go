@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Report represents the reports table: a user flagging a review or comment.
+// TargetType: "review" or "comment"; a user reports an item at most once
+// (unique constraint on user_id + target_type + target_id).
+// Reason: "spam", "abusive", "misleading", "inappropriate" or "other"
+// Status: "open" until an admin resolves the item, then "resolved" with the
+// admin's Resolution ("dismiss", "hide", "warn" or "ban").
+type Report struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_report_user_target" json:"user_id"`
+	TargetType string     `gorm:"size:20;not null;uniqueIndex:idx_report_user_target;index:idx_report_target" json:"target_type"`
+	TargetID   uint       `gorm:"not null;uniqueIndex:idx_report_user_target;index:idx_report_target" json:"target_id"`
+	Reason     string     `gorm:"size:30;not null" json:"reason"`
+	Note       string     `gorm:"size:500" json:"note"`
+	Status     string     `gorm:"size:20;default:'open';not null;index" json:"status"`
+	Resolution string     `gorm:"size:20" json:"resolution"`
+	ResolvedBy *uint      `json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// ScheduleGuide represents the schedule_guides junction table.
+// Assigns a user with role "guide" to lead a tour schedule.
+// Composite primary key: (ScheduleID, GuideID), which also keeps a guide from
+// being assigned to the same schedule twice.
+type ScheduleGuide struct {
+	ScheduleID uint      `gorm:"primaryKey" json:"schedule_id"`
+	GuideID    uint      `gorm:"primaryKey;index" json:"guide_id"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relationships
+	Schedule *TourSchedule `gorm:"foreignKey:ScheduleID" json:"schedule,omitempty"`
+	Guide    *User         `gorm:"foreignKey:GuideID" json:"guide,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// SlugHistory represents the slug_histories table.
+// Records slugs a tour or category used before it was renamed so old links
+// can be redirected. EntityType: "tour" or "category".
+// A slug belongs to at most one entity per type; the latest owner wins.
+type SlugHistory struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EntityType string    `gorm:"size:20;not null;uniqueIndex:idx_slug_history_slug" json:"entity_type"`
+	Slug       string    `gorm:"size:500;not null;uniqueIndex:idx_slug_history_slug" json:"slug"`
+	EntityID   uint      `gorm:"not null;index" json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
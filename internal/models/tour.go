@@ -9,26 +9,143 @@ import (
 
 // Tour represents the tours table.
 // Status: "draft", "active", or "inactive"
-// Images stored as JSON array of URLs.
+// Images stored as JSON array of ImageAsset (older rows hold plain URLs).
+// MinParticipants is the number of confirmed travellers a schedule needs to
+// depart; 0 means the tour always runs.
+// SlugPinned keeps a custom Slug when the title changes. Slug is unique among
+// tours that are not deleted, so a trashed tour may lose it to a live one.
+// Latitude/Longitude locate the tour and MeetingLatitude/MeetingLongitude the
+// meeting point, in WGS84 decimal degrees; nil when unknown.
+// Saved is not stored; listings set it when the viewer wishlisted the tour.
+// AvgRating and RatingCount are kept in sync with the ratings table.
+// Title, Slug and Description are in the default locale; Translations hold
+// the other locales.
 type Tour struct {
-	ID              uint           `gorm:"primaryKey" json:"id"`
-	Title           string         `gorm:"size:500;not null" json:"title"`
-	Slug            string         `gorm:"size:500;uniqueIndex;not null" json:"slug"`
-	Description     string         `gorm:"type:text" json:"description"`
-	Price           float64        `gorm:"type:decimal(15,2);not null" json:"price"`
-	DurationDays    int            `gorm:"not null" json:"duration_days"`
-	Location        string         `gorm:"size:500" json:"location"`
-	MaxParticipants int            `gorm:"not null" json:"max_participants"`
-	Images          datatypes.JSON `gorm:"type:json" json:"images"`
-	Status          string         `gorm:"size:20;default:'draft';not null" json:"status"`
-	AvgRating       float64        `gorm:"type:decimal(3,2);default:0;check:avg_rating >= 0 AND avg_rating <= 5" json:"avg_rating"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	Title            string         `gorm:"size:500;not null" json:"title"`
+	Slug             string         `gorm:"size:500;not null;uniqueIndex:idx_tours_slug_live,where:deleted_at IS NULL" json:"slug"`
+	SlugPinned       bool           `gorm:"not null;default:false" json:"slug_pinned"`
+	Description      string         `gorm:"type:text" json:"description"`
+	Price            float64        `gorm:"type:decimal(15,2);not null" json:"price"`
+	DurationDays     int            `gorm:"not null" json:"duration_days"`
+	Location         string         `gorm:"size:500" json:"location"`
+	Latitude         *float64       `gorm:"index:idx_tours_lat_lng,priority:1" json:"latitude"`
+	Longitude        *float64       `gorm:"index:idx_tours_lat_lng,priority:2" json:"longitude"`
+	MeetingLatitude  *float64       `json:"meeting_latitude"`
+	MeetingLongitude *float64       `json:"meeting_longitude"`
+	MaxParticipants  int            `gorm:"not null" json:"max_participants"`
+	MinParticipants  int            `gorm:"not null;default:0" json:"min_participants"`
+	Images           datatypes.JSON `gorm:"type:json" json:"images"`
+	Status           string         `gorm:"size:20;default:'draft';not null" json:"status"`
+	AvgRating        float64        `gorm:"type:decimal(3,2);default:0;check:avg_rating >= 0 AND avg_rating <= 5" json:"avg_rating"`
+	RatingCount      int            `gorm:"not null;default:0" json:"rating_count"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+	Saved            bool           `gorm:"-" json:"saved,omitempty"`
 
 	// Relationships
-	Categories []Category     `gorm:"many2many:tour_categories" json:"categories,omitempty"`
-	Schedules  []TourSchedule `gorm:"foreignKey:TourID" json:"schedules,omitempty"`
-	Bookings   []Booking      `gorm:"foreignKey:TourID" json:"bookings,omitempty"`
-	Ratings    []Rating       `gorm:"foreignKey:TourID" json:"ratings,omitempty"`
+	Categories []Category         `gorm:"many2many:tour_categories" json:"categories,omitempty"`
+	Schedules  []TourSchedule     `gorm:"foreignKey:TourID" json:"schedules,omitempty"`
+	Bookings   []Booking          `gorm:"foreignKey:TourID" json:"bookings,omitempty"`
+	Ratings    []Rating           `gorm:"foreignKey:TourID" json:"ratings,omitempty"`
+	Itinerary  []TourItineraryDay `gorm:"foreignKey:TourID" json:"itinerary,omitempty"`
+
+	Inclusions    []TourInclusion    `gorm:"foreignKey:TourID" json:"inclusions,omitempty"`
+	MeetingPoints []TourMeetingPoint `gorm:"foreignKey:TourID" json:"meeting_points,omitempty"`
+	FAQs          []TourFAQ          `gorm:"foreignKey:TourID" json:"faqs,omitempty"`
+
+	Translations          []TourTranslation          `gorm:"foreignKey:TourID" json:"translations,omitempty"`
+	ItineraryTranslations []TourItineraryTranslation `gorm:"foreignKey:TourID" json:"itinerary_translations,omitempty"`
+}
+
+// Translation returns the tour's translation into locale, or nil.
+func (t *Tour) Translation(locale string) *TourTranslation {
+	for i := range t.Translations {
+		if t.Translations[i].Locale == locale {
+			return &t.Translations[i]
+		}
+	}
+	return nil
+}
+
+// ItineraryTranslation returns the translation of one itinerary day into
+// locale, or nil.
+func (t *Tour) ItineraryTranslation(locale string, dayNumber int) *TourItineraryTranslation {
+	for i := range t.ItineraryTranslations {
+		tr := &t.ItineraryTranslations[i]
+		if tr.Locale == locale && tr.DayNumber == dayNumber {
+			return tr
+		}
+	}
+	return nil
+}
+
+// Localize swaps the tour's title, slug, description, itinerary text and
+// category names for their translations into locale. Translations must be
+// preloaded; text without a translation stays in the default locale.
+func (t *Tour) Localize(locale string) {
+	if tr := t.Translation(locale); tr != nil {
+		t.Title, t.Slug = tr.Title, tr.Slug
+		if tr.Description != "" {
+			t.Description = tr.Description
+		}
+	}
+	for i := range t.Itinerary {
+		day := &t.Itinerary[i]
+		tr := t.ItineraryTranslation(locale, day.DayNumber)
+		if tr == nil {
+			continue
+		}
+		if tr.Title != "" {
+			day.Title = tr.Title
+		}
+		if tr.Description != "" {
+			day.Description = tr.Description
+		}
+		if tr.Accommodation != "" {
+			day.Accommodation = tr.Accommodation
+		}
+	}
+	for i := range t.Categories {
+		t.Categories[i].Localize(locale)
+	}
+}
+
+// LocalizeTours localizes every tour in the slice.
+func LocalizeTours(tours []Tour, locale string) {
+	for i := range tours {
+		tours[i].Localize(locale)
+	}
+}
+
+// InclusionsOf returns the tour's inclusion items of one kind, in order.
+func (t *Tour) InclusionsOf(kind string) []TourInclusion {
+	var items []TourInclusion
+	for _, item := range t.Inclusions {
+		if item.Kind == kind {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// FindMeetingPoint returns the tour's meeting point with the given ID.
+func (t *Tour) FindMeetingPoint(id uint) (*TourMeetingPoint, bool) {
+	for i := range t.MeetingPoints {
+		if t.MeetingPoints[i].ID == id {
+			return &t.MeetingPoints[i], true
+		}
+	}
+	return nil, false
+}
+
+// HasCoordinates reports whether the tour can be placed on a map.
+func (t *Tour) HasCoordinates() bool {
+	return t.Latitude != nil && t.Longitude != nil
+}
+
+// HasMeetingPoint reports whether the meeting point has coordinates.
+func (t *Tour) HasMeetingPoint() bool {
+	return t.MeetingLatitude != nil && t.MeetingLongitude != nil
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// TourFAQ represents the tour_faqs table: question and answer entries shown
+// on the tour page, ordered by Position starting at 1.
+type TourFAQ struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TourID    uint      `gorm:"not null;index" json:"tour_id"`
+	Position  int       `gorm:"not null" json:"position"`
+	Question  string    `gorm:"size:500;not null" json:"question"`
+	Answer    string    `gorm:"type:text;not null" json:"answer"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
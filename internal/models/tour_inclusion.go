@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// TourInclusion represents the tour_inclusions table.
+// Kind: "included", "excluded", or "bring" (what travellers should bring).
+// Items are listed per kind in Position order starting at 1.
+type TourInclusion struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TourID    uint      `gorm:"not null;index" json:"tour_id"`
+	Kind      string    `gorm:"size:20;not null" json:"kind"`
+	Position  int       `gorm:"not null" json:"position"`
+	Text      string    `gorm:"size:500;not null" json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
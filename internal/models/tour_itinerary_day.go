@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TourItineraryDay represents the tour_itinerary_days table.
+// Each tour has one row per day, ordered by DayNumber starting at 1.
+// Images stored as JSON array of URLs.
+type TourItineraryDay struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	TourID        uint           `gorm:"not null;uniqueIndex:idx_tour_itinerary_day" json:"tour_id"`
+	DayNumber     int            `gorm:"not null;uniqueIndex:idx_tour_itinerary_day" json:"day_number"`
+	Title         string         `gorm:"size:500;not null" json:"title"`
+	Description   string         `gorm:"type:text" json:"description"`
+	Breakfast     bool           `gorm:"not null;default:false" json:"breakfast"`
+	Lunch         bool           `gorm:"not null;default:false" json:"lunch"`
+	Dinner        bool           `gorm:"not null;default:false" json:"dinner"`
+	Accommodation string         `gorm:"size:500" json:"accommodation"`
+	Images        datatypes.JSON `gorm:"type:json" json:"images"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
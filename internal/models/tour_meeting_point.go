@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// TourMeetingPoint represents the tour_meeting_points table: where and when
+// travellers are picked up or meet the guide. PickupTime is "HH:MM" local
+// time, empty when not fixed. Points are ordered by Position starting at 1.
+type TourMeetingPoint struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TourID     uint      `gorm:"not null;index" json:"tour_id"`
+	Position   int       `gorm:"not null" json:"position"`
+	Name       string    `gorm:"size:255;not null" json:"name"`
+	Address    string    `gorm:"size:500" json:"address"`
+	PickupTime string    `gorm:"size:5" json:"pickup_time"`
+	Note       string    `gorm:"size:500" json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
+
+// Label is the one-line summary shown in the booking form and stored on
+// bookings, e.g. "07:30 — Nhà hát Lớn (1 Tràng Tiền)".
+func (p *TourMeetingPoint) Label() string {
+	label := p.Name
+	if p.PickupTime != "" {
+		label = p.PickupTime + " — " + label
+	}
+	if p.Address != "" {
+		label += " (" + p.Address + ")"
+	}
+	return label
+}
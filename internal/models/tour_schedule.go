@@ -7,6 +7,7 @@ import (
 // TourSchedule represents the tour_schedules table.
 // Each tour can have multiple departure schedules.
 // Status: "open", "full", or "cancelled"
+// Guaranteed is set once confirmed participants reach the tour's MinParticipants.
 type TourSchedule struct {
 	ID             uint      `gorm:"primaryKey" json:"id"`
 	TourID         uint      `gorm:"not null;index" json:"tour_id"`
@@ -15,9 +16,11 @@ type TourSchedule struct {
 	AvailableSlots int       `gorm:"not null" json:"available_slots"`
 	PriceOverride  *float64  `gorm:"type:decimal(15,2)" json:"price_override"`
 	Status         string    `gorm:"size:20;default:'open';not null" json:"status"`
+	Guaranteed     bool      `gorm:"not null;default:false" json:"guaranteed"`
 	CreatedAt      time.Time `json:"created_at"`
 
 	// Relationships
-	Tour     *Tour     `gorm:"foreignKey:TourID" json:"tour,omitempty"`
-	Bookings []Booking `gorm:"foreignKey:ScheduleID" json:"bookings,omitempty"`
+	Tour     *Tour           `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+	Bookings []Booking       `gorm:"foreignKey:ScheduleID" json:"bookings,omitempty"`
+	Guides   []ScheduleGuide `gorm:"foreignKey:ScheduleID" json:"guides,omitempty"`
 }
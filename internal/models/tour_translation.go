@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// TourTranslation represents the tour_translations table: the title, slug
+// and description of a tour in a locale other than the default. The
+// default-locale text lives on the tour itself.
+type TourTranslation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TourID      uint      `gorm:"not null;uniqueIndex:idx_tour_translation_locale" json:"tour_id"`
+	Locale      string    `gorm:"size:10;not null;uniqueIndex:idx_tour_translation_locale;uniqueIndex:idx_tour_translation_slug" json:"locale"`
+	Title       string    `gorm:"size:500;not null" json:"title"`
+	Slug        string    `gorm:"size:500;not null;uniqueIndex:idx_tour_translation_slug" json:"slug"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationships
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
+
+// TourItineraryTranslation represents the tour_itinerary_translations
+// table. Rows are keyed by day number rather than itinerary row ID because
+// the itinerary is rewritten on every save.
+type TourItineraryTranslation struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TourID        uint      `gorm:"not null;uniqueIndex:idx_tour_itinerary_translation" json:"tour_id"`
+	DayNumber     int       `gorm:"not null;uniqueIndex:idx_tour_itinerary_translation" json:"day_number"`
+	Locale        string    `gorm:"size:10;not null;uniqueIndex:idx_tour_itinerary_translation" json:"locale"`
+	Title         string    `gorm:"size:500" json:"title"`
+	Description   string    `gorm:"type:text" json:"description"`
+	Accommodation string    `gorm:"size:500" json:"accommodation"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
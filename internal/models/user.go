@@ -7,7 +7,7 @@ import (
 )
 
 // User represents the users table.
-// Role: "admin" or "user"
+// Role: "admin", "guide", or "user"
 // Status: "active", "inactive", or "banned"
 type User struct {
 	ID                uint           `gorm:"primaryKey" json:"id"`
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Wishlist represents the wishlists junction table: tours a user saved.
+// Composite primary key: (UserID, TourID)
+// NotifiedPrice and NotifiedAt record what the user last heard about the
+// tour; a lower price or a schedule created after NotifiedAt triggers a
+// notification.
+type Wishlist struct {
+	UserID        uint      `gorm:"primaryKey" json:"user_id"`
+	TourID        uint      `gorm:"primaryKey;index" json:"tour_id"`
+	NotifiedPrice float64   `gorm:"type:decimal(15,2);not null" json:"notified_price"`
+	NotifiedAt    time.Time `gorm:"not null" json:"notified_at"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Tour *Tour `gorm:"foreignKey:TourID" json:"tour,omitempty"`
+}
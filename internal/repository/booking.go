@@ -28,6 +28,9 @@ type BookingRepo interface {
 	FindByUserID(ctx context.Context, userID uint, page, limit int) ([]models.Booking, int64, error)
 	FindAll(ctx context.Context, filter BookingFilter) ([]models.Booking, int64, error)
 	UpdateStatus(ctx context.Context, id uint, status string) error
+	FindPassengersBySchedule(ctx context.Context, scheduleID uint) ([]models.Booking, error)
+	FindLatestCompleted(ctx context.Context, userID, tourID uint) (*models.Booking, error)
+	FindReviewable(ctx context.Context, userID, keepID uint) ([]models.Booking, error)
 }
 
 type bookingRepository struct {
@@ -140,3 +143,56 @@ func (r *bookingRepository) UpdateStatus(ctx context.Context, id uint, status st
 	}
 	return nil
 }
+
+// FindPassengersBySchedule returns the non-cancelled bookings of a schedule
+// together with the booking user, for passenger manifests.
+func (r *bookingRepository) FindPassengersBySchedule(ctx context.Context, scheduleID uint) ([]models.Booking, error) {
+	var bookings []models.Booking
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("schedule_id = ? AND status != ?", scheduleID, constants.BookingStatusCancelled).
+		Order("created_at ASC").
+		Find(&bookings).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxBookingFindBySchedule, err)
+	}
+	return bookings, nil
+}
+
+// FindLatestCompleted returns the user's completed booking of a tour with the
+// most recent departure, or gorm.ErrRecordNotFound when there is none.
+func (r *bookingRepository) FindLatestCompleted(ctx context.Context, userID, tourID uint) (*models.Booking, error) {
+	var booking models.Booking
+	if err := r.db.WithContext(ctx).
+		Preload("Schedule").
+		Joins("JOIN tour_schedules ON tour_schedules.id = bookings.schedule_id").
+		Where("bookings.user_id = ? AND bookings.tour_id = ? AND bookings.status = ?", userID, tourID, constants.BookingStatusCompleted).
+		Order("tour_schedules.departure_date DESC").
+		First(&booking).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxBookingFindCompleted, err)
+	}
+	return &booking, nil
+}
+
+// FindReviewable returns the trips the user may attach a review to, with
+// their tour and schedule, newest first: completed bookings and confirmed
+// ones that have already departed. keepID (0 = none) is returned whatever
+// its state, so an edited review keeps the trip it is linked to.
+func (r *bookingRepository) FindReviewable(ctx context.Context, userID, keepID uint) ([]models.Booking, error) {
+	travelled := r.db.
+		Where("bookings.status = ?", constants.BookingStatusCompleted).
+		Or("bookings.status = ? AND tour_schedules.departure_date < ?", constants.BookingStatusConfirmed, time.Now()).
+		Or("bookings.id = ?", keepID)
+
+	var bookings []models.Booking
+	if err := r.db.WithContext(ctx).
+		Preload("Tour").
+		Preload("Schedule").
+		Joins("JOIN tour_schedules ON tour_schedules.id = bookings.schedule_id").
+		Where("bookings.user_id = ?", userID).
+		Where(travelled).
+		Order("bookings.created_at DESC").
+		Find(&bookings).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxBookingFindReviewable, err)
+	}
+	return bookings, nil
+}
@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepo interface {
@@ -24,8 +27,17 @@ type CategoryRepo interface {
 	HasTours(ctx context.Context, id uint) (bool, error)
 	HasChildren(ctx context.Context, id uint) (bool, error)
 	GetDescendantIDs(ctx context.Context, parentID uint) ([]uint, error)
+	FindAncestors(ctx context.Context, cat *models.Category) ([]models.Category, error)
+	ChildIDs(ctx context.Context, parentID *uint) ([]uint, error)
+	Move(ctx context.Context, id uint, parentID *uint) error
+	UpdatePositions(ctx context.Context, ids []uint) error
+	Merge(ctx context.Context, sourceID, targetID uint) (*CategoryMergeResult, error)
+	ReplaceTranslations(ctx context.Context, categoryID uint, translations []models.CategoryTranslation) error
 }
 
+// categoryOrder sorts siblings by their explicit position, then by name.
+const categoryOrder = "position ASC, name ASC"
+
 type categoryRepository struct {
 	db *gorm.DB
 }
@@ -38,10 +50,11 @@ func (r *categoryRepository) FindAll(ctx context.Context) ([]models.Category, er
 	var cats []models.Category
 	if err := r.db.WithContext(ctx).
 		Preload("Children", func(db *gorm.DB) *gorm.DB {
-			return db.Order("name ASC")
+			return db.Order(categoryOrder)
 		}).
 		Preload("Parent").
-		Order("name ASC").
+		Scopes(preloadCategoryTranslations).
+		Order(categoryOrder).
 		Find(&cats).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindAll, err)
 	}
@@ -53,9 +66,10 @@ func (r *categoryRepository) FindAllParents(ctx context.Context) ([]models.Categ
 	if err := r.db.WithContext(ctx).
 		Where("parent_id IS NULL").
 		Preload("Children", func(db *gorm.DB) *gorm.DB {
-			return db.Order("name ASC")
+			return db.Order(categoryOrder)
 		}).
-		Order("name ASC").
+		Scopes(preloadCategoryTranslations).
+		Order(categoryOrder).
 		Find(&cats).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindAllParents, err)
 	}
@@ -67,36 +81,63 @@ func (r *categoryRepository) FindByID(ctx context.Context, id uint) (*models.Cat
 	if err := r.db.WithContext(ctx).
 		Preload("Parent").
 		Preload("Children").
+		Preload("Translations").
 		First(&cat, id).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindByID, err)
 	}
 	return &cat, nil
 }
 
+// FindBySlug matches current and translated slugs and falls back to the
+// slug history for renamed categories; the returned category's Slug is
+// always the current default-locale one.
 func (r *categoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
 	var cat models.Category
-	if err := r.db.WithContext(ctx).
-		Where("slug = ?", slug).
-		First(&cat).Error; err != nil {
+	err := r.db.WithContext(ctx).
+		Preload("Translations").
+		Where("slug = ? OR id IN (?)", slug,
+			r.db.Model(&models.CategoryTranslation{}).Select("category_id").Where("slug = ?", slug)).
+		First(&cat).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		id, hErr := historicalEntityID(ctx, r.db, constants.SlugEntityCategory, slug)
+		if hErr != nil {
+			if errors.Is(hErr, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindBySlug, err)
+			}
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxSlugHistoryFind, hErr)
+		}
+		if err := r.db.WithContext(ctx).Preload("Translations").First(&cat, id).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindBySlug, err)
+		}
+		return &cat, nil
+	}
+	if err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindBySlug, err)
 	}
 	return &cat, nil
 }
 
+// ExistsBySlug reports whether slug is taken by a category in any locale.
+// Translated slugs of trashed categories count too: unlike the live-only
+// default-locale index, the translation slug index spans the trash.
 func (r *categoryRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Category{}).
-		Where("slug = ?", slug).
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Category{}).
+		Where("(slug = ? AND deleted_at IS NULL) OR id IN (?)", slug,
+			r.db.Model(&models.CategoryTranslation{}).Select("category_id").Where("slug = ?", slug)).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryCheckSlugExists, err)
 	}
 	return count > 0, nil
 }
 
+// ExistsBySlugExcluding reports whether a category other than excludeID
+// uses slug in any locale, counting translations as ExistsBySlug does.
 func (r *categoryRepository) ExistsBySlugExcluding(ctx context.Context, slug string, excludeID uint) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Category{}).
-		Where("slug = ? AND id != ?", slug, excludeID).
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Category{}).
+		Where("((slug = ? AND deleted_at IS NULL) OR id IN (?)) AND id != ?", slug,
+			r.db.Model(&models.CategoryTranslation{}).Select("category_id").Where("slug = ?", slug), excludeID).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryCheckSlugExcluding, err)
 	}
@@ -113,8 +154,25 @@ func (r *categoryRepository) CountByIDs(ctx context.Context, ids []uint) (int64,
 	return count, nil
 }
 
+// Create inserts the category as the last child of its parent and fills in
+// its Path, Depth and Position.
 func (r *categoryRepository) Create(ctx context.Context, cat *models.Category) error {
-	if err := r.db.WithContext(ctx).Create(cat).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parentPath, depth, err := categoryParentPath(tx, cat.ParentID)
+		if err != nil {
+			return err
+		}
+		if cat.Position, err = nextCategoryPosition(tx, cat.ParentID); err != nil {
+			return err
+		}
+		cat.Depth = depth
+		if err := tx.Create(cat).Error; err != nil {
+			return err
+		}
+		cat.Path = fmt.Sprintf("%s%d/", parentPath, cat.ID)
+		return tx.Model(cat).Update("path", cat.Path).Error
+	})
+	if err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryCreate, err)
 	}
 	return nil
@@ -155,26 +213,229 @@ func (r *categoryRepository) HasChildren(ctx context.Context, id uint) (bool, er
 	return count > 0, nil
 }
 
+// GetDescendantIDs returns every category below parentID, at any depth.
 func (r *categoryRepository) GetDescendantIDs(ctx context.Context, parentID uint) ([]uint, error) {
+	var parent models.Category
+	if err := r.db.WithContext(ctx).Select("id", "path").First(&parent, parentID).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryGetDescendantIDs, err)
+	}
+	if parent.Path == "" {
+		return nil, nil
+	}
 	var ids []uint
-	if err := r.collectDescendants(ctx, parentID, &ids); err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).
+		Where("path LIKE ? AND id <> ?", parent.Path+"%", parentID).
+		Order("path ASC").
+		Pluck("id", &ids).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryGetDescendantIDs, err)
 	}
 	return ids, nil
 }
 
-func (r *categoryRepository) collectDescendants(ctx context.Context, parentID uint, ids *[]uint) error {
-	var childIDs []uint
+// FindAncestors returns the categories above cat, root first, with their
+// translations.
+func (r *categoryRepository) FindAncestors(ctx context.Context, cat *models.Category) ([]models.Category, error) {
+	ids := cat.AncestorIDs()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var ancestors []models.Category
+	if err := r.db.WithContext(ctx).
+		Preload("Translations").
+		Where("id IN ?", ids).
+		Order("depth ASC").
+		Find(&ancestors).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryFindAncestors, err)
+	}
+	return ancestors, nil
+}
+
+// ChildIDs returns the direct children of parentID in display order; nil
+// means the top level.
+func (r *categoryRepository) ChildIDs(ctx context.Context, parentID *uint) ([]uint, error) {
+	var ids []uint
 	if err := r.db.WithContext(ctx).Model(&models.Category{}).
-		Where("parent_id = ?", parentID).
-		Pluck("id", &childIDs).Error; err != nil {
+		Where(categoryParentCond(parentID)).
+		Order(categoryOrder).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryChildIDs, err)
+	}
+	return ids, nil
+}
+
+// Move puts the category, with its whole subtree, last under parentID (nil
+// for the top level). The caller makes sure parentID is not in the subtree.
+func (r *categoryRepository) Move(ctx context.Context, id uint, parentID *uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return moveCategorySubtree(tx, id, parentID)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryMove, err)
+	}
+	return nil
+}
+
+// UpdatePositions numbers the given sibling categories in order.
+func (r *categoryRepository) UpdatePositions(ctx context.Context, ids []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&models.Category{}).Where("id = ?", id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryUpdatePositions, err)
+	}
+	return nil
+}
+
+// CategoryMergeResult counts what a merge moved to the target category.
+type CategoryMergeResult struct {
+	Tours    int64
+	Children int
+}
+
+// Merge moves every tour link and subcategory of sourceID to targetID,
+// points the source's former slugs at the target and soft-deletes the
+// source. The caller makes sure targetID is not in the source's subtree.
+func (r *categoryRepository) Merge(ctx context.Context, sourceID, targetID uint) (*CategoryMergeResult, error) {
+	result := &CategoryMergeResult{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		moved := tx.Exec(`INSERT INTO tour_categories (tour_id, category_id)
+			SELECT tour_id, ? FROM tour_categories
+			WHERE category_id = ? AND tour_id NOT IN (SELECT tour_id FROM tour_categories WHERE category_id = ?)`,
+			targetID, sourceID, targetID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.Tours = moved.RowsAffected
+		if err := tx.Exec("DELETE FROM tour_categories WHERE category_id = ?", sourceID).Error; err != nil {
+			return err
+		}
+
+		var children []uint
+		if err := tx.Unscoped().Model(&models.Category{}).Where("parent_id = ?", sourceID).
+			Order(categoryOrder).Pluck("id", &children).Error; err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := moveCategorySubtree(tx, child, &targetID); err != nil {
+				return err
+			}
+		}
+		result.Children = len(children)
+
+		if err := tx.Model(&models.SlugHistory{}).
+			Where("entity_type = ? AND entity_id = ?", constants.SlugEntityCategory, sourceID).
+			Update("entity_id", targetID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, sourceID).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryMerge, err)
+	}
+	return result, nil
+}
+
+func categoryParentCond(parentID *uint) clause.Expr {
+	if parentID == nil {
+		return clause.Expr{SQL: "parent_id IS NULL"}
+	}
+	return clause.Expr{SQL: "parent_id = ?", Vars: []any{*parentID}}
+}
+
+// categoryParentPath returns the Path of parentID and the depth of its
+// children; the top level has an empty path and depth 0.
+func categoryParentPath(tx *gorm.DB, parentID *uint) (string, int, error) {
+	if parentID == nil {
+		return "/", 0, nil
+	}
+	var parent models.Category
+	if err := tx.Unscoped().Select("id", "path", "depth").First(&parent, *parentID).Error; err != nil {
+		return "", 0, err
+	}
+	return parent.Path, parent.Depth + 1, nil
+}
+
+func nextCategoryPosition(tx *gorm.DB, parentID *uint) (int, error) {
+	var last *int
+	if err := tx.Model(&models.Category{}).
+		Where(categoryParentCond(parentID)).
+		Select("MAX(position)").
+		Scan(&last).Error; err != nil {
+		return 0, err
+	}
+	if last == nil {
+		return 1, nil
+	}
+	return *last + 1, nil
+}
+
+// moveCategorySubtree reparents the category and rewrites the Path and
+// Depth of it and every row below it, deleted ones included.
+func moveCategorySubtree(tx *gorm.DB, id uint, parentID *uint) error {
+	var cat models.Category
+	if err := tx.Unscoped().Select("id", "path", "depth").First(&cat, id).Error; err != nil {
 		return err
 	}
-	for _, cid := range childIDs {
-		*ids = append(*ids, cid)
-		if err := r.collectDescendants(ctx, cid, ids); err != nil {
+	parentPath, depth, err := categoryParentPath(tx, parentID)
+	if err != nil {
+		return err
+	}
+	position, err := nextCategoryPosition(tx, parentID)
+	if err != nil {
+		return err
+	}
+
+	newPath := fmt.Sprintf("%s%d/", parentPath, id)
+	subtree := tx.Unscoped().Model(&models.Category{})
+	if cat.Path == "" {
+		// Not backfilled yet; an empty prefix would match every row.
+		subtree = subtree.Where("id = ?", id)
+	} else {
+		subtree = subtree.Where("path LIKE ?", cat.Path+"%")
+	}
+	if err := subtree.
+		Updates(map[string]any{
+			"path":  gorm.Expr("? || SUBSTR(path, ?)", newPath, len(cat.Path)+1),
+			"depth": gorm.Expr("depth + ?", depth-cat.Depth),
+		}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&models.Category{}).Where("id = ?", id).
+		Updates(map[string]any{"parent_id": parentID, "position": position}).Error
+}
+
+// preloadCategoryTranslations loads the translations of the categories and
+// of their loaded parents and children.
+func preloadCategoryTranslations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Translations").
+		Preload("Children.Translations").
+		Preload("Parent.Translations")
+}
+
+// ReplaceTranslations swaps the category's translations for the given ones.
+func (r *categoryRepository) ReplaceTranslations(ctx context.Context, categoryID uint, translations []models.CategoryTranslation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", categoryID).Delete(&models.CategoryTranslation{}).Error; err != nil {
 			return err
 		}
+		if len(translations) == 0 {
+			return nil
+		}
+		for i := range translations {
+			translations[i].ID = 0
+			translations[i].CategoryID = categoryID
+		}
+		return tx.Create(&translations).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryReplaceTranslations, err)
 	}
 	return nil
 }
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"sun-booking-tours/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SortDistance orders radius search results nearest first.
+const SortDistance = "distance"
+
+// GeoBBox is a map viewport in WGS84 degrees. When MinLng > MaxLng the box
+// crosses the antimeridian.
+type GeoBBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// Valid reports whether the box has in-range corners.
+func (b GeoBBox) Valid() bool {
+	return utils.ValidLatLng(b.MinLat, b.MinLng) && utils.ValidLatLng(b.MaxLat, b.MaxLng) &&
+		b.MinLat <= b.MaxLat
+}
+
+func withinBBox(db *gorm.DB, b GeoBBox) *gorm.DB {
+	db = db.Where("latitude BETWEEN ? AND ?", b.MinLat, b.MaxLat)
+	if b.MinLng > b.MaxLng {
+		return db.Where("(longitude >= ? OR longitude <= ?)", b.MinLng, b.MaxLng)
+	}
+	return db.Where("longitude BETWEEN ? AND ?", b.MinLng, b.MaxLng)
+}
+
+// withinRadius keeps rows within radiusKm of (lat, lng). The bounding box
+// lets the planner use idx_tours_lat_lng; the Haversine check is exact.
+func withinRadius(db *gorm.DB, lat, lng, radiusKm float64) *gorm.DB {
+	minLat, minLng, maxLat, maxLng := utils.RadiusBounds(lat, lng, radiusKm)
+	db = withinBBox(db, GeoBBox{MinLat: minLat, MinLng: minLng, MaxLat: maxLat, MaxLng: maxLng})
+	return db.Where(clause.Expr{
+		SQL:  haversineSQL + " <= ?",
+		Vars: []any{lat, lat, lng, radiusKm},
+	})
+}
+
+// orderByDistance sorts nearest to (lat, lng) first, then by the plain
+// "then" clause.
+func orderByDistance(db *gorm.DB, lat, lng float64, then string) *gorm.DB {
+	return orderByExpr(db, clause.Expr{SQL: haversineSQL + " ASC", Vars: []any{lat, lat, lng}}, then)
+}
+
+// haversineSQL is the great-circle distance in km from the point bound to
+// its three placeholders (lat, lat, lng) to the row's latitude/longitude.
+// LEAST guards asin against rounding just above 1.
+const haversineSQL = `(2 * 6371.0 * asin(LEAST(1, sqrt(
+	power(sin(radians(latitude - ?) / 2), 2) +
+	cos(radians(?)) * cos(radians(latitude)) * power(sin(radians(longitude - ?) / 2), 2)))))`
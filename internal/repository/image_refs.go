@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// countImageReferences counts the rows of query whose images JSON column
+// holds url, either as a bare string or as an asset URL. The URL is matched
+// in its JSON-encoded form, which is how MarshalImageAssets stores it.
+func countImageReferences(query *gorm.DB, url string) (int64, error) {
+	quoted, err := json.Marshal(url)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = query.Where("CAST(images AS TEXT) LIKE ?", "%"+string(quoted)+"%").Count(&count).Error
+	return count, err
+}
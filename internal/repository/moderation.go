@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ModerationRepo reads a user's earlier submissions for the duplicate and
+// posting velocity rules.
+type ModerationRepo interface {
+	// RecentContents returns the text of the user's reviews or comments
+	// created since the given time, newest first, leaving out excludeID
+	// (the item being edited, if any). Review text is the title and
+	// content joined by a newline.
+	RecentContents(ctx context.Context, kind string, userID, excludeID uint, since time.Time) ([]string, error)
+}
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) ModerationRepo {
+	return &moderationRepository{db: db}
+}
+
+func (r *moderationRepository) RecentContents(ctx context.Context, kind string, userID, excludeID uint, since time.Time) ([]string, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ? AND created_at >= ?", userID, since)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	query = query.Order("created_at DESC")
+
+	var contents []string
+	switch kind {
+	case constants.ModerationKindReview:
+		var rows []struct{ Title, Content string }
+		if err := query.Model(&models.Review{}).Select("title, content").Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxModerationRecent, err)
+		}
+		for _, row := range rows {
+			contents = append(contents, row.Title+"\n"+row.Content)
+		}
+	default:
+		if err := query.Model(&models.Comment{}).Pluck("content", &contents).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxModerationRecent, err)
+		}
+	}
+	return contents, nil
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 
+	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/models"
 
@@ -16,6 +19,38 @@ type RatingRepo interface {
 	FindByUserAndTour(ctx context.Context, userID, tourID uint) (*models.Rating, error)
 	FindByTourID(ctx context.Context, tourID uint, page, limit int) ([]models.Rating, int64, error)
 	CalcAvgByTourID(ctx context.Context, tourID uint) (float64, error)
+	StatsByTourID(ctx context.Context, tourID uint) (*RatingStats, error)
+	FindByID(ctx context.Context, id uint) (*models.Rating, error)
+	FindAll(ctx context.Context, filter RatingFilter) ([]models.Rating, int64, error)
+	SaveReply(ctx context.Context, reply *models.RatingReply) error
+	HasGrant(ctx context.Context, userID, tourID uint) (bool, error)
+	CreateGrant(ctx context.Context, grant *models.RatingGrant) error
+	DeleteGrant(ctx context.Context, userID, tourID uint) (int64, error)
+	FindGrantsByUser(ctx context.Context, userID uint) ([]models.RatingGrant, error)
+}
+
+// RatingFilter selects ratings for the admin list. Unanswered keeps ratings
+// without a reply, oldest first; MaxScore (0 = any) caps the score.
+type RatingFilter struct {
+	Unanswered bool
+	MaxScore   int
+	Page       int
+	Limit      int
+}
+
+// RatingStats aggregates a tour's ratings. Distribution counts ratings by
+// score; Aspects maps each of models.RatingAspectKeys to the average and
+// number of ratings that scored it.
+type RatingStats struct {
+	Distribution map[int]int64
+	Aspects      map[string]RatingAspectStat
+}
+
+// RatingAspectStat is the average of one sub-score over the ratings that
+// gave it.
+type RatingAspectStat struct {
+	Average float64
+	Count   int64
 }
 
 type ratingRepository struct {
@@ -30,7 +65,7 @@ func (r *ratingRepository) Upsert(ctx context.Context, rating *models.Rating) er
 	if err := r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "user_id"}, {Name: "tour_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{"score", "comment", "updated_at"}),
+			DoUpdates: clause.AssignmentColumns(ratingUpsertColumns()),
 		}).
 		Create(rating).Error; err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingUpsert, err)
@@ -38,6 +73,14 @@ func (r *ratingRepository) Upsert(ctx context.Context, rating *models.Rating) er
 	return nil
 }
 
+func ratingUpsertColumns() []string {
+	cols := []string{"booking_id", "score", "comment", "updated_at"}
+	for _, aspect := range models.RatingAspectKeys {
+		cols = append(cols, models.RatingAspectColumn(aspect))
+	}
+	return cols
+}
+
 func (r *ratingRepository) FindByUserAndTour(ctx context.Context, userID, tourID uint) (*models.Rating, error) {
 	var rating models.Rating
 	if err := r.db.WithContext(ctx).
@@ -64,6 +107,8 @@ func (r *ratingRepository) FindByTourID(ctx context.Context, tourID uint, page,
 	var ratings []models.Rating
 	if err := r.db.WithContext(ctx).
 		Preload("User").
+		Preload("Booking.Schedule").
+		Preload("Reply").
 		Where("tour_id = ?", tourID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -87,3 +132,152 @@ func (r *ratingRepository) CalcAvgByTourID(ctx context.Context, tourID uint) (fl
 	}
 	return *avg, nil
 }
+
+func (r *ratingRepository) StatsByTourID(ctx context.Context, tourID uint) (*RatingStats, error) {
+	var rows []struct {
+		Score int
+		Count int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Rating{}).
+		Select("score, COUNT(*) AS count").
+		Where("tour_id = ?", tourID).
+		Group("score").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingStats, err)
+	}
+	stats := &RatingStats{
+		Distribution: make(map[int]int64, len(rows)),
+		Aspects:      make(map[string]RatingAspectStat, len(models.RatingAspectKeys)),
+	}
+	for _, row := range rows {
+		stats.Distribution[row.Score] = row.Count
+	}
+
+	selects := make([]string, 0, 2*len(models.RatingAspectKeys))
+	for _, aspect := range models.RatingAspectKeys {
+		col := models.RatingAspectColumn(aspect)
+		selects = append(selects, fmt.Sprintf("AVG(%s), COUNT(%s)", col, col))
+	}
+	avgs := make([]sql.NullFloat64, len(models.RatingAspectKeys))
+	counts := make([]int64, len(models.RatingAspectKeys))
+	dest := make([]any, 0, 2*len(models.RatingAspectKeys))
+	for i := range models.RatingAspectKeys {
+		dest = append(dest, &avgs[i], &counts[i])
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Rating{}).
+		Select(strings.Join(selects, ", ")).
+		Where("tour_id = ?", tourID).
+		Row().Scan(dest...); err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingStats, err)
+	}
+	for i, aspect := range models.RatingAspectKeys {
+		stats.Aspects[aspect] = RatingAspectStat{Average: avgs[i].Float64, Count: counts[i]}
+	}
+	return stats, nil
+}
+
+func (r *ratingRepository) FindByID(ctx context.Context, id uint) (*models.Rating, error) {
+	var rating models.Rating
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Tour").
+		Preload("Reply").
+		First(&rating, id).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingFindByID, err)
+	}
+	return &rating, nil
+}
+
+func (r *ratingRepository) FindAll(ctx context.Context, filter RatingFilter) ([]models.Rating, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Rating{})
+	if filter.Unanswered {
+		query = query.Where("NOT EXISTS (?)", r.db.Model(&models.RatingReply{}).
+			Select("1").Where("rating_replies.rating_id = ratings.id"))
+	}
+	if filter.MaxScore > 0 {
+		query = query.Where("score <= ?", filter.MaxScore)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingCountAll, err)
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = constants.DefaultPageLimit
+	}
+	order := "created_at DESC"
+	if filter.Unanswered {
+		order = "created_at ASC"
+	}
+
+	var ratings []models.Rating
+	if err := query.
+		Preload("User").
+		Preload("Tour").
+		Preload("Reply.Admin").
+		Order(order).
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&ratings).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingFindAll, err)
+	}
+	return ratings, total, nil
+}
+
+// SaveReply creates the rating's reply or overwrites the existing one.
+func (r *ratingRepository) SaveReply(ctx context.Context, reply *models.RatingReply) error {
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "rating_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"admin_id", "content", "updated_at"}),
+		}).
+		Create(reply).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingSaveReply, err)
+	}
+	return nil
+}
+
+func (r *ratingRepository) HasGrant(ctx context.Context, userID, tourID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RatingGrant{}).
+		Where("user_id = ? AND tour_id = ?", userID, tourID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingHasGrant, err)
+	}
+	return count > 0, nil
+}
+
+func (r *ratingRepository) CreateGrant(ctx context.Context, grant *models.RatingGrant) error {
+	if err := r.db.WithContext(ctx).Create(grant).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingCreateGrant, err)
+	}
+	return nil
+}
+
+func (r *ratingRepository) DeleteGrant(ctx context.Context, userID, tourID uint) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND tour_id = ?", userID, tourID).
+		Delete(&models.RatingGrant{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingDeleteGrant, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// FindGrantsByUser lists the user's rating grants with their tours, newest
+// first.
+func (r *ratingRepository) FindGrantsByUser(ctx context.Context, userID uint) ([]models.RatingGrant, error) {
+	var grants []models.RatingGrant
+	if err := r.db.WithContext(ctx).
+		Preload("Tour").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingFindGrants, err)
+	}
+	return grants, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CoBooking counts the distinct users who booked both TourID and OtherTourID.
+type CoBooking struct {
+	TourID      uint
+	OtherTourID uint
+	Users       int64
+}
+
+// UserTour is one (user, tour) pair from the booking history.
+type UserTour struct {
+	UserID uint
+	TourID uint
+}
+
+type RecommendationRepo interface {
+	FindCandidateTours(ctx context.Context) ([]models.Tour, error)
+	CoBookingCounts(ctx context.Context) ([]CoBooking, error)
+	UserBookedTours(ctx context.Context) ([]UserTour, error)
+	UserRatings(ctx context.Context) ([]models.Rating, error)
+	ReplaceAll(ctx context.Context, tourRecs []models.TourRecommendation, userRecs []models.UserRecommendation) error
+	FindForTour(ctx context.Context, tourID uint, kind string, limit int) ([]models.Tour, error)
+	FindForUser(ctx context.Context, userID uint, limit int) ([]models.Tour, error)
+}
+
+type recommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepo {
+	return &recommendationRepository{db: db}
+}
+
+// FindCandidateTours loads every active tour with the fields the scorer
+// needs and its category links.
+func (r *recommendationRepository) FindCandidateTours(ctx context.Context) ([]models.Tour, error) {
+	var tours []models.Tour
+	if err := r.db.WithContext(ctx).
+		Select("id, location, latitude, longitude").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB {
+			return db.Select("categories.id")
+		}).
+		Where("status = ?", constants.TourStatusActive).
+		Find(&tours).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationCandidates, err)
+	}
+	return tours, nil
+}
+
+// CoBookingCounts returns, for every ordered pair of tours, how many users
+// have non-cancelled bookings on both.
+func (r *recommendationRepository) CoBookingCounts(ctx context.Context) ([]CoBooking, error) {
+	var rows []CoBooking
+	if err := r.db.WithContext(ctx).
+		Table("bookings AS a").
+		Select("a.tour_id AS tour_id, b.tour_id AS other_tour_id, COUNT(DISTINCT a.user_id) AS users").
+		Joins("JOIN bookings AS b ON b.user_id = a.user_id AND b.tour_id <> a.tour_id").
+		Where("a.status <> ? AND b.status <> ?", constants.BookingStatusCancelled, constants.BookingStatusCancelled).
+		Group("a.tour_id, b.tour_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationCoBookings, err)
+	}
+	return rows, nil
+}
+
+func (r *recommendationRepository) UserBookedTours(ctx context.Context) ([]UserTour, error) {
+	var rows []UserTour
+	if err := r.db.WithContext(ctx).
+		Model(&models.Booking{}).
+		Distinct("user_id", "tour_id").
+		Where("status <> ?", constants.BookingStatusCancelled).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationUserBookings, err)
+	}
+	return rows, nil
+}
+
+func (r *recommendationRepository) UserRatings(ctx context.Context) ([]models.Rating, error) {
+	var ratings []models.Rating
+	if err := r.db.WithContext(ctx).
+		Select("user_id, tour_id, score").
+		Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationUserRatings, err)
+	}
+	return ratings, nil
+}
+
+// ReplaceAll swaps the previous recommendations for a fresh set in one
+// transaction, so readers never see a half-built table.
+func (r *recommendationRepository) ReplaceAll(ctx context.Context, tourRecs []models.TourRecommendation, userRecs []models.UserRecommendation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.TourRecommendation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.UserRecommendation{}).Error; err != nil {
+			return err
+		}
+		if len(tourRecs) > 0 {
+			if err := tx.CreateInBatches(tourRecs, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(userRecs) > 0 {
+			if err := tx.CreateInBatches(userRecs, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationReplace, err)
+	}
+	return nil
+}
+
+// FindForTour returns the still-active recommended tours of one kind, in
+// rank order.
+func (r *recommendationRepository) FindForTour(ctx context.Context, tourID uint, kind string, limit int) ([]models.Tour, error) {
+	var tours []models.Tour
+	if err := r.db.WithContext(ctx).
+		Preload("Translations").
+		Joins("JOIN tour_recommendations ON tour_recommendations.recommended_tour_id = tours.id").
+		Where("tour_recommendations.tour_id = ? AND tour_recommendations.kind = ?", tourID, kind).
+		Where("tours.status = ?", constants.TourStatusActive).
+		Order("tour_recommendations.rank ASC").
+		Limit(limit).
+		Find(&tours).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationFindForTour, err)
+	}
+	return tours, nil
+}
+
+func (r *recommendationRepository) FindForUser(ctx context.Context, userID uint, limit int) ([]models.Tour, error) {
+	var tours []models.Tour
+	if err := r.db.WithContext(ctx).
+		Preload("Translations").
+		Joins("JOIN user_recommendations ON user_recommendations.tour_id = tours.id").
+		Where("user_recommendations.user_id = ?", userID).
+		Where("tours.status = ?", constants.TourStatusActive).
+		Order("user_recommendations.rank ASC").
+		Limit(limit).
+		Find(&tours).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationFindForUser, err)
+	}
+	return tours, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReportTarget identifies a reported review or comment.
+type ReportTarget struct {
+	Type string
+	ID   uint
+}
+
+// ReportGroupRow is one reported item in the admin queue with the number of
+// its open reports.
+type ReportGroupRow struct {
+	TargetType  string
+	TargetID    uint
+	ReportCount int64
+	LastID      uint
+}
+
+type ReportRepo interface {
+	Create(ctx context.Context, report *models.Report) error
+	Exists(ctx context.Context, userID uint, target ReportTarget) (bool, error)
+	CountOpen(ctx context.Context, target ReportTarget) (int64, error)
+	// FindOpenGroups lists reported items with open reports, most reported
+	// first, then most recently reported.
+	FindOpenGroups(ctx context.Context, page, limit int) ([]ReportGroupRow, int64, error)
+	FindOpenByTargets(ctx context.Context, targets []ReportTarget) ([]models.Report, error)
+	// Resolve closes every open report of the item with the admin's action.
+	Resolve(ctx context.Context, target ReportTarget, resolution string, adminID uint) error
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepo {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	if err := r.db.WithContext(ctx).Create(report).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReportCreate, err)
+	}
+	return nil
+}
+
+func (r *reportRepository) Exists(ctx context.Context, userID uint, target ReportTarget) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Report{}).
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, target.Type, target.ID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxReportExists, err)
+	}
+	return count > 0, nil
+}
+
+func (r *reportRepository) CountOpen(ctx context.Context, target ReportTarget) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", target.Type, target.ID, constants.ReportStatusOpen).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReportCount, err)
+	}
+	return count, nil
+}
+
+func (r *reportRepository) FindOpenGroups(ctx context.Context, page, limit int) ([]ReportGroupRow, int64, error) {
+	groups := r.db.WithContext(ctx).Model(&models.Report{}).
+		Select("target_type, target_id, COUNT(*) AS report_count, MAX(id) AS last_id").
+		Where("status = ?", constants.ReportStatusOpen).
+		Group("target_type, target_id")
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS report_groups", groups).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReportCountGroups, err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = constants.DefaultPageLimit
+	}
+
+	var rows []ReportGroupRow
+	if err := groups.
+		Order("report_count DESC, last_id DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReportFindGroups, err)
+	}
+	return rows, total, nil
+}
+
+func (r *reportRepository) FindOpenByTargets(ctx context.Context, targets []ReportTarget) ([]models.Report, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	byType := map[string][]uint{}
+	for _, t := range targets {
+		byType[t.Type] = append(byType[t.Type], t.ID)
+	}
+	match := r.db.Where("1 = 0")
+	for targetType, ids := range byType {
+		match = match.Or("target_type = ? AND target_id IN ?", targetType, ids)
+	}
+
+	var reports []models.Report
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("status = ?", constants.ReportStatusOpen).
+		Where(match).
+		Order("created_at ASC").
+		Find(&reports).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReportFindByTargets, err)
+	}
+	return reports, nil
+}
+
+func (r *reportRepository) Resolve(ctx context.Context, target ReportTarget, resolution string, adminID uint) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", target.Type, target.ID, constants.ReportStatusOpen).
+		Updates(map[string]any{
+			"status":      constants.ReportStatusResolved,
+			"resolution":  resolution,
+			"resolved_by": adminID,
+			"resolved_at": now,
+		}).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReportResolve, err)
+	}
+	return nil
+}
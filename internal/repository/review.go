@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
@@ -11,14 +12,30 @@ import (
 	"gorm.io/gorm"
 )
 
+// ReviewFilter narrows review listings. TourID keeps reviews linked to that
+// tour; Location keeps reviews whose tour's location contains it.
 type ReviewFilter struct {
-	Status  string
-	Type    string
-	UserID  uint
-	Keyword string
-	Sort    string
-	Page    int
-	Limit   int
+	Status   string
+	Type     string
+	UserID   uint
+	TourID   uint
+	Location string
+	Keyword  string
+	Sort     string
+	Page     int
+	Limit    int
+}
+
+func reviewTourScope(filter ReviewFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.TourID > 0 {
+			db = db.Where("reviews.tour_id = ?", filter.TourID)
+		}
+		if filter.Location != "" {
+			db = db.Where("reviews.tour_id IN (SELECT id FROM tours WHERE "+locationMatchSQL+")", locationPattern(filter.Location))
+		}
+		return db
+	}
 }
 
 type ReviewRepo interface {
@@ -29,14 +46,39 @@ type ReviewRepo interface {
 	FindAll(ctx context.Context, filter ReviewFilter) ([]models.Review, int64, error)
 	Update(ctx context.Context, review *models.Review) error
 	Delete(ctx context.Context, id uint) error
-	UpdateStatus(ctx context.Context, id uint, status string) error
+	UpdateStatus(ctx context.Context, id uint, status, rejectReason string) error
 	IncrementLikeCount(ctx context.Context, id uint, delta int) error
+	// CountImageReferences counts reviews, trashed ones included, whose
+	// images contain url.
+	CountImageReferences(ctx context.Context, url string) (int64, error)
+}
+
+// CommentFilter narrows the admin comment listing.
+type CommentFilter struct {
+	Status   string
+	ReviewID uint
+	UserID   uint
+	Page     int
+	Limit    int
 }
 
 type CommentRepo interface {
 	Create(ctx context.Context, comment *models.Comment) error
-	FindByReviewID(ctx context.Context, reviewID uint) ([]models.Comment, error)
+	// FindByReviewID returns a page of the review's top-level comments,
+	// newest first, each with its replies nested in Children oldest first,
+	// and the number of top-level comments. Only approved comments and the
+	// viewer's own comments are included; deleted placeholders only while
+	// a reply the viewer can see remains.
+	FindByReviewID(ctx context.Context, reviewID, viewerID uint, page, limit int) ([]models.Comment, int64, error)
 	FindByID(ctx context.Context, id uint) (*models.Comment, error)
+	FindAll(ctx context.Context, filter CommentFilter) ([]models.Comment, int64, error)
+	HasReplies(ctx context.Context, id uint) (bool, error)
+	// UpdateContent saves an edit of the comment and keeps the previous
+	// content in its edit history; an empty previous keeps no revision.
+	UpdateContent(ctx context.Context, comment *models.Comment, previous string) error
+	UpdateStatus(ctx context.Context, id uint, status, rejectReason string) error
+	// SoftDelete turns the comment into a placeholder for its replies.
+	SoftDelete(ctx context.Context, id uint) error
 	Delete(ctx context.Context, id uint) error
 }
 
@@ -66,6 +108,7 @@ func (r *reviewRepository) FindByID(ctx context.Context, id uint) (*models.Revie
 	var review models.Review
 	if err := r.db.WithContext(ctx).
 		Preload("User").
+		Preload("Tour").
 		First(&review, id).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewFindByID, err)
 	}
@@ -98,16 +141,16 @@ func (r *reviewRepository) FindByUserID(ctx context.Context, userID uint, page,
 }
 
 func (r *reviewRepository) FindAllPublic(ctx context.Context, filter ReviewFilter) ([]models.Review, int64, error) {
+	tsq := fullTextQuery(filter.Keyword)
 	baseScope := func(db *gorm.DB) *gorm.DB {
 		db = db.Where("status = ?", constants.ReviewStatusApproved)
 		if filter.Type != "" {
 			db = db.Where("type = ?", filter.Type)
 		}
-		if filter.Keyword != "" {
-			kw := "%" + filter.Keyword + "%"
-			db = db.Where("(title ILIKE ? OR content ILIKE ?)", kw, kw)
+		if tsq != "" {
+			db = matchFullText(db, tsq)
 		}
-		return db
+		return db.Scopes(reviewTourScope(filter))
 	}
 
 	var total int64
@@ -128,11 +171,17 @@ func (r *reviewRepository) FindAllPublic(ctx context.Context, filter ReviewFilte
 		orderClause = "like_count DESC, created_at DESC"
 	}
 
+	findQuery := r.db.WithContext(ctx).Scopes(baseScope)
+	if tsq != "" && (filter.Sort == "" || filter.Sort == SortRelevance) {
+		findQuery = orderByRank(findQuery, tsq, orderClause)
+	} else {
+		findQuery = findQuery.Order(orderClause)
+	}
+
 	var reviews []models.Review
-	if err := r.db.WithContext(ctx).
+	if err := findQuery.
 		Preload("User").
-		Scopes(baseScope).
-		Order(orderClause).
+		Preload("Tour").
 		Limit(filter.Limit).
 		Offset(offset).
 		Find(&reviews).Error; err != nil {
@@ -153,9 +202,10 @@ func (r *reviewRepository) FindAll(ctx context.Context, filter ReviewFilter) ([]
 	if filter.UserID > 0 {
 		query = query.Where("user_id = ?", filter.UserID)
 	}
-	if filter.Keyword != "" {
-		kw := "%" + filter.Keyword + "%"
-		query = query.Where("(title ILIKE ? OR content ILIKE ?)", kw, kw)
+	query = query.Scopes(reviewTourScope(filter))
+	tsq := fullTextQuery(filter.Keyword)
+	if tsq != "" {
+		query = matchFullText(query, tsq)
 	}
 
 	var total int64
@@ -171,10 +221,16 @@ func (r *reviewRepository) FindAll(ctx context.Context, filter ReviewFilter) ([]
 	}
 	offset := (filter.Page - 1) * filter.Limit
 
+	if tsq != "" {
+		query = orderByRank(query, tsq, "created_at DESC")
+	} else {
+		query = query.Order("created_at DESC")
+	}
+
 	var reviews []models.Review
 	if err := query.
 		Preload("User").
-		Order("created_at DESC").
+		Preload("Tour").
 		Limit(filter.Limit).
 		Offset(offset).
 		Find(&reviews).Error; err != nil {
@@ -197,11 +253,11 @@ func (r *reviewRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
-func (r *reviewRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+func (r *reviewRepository) UpdateStatus(ctx context.Context, id uint, status, rejectReason string) error {
 	if err := r.db.WithContext(ctx).
 		Model(&models.Review{}).
 		Where("id = ?", id).
-		Update("status", status).Error; err != nil {
+		Updates(map[string]any{"status": status, "reject_reason": rejectReason}).Error; err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewUpdateStatus, err)
 	}
 	return nil
@@ -217,6 +273,14 @@ func (r *reviewRepository) IncrementLikeCount(ctx context.Context, id uint, delt
 	return nil
 }
 
+func (r *reviewRepository) CountImageReferences(ctx context.Context, url string) (int64, error) {
+	count, err := countImageReferences(r.db.WithContext(ctx).Unscoped().Model(&models.Review{}), url)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewCountImageRefs, err)
+	}
+	return count, nil
+}
+
 type commentRepository struct {
 	db *gorm.DB
 }
@@ -232,33 +296,221 @@ func (r *commentRepository) Create(ctx context.Context, comment *models.Comment)
 	return nil
 }
 
-func (r *commentRepository) FindByReviewID(ctx context.Context, reviewID uint) ([]models.Comment, error) {
-	var comments []models.Comment
-	if err := r.db.WithContext(ctx).
-		Preload("User").
-		Preload("Children", func(db *gorm.DB) *gorm.DB {
-			return db.Preload("User").Order("created_at ASC")
-		}).
+func (r *commentRepository) FindByReviewID(ctx context.Context, reviewID, viewerID uint, page, limit int) ([]models.Comment, int64, error) {
+	visible := func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? OR user_id = ?", constants.CommentStatusApproved, viewerID)
+	}
+	withDetails := func(db *gorm.DB) *gorm.DB {
+		return db.Preload("User").Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC, id DESC")
+		})
+	}
+
+	// Placeholders with no visible reply left are dropped by
+	// buildCommentTree, so they must not count towards the pages either.
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).
 		Where("review_id = ? AND parent_id IS NULL", reviewID).
-		Order("created_at DESC").
-		Find(&comments).Error; err != nil {
-		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentFindByReview, err)
+		Scopes(visible).
+		Where("comments.removed_at IS NULL OR EXISTS (SELECT 1 FROM comments AS r WHERE r.root_id = comments.id AND r.removed_at IS NULL AND (r.status = ? OR r.user_id = ?))",
+			constants.CommentStatusApproved, viewerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentCountRoots, err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = constants.CommentPageLimit
+	}
+
+	var roots []models.Comment
+	if err := query.
+		Scopes(withDetails).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&roots).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentFindByReview, err)
+	}
+	if len(roots) == 0 {
+		return roots, total, nil
+	}
+
+	rootIDs := make([]uint, len(roots))
+	for i, c := range roots {
+		rootIDs[i] = c.ID
+	}
+	var replies []models.Comment
+	if err := r.db.WithContext(ctx).
+		Where("root_id IN ?", rootIDs).
+		Scopes(visible, withDetails).
+		Order("created_at ASC, id ASC").
+		Find(&replies).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentFindReplies, err)
+	}
+	return buildCommentTree(roots, replies), total, nil
+}
+
+// buildCommentTree nests the replies under their parents, keeping their
+// order. Replies whose parent is not visible are left out, and so are
+// deleted placeholders that have no visible replies left.
+func buildCommentTree(roots, replies []models.Comment) []models.Comment {
+	children := make(map[uint][]models.Comment, len(replies))
+	for _, c := range replies {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(c models.Comment) (models.Comment, bool)
+	attach = func(c models.Comment) (models.Comment, bool) {
+		c.Children = nil
+		for _, child := range children[c.ID] {
+			if child, ok := attach(child); ok {
+				c.Children = append(c.Children, child)
+			}
+		}
+		return c, !c.IsRemoved() || len(c.Children) > 0
+	}
+
+	tree := make([]models.Comment, 0, len(roots))
+	for _, root := range roots {
+		if root, ok := attach(root); ok {
+			tree = append(tree, root)
+		}
 	}
-	return comments, nil
+	return tree
 }
 
 func (r *commentRepository) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
 	var comment models.Comment
-	if err := r.db.WithContext(ctx).First(&comment, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Review").
+		First(&comment, id).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentFindByID, err)
 	}
 	return &comment, nil
 }
 
-func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+func (r *commentRepository) FindAll(ctx context.Context, filter CommentFilter) ([]models.Comment, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Comment{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ReviewID > 0 {
+		query = query.Where("review_id = ?", filter.ReviewID)
+	}
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentCountAll, err)
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = constants.DefaultPageLimit
+	}
+	offset := (filter.Page - 1) * filter.Limit
+
+	// The pending queue is worked oldest first.
+	order := "created_at DESC"
+	if filter.Status == constants.CommentStatusPending {
+		order = "created_at ASC"
+	}
+
+	var comments []models.Comment
+	if err := query.
+		Preload("User").
+		Preload("Review").
+		Order(order).
+		Limit(filter.Limit).
+		Offset(offset).
+		Find(&comments).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentFindAll, err)
+	}
+	return comments, total, nil
+}
+
+func (r *commentRepository) UpdateStatus(ctx context.Context, id uint, status, rejectReason string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.Comment{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": status, "reject_reason": rejectReason}).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCommentUpdateStatus, err)
+	}
+	return nil
+}
+
+func (r *commentRepository) HasReplies(ctx context.Context, id uint) (bool, error) {
+	var count int64
 	if err := r.db.WithContext(ctx).
-		Where("id = ? OR parent_id = ?", id, id).
-		Delete(&models.Comment{}).Error; err != nil {
+		Model(&models.Comment{}).
+		Where("parent_id = ?", id).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxCommentHasReplies, err)
+	}
+	return count > 0, nil
+}
+
+func (r *commentRepository) UpdateContent(ctx context.Context, comment *models.Comment, previous string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if previous != "" {
+			if err := tx.Create(&models.CommentRevision{CommentID: comment.ID, Content: previous}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Comment{}).
+			Where("id = ?", comment.ID).
+			Updates(map[string]any{
+				"content":          comment.Content,
+				"status":           comment.Status,
+				"moderation_score": comment.ModerationScore,
+				"moderation_flags": comment.ModerationFlags,
+				"reject_reason":    comment.RejectReason,
+				"edited_at":        comment.EditedAt,
+			}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCommentUpdate, err)
+	}
+	return nil
+}
+
+// SoftDelete clears the content and edit history of the comment and marks
+// it removed.
+func (r *commentRepository) SoftDelete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", id).Delete(&models.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Comment{}).
+			Where("id = ?", id).
+			Updates(map[string]any{"content": "", "removed_at": time.Now()}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCommentSoftDelete, err)
+	}
+	return nil
+}
+
+// Delete removes a comment without replies, with its edit history.
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", id).Delete(&models.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Comment{}, id).Error
+	})
+	if err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxCommentDelete, err)
 	}
 	return nil
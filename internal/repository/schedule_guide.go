@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ScheduleGuideRepo interface {
+	FindByScheduleID(ctx context.Context, scheduleID uint) ([]models.ScheduleGuide, error)
+	IsAssigned(ctx context.Context, scheduleID, guideID uint) (bool, error)
+	HasConflict(ctx context.Context, guideID, excludeScheduleID uint, departure, returnDate time.Time) (bool, error)
+	Assign(ctx context.Context, assignment *models.ScheduleGuide) error
+	Unassign(ctx context.Context, scheduleID, guideID uint) error
+	UnassignGuide(ctx context.Context, guideID uint) error
+	FindSchedulesByGuide(ctx context.Context, guideID uint, from time.Time) ([]models.TourSchedule, error)
+}
+
+type scheduleGuideRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleGuideRepository(db *gorm.DB) ScheduleGuideRepo {
+	return &scheduleGuideRepository{db: db}
+}
+
+func (r *scheduleGuideRepository) FindByScheduleID(ctx context.Context, scheduleID uint) ([]models.ScheduleGuide, error) {
+	var assignments []models.ScheduleGuide
+	if err := r.db.WithContext(ctx).
+		Preload("Guide").
+		Where("schedule_id = ?", scheduleID).
+		Order("created_at ASC").
+		Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideFindBySchedule, err)
+	}
+	return assignments, nil
+}
+
+func (r *scheduleGuideRepository) IsAssigned(ctx context.Context, scheduleID, guideID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.ScheduleGuide{}).
+		Where("schedule_id = ? AND guide_id = ?", scheduleID, guideID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideIsAssigned, err)
+	}
+	return count > 0, nil
+}
+
+// HasConflict reports whether the guide already leads another non-cancelled
+// schedule whose [departure, return] range overlaps the given dates.
+func (r *scheduleGuideRepository) HasConflict(ctx context.Context, guideID, excludeScheduleID uint, departure, returnDate time.Time) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.ScheduleGuide{}).
+		Joins("JOIN tour_schedules ON tour_schedules.id = schedule_guides.schedule_id").
+		Where("schedule_guides.guide_id = ? AND schedule_guides.schedule_id != ?", guideID, excludeScheduleID).
+		Where("tour_schedules.status != ?", constants.ScheduleStatusCancelled).
+		Where("tour_schedules.departure_date <= ? AND tour_schedules.return_date >= ?", returnDate, departure).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideHasConflict, err)
+	}
+	return count > 0, nil
+}
+
+func (r *scheduleGuideRepository) Assign(ctx context.Context, assignment *models.ScheduleGuide) error {
+	if err := r.db.WithContext(ctx).Create(assignment).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideAssign, err)
+	}
+	return nil
+}
+
+func (r *scheduleGuideRepository) Unassign(ctx context.Context, scheduleID, guideID uint) error {
+	if err := r.db.WithContext(ctx).
+		Where("schedule_id = ? AND guide_id = ?", scheduleID, guideID).
+		Delete(&models.ScheduleGuide{}).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideUnassign, err)
+	}
+	return nil
+}
+
+// UnassignGuide removes every schedule assignment of the guide.
+func (r *scheduleGuideRepository) UnassignGuide(ctx context.Context, guideID uint) error {
+	if err := r.db.WithContext(ctx).
+		Where("guide_id = ?", guideID).
+		Delete(&models.ScheduleGuide{}).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideUnassignGuide, err)
+	}
+	return nil
+}
+
+func (r *scheduleGuideRepository) FindSchedulesByGuide(ctx context.Context, guideID uint, from time.Time) ([]models.TourSchedule, error) {
+	var schedules []models.TourSchedule
+	if err := r.db.WithContext(ctx).
+		Preload("Tour").
+		Where("id IN (?)",
+			r.db.Table("schedule_guides").Select("schedule_id").Where("guide_id = ?", guideID),
+		).
+		Where("return_date >= ?", from).
+		Order("departure_date ASC").
+		Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleGuideFindByGuide, err)
+	}
+	return schedules, nil
+}
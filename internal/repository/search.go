@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"strings"
+
+	"sun-booking-tours/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SortRelevance orders full-text search results by ts_rank.
+const SortRelevance = "relevance"
+
+// locationMatchSQL is the tour location filter, shared with the location
+// facet counts: a case-insensitive substring match on locationPattern.
+const locationMatchSQL = "LOWER(location) LIKE ?"
+
+func locationPattern(location string) string {
+	return "%" + strings.ToLower(strings.TrimSpace(location)) + "%"
+}
+
+// fullTextQuery turns a raw user query into a to_tsquery expression over the
+// accent-folded search_vector column, or "" when the query has no words.
+func fullTextQuery(q string) string {
+	return utils.TSQuery(utils.SearchTerms(q))
+}
+
+// matchFullText restricts db to rows whose search_vector matches tsq.
+func matchFullText(db *gorm.DB, tsq string) *gorm.DB {
+	return db.Where("search_vector @@ to_tsquery('simple', ?)", tsq)
+}
+
+// orderByRank sorts by descending relevance for tsq, then by the plain
+// "then" clause.
+func orderByRank(db *gorm.DB, tsq, then string) *gorm.DB {
+	return orderByExpr(db, clause.Expr{
+		SQL:  "ts_rank(search_vector, to_tsquery('simple', ?)) DESC",
+		Vars: []any{tsq},
+	}, then)
+}
+
+// orderByExpr sorts by a parameterised expression, then by the plain "then"
+// clause. GORM's Order ignores clause.Expr values and drops an expression
+// when later Order calls merge into it, so both parts go in one clause.
+func orderByExpr(db *gorm.DB, expr clause.Expr, then string) *gorm.DB {
+	if then != "" {
+		expr.SQL += ", " + then
+	}
+	return db.Clauses(clause.OrderBy{Expression: expr})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SlugHistoryRepo interface {
+	FindByEntity(ctx context.Context, entityType string, entityID uint) ([]models.SlugHistory, error)
+	RecordChange(ctx context.Context, entityType string, entityID uint, oldSlug, newSlug string) error
+}
+
+type slugHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugHistoryRepository(db *gorm.DB) SlugHistoryRepo {
+	return &slugHistoryRepository{db: db}
+}
+
+func (r *slugHistoryRepository) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]models.SlugHistory, error) {
+	var history []models.SlugHistory
+	if err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("updated_at DESC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxSlugHistoryFindByEntity, err)
+	}
+	return history, nil
+}
+
+// RecordChange remembers oldSlug as a former slug of the entity. newSlug is
+// dropped from the history so an entity reverting to an old slug, or taking
+// over one another entity used to have, is served directly.
+func (r *slugHistoryRepository) RecordChange(ctx context.Context, entityType string, entityID uint, oldSlug, newSlug string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entity_type = ? AND slug = ?", entityType, newSlug).
+			Delete(&models.SlugHistory{}).Error; err != nil {
+			return err
+		}
+		if oldSlug == "" || oldSlug == newSlug {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}, {Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"entity_id", "updated_at"}),
+		}).Create(&models.SlugHistory{
+			EntityType: entityType,
+			Slug:       oldSlug,
+			EntityID:   entityID,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxSlugHistoryRecord, err)
+	}
+	return nil
+}
+
+// historicalEntityID returns the entity that last used slug, or
+// gorm.ErrRecordNotFound.
+func historicalEntityID(ctx context.Context, db *gorm.DB, entityType, slug string) (uint, error) {
+	var entry models.SlugHistory
+	if err := db.WithContext(ctx).
+		Where("entity_type = ? AND slug = ?", entityType, slug).
+		First(&entry).Error; err != nil {
+		return 0, err
+	}
+	return entry.EntityID, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,20 +14,35 @@ import (
 )
 
 type TourFilter struct {
-	Status           string
+	Status string
+	// CategoryID and CategorySlugs also match tours filed under descendant
+	// categories. Several slugs match any of them unless CategoryMatch is
+	// CategoryMatchAll.
 	CategoryID       uint
-	CategorySlug     string
+	CategorySlugs    []string
+	CategoryMatch    string
 	Search           string
 	MinPrice         float64
 	MaxPrice         float64
-	Location         string
+	Location         string // case-insensitive substring
 	MinDuration      int
 	MaxDuration      int
+	MinRating        float64
+	NearLat          float64
+	NearLng          float64
+	RadiusKm         float64
+	BBox             *GeoBBox
 	SortBy           string
 	SortOrder        string
 	Page             int
 	Limit            int
 	IncludeSchedules bool
+	IncludeFacets    bool
+	// SavedBy flags the tours this user has in their wishlist; 0 skips it.
+	SavedBy uint
+	// Locale translates the results and facet labels; empty keeps the
+	// default-locale text.
+	Locale string
 }
 
 type TourRepo interface {
@@ -38,14 +54,27 @@ type TourRepo interface {
 	ExistsBySlug(ctx context.Context, slug string) (bool, error)
 	ExistsBySlugExcluding(ctx context.Context, slug string, excludeID uint) (bool, error)
 	Create(ctx context.Context, tour *models.Tour) error
+	CreateClone(ctx context.Context, tour *models.Tour) error
 	Update(ctx context.Context, tour *models.Tour) error
 	Delete(ctx context.Context, id uint) error
 	HasActiveBookings(ctx context.Context, tourID uint) (bool, error)
+	CountBookedBySchedule(ctx context.Context, tourID uint) (map[uint]int, error)
 	ReplaceCategories(ctx context.Context, tour *models.Tour, categories []models.Category) error
+	AddCategories(ctx context.Context, tourIDs, categoryIDs []uint) (int64, error)
+	RemoveCategories(ctx context.Context, tourIDs, categoryIDs []uint) (int64, error)
+	ReplaceItinerary(ctx context.Context, tourID uint, days []models.TourItineraryDay) error
+	// CountImageReferences counts tours, trashed ones included, whose images
+	// contain url.
+	CountImageReferences(ctx context.Context, url string) (int64, error)
+	ReplaceDetails(ctx context.Context, tourID uint, details TourDetails) error
+	ReplaceTranslations(ctx context.Context, tourID uint, translations []models.TourTranslation, itinerary []models.TourItineraryTranslation) error
 	CountRatingsByTourID(ctx context.Context, tourID uint) (int64, error)
-	UpdateAvgRating(ctx context.Context, tourID uint, avg float64) error
+	UpdateRatingStats(ctx context.Context, tourID uint, avg float64, count int64) error
 	FindFeatured(ctx context.Context, limit int) ([]models.Tour, error)
 	FindLatest(ctx context.Context, limit int) ([]models.Tour, error)
+	FindForExport(ctx context.Context) ([]models.Tour, error)
+	CountFacets(ctx context.Context, filter TourFilter) (*TourFacetCounts, error)
+	FindPins(ctx context.Context, filter TourFilter, limit int) ([]models.Tour, error)
 }
 
 type tourRepository struct {
@@ -56,44 +85,16 @@ func NewTourRepository(db *gorm.DB) TourRepo {
 	return &tourRepository{db: db}
 }
 
-func (r *tourRepository) FindAll(ctx context.Context, filter TourFilter) ([]models.Tour, int64, error) {
-	query := r.db.WithContext(ctx).Model(&models.Tour{})
+// bayesianRatingSQL ranks tours by their average rating pulled towards the
+// site-wide average by constants.RatingPriorWeight phantom ratings, so a
+// single 5-star review does not outrank hundreds of 4.8s.
+var bayesianRatingSQL = fmt.Sprintf(
+	"(avg_rating * rating_count + %[1]d * (SELECT COALESCE(AVG(score), 0) FROM ratings)) / (rating_count + %[1]d)",
+	constants.RatingPriorWeight)
 
-	if filter.Status != "" {
-		query = query.Where("status = ?", filter.Status)
-	}
-	if filter.CategorySlug != "" {
-		query = query.Where("id IN (?)",
-			r.db.Table("tour_categories").Select("tour_id").
-				Where("category_id IN (?)",
-					r.db.Table("categories").Select("id").Where("slug = ?", filter.CategorySlug),
-				),
-		)
-	}
-	if filter.CategoryID > 0 {
-		query = query.Where("id IN (?)",
-			r.db.Table("tour_categories").Select("tour_id").Where("category_id = ?", filter.CategoryID),
-		)
-	}
-	if filter.Search != "" {
-		like := "%" + filter.Search + "%"
-		query = query.Where("title ILIKE ? OR description ILIKE ? OR location ILIKE ?", like, like, like)
-	}
-	if filter.MinPrice > 0 {
-		query = query.Where("price >= ?", filter.MinPrice)
-	}
-	if filter.MaxPrice > 0 {
-		query = query.Where("price <= ?", filter.MaxPrice)
-	}
-	if filter.Location != "" {
-		query = query.Where("location ILIKE ?", "%"+filter.Location+"%")
-	}
-	if filter.MinDuration > 0 {
-		query = query.Where("duration_days >= ?", filter.MinDuration)
-	}
-	if filter.MaxDuration > 0 {
-		query = query.Where("duration_days <= ?", filter.MaxDuration)
-	}
+func (r *tourRepository) FindAll(ctx context.Context, filter TourFilter) ([]models.Tour, int64, error) {
+	query := r.filteredQuery(ctx, filter)
+	tsq := fullTextQuery(filter.Search)
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -112,8 +113,10 @@ func (r *tourRepository) FindAll(ctx context.Context, filter TourFilter) ([]mode
 
 	sortCol := "created_at"
 	switch filter.SortBy {
-	case "price", "avg_rating", "duration_days":
+	case "price", "duration_days":
 		sortCol = filter.SortBy
+	case "avg_rating":
+		sortCol = bayesianRatingSQL
 	}
 	sortDir := "DESC"
 	if filter.SortOrder == "asc" {
@@ -121,7 +124,8 @@ func (r *tourRepository) FindAll(ctx context.Context, filter TourFilter) ([]mode
 	}
 
 	findQuery := query.
-		Preload("Categories")
+		Preload("Categories").
+		Scopes(preloadTranslations)
 	if filter.IncludeSchedules {
 		now := time.Now()
 		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
@@ -131,16 +135,80 @@ func (r *tourRepository) FindAll(ctx context.Context, filter TourFilter) ([]mode
 				Order("departure_date ASC")
 		})
 	}
+	order := sortCol + " " + sortDir
+	switch {
+	case tsq != "" && (filter.SortBy == "" || filter.SortBy == SortRelevance):
+		findQuery = orderByRank(findQuery, tsq, order)
+	case filter.RadiusKm > 0 && filter.SortBy == SortDistance:
+		findQuery = orderByDistance(findQuery, filter.NearLat, filter.NearLng, order)
+	default:
+		findQuery = findQuery.Order(order)
+	}
 	if err := findQuery.
-		Order(sortCol + " " + sortDir).
 		Limit(filter.Limit).
 		Offset(offset).
 		Find(&tours).Error; err != nil {
 		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTourFindAll, err)
 	}
+	if err := markSaved(ctx, r.db, tours, filter.SavedBy); err != nil {
+		return nil, 0, err
+	}
 	return tours, total, nil
 }
 
+// FindPins returns the map-relevant columns of located tours matching
+// filter, best rated first.
+func (r *tourRepository) FindPins(ctx context.Context, filter TourFilter, limit int) ([]models.Tour, error) {
+	var tours []models.Tour
+	if err := r.filteredQuery(ctx, filter).
+		Select("id, title, slug, price, location, avg_rating, images, latitude, longitude, meeting_latitude, meeting_longitude").
+		Where("latitude IS NOT NULL AND longitude IS NOT NULL").
+		Order("avg_rating DESC, id ASC").
+		Limit(limit).
+		Find(&tours).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourFindPins, err)
+	}
+	return tours, nil
+}
+
+// filteredQuery applies every TourFilter condition except paging and sorting.
+func (r *tourRepository) filteredQuery(ctx context.Context, filter TourFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Tour{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	query = filterByCategories(r.db, query, filter)
+	if tsq := fullTextQuery(filter.Search); tsq != "" {
+		query = matchFullText(query, tsq)
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("price <= ?", filter.MaxPrice)
+	}
+	if filter.Location != "" {
+		query = query.Where(locationMatchSQL, locationPattern(filter.Location))
+	}
+	if filter.MinDuration > 0 {
+		query = query.Where("duration_days >= ?", filter.MinDuration)
+	}
+	if filter.MaxDuration > 0 {
+		query = query.Where("duration_days <= ?", filter.MaxDuration)
+	}
+	if filter.MinRating > 0 {
+		query = query.Where("avg_rating >= ?", filter.MinRating)
+	}
+	if filter.RadiusKm > 0 {
+		query = withinRadius(query, filter.NearLat, filter.NearLng, filter.RadiusKm)
+	}
+	if filter.BBox != nil {
+		query = withinBBox(query, *filter.BBox)
+	}
+	return query
+}
+
 func (r *tourRepository) FindByID(ctx context.Context, id uint) (*models.Tour, error) {
 	var tour models.Tour
 	if err := r.db.WithContext(ctx).
@@ -148,6 +216,11 @@ func (r *tourRepository) FindByID(ctx context.Context, id uint) (*models.Tour, e
 		Preload("Schedules", func(db *gorm.DB) *gorm.DB {
 			return db.Order("departure_date ASC")
 		}).
+		Preload("Itinerary", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_number ASC")
+		}).
+		Scopes(preloadDetails, preloadTranslations).
+		Preload("ItineraryTranslations").
 		First(&tour, id).Error; err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourFindByID, err)
 	}
@@ -165,20 +238,27 @@ func (r *tourRepository) FindBySlug(ctx context.Context, slug string) (*models.T
 	return &tour, nil
 }
 
+// ExistsBySlug reports whether slug is taken by a tour in any locale.
+// Translated slugs of trashed tours count too: unlike the live-only
+// default-locale index, the translation slug index spans the trash.
 func (r *tourRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Tour{}).
-		Where("slug = ?", slug).
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Tour{}).
+		Where("(slug = ? AND deleted_at IS NULL) OR id IN (?)", slug,
+			r.db.Model(&models.TourTranslation{}).Select("tour_id").Where("slug = ?", slug)).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCheckSlugExists, err)
 	}
 	return count > 0, nil
 }
 
+// ExistsBySlugExcluding reports whether a tour other than excludeID uses
+// slug in any locale, counting translations as ExistsBySlug does.
 func (r *tourRepository) ExistsBySlugExcluding(ctx context.Context, slug string, excludeID uint) (bool, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Tour{}).
-		Where("slug = ? AND id != ?", slug, excludeID).
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Tour{}).
+		Where("((slug = ? AND deleted_at IS NULL) OR id IN (?)) AND id != ?", slug,
+			r.db.Model(&models.TourTranslation{}).Select("tour_id").Where("slug = ?", slug), excludeID).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCheckSlugExcluding, err)
 	}
@@ -192,6 +272,16 @@ func (r *tourRepository) Create(ctx context.Context, tour *models.Tour) error {
 	return nil
 }
 
+// CreateClone inserts a copied tour with every child row set on it in one
+// statement group, so a failure leaves nothing behind. Categories must
+// carry IDs only; they are linked, never written.
+func (r *tourRepository) CreateClone(ctx context.Context, tour *models.Tour) error {
+	if err := r.db.WithContext(ctx).Omit("Categories.*").Create(tour).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourCreateClone, err)
+	}
+	return nil
+}
+
 func (r *tourRepository) Update(ctx context.Context, tour *models.Tour) error {
 	if err := r.db.WithContext(ctx).Save(tour).Error; err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourUpdate, err)
@@ -216,6 +306,27 @@ func (r *tourRepository) HasActiveBookings(ctx context.Context, tourID uint) (bo
 	return count > 0, nil
 }
 
+// CountBookedBySchedule sums the participants of pending and confirmed
+// bookings per schedule of the tour, i.e. the seats taken from each.
+func (r *tourRepository) CountBookedBySchedule(ctx context.Context, tourID uint) (map[uint]int, error) {
+	var rows []struct {
+		ScheduleID uint
+		Booked     int
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Booking{}).
+		Select("schedule_id, SUM(num_participants) AS booked").
+		Where("tour_id = ? AND status IN ?", tourID, []string{constants.BookingStatusPending, constants.BookingStatusConfirmed}).
+		Group("schedule_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountBooked, err)
+	}
+	booked := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		booked[row.ScheduleID] = row.Booked
+	}
+	return booked, nil
+}
+
 func (r *tourRepository) ReplaceCategories(ctx context.Context, tour *models.Tour, categories []models.Category) error {
 	if err := r.db.WithContext(ctx).Model(tour).Association("Categories").Replace(categories); err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourReplaceCategories, err)
@@ -223,40 +334,222 @@ func (r *tourRepository) ReplaceCategories(ctx context.Context, tour *models.Tou
 	return nil
 }
 
+// AddCategories links every live tour in tourIDs to every live category in
+// categoryIDs and returns how many links were new.
+func (r *tourRepository) AddCategories(ctx context.Context, tourIDs, categoryIDs []uint) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`INSERT INTO tour_categories (tour_id, category_id)
+		SELECT t.id, c.id FROM tours t CROSS JOIN categories c
+		WHERE t.id IN ? AND t.deleted_at IS NULL AND c.id IN ? AND c.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM tour_categories tc WHERE tc.tour_id = t.id AND tc.category_id = c.id)`,
+		tourIDs, categoryIDs)
+	if result.Error != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTourAddCategories, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RemoveCategories unlinks the tours from the categories and returns how
+// many links went.
+func (r *tourRepository) RemoveCategories(ctx context.Context, tourIDs, categoryIDs []uint) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Exec("DELETE FROM tour_categories WHERE tour_id IN ? AND category_id IN ?", tourIDs, categoryIDs)
+	if result.Error != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTourRemoveCategories, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *tourRepository) CountImageReferences(ctx context.Context, url string) (int64, error) {
+	count, err := countImageReferences(r.db.WithContext(ctx).Unscoped().Model(&models.Tour{}), url)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountImageRefs, err)
+	}
+	return count, nil
+}
+
+// ReplaceItinerary swaps the tour's itinerary for the given days, numbering
+// them in slice order.
+func (r *tourRepository) ReplaceItinerary(ctx context.Context, tourID uint, days []models.TourItineraryDay) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tour_id = ?", tourID).Delete(&models.TourItineraryDay{}).Error; err != nil {
+			return err
+		}
+		if len(days) == 0 {
+			return nil
+		}
+		for i := range days {
+			days[i].ID = 0
+			days[i].TourID = tourID
+			days[i].DayNumber = i + 1
+		}
+		return tx.Create(&days).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourReplaceItinerary, err)
+	}
+	return nil
+}
+
+// TourDetails are the structured child collections edited with a tour.
+type TourDetails struct {
+	Inclusions    []models.TourInclusion
+	MeetingPoints []models.TourMeetingPoint
+	FAQs          []models.TourFAQ
+}
+
+// ReplaceDetails swaps the tour's inclusions, meeting points and FAQs for
+// the given ones, numbering each collection in slice order.
+func (r *tourRepository) ReplaceDetails(ctx context.Context, tourID uint, details TourDetails) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&models.TourInclusion{}, &models.TourMeetingPoint{}, &models.TourFAQ{}} {
+			if err := tx.Where("tour_id = ?", tourID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if len(details.Inclusions) > 0 {
+			for i := range details.Inclusions {
+				details.Inclusions[i].ID = 0
+				details.Inclusions[i].TourID = tourID
+				details.Inclusions[i].Position = i + 1
+			}
+			if err := tx.Create(&details.Inclusions).Error; err != nil {
+				return err
+			}
+		}
+		if len(details.MeetingPoints) > 0 {
+			for i := range details.MeetingPoints {
+				details.MeetingPoints[i].ID = 0
+				details.MeetingPoints[i].TourID = tourID
+				details.MeetingPoints[i].Position = i + 1
+			}
+			if err := tx.Create(&details.MeetingPoints).Error; err != nil {
+				return err
+			}
+		}
+		if len(details.FAQs) > 0 {
+			for i := range details.FAQs {
+				details.FAQs[i].ID = 0
+				details.FAQs[i].TourID = tourID
+				details.FAQs[i].Position = i + 1
+			}
+			if err := tx.Create(&details.FAQs).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourReplaceDetails, err)
+	}
+	return nil
+}
+
+// ReplaceTranslations swaps the tour's translations and itinerary
+// translations for the given ones.
+func (r *tourRepository) ReplaceTranslations(ctx context.Context, tourID uint, translations []models.TourTranslation, itinerary []models.TourItineraryTranslation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&models.TourTranslation{}, &models.TourItineraryTranslation{}} {
+			if err := tx.Where("tour_id = ?", tourID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if len(translations) > 0 {
+			for i := range translations {
+				translations[i].ID = 0
+				translations[i].TourID = tourID
+			}
+			if err := tx.Create(&translations).Error; err != nil {
+				return err
+			}
+		}
+		if len(itinerary) > 0 {
+			for i := range itinerary {
+				itinerary[i].ID = 0
+				itinerary[i].TourID = tourID
+			}
+			if err := tx.Create(&itinerary).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourReplaceTranslations, err)
+	}
+	return nil
+}
+
+// FindBySlugPublic looks the slug up among current slugs first, then among
+// translated slugs and then in the slug history, so renamed tours stay
+// reachable in every locale. Callers detect a non-canonical slug by
+// comparing the localized tour's Slug with the one requested.
 func (r *tourRepository) FindBySlugPublic(ctx context.Context, slug string) (*models.Tour, error) {
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	var tour models.Tour
-	if err := r.db.WithContext(ctx).
-		Preload("Categories").
-		Preload("Schedules", func(db *gorm.DB) *gorm.DB {
-			return db.
-				Where("status = ? AND departure_date >= ?", constants.ScheduleStatusOpen, startOfDay).
-				Order("departure_date ASC")
-		}).
-		Where("slug = ? AND status = ?", slug, constants.TourStatusActive).
-		First(&tour).Error; err != nil {
+	err := r.publicTourQuery(ctx).
+		Where("slug = ? OR id IN (?)", slug,
+			r.db.Model(&models.TourTranslation{}).Select("tour_id").Where("slug = ?", slug)).
+		First(&tour).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		id, hErr := historicalEntityID(ctx, r.db, constants.SlugEntityTour, slug)
+		if hErr != nil {
+			if errors.Is(hErr, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxPublicTourFindBySlug, err)
+			}
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxSlugHistoryFind, hErr)
+		}
+		return r.FindByIDPublic(ctx, id)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxPublicTourFindBySlug, err)
 	}
 	return &tour, nil
 }
 
 func (r *tourRepository) FindByIDPublic(ctx context.Context, id uint) (*models.Tour, error) {
+	var tour models.Tour
+	if err := r.publicTourQuery(ctx).
+		First(&tour, id).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxPublicTourFindByID, err)
+	}
+	return &tour, nil
+}
+
+// preloadDetails loads the inclusions, meeting points and FAQs in order.
+func preloadDetails(db *gorm.DB) *gorm.DB {
+	byPosition := func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }
+	return db.
+		Preload("Inclusions", byPosition).
+		Preload("MeetingPoints", byPosition).
+		Preload("FAQs", byPosition)
+}
+
+// preloadTranslations loads the tour's and its categories' translations,
+// which listings need to localize titles, slugs and category names.
+func preloadTranslations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Translations").
+		Preload("Categories.Translations")
+}
+
+// publicTourQuery scopes to active tours and preloads what the detail page
+// shows: categories, upcoming open schedules, the itinerary, the structured
+// details and all translations.
+func (r *tourRepository) publicTourQuery(ctx context.Context) *gorm.DB {
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	var tour models.Tour
-	if err := r.db.WithContext(ctx).
+	return r.db.WithContext(ctx).
 		Preload("Categories").
 		Preload("Schedules", func(db *gorm.DB) *gorm.DB {
 			return db.
 				Where("status = ? AND departure_date >= ?", constants.ScheduleStatusOpen, startOfDay).
 				Order("departure_date ASC")
 		}).
-		Where("status = ?", constants.TourStatusActive).
-		First(&tour, id).Error; err != nil {
-		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxPublicTourFindByID, err)
-	}
-	return &tour, nil
+		Preload("Itinerary", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_number ASC")
+		}).
+		Scopes(preloadDetails, preloadTranslations).
+		Preload("ItineraryTranslations").
+		Where("status = ?", constants.TourStatusActive)
 }
 
 func (r *tourRepository) CountRatingsByTourID(ctx context.Context, tourID uint) (int64, error) {
@@ -269,11 +562,11 @@ func (r *tourRepository) CountRatingsByTourID(ctx context.Context, tourID uint)
 	return count, nil
 }
 
-func (r *tourRepository) UpdateAvgRating(ctx context.Context, tourID uint, avg float64) error {
+func (r *tourRepository) UpdateRatingStats(ctx context.Context, tourID uint, avg float64, count int64) error {
 	if err := r.db.WithContext(ctx).
 		Model(&models.Tour{}).
 		Where("id = ?", tourID).
-		Update("avg_rating", avg).Error; err != nil {
+		Updates(map[string]any{"avg_rating": avg, "rating_count": count}).Error; err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingUpdateTourAvg, err)
 	}
 	return nil
@@ -282,6 +575,7 @@ func (r *tourRepository) UpdateAvgRating(ctx context.Context, tourID uint, avg f
 func (r *tourRepository) FindFeatured(ctx context.Context, limit int) ([]models.Tour, error) {
 	var tours []models.Tour
 	if err := r.db.WithContext(ctx).
+		Preload("Translations").
 		Where("status = ?", constants.TourStatusActive).
 		Order("avg_rating DESC, created_at DESC").
 		Limit(limit).
@@ -294,6 +588,7 @@ func (r *tourRepository) FindFeatured(ctx context.Context, limit int) ([]models.
 func (r *tourRepository) FindLatest(ctx context.Context, limit int) ([]models.Tour, error) {
 	var tours []models.Tour
 	if err := r.db.WithContext(ctx).
+		Preload("Translations").
 		Where("status = ?", constants.TourStatusActive).
 		Order("created_at DESC").
 		Limit(limit).
@@ -302,3 +597,27 @@ func (r *tourRepository) FindLatest(ctx context.Context, limit int) ([]models.To
 	}
 	return tours, nil
 }
+
+// FindForExport loads every tour in any status with its categories,
+// schedules, itinerary, details and translations, oldest first.
+func (r *tourRepository) FindForExport(ctx context.Context) ([]models.Tour, error) {
+	var tours []models.Tour
+	if err := r.db.WithContext(ctx).
+		Preload("Categories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("categories.id ASC")
+		}).
+		Preload("Schedules", func(db *gorm.DB) *gorm.DB {
+			return db.Order("departure_date ASC, id ASC")
+		}).
+		Preload("Itinerary", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_number ASC")
+		}).
+		Scopes(preloadDetails).
+		Preload("Translations").
+		Preload("ItineraryTranslations").
+		Order("id ASC").
+		Find(&tours).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourFindForExport, err)
+	}
+	return tours, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// CategoryMatchAll keeps only tours in every selected category; any other
+// TourFilter.CategoryMatch keeps tours in at least one of them.
+const CategoryMatchAll = "all"
+
+// categoriesWithSlugs selects the categories whose default or translated
+// slug is one of slugs.
+func categoriesWithSlugs(db *gorm.DB, slugs []string) *gorm.DB {
+	return db.Table("categories").
+		Where("(slug IN ? OR id IN (?))", slugs,
+			db.Table("category_translations").Select("category_id").Where("slug IN ?", slugs))
+}
+
+// categoryTourIDs selects the tours linked to roots, a query on categories,
+// or to any live category below them.
+func categoryTourIDs(db, roots *gorm.DB) *gorm.DB {
+	paths := roots.Select("path").Where("deleted_at IS NULL AND path <> ''")
+	subtree := db.Table("categories AS c").Select("c.id").
+		Joins("JOIN (?) AS roots ON c.path LIKE roots.path || '%'", paths).
+		Where("c.deleted_at IS NULL")
+	return db.Table("tour_categories").Select("tour_id").Where("category_id IN (?)", subtree)
+}
+
+// filterByCategories narrows query to the tours in the selected categories,
+// descendants included.
+func filterByCategories(db, query *gorm.DB, filter TourFilter) *gorm.DB {
+	if len(filter.CategorySlugs) > 0 {
+		if filter.CategoryMatch == CategoryMatchAll {
+			for _, slug := range filter.CategorySlugs {
+				query = query.Where("id IN (?)", categoryTourIDs(db, categoriesWithSlugs(db, []string{slug})))
+			}
+		} else {
+			query = query.Where("id IN (?)", categoryTourIDs(db, categoriesWithSlugs(db, filter.CategorySlugs)))
+		}
+	}
+	if filter.CategoryID > 0 {
+		query = query.Where("id IN (?)", categoryTourIDs(db, db.Table("categories").Where("id = ?", filter.CategoryID)))
+	}
+	return query
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appErrors "sun-booking-tours/internal/errors"
+
+	"gorm.io/gorm"
+)
+
+// maxLocationFacets caps how many distinct locations are counted.
+const maxLocationFacets = 8
+
+// FacetRange is an inclusive [Min, Max] bucket, or [Min, Max) when HalfOpen
+// is set so that decimal values cannot fall between adjacent buckets. Max 0
+// means unbounded.
+type FacetRange struct {
+	Min      float64
+	Max      float64
+	HalfOpen bool
+}
+
+var (
+	// TourPriceRanges are the price buckets (VND) offered as filter chips.
+	// They are counted half-open so that a price on a boundary, or a
+	// decimal one just below it, is counted in exactly one bucket.
+	TourPriceRanges = []FacetRange{
+		{Min: 0, Max: 2_000_000, HalfOpen: true},
+		{Min: 2_000_000, Max: 5_000_000, HalfOpen: true},
+		{Min: 5_000_000, Max: 10_000_000, HalfOpen: true},
+		{Min: 10_000_000, HalfOpen: true},
+	}
+	// TourDurationRanges are the duration buckets (days).
+	TourDurationRanges = []FacetRange{
+		{Min: 1, Max: 1},
+		{Min: 2, Max: 3},
+		{Min: 4, Max: 7},
+		{Min: 8},
+	}
+	// TourRatingBands are the minimum average ratings offered as chips.
+	TourRatingBands = []float64{4.5, 4, 3}
+)
+
+// LocationCount is the number of matching tours at one location.
+type LocationCount struct {
+	Location string
+	Count    int64
+}
+
+// TourFacetCounts holds per-option result counts for the tours listing.
+// Each dimension is counted with every other active filter applied but its
+// own filter cleared, so a count is the number of results that option would
+// give. A category counts the tours filed under it or any descendant.
+// Prices, Durations and Ratings line up with TourPriceRanges,
+// TourDurationRanges and TourRatingBands.
+type TourFacetCounts struct {
+	Categories map[uint]int64
+	Locations  []LocationCount
+	Prices     []int64
+	Durations  []int64
+	Ratings    []int64
+}
+
+func (r *tourRepository) CountFacets(ctx context.Context, filter TourFilter) (*TourFacetCounts, error) {
+	counts := &TourFacetCounts{Categories: make(map[uint]int64)}
+
+	f := filter
+	f.CategoryID, f.CategorySlugs = 0, nil
+	var cats []struct {
+		CategoryID uint
+		Count      int64
+	}
+	if err := r.db.WithContext(ctx).Table("categories AS root").
+		Select("root.id AS category_id, COUNT(DISTINCT tc.tour_id) AS count").
+		Joins("JOIN categories AS c ON c.path LIKE root.path || '%' AND c.deleted_at IS NULL").
+		Joins("JOIN tour_categories AS tc ON tc.category_id = c.id").
+		Where("root.deleted_at IS NULL AND root.path <> ''").
+		Where("tc.tour_id IN (?)", r.filteredQuery(ctx, f).Select("id")).
+		Group("root.id").
+		Scan(&cats).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountFacets, err)
+	}
+	for _, c := range cats {
+		counts.Categories[c.CategoryID] = c.Count
+	}
+
+	f = filter
+	f.Location = ""
+	locations, err := countLocations(r.filteredQuery(ctx, f), r.filteredQuery(ctx, f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountFacets, err)
+	}
+	counts.Locations = locations
+
+	f = filter
+	f.MinPrice, f.MaxPrice = 0, 0
+	prices, err := countRanges(r.filteredQuery(ctx, f), "price", TourPriceRanges)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountFacets, err)
+	}
+	counts.Prices = prices
+
+	f = filter
+	f.MinDuration, f.MaxDuration = 0, 0
+	durations, err := countRanges(r.filteredQuery(ctx, f), "duration_days", TourDurationRanges)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountFacets, err)
+	}
+	counts.Durations = durations
+
+	f = filter
+	f.MinRating = 0
+	bands := make([]FacetRange, len(TourRatingBands))
+	for i, rating := range TourRatingBands {
+		bands[i] = FacetRange{Min: rating}
+	}
+	ratings, err := countRanges(r.filteredQuery(ctx, f), "avg_rating", bands)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourCountFacets, err)
+	}
+	counts.Ratings = ratings
+
+	return counts, nil
+}
+
+// countLocations picks the most common locations of query, folding case,
+// then counts each one with the same substring match the location filter
+// uses, so an option's count is what selecting it returns. list and count
+// must be two fresh copies of the same query.
+func countLocations(list, count *gorm.DB) ([]LocationCount, error) {
+	var names []string
+	if err := list.
+		Select("MIN(location)").
+		Where("location <> ''").
+		Group("LOWER(location)").
+		Order("COUNT(*) DESC, MIN(location) ASC").
+		Limit(maxLocationFacets).
+		Scan(&names).Error; err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	cols := make([]string, len(names))
+	args := make([]any, len(names))
+	for i, name := range names {
+		cols[i] = fmt.Sprintf("COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0)", locationMatchSQL)
+		args[i] = locationPattern(name)
+	}
+	out := make([]int64, len(names))
+	dest := make([]any, len(names))
+	for i := range out {
+		dest[i] = &out[i]
+	}
+	if err := count.Select(strings.Join(cols, ", "), args...).Row().Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	locations := make([]LocationCount, len(names))
+	for i, name := range names {
+		locations[i] = LocationCount{Location: name, Count: out[i]}
+	}
+	sort.SliceStable(locations, func(i, j int) bool { return locations[i].Count > locations[j].Count })
+	return locations, nil
+}
+
+// countRanges counts the rows of query falling into each range of column
+// with a single conditional-aggregate query.
+func countRanges(query *gorm.DB, column string, ranges []FacetRange) ([]int64, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	cols := make([]string, len(ranges))
+	var args []any
+	for i, rg := range ranges {
+		cond := column + " >= ?"
+		args = append(args, rg.Min)
+		if rg.Max > 0 {
+			op := " <= ?"
+			if rg.HalfOpen {
+				op = " < ?"
+			}
+			cond += " AND " + column + op
+			args = append(args, rg.Max)
+		}
+		cols[i] = fmt.Sprintf("COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0)", cond)
+	}
+
+	out := make([]int64, len(ranges))
+	dest := make([]any, len(ranges))
+	for i := range out {
+		dest[i] = &out[i]
+	}
+	if err := query.Select(strings.Join(cols, ", "), args...).Row().Scan(dest...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
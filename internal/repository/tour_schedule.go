@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
@@ -18,6 +19,9 @@ type ScheduleRepo interface {
 	Update(ctx context.Context, schedule *models.TourSchedule) error
 	Delete(ctx context.Context, id uint) error
 	HasBookings(ctx context.Context, scheduleID uint) (bool, error)
+	CountConfirmedParticipants(ctx context.Context, scheduleID uint) (int, error)
+	SetGuaranteed(ctx context.Context, id uint, guaranteed bool) error
+	FindDueForCutoff(ctx context.Context, from, deadline time.Time) ([]models.TourSchedule, error)
 }
 
 type scheduleRepository struct {
@@ -31,6 +35,7 @@ func NewScheduleRepository(db *gorm.DB) ScheduleRepo {
 func (r *scheduleRepository) FindByTourID(ctx context.Context, tourID uint) ([]models.TourSchedule, error) {
 	var schedules []models.TourSchedule
 	if err := r.db.WithContext(ctx).
+		Preload("Guides.Guide").
 		Where("tour_id = ?", tourID).
 		Order("departure_date ASC").
 		Find(&schedules).Error; err != nil {
@@ -79,3 +84,43 @@ func (r *scheduleRepository) HasBookings(ctx context.Context, scheduleID uint) (
 	}
 	return count > 0, nil
 }
+
+// CountConfirmedParticipants sums the travellers of confirmed and completed
+// bookings on a schedule.
+func (r *scheduleRepository) CountConfirmedParticipants(ctx context.Context, scheduleID uint) (int, error) {
+	var total int
+	if err := r.db.WithContext(ctx).Model(&models.Booking{}).
+		Select("COALESCE(SUM(num_participants), 0)").
+		Where("schedule_id = ? AND status IN ?", scheduleID, []string{constants.BookingStatusConfirmed, constants.BookingStatusCompleted}).
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleCountConfirmed, err)
+	}
+	return total, nil
+}
+
+func (r *scheduleRepository) SetGuaranteed(ctx context.Context, id uint, guaranteed bool) error {
+	if err := r.db.WithContext(ctx).Model(&models.TourSchedule{}).
+		Where("id = ?", id).
+		Update("guaranteed", guaranteed).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleSetGuaranteed, err)
+	}
+	return nil
+}
+
+// FindDueForCutoff returns bookable, not yet guaranteed schedules of tours
+// with a participant minimum that depart between from and deadline.
+func (r *scheduleRepository) FindDueForCutoff(ctx context.Context, from, deadline time.Time) ([]models.TourSchedule, error) {
+	var schedules []models.TourSchedule
+	if err := r.db.WithContext(ctx).
+		Preload("Tour").
+		Joins("JOIN tours ON tours.id = tour_schedules.tour_id AND tours.deleted_at IS NULL").
+		Where("tours.min_participants > 0").
+		Where("tour_schedules.guaranteed = ?", false).
+		Where("tour_schedules.status IN ?", []string{constants.ScheduleStatusOpen, constants.ScheduleStatusFull}).
+		Where("tour_schedules.departure_date >= ? AND tour_schedules.departure_date <= ?", from, deadline).
+		Order("tour_schedules.departure_date ASC").
+		Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleFindDueForCutoff, err)
+	}
+	return schedules, nil
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TrashRepo reads and recovers soft-deleted tours, categories and reviews,
+// and removes them for good.
+type TrashRepo interface {
+	FindTours(ctx context.Context, page, limit int) ([]models.Tour, int64, error)
+	FindCategories(ctx context.Context, page, limit int) ([]models.Category, int64, error)
+	FindReviews(ctx context.Context, page, limit int) ([]models.Review, int64, error)
+	FindTour(ctx context.Context, id uint) (*models.Tour, error)
+	FindCategory(ctx context.Context, id uint) (*models.Category, error)
+	FindReview(ctx context.Context, id uint) (*models.Review, error)
+	RestoreTour(ctx context.Context, tour *models.Tour) error
+	RestoreCategory(ctx context.Context, cat *models.Category) error
+	RestoreReview(ctx context.Context, id uint) error
+	TourHasBookings(ctx context.Context, id uint) (bool, error)
+	PurgeTour(ctx context.Context, id uint) error
+	PurgeCategory(ctx context.Context, id uint) error
+	PurgeReview(ctx context.Context, id uint) error
+	ExpiredIDs(ctx context.Context, kind string, before time.Time) ([]uint, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepo {
+	return &trashRepository{db: db}
+}
+
+// trashed limits a query to soft-deleted rows, most recently deleted first.
+func trashed(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC")
+}
+
+func (r *trashRepository) FindTours(ctx context.Context, page, limit int) ([]models.Tour, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Tour{}).Unscoped().
+		Where("deleted_at IS NOT NULL").Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFind, err)
+	}
+	var tours []models.Tour
+	if err := r.db.WithContext(ctx).Scopes(trashed).
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&tours).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFind, err)
+	}
+	return tours, total, nil
+}
+
+func (r *trashRepository) FindCategories(ctx context.Context, page, limit int) ([]models.Category, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Unscoped().
+		Where("deleted_at IS NOT NULL").Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFind, err)
+	}
+	var cats []models.Category
+	if err := r.db.WithContext(ctx).Scopes(trashed).
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&cats).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFind, err)
+	}
+	return cats, total, nil
+}
+
+func (r *trashRepository) FindReviews(ctx context.Context, page, limit int) ([]models.Review, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Review{}).Unscoped().
+		Where("deleted_at IS NOT NULL").Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFind, err)
+	}
+	var reviews []models.Review
+	if err := r.db.WithContext(ctx).Scopes(trashed).
+		Preload("User").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFind, err)
+	}
+	return reviews, total, nil
+}
+
+func (r *trashRepository) FindTour(ctx context.Context, id uint) (*models.Tour, error) {
+	var tour models.Tour
+	if err := r.db.WithContext(ctx).Scopes(trashed).
+		Preload("Translations").
+		First(&tour, id).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFindByID, err)
+	}
+	return &tour, nil
+}
+
+func (r *trashRepository) FindCategory(ctx context.Context, id uint) (*models.Category, error) {
+	var cat models.Category
+	if err := r.db.WithContext(ctx).Scopes(trashed).
+		Preload("Translations").
+		First(&cat, id).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFindByID, err)
+	}
+	return &cat, nil
+}
+
+func (r *trashRepository) FindReview(ctx context.Context, id uint) (*models.Review, error) {
+	var review models.Review
+	if err := r.db.WithContext(ctx).Scopes(trashed).
+		First(&review, id).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashFindByID, err)
+	}
+	return &review, nil
+}
+
+// RestoreTour undeletes the tour with its Slug and translation slugs, which
+// the caller may have changed to avoid clashing with live tours.
+func (r *trashRepository) RestoreTour(ctx context.Context, tour *models.Tour) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tr := range tour.Translations {
+			if err := tx.Model(&models.TourTranslation{}).Where("id = ?", tr.ID).
+				Update("slug", tr.Slug).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Model(&models.Tour{}).Where("id = ?", tour.ID).
+			Updates(map[string]any{"slug": tour.Slug, "deleted_at": nil}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTrashRestore, err)
+	}
+	return nil
+}
+
+// RestoreCategory undeletes the category with its Slug and translation
+// slugs, which the caller may have changed.
+func (r *trashRepository) RestoreCategory(ctx context.Context, cat *models.Category) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tr := range cat.Translations {
+			if err := tx.Model(&models.CategoryTranslation{}).Where("id = ?", tr.ID).
+				Update("slug", tr.Slug).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Model(&models.Category{}).Where("id = ?", cat.ID).
+			Updates(map[string]any{"slug": cat.Slug, "deleted_at": nil}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTrashRestore, err)
+	}
+	return nil
+}
+
+func (r *trashRepository) RestoreReview(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Review{}).Where("id = ?", id).
+		Update("deleted_at", nil).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTrashRestore, err)
+	}
+	return nil
+}
+
+// TourHasBookings reports whether any booking, in any status, refers to the
+// tour. Such tours are kept so booking history stays intact.
+func (r *trashRepository) TourHasBookings(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Booking{}).
+		Where("tour_id = ?", id).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashHasBookings, err)
+	}
+	return count > 0, nil
+}
+
+// PurgeTour hard-deletes the tour and every row that belongs to it. The
+// caller makes sure it has no bookings.
+func (r *trashRepository) PurgeTour(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		schedules := tx.Model(&models.TourSchedule{}).Select("id").Where("tour_id = ?", id)
+		if err := tx.Where("schedule_id IN (?)", schedules).Delete(&models.ScheduleGuide{}).Error; err != nil {
+			return err
+		}
+		ratings := tx.Model(&models.Rating{}).Select("id").Where("tour_id = ?", id)
+		if err := tx.Where("rating_id IN (?)", ratings).Delete(&models.RatingReply{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []any{
+			&models.TourSchedule{}, &models.TourItineraryDay{}, &models.TourInclusion{},
+			&models.TourMeetingPoint{}, &models.TourFAQ{}, &models.TourTranslation{},
+			&models.TourItineraryTranslation{}, &models.Rating{}, &models.Wishlist{},
+			&models.UserRecommendation{},
+		} {
+			if err := tx.Where("tour_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("tour_id = ? OR recommended_tour_id = ?", id, id).
+			Delete(&models.TourRecommendation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM tour_categories WHERE tour_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Review{}).Where("tour_id = ?", id).
+			Update("tour_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", constants.SlugEntityTour, id).
+			Delete(&models.SlugHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Tour{}, id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTrashPurge, err)
+	}
+	return nil
+}
+
+// PurgeCategory hard-deletes the category. Its subcategories, if any, move
+// to the top level.
+func (r *trashRepository) PurgeCategory(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var children []uint
+		if err := tx.Unscoped().Model(&models.Category{}).Where("parent_id = ?", id).
+			Pluck("id", &children).Error; err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := moveCategorySubtree(tx, child, nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&models.CategoryTranslation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM tour_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", constants.SlugEntityCategory, id).
+			Delete(&models.SlugHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Category{}, id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTrashPurge, err)
+	}
+	return nil
+}
+
+// PurgeReview hard-deletes the review with its comments, their edit
+// history and reports, and its likes.
+func (r *trashRepository) PurgeReview(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		comments := tx.Model(&models.Comment{}).Select("id").Where("review_id = ?", id)
+		if err := tx.Where("(target_type = ? AND target_id = ?) OR (target_type = ? AND target_id IN (?))",
+			constants.ReportTargetReview, id, constants.ReportTargetComment, comments).
+			Delete(&models.Report{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN (?)", comments).Delete(&models.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", id).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", id).Delete(&models.ReviewLike{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Review{}, id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTrashPurge, err)
+	}
+	return nil
+}
+
+// ExpiredIDs returns the records of kind deleted before the given time,
+// oldest first.
+func (r *trashRepository) ExpiredIDs(ctx context.Context, kind string, before time.Time) ([]uint, error) {
+	var model any
+	switch kind {
+	case constants.TrashKindTours:
+		model = &models.Tour{}
+	case constants.TrashKindCategories:
+		model = &models.Category{}
+	case constants.TrashKindReviews:
+		model = &models.Review{}
+	default:
+		return nil, appErrors.ErrTrashKind
+	}
+
+	var ids []uint
+	if err := r.db.WithContext(ctx).Unscoped().Model(model).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashExpired, err)
+	}
+	return ids, nil
+}
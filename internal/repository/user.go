@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"sun-booking-tours/internal/constants"
 	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/models"
 
@@ -31,6 +32,8 @@ type UserRepo interface {
 	FindAll(ctx context.Context, filter UserFilter) ([]models.User, int64, error)
 	FindByIDWithRelations(ctx context.Context, id uint) (*models.User, error)
 	UpdateStatus(ctx context.Context, id uint, status string) error
+	UpdateRole(ctx context.Context, id uint, role string) error
+	FindActiveByRole(ctx context.Context, role string) ([]models.User, error)
 	FindByVerifyToken(ctx context.Context, token string) (*models.User, error)
 }
 
@@ -160,6 +163,26 @@ func (r *userRepository) UpdateStatus(ctx context.Context, id uint, status strin
 	return nil
 }
 
+func (r *userRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	if err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", id).
+		Update("role", role).Error; err != nil {
+		return fmt.Errorf("%s: %w", messages.ErrCtxUpdateUserRole, err)
+	}
+	return nil
+}
+
+func (r *userRepository) FindActiveByRole(ctx context.Context, role string) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.WithContext(ctx).
+		Where("role = ? AND status = ?", role, constants.StatusActive).
+		Order("full_name ASC").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", messages.ErrCtxFindUsersByRole, err)
+	}
+	return users, nil
+}
+
 func (r *userRepository) FindByVerifyToken(ctx context.Context, token string) (*models.User, error) {
 	var user models.User
 	if err := r.db.WithContext(ctx).
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type WishlistRepo interface {
+	Exists(ctx context.Context, userID, tourID uint) (bool, error)
+	Create(ctx context.Context, item *models.Wishlist) error
+	Delete(ctx context.Context, userID, tourID uint) error
+	FindByUser(ctx context.Context, userID uint, page, limit int) ([]models.Wishlist, int64, error)
+	FindPendingNotifications(ctx context.Context, now time.Time) ([]models.Wishlist, error)
+	FindNewSchedules(ctx context.Context, tourID uint, since, now time.Time) ([]models.TourSchedule, error)
+	MarkNotified(ctx context.Context, userID, tourID uint, price float64, at time.Time) error
+}
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) WishlistRepo {
+	return &wishlistRepository{db: db}
+}
+
+func (r *wishlistRepository) Exists(ctx context.Context, userID, tourID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Wishlist{}).
+		Where("user_id = ? AND tour_id = ?", userID, tourID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistCheck, err)
+	}
+	return count > 0, nil
+}
+
+func (r *wishlistRepository) Create(ctx context.Context, item *models.Wishlist) error {
+	if err := r.db.WithContext(ctx).Create(item).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistCreate, err)
+	}
+	return nil
+}
+
+func (r *wishlistRepository) Delete(ctx context.Context, userID, tourID uint) error {
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND tour_id = ?", userID, tourID).
+		Delete(&models.Wishlist{}).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistDelete, err)
+	}
+	return nil
+}
+
+// FindByUser returns one page of the user's saved tours, newest first.
+// Entries whose tour was deleted are skipped.
+func (r *wishlistRepository) FindByUser(ctx context.Context, userID uint, page, limit int) ([]models.Wishlist, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Wishlist{}).
+		Joins("JOIN tours ON tours.id = wishlists.tour_id AND tours.deleted_at IS NULL").
+		Where("wishlists.user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistCountByUser, err)
+	}
+
+	var items []models.Wishlist
+	if err := query.
+		Preload("Tour").
+		Preload("Tour.Translations").
+		Order("wishlists.created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistFindByUser, err)
+	}
+	return items, total, nil
+}
+
+// FindPendingNotifications returns the entries of active tours whose price
+// fell below the notified price or that gained an open, upcoming schedule
+// since the user was last notified. User and Tour are preloaded.
+func (r *wishlistRepository) FindPendingNotifications(ctx context.Context, now time.Time) ([]models.Wishlist, error) {
+	newSchedule := r.db.Table("tour_schedules").Select("1").
+		Where("tour_schedules.tour_id = wishlists.tour_id").
+		Where("tour_schedules.created_at > wishlists.notified_at").
+		Where("tour_schedules.status = ? AND tour_schedules.departure_date > ?", constants.ScheduleStatusOpen, now)
+
+	var items []models.Wishlist
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN tours ON tours.id = wishlists.tour_id AND tours.deleted_at IS NULL").
+		Where("tours.status = ?", constants.TourStatusActive).
+		Where("tours.price < wishlists.notified_price OR EXISTS (?)", newSchedule).
+		Preload("User").
+		Preload("Tour").
+		Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistPending, err)
+	}
+	return items, nil
+}
+
+// FindNewSchedules returns the open schedules of a tour created after since
+// that have not departed yet, soonest first.
+func (r *wishlistRepository) FindNewSchedules(ctx context.Context, tourID uint, since, now time.Time) ([]models.TourSchedule, error) {
+	var schedules []models.TourSchedule
+	if err := r.db.WithContext(ctx).
+		Where("tour_id = ? AND created_at > ?", tourID, since).
+		Where("status = ? AND departure_date > ?", constants.ScheduleStatusOpen, now).
+		Order("departure_date ASC").
+		Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistNewSchedule, err)
+	}
+	return schedules, nil
+}
+
+func (r *wishlistRepository) MarkNotified(ctx context.Context, userID, tourID uint, price float64, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.Wishlist{}).
+		Where("user_id = ? AND tour_id = ?", userID, tourID).
+		Updates(map[string]any{"notified_price": price, "notified_at": at}).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistMarkNotify, err)
+	}
+	return nil
+}
+
+// markSaved sets Tour.Saved on the tours userID has in their wishlist.
+func markSaved(ctx context.Context, db *gorm.DB, tours []models.Tour, userID uint) error {
+	if userID == 0 || len(tours) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tours))
+	for i, t := range tours {
+		ids[i] = t.ID
+	}
+	var savedIDs []uint
+	if err := db.WithContext(ctx).Model(&models.Wishlist{}).
+		Where("user_id = ? AND tour_id IN ?", userID, ids).
+		Pluck("tour_id", &savedIDs).Error; err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistSavedIDs, err)
+	}
+	saved := make(map[uint]bool, len(savedIDs))
+	for _, id := range savedIDs {
+		saved[id] = true
+	}
+	for i := range tours {
+		tours[i].Saved = saved[tours[i].ID]
+	}
+	return nil
+}
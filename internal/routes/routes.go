@@ -10,12 +10,13 @@ import (
 	"sun-booking-tours/internal/middleware"
 	"sun-booking-tours/internal/repository"
 	"sun-booking-tours/internal/services"
+	"sun-booking-tours/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
+func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config, store storage.Storage) {
 	router.GET("/health", healthCheck)
 
 	router.Use(middleware.LoadUser(db))
@@ -26,12 +27,14 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	authService := services.NewAuthService(db, userRepo, socialAcctRepo, emailService, cfg.BaseURL)
 	catRepo := repository.NewCategoryRepository(db)
 	tourRepo := repository.NewTourRepository(db)
+	slugRepo := repository.NewSlugHistoryRepository(db)
+	mediaService := services.NewMediaService(store, cfg.UploadMaxBytes, cfg.ImageWebPEncoder)
 
-	setupPublicRoutes(router, db, authService, cfg, userRepo, catRepo, tourRepo)
-	setupAdminRoutes(router, db, authService, catRepo)
+	setupPublicRoutes(router, db, authService, emailService, cfg, userRepo, catRepo, tourRepo, slugRepo, mediaService)
+	setupAdminRoutes(router, db, cfg, authService, emailService, catRepo, slugRepo, mediaService)
 }
 
-func setupPublicRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService, cfg *config.Config, userRepo repository.UserRepo, catRepo repository.CategoryRepo, tourRepo repository.TourRepo) {
+func setupPublicRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService, emailService *services.EmailService, cfg *config.Config, userRepo repository.UserRepo, catRepo repository.CategoryRepo, tourRepo repository.TourRepo, slugRepo repository.SlugHistoryRepo, mediaService *services.MediaService) {
 	authHandler := publicHandlers.NewAuthHandler(authService, cfg)
 
 	profileService := services.NewProfileService(userRepo)
@@ -41,30 +44,40 @@ func setupPublicRoutes(router *gin.Engine, db *gorm.DB, authService *services.Au
 	bankAccountService := services.NewBankAccountService(db, bankAccountRepo)
 	bankAccountHandler := publicHandlers.NewBankAccountHandler(bankAccountService)
 
-	categoryService := services.NewCategoryService(catRepo)
-	tourService := services.NewTourService(tourRepo, catRepo)
+	categoryService := services.NewCategoryService(catRepo, slugRepo)
+	tourService := services.NewTourService(db, tourRepo, catRepo, slugRepo, mediaService)
+	bookingRepo := repository.NewBookingRepository(db)
 	ratingRepo := repository.NewRatingRepository(db)
-	ratingService := services.NewRatingService(ratingRepo, tourRepo)
-	publicTourHandler := publicHandlers.NewPublicTourHandler(tourService, categoryService, ratingService)
+	ratingService := services.NewRatingService(ratingRepo, tourRepo, bookingRepo, emailService, cfg.BaseURL)
+	reviewRepo := repository.NewReviewRepository(db)
+	likeRepo := repository.NewReviewLikeRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	moderationService := services.NewModerationService(services.DefaultModerationRules(repository.NewModerationRepository(db))...)
+	reviewService := services.NewReviewService(db, reviewRepo, likeRepo, commentRepo, bookingRepo, mediaService, moderationService)
+	reviewHandler := publicHandlers.NewReviewHandler(reviewService)
+	reportRepo := repository.NewReportRepository(db)
+	reportHandler := publicHandlers.NewReportHandler(services.NewReportService(db, reportRepo, reviewRepo, commentRepo, emailService))
+	recService := services.NewRecommendationService(repository.NewRecommendationRepository(db))
+	wishlistService := services.NewWishlistService(repository.NewWishlistRepository(db), emailService, cfg.BaseURL)
+	publicTourHandler := publicHandlers.NewPublicTourHandler(tourService, categoryService, ratingService, reviewService, recService, wishlistService)
+	wishlistHandler := publicHandlers.NewWishlistHandler(wishlistService, tourService)
 	ratingHandler := publicHandlers.NewRatingHandler(ratingService, tourService)
-	homeHandler := publicHandlers.NewHomeHandler(tourService)
+	homeHandler := publicHandlers.NewHomeHandler(tourService, recService)
 
 	scheduleRepo := repository.NewScheduleRepository(db)
-	bookingRepo := repository.NewBookingRepository(db)
 	bookingService := services.NewBookingService(db, bookingRepo, scheduleRepo)
 	bookingHandler := publicHandlers.NewBookingHandler(bookingService, tourService)
 
-	reviewRepo := repository.NewReviewRepository(db)
-	likeRepo := repository.NewReviewLikeRepository(db)
-	commentRepo := repository.NewCommentRepository(db)
-	reviewService := services.NewReviewService(db, reviewRepo, likeRepo, commentRepo)
-	reviewHandler := publicHandlers.NewReviewHandler(reviewService)
+	scheduleGuideRepo := repository.NewScheduleGuideRepository(db)
+	guideService := services.NewGuideService(scheduleGuideRepo, scheduleRepo, userRepo, bookingRepo)
+	guideHandler := publicHandlers.NewGuideHandler(guideService)
 
 	public := router.Group("/")
 	public.Use(middleware.LoadCategories(catRepo))
 	{
 		public.GET("/", homeHandler.Index)
 		public.GET("/tours", publicTourHandler.List)
+		public.GET("/tours.geojson", publicTourHandler.GeoJSON)
 		public.GET("/tours/:slug", publicTourHandler.Detail)
 		public.GET("/register", authHandler.RegisterForm)
 		public.POST("/register", authHandler.Register)
@@ -103,6 +116,8 @@ func setupPublicRoutes(router *gin.Engine, db *gorm.DB, authService *services.Au
 		auth.GET("/tours/:slug/book", bookingHandler.Form)
 		auth.POST("/tours/:slug/book", bookingHandler.Create)
 		auth.POST("/tours/:slug/rate", ratingHandler.Rate)
+		auth.POST("/tours/:slug/wishlist", wishlistHandler.Toggle)
+		auth.GET("/my/wishlist", wishlistHandler.MyList)
 		auth.GET("/my/bookings", bookingHandler.MyList)
 		auth.GET("/my/bookings/:id", bookingHandler.Detail)
 		auth.POST("/my/bookings/:id/cancel", bookingHandler.Cancel)
@@ -112,29 +127,41 @@ func setupPublicRoutes(router *gin.Engine, db *gorm.DB, authService *services.Au
 		auth.POST("/reviews/:id/like", reviewHandler.ToggleLike)
 		auth.POST("/reviews/:id/comments", reviewHandler.AddComment)
 		auth.POST("/comments/:id/reply", reviewHandler.ReplyComment)
+		auth.POST("/comments/:id/edit", reviewHandler.EditComment)
 		auth.POST("/comments/:id/delete", reviewHandler.DeleteComment)
+		auth.POST("/reviews/:id/report", reportHandler.ReportReview)
+		auth.POST("/comments/:id/report", reportHandler.ReportComment)
 		auth.GET("/my/reviews", reviewHandler.MyList)
 		auth.GET("/my/reviews/:id/edit", reviewHandler.EditForm)
 		auth.POST("/my/reviews/:id/edit", reviewHandler.Update)
 		auth.POST("/my/reviews/:id/delete", reviewHandler.Delete)
 	}
+
+	// Guide portal (requires login with role=guide)
+	guide := public.Group("/guide", middleware.RequireGuide())
+	{
+		guide.GET("/schedules", guideHandler.List)
+		guide.GET("/schedules/:id", guideHandler.Detail)
+	}
 }
 
-func setupAdminRoutes(router *gin.Engine, db *gorm.DB, authService *services.AuthService, catRepo repository.CategoryRepo) {
+func setupAdminRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config, authService *services.AuthService, emailService *services.EmailService, catRepo repository.CategoryRepo, slugRepo repository.SlugHistoryRepo, mediaService *services.MediaService) {
 	statsRepo := repository.NewStatsRepository(db)
 	statsService := services.NewStatsService(statsRepo)
 	dashboardHandler := adminHandlers.NewDashboardHandler(statsService)
 	adminAuthHandler := adminHandlers.NewAdminAuthHandler(authService)
 
-	categoryService := services.NewCategoryService(catRepo)
+	categoryService := services.NewCategoryService(catRepo, slugRepo)
 	categoryHandler := adminHandlers.NewCategoryHandler(categoryService)
 
 	tourRepo := repository.NewTourRepository(db)
-	tourService := services.NewTourService(tourRepo, catRepo)
+	tourService := services.NewTourService(db, tourRepo, catRepo, slugRepo, mediaService)
 	tourHandler := adminHandlers.NewTourHandler(tourService, categoryService)
+	tourImportHandler := adminHandlers.NewTourImportHandler(services.NewTourImportService(db, tourRepo, mediaService))
 
 	scheduleRepo := repository.NewScheduleRepository(db)
-	scheduleService := services.NewScheduleService(scheduleRepo, tourRepo)
+	scheduleGuideRepo := repository.NewScheduleGuideRepository(db)
+	scheduleService := services.NewScheduleService(scheduleRepo, tourRepo, scheduleGuideRepo)
 	scheduleHandler := adminHandlers.NewScheduleHandler(scheduleService, tourService)
 
 	bookingRepo := repository.NewBookingRepository(db)
@@ -148,12 +175,25 @@ func setupAdminRoutes(router *gin.Engine, db *gorm.DB, authService *services.Aut
 	reviewRepo := repository.NewReviewRepository(db)
 	likeRepo := repository.NewReviewLikeRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
-	adminReviewService := services.NewReviewService(db, reviewRepo, likeRepo, commentRepo)
+	moderationService := services.NewModerationService(services.DefaultModerationRules(repository.NewModerationRepository(db))...)
+	adminReviewService := services.NewReviewService(db, reviewRepo, likeRepo, commentRepo, bookingRepo, mediaService, moderationService)
 	adminReviewHandler := adminHandlers.NewReviewHandler(adminReviewService)
+	adminCommentHandler := adminHandlers.NewCommentHandler(adminReviewService)
+
+	adminRatingService := services.NewRatingService(repository.NewRatingRepository(db), tourRepo, bookingRepo, emailService, cfg.BaseURL)
+	adminRatingHandler := adminHandlers.NewRatingHandler(adminRatingService)
 
 	userRepo := repository.NewUserRepository(db)
-	adminUserService := services.NewAdminUserService(userRepo)
-	adminUserHandler := adminHandlers.NewUserHandler(adminUserService)
+	adminUserService := services.NewAdminUserService(db, userRepo)
+	adminUserHandler := adminHandlers.NewUserHandler(adminUserService, adminRatingService)
+	reportService := services.NewReportService(db, repository.NewReportRepository(db), reviewRepo, commentRepo, emailService)
+	adminReportHandler := adminHandlers.NewReportHandler(reportService)
+
+	guideService := services.NewGuideService(scheduleGuideRepo, scheduleRepo, userRepo, bookingRepo)
+	scheduleGuideHandler := adminHandlers.NewScheduleGuideHandler(guideService, scheduleService)
+
+	trashService := services.NewTrashService(db, repository.NewTrashRepository(db), tourRepo, catRepo, slugRepo, cfg.TrashRetention)
+	trashHandler := adminHandlers.NewTrashHandler(trashService)
 
 	admin := router.Group("/admin")
 	{
@@ -173,13 +213,24 @@ func setupAdminRoutes(router *gin.Engine, db *gorm.DB, authService *services.Aut
 		adminAuth.GET("/categories/:id/edit", categoryHandler.EditForm)
 		adminAuth.POST("/categories/:id/edit", categoryHandler.Update)
 		adminAuth.POST("/categories/:id/delete", categoryHandler.Delete)
+		adminAuth.POST("/categories/reorder", categoryHandler.Reorder)
+		adminAuth.GET("/categories/:id/move", categoryHandler.MoveForm)
+		adminAuth.POST("/categories/:id/move", categoryHandler.Move)
+		adminAuth.GET("/categories/:id/merge", categoryHandler.MergeForm)
+		adminAuth.POST("/categories/:id/merge", categoryHandler.Merge)
 
 		adminAuth.GET("/tours", tourHandler.List)
 		adminAuth.GET("/tours/create", tourHandler.CreateForm)
 		adminAuth.POST("/tours/create", tourHandler.Create)
+		adminAuth.GET("/tours/import", tourImportHandler.Form)
+		adminAuth.POST("/tours/import", tourImportHandler.Import)
+		adminAuth.GET("/tours/export", tourImportHandler.Export)
+		adminAuth.POST("/tours/bulk-categories", tourHandler.BulkCategories)
 		adminAuth.GET("/tours/:id/edit", tourHandler.EditForm)
 		adminAuth.POST("/tours/:id/edit", tourHandler.Update)
 		adminAuth.POST("/tours/:id/delete", tourHandler.Delete)
+		adminAuth.GET("/tours/:id/clone", tourHandler.CloneForm)
+		adminAuth.POST("/tours/:id/clone", tourHandler.Clone)
 
 		adminAuth.GET("/tours/:id/schedules", scheduleHandler.List)
 		adminAuth.GET("/tours/:id/schedules/create", scheduleHandler.CreateForm)
@@ -187,6 +238,9 @@ func setupAdminRoutes(router *gin.Engine, db *gorm.DB, authService *services.Aut
 		adminAuth.GET("/schedules/:id/edit", scheduleHandler.EditForm)
 		adminAuth.POST("/schedules/:id/edit", scheduleHandler.Update)
 		adminAuth.POST("/schedules/:id/delete", scheduleHandler.Delete)
+		adminAuth.GET("/schedules/:id/guides", scheduleGuideHandler.List)
+		adminAuth.POST("/schedules/:id/guides", scheduleGuideHandler.Assign)
+		adminAuth.POST("/schedules/:id/guides/:guide_id/delete", scheduleGuideHandler.Unassign)
 
 		adminAuth.GET("/bookings", adminBookingHandler.List)
 		adminAuth.POST("/bookings/:id/confirm", adminBookingHandler.Confirm)
@@ -199,9 +253,26 @@ func setupAdminRoutes(router *gin.Engine, db *gorm.DB, authService *services.Aut
 		adminAuth.POST("/reviews/:id/approve", adminReviewHandler.Approve)
 		adminAuth.POST("/reviews/:id/reject", adminReviewHandler.Reject)
 
+		adminAuth.GET("/comments", adminCommentHandler.List)
+		adminAuth.POST("/comments/:id/approve", adminCommentHandler.Approve)
+		adminAuth.POST("/comments/:id/reject", adminCommentHandler.Reject)
+
+		adminAuth.GET("/reports", adminReportHandler.List)
+		adminAuth.POST("/reports/:type/:id/resolve", adminReportHandler.Resolve)
+
+		adminAuth.GET("/ratings", adminRatingHandler.List)
+		adminAuth.POST("/ratings/:id/reply", adminRatingHandler.Reply)
+
 		adminAuth.GET("/users", adminUserHandler.List)
 		adminAuth.GET("/users/:id", adminUserHandler.Detail)
 		adminAuth.POST("/users/:id/status", adminUserHandler.UpdateStatus)
+		adminAuth.POST("/users/:id/role", adminUserHandler.UpdateRole)
+		adminAuth.POST("/users/:id/rating-grants", adminUserHandler.AllowRating)
+		adminAuth.POST("/users/:id/rating-grants/:tour_id/delete", adminUserHandler.RevokeRating)
+
+		adminAuth.GET("/trash", trashHandler.List)
+		adminAuth.POST("/trash/:kind/:id/restore", trashHandler.Restore)
+		adminAuth.POST("/trash/:kind/:id/purge", trashHandler.Purge)
 	}
 }
 
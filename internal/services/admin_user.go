@@ -14,11 +14,12 @@ import (
 )
 
 type AdminUserService struct {
+	db       *gorm.DB
 	userRepo repository.UserRepo
 }
 
-func NewAdminUserService(userRepo repository.UserRepo) *AdminUserService {
-	return &AdminUserService{userRepo: userRepo}
+func NewAdminUserService(db *gorm.DB, userRepo repository.UserRepo) *AdminUserService {
+	return &AdminUserService{db: db, userRepo: userRepo}
 }
 
 func (s *AdminUserService) ListUsers(ctx context.Context, filter repository.UserFilter) ([]models.User, int64, error) {
@@ -63,3 +64,44 @@ func (s *AdminUserService) UpdateUserStatus(ctx context.Context, adminUser *mode
 	}
 	return nil
 }
+
+// UpdateUserRole switches a non-admin account between the "user" and "guide"
+// roles. Promotion to admin is not possible from the admin panel. Demoting a
+// guide also removes them from every schedule they were assigned to.
+func (s *AdminUserService) UpdateUserRole(ctx context.Context, adminUser *models.User, targetID uint, role string) error {
+	if role != constants.RoleUser && role != constants.RoleGuide {
+		return appErrors.ErrInvalidRole
+	}
+	if adminUser.ID == targetID {
+		return appErrors.ErrCannotChangeOwnRole
+	}
+
+	target, err := s.userRepo.FindByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxUserFindByID, err)
+	}
+
+	if target.Role == constants.RoleAdmin {
+		return appErrors.ErrCannotChangeAdminRole
+	}
+
+	if target.Role != constants.RoleGuide || role == constants.RoleGuide {
+		if err := s.userRepo.UpdateRole(ctx, targetID, role); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxUserUpdateRole, err)
+		}
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).UpdateRole(ctx, targetID, role); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxUserUpdateRole, err)
+		}
+		if err := repository.NewScheduleGuideRepository(tx).UnassignGuide(ctx, targetID); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxUserUpdateRole, err)
+		}
+		return nil
+	})
+}
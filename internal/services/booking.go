@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 
@@ -23,7 +25,7 @@ func NewBookingService(db *gorm.DB, bookingRepo repository.BookingRepo, schedule
 	return &BookingService{db: db, bookingRepo: bookingRepo, scheduleRepo: scheduleRepo}
 }
 
-func (s *BookingService) CreateBooking(ctx context.Context, userID, tourID, scheduleID uint, numParticipants int, tourPrice float64, note string) (*models.Booking, error) {
+func (s *BookingService) CreateBooking(ctx context.Context, userID, tourID, scheduleID uint, numParticipants int, tourPrice float64, note, pickupPoint string) (*models.Booking, error) {
 	var booking *models.Booking
 
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -76,6 +78,7 @@ func (s *BookingService) CreateBooking(ctx context.Context, userID, tourID, sche
 			TotalPrice:      totalPrice,
 			Status:          constants.BookingStatusPending,
 			Note:            note,
+			PickupPoint:     pickupPoint,
 		}
 		if err := tx.Create(booking).Error; err != nil {
 			return fmt.Errorf("%s: %w", appErrors.ErrCtxBookingCreate, err)
@@ -126,7 +129,7 @@ func (s *BookingService) CancelBooking(ctx context.Context, id, userID uint) err
 		return appErrors.ErrBookingCannotCancel
 	}
 
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		bookingResult := tx.Model(&models.Booking{}).
 			Where("id = ? AND user_id = ? AND status IN ?", id, userID, []string{
 				constants.BookingStatusPending,
@@ -163,6 +166,12 @@ func (s *BookingService) CancelBooking(ctx context.Context, id, userID uint) err
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.refreshScheduleGuarantee(ctx, booking)
+	return nil
 }
 
 func (s *BookingService) ListAllBookings(ctx context.Context, filter repository.BookingFilter) ([]models.Booking, int64, error) {
@@ -184,7 +193,25 @@ func (s *BookingService) AdminConfirmBooking(ctx context.Context, id uint) error
 	if booking.Status != constants.BookingStatusPending {
 		return appErrors.ErrBookingCannotConfirm
 	}
-	return s.bookingRepo.UpdateStatus(ctx, id, constants.BookingStatusConfirmed)
+	if err := s.bookingRepo.UpdateStatus(ctx, id, constants.BookingStatusConfirmed); err != nil {
+		return err
+	}
+
+	s.refreshScheduleGuarantee(ctx, booking)
+	return nil
+}
+
+// refreshScheduleGuarantee recomputes the guaranteed flag of the booking's
+// schedule after its confirmed participants changed. A failed refresh must not
+// undo the booking change, so it is only logged.
+func (s *BookingService) refreshScheduleGuarantee(ctx context.Context, booking *models.Booking) {
+	if booking.Schedule == nil {
+		return
+	}
+	booking.Schedule.Tour = booking.Tour
+	if _, err := refreshGuaranteed(ctx, s.scheduleRepo, booking.Schedule); err != nil {
+		slog.ErrorContext(ctx, messages.LogDepartureRefreshFailed, "schedule_id", booking.ScheduleID, "error", err)
+	}
 }
 
 func (s *BookingService) AdminCancelBooking(ctx context.Context, id uint) error {
@@ -199,7 +226,7 @@ func (s *BookingService) AdminCancelBooking(ctx context.Context, id uint) error
 		return appErrors.ErrBookingCannotCancel
 	}
 
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Model(&models.Booking{}).Where("id = ?", id).Update("status", constants.BookingStatusCancelled).Error; err != nil {
 			return fmt.Errorf("%s: %w", appErrors.ErrCtxBookingUpdateStatus, err)
 		}
@@ -226,6 +253,12 @@ func (s *BookingService) AdminCancelBooking(ctx context.Context, id uint) error
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.refreshScheduleGuarantee(ctx, booking)
+	return nil
 }
 
 func (s *BookingService) AdminCompleteBooking(ctx context.Context, id uint) error {
@@ -4,57 +4,98 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
-	"sun-booking-tours/internal/utils"
 
 	"gorm.io/gorm"
 )
 
 type CategoryForm struct {
 	Name        string `form:"name" binding:"required,max=255"`
+	Slug        string `form:"slug" binding:"max=255"`
+	SlugPinned  bool   `form:"slug_pinned"`
 	Description string `form:"description"`
 	ParentID    uint   `form:"parent_id"`
+
+	// Translations hold the text in the other supported locales; handlers
+	// fill them from the language tabs.
+	Translations []TranslationForm `form:"-"`
 }
 
+// CategoryTree is a category with its subcategories, nested to any depth.
 type CategoryTree struct {
 	models.Category
-	Children []models.Category
+	Children []CategoryTree
 }
 
 type CategoryService struct {
-	repo repository.CategoryRepo
+	repo     repository.CategoryRepo
+	slugRepo repository.SlugHistoryRepo
 }
 
-func NewCategoryService(repo repository.CategoryRepo) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepo, slugRepo repository.SlugHistoryRepo) *CategoryService {
+	return &CategoryService{repo: repo, slugRepo: slugRepo}
 }
 
+// ListCategories returns the whole category forest, siblings in display
+// order.
 func (s *CategoryService) ListCategories(ctx context.Context) ([]CategoryTree, error) {
-	all, err := s.repo.FindAllParents(ctx)
+	all, err := s.repo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceListCategories, err)
 	}
-
-	trees := make([]CategoryTree, 0, len(all))
-	for _, cat := range all {
-		trees = append(trees, CategoryTree{
-			Category: cat,
-			Children: cat.Children,
-		})
-	}
-	return trees, nil
+	return buildCategoryTree(all), nil
 }
 
+// AllFlatCategories lists every category in tree order: each one is
+// followed by its subtree, so Depth can be used to indent it.
 func (s *CategoryService) AllFlatCategories(ctx context.Context) ([]models.Category, error) {
-	cats, err := s.repo.FindAll(ctx)
+	all, err := s.repo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceAllFlat, err)
 	}
-	return cats, nil
+	return flattenCategoryTree(buildCategoryTree(all), nil), nil
+}
+
+// buildCategoryTree nests cats, which must already be in sibling order.
+// A category whose parent is missing from cats is treated as a root.
+func buildCategoryTree(cats []models.Category) []CategoryTree {
+	known := make(map[uint]bool, len(cats))
+	for _, c := range cats {
+		known[c.ID] = true
+	}
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, c := range cats {
+		if c.ParentID == nil || !known[*c.ParentID] {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var build func(level []models.Category) []CategoryTree
+	build = func(level []models.Category) []CategoryTree {
+		trees := make([]CategoryTree, 0, len(level))
+		for _, c := range level {
+			trees = append(trees, CategoryTree{Category: c, Children: build(children[c.ID])})
+		}
+		return trees
+	}
+	return build(roots)
+}
+
+func flattenCategoryTree(trees []CategoryTree, out []models.Category) []models.Category {
+	for _, t := range trees {
+		out = append(out, t.Category)
+		out = flattenCategoryTree(t.Children, out)
+	}
+	return out
 }
 
 func (s *CategoryService) GetCategory(ctx context.Context, id uint) (*models.Category, error) {
@@ -74,31 +115,36 @@ func (s *CategoryService) CreateCategory(ctx context.Context, form *CategoryForm
 		return appErrors.NewAppError(400, appErrors.ErrMsgCategoryNameRequired)
 	}
 
-	slug := utils.Slugify(name)
+	slug, pinned, ok := buildSlug(name, form.Slug, form.SlugPinned)
+	if !ok {
+		return appErrors.NewAppError(400, appErrors.ErrMsgCategorySlugInvalid)
+	}
 
 	exists, err := s.repo.ExistsBySlug(ctx, slug)
 	if err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceCreateCheckSlug, err)
 	}
 	if exists {
-		return appErrors.NewAppError(409, appErrors.ErrMsgCategoryNameDuplicate)
+		return appErrors.NewAppError(409, categorySlugDuplicateMsg(pinned))
 	}
 
-	var parentID *uint
-	if form.ParentID > 0 {
-		parent, pErr := s.repo.FindByID(ctx, form.ParentID)
-		if pErr != nil {
-			return appErrors.NewAppError(400, appErrors.ErrMsgCategoryParentNotFound)
-		}
-		if parent.ParentID != nil {
-			return appErrors.NewAppError(400, appErrors.ErrMsgCategoryParentMustBeRoot)
-		}
-		parentID = &form.ParentID
+	translations, err := buildCategoryTranslations(form.Translations)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCategoryTranslationSlugs(ctx, 0, translations); err != nil {
+		return err
+	}
+
+	parentID, err := s.checkParent(ctx, 0, form.ParentID)
+	if err != nil {
+		return err
 	}
 
 	cat := models.Category{
 		Name:        name,
 		Slug:        slug,
+		SlugPinned:  pinned,
 		Description: strings.TrimSpace(form.Description),
 		ParentID:    parentID,
 	}
@@ -106,7 +152,11 @@ func (s *CategoryService) CreateCategory(ctx context.Context, form *CategoryForm
 	if err := s.repo.Create(ctx, &cat); err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceCreate, err)
 	}
-	return nil
+
+	if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityCategory, cat.ID, "", slug); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceSlugHistory, err)
+	}
+	return s.saveCategoryTranslations(ctx, cat.ID, nil, translations)
 }
 
 func (s *CategoryService) UpdateCategory(ctx context.Context, id uint, form *CategoryForm) error {
@@ -123,51 +173,79 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id uint, form *Cat
 		return appErrors.NewAppError(400, appErrors.ErrMsgCategoryNameRequired)
 	}
 
-	slug := utils.Slugify(name)
+	slug, pinned, ok := buildSlug(name, form.Slug, form.SlugPinned)
+	if !ok {
+		return appErrors.NewAppError(400, appErrors.ErrMsgCategorySlugInvalid)
+	}
 
 	slugExists, err := s.repo.ExistsBySlugExcluding(ctx, slug, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceUpdateCheckSlug, err)
 	}
 	if slugExists {
-		return appErrors.NewAppError(409, appErrors.ErrMsgCategoryNameDuplicate)
+		return appErrors.NewAppError(409, categorySlugDuplicateMsg(pinned))
 	}
 
-	var parentID *uint
-	if form.ParentID > 0 {
-		if form.ParentID == id {
-			return appErrors.NewAppError(400, appErrors.ErrMsgCategorySelfParent)
-		}
-
-		descendants, dErr := s.repo.GetDescendantIDs(ctx, id)
-		if dErr != nil {
-			return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceUpdateGetDescendants, dErr)
-		}
-		for _, did := range descendants {
-			if did == form.ParentID {
-				return appErrors.NewAppError(400, appErrors.ErrMsgCategoryChildAsParent)
-			}
-		}
+	translations, err := buildCategoryTranslations(form.Translations)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCategoryTranslationSlugs(ctx, id, translations); err != nil {
+		return err
+	}
 
-		parent, pErr := s.repo.FindByID(ctx, form.ParentID)
-		if pErr != nil {
-			return appErrors.NewAppError(400, appErrors.ErrMsgCategoryParentNotFound)
-		}
-		if parent.ParentID != nil {
-			return appErrors.NewAppError(400, appErrors.ErrMsgCategoryParentMustBeRoot)
-		}
-		parentID = &form.ParentID
+	parentID, err := s.checkParent(ctx, id, form.ParentID)
+	if err != nil {
+		return err
 	}
+	moved := !sameParent(cat.ParentID, parentID)
 
+	oldSlug := cat.Slug
 	cat.Name = name
 	cat.Slug = slug
+	cat.SlugPinned = pinned
 	cat.Description = strings.TrimSpace(form.Description)
-	cat.ParentID = parentID
+	// Translations are replaced separately; keep Save from upserting them.
+	oldTranslations := cat.Translations
+	cat.Translations = nil
 
 	if err := s.repo.Update(ctx, cat); err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceUpdate, err)
 	}
-	return nil
+	if moved {
+		if err := s.repo.Move(ctx, id, parentID); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMove, err)
+		}
+	}
+
+	if oldSlug != slug {
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityCategory, cat.ID, oldSlug, slug); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceSlugHistory, err)
+		}
+	}
+	return s.saveCategoryTranslations(ctx, cat.ID, oldTranslations, translations)
+}
+
+// GetCategoryBySlug resolves current and former slugs; compare the result's
+// Slug with the requested one to detect a rename.
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	cat, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceGetBySlug, err)
+	}
+	return cat, nil
+}
+
+// SlugHistory lists the category's former slugs, newest first.
+func (s *CategoryService) SlugHistory(ctx context.Context, id uint) ([]models.SlugHistory, error) {
+	history, err := s.slugRepo.FindByEntity(ctx, constants.SlugEntityCategory, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceSlugHistory, err)
+	}
+	return history, nil
 }
 
 func (s *CategoryService) DeleteCategory(ctx context.Context, id uint) error {
@@ -200,3 +278,159 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, id uint) error {
 	}
 	return nil
 }
+
+// MoveCategory puts the category and its whole subtree last under
+// parentID; 0 moves it to the top level.
+func (s *CategoryService) MoveCategory(ctx context.Context, id, parentID uint) error {
+	cat, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.ErrCategoryNotFound
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMove, err)
+	}
+	newParent, err := s.checkParent(ctx, id, parentID)
+	if err != nil {
+		return err
+	}
+	if sameParent(cat.ParentID, newParent) {
+		return nil
+	}
+	if err := s.repo.Move(ctx, id, newParent); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMove, err)
+	}
+	return nil
+}
+
+// ReorderCategories sets the display order of the children of parentID (0
+// for the top level). ids must list exactly those children.
+func (s *CategoryService) ReorderCategories(ctx context.Context, parentID uint, ids []uint) error {
+	var parent *uint
+	if parentID > 0 {
+		parent = &parentID
+	}
+	current, err := s.repo.ChildIDs(ctx, parent)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceReorder, err)
+	}
+	if len(ids) != len(current) {
+		return appErrors.NewAppError(400, appErrors.ErrMsgCategoryReorderInvalid)
+	}
+	siblings := make(map[uint]bool, len(current))
+	for _, id := range current {
+		siblings[id] = true
+	}
+	for _, id := range ids {
+		if !siblings[id] {
+			return appErrors.NewAppError(400, appErrors.ErrMsgCategoryReorderInvalid)
+		}
+		delete(siblings, id)
+	}
+
+	if err := s.repo.UpdatePositions(ctx, ids); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceReorder, err)
+	}
+	return nil
+}
+
+// Breadcrumbs returns the categories above cat, root first.
+func (s *CategoryService) Breadcrumbs(ctx context.Context, cat *models.Category) ([]models.Category, error) {
+	ancestors, err := s.repo.FindAncestors(ctx, cat)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceBreadcrumbs, err)
+	}
+	return ancestors, nil
+}
+
+// CategoryMerge summarizes a merge for the admin.
+type CategoryMerge struct {
+	Source   string
+	Target   string
+	Tours    int64
+	Children int
+}
+
+// MergeCategory folds the source category into the target: tours and
+// subcategories move over, the source's slugs redirect to the target and the
+// source goes to the trash. Tours are counted when they gain the target.
+func (s *CategoryService) MergeCategory(ctx context.Context, sourceID, targetID uint) (*CategoryMerge, error) {
+	if sourceID == targetID {
+		return nil, appErrors.NewAppError(400, appErrors.ErrMsgCategoryMergeSelf)
+	}
+	source, err := s.repo.FindByID(ctx, sourceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMerge, err)
+	}
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NewAppError(400, appErrors.ErrMsgCategoryMergeTargetNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMerge, err)
+	}
+	descendants, err := s.repo.GetDescendantIDs(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMerge, err)
+	}
+	if slices.Contains(descendants, targetID) {
+		return nil, appErrors.NewAppError(400, appErrors.ErrMsgCategoryMergeIntoChild)
+	}
+
+	result, err := s.repo.Merge(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceMerge, err)
+	}
+
+	oldSlugs := []string{source.Slug}
+	for _, tr := range source.Translations {
+		oldSlugs = append(oldSlugs, tr.Slug)
+	}
+	for _, slug := range oldSlugs {
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityCategory, targetID, slug, target.Slug); err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceSlugHistory, err)
+		}
+	}
+	return &CategoryMerge{Source: source.Name, Target: target.Name, Tours: result.Tours, Children: result.Children}, nil
+}
+
+// checkParent validates parentID as the new parent of category id (0 for
+// an unsaved category) and returns it as a nullable ID. A category cannot
+// sit below itself or one of its descendants.
+func (s *CategoryService) checkParent(ctx context.Context, id, parentID uint) (*uint, error) {
+	if parentID == 0 {
+		return nil, nil
+	}
+	if id > 0 {
+		if parentID == id {
+			return nil, appErrors.NewAppError(400, appErrors.ErrMsgCategorySelfParent)
+		}
+		descendants, err := s.repo.GetDescendantIDs(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceUpdateGetDescendants, err)
+		}
+		if slices.Contains(descendants, parentID) {
+			return nil, appErrors.NewAppError(400, appErrors.ErrMsgCategoryChildAsParent)
+		}
+	}
+	if _, err := s.repo.FindByID(ctx, parentID); err != nil {
+		return nil, appErrors.NewAppError(400, appErrors.ErrMsgCategoryParentNotFound)
+	}
+	return &parentID, nil
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func categorySlugDuplicateMsg(pinned bool) string {
+	if pinned {
+		return appErrors.ErrMsgCategorySlugDuplicate
+	}
+	return appErrors.ErrMsgCategoryNameDuplicate
+}
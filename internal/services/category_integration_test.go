@@ -0,0 +1,157 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupCategoryServiceDB(t *testing.T) (*CategoryService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+
+	svc := NewCategoryService(repository.NewCategoryRepository(db), repository.NewSlugHistoryRepository(db))
+	return svc, db
+}
+
+// createCategory adds a category under parentID (0 for the top level) and
+// returns it as stored.
+func createCategory(t *testing.T, svc *CategoryService, db *gorm.DB, name string, parentID uint) models.Category {
+	t.Helper()
+	require.NoError(t, svc.CreateCategory(context.Background(), &CategoryForm{Name: name, ParentID: parentID}))
+	var cat models.Category
+	require.NoError(t, db.Where("name = ?", name).First(&cat).Error)
+	return cat
+}
+
+func reloadCategory(t *testing.T, db *gorm.DB, id uint) models.Category {
+	t.Helper()
+	var cat models.Category
+	require.NoError(t, db.First(&cat, id).Error)
+	return cat
+}
+
+// --- tree -----------------------------------------------------------
+
+func TestCreateCategory_NestsToAnyDepth(t *testing.T) {
+	svc, db := setupCategoryServiceDB(t)
+	asia := createCategory(t, svc, db, "Châu Á", 0)
+	vietnam := createCategory(t, svc, db, "Việt Nam", asia.ID)
+	north := createCategory(t, svc, db, "Miền Bắc", vietnam.ID)
+	sapa := createCategory(t, svc, db, "Sa Pa", north.ID)
+
+	assert.Equal(t, 3, sapa.Depth)
+	assert.Equal(t, []uint{asia.ID, vietnam.ID, north.ID}, sapa.AncestorIDs())
+
+	flat, err := svc.AllFlatCategories(context.Background())
+	require.NoError(t, err)
+	var names []string
+	for _, c := range flat {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"Châu Á", "Việt Nam", "Miền Bắc", "Sa Pa"}, names)
+}
+
+func TestMoveCategory_RewritesSubtree(t *testing.T) {
+	svc, db := setupCategoryServiceDB(t)
+	ctx := context.Background()
+	north := createCategory(t, svc, db, "Miền Bắc", 0)
+	south := createCategory(t, svc, db, "Miền Nam", 0)
+	west := createCategory(t, svc, db, "Tây Bắc", north.ID)
+	sapa := createCategory(t, svc, db, "Sa Pa", west.ID)
+
+	require.NoError(t, svc.MoveCategory(ctx, west.ID, south.ID))
+
+	west = reloadCategory(t, db, west.ID)
+	sapa = reloadCategory(t, db, sapa.ID)
+	require.NotNil(t, west.ParentID)
+	assert.Equal(t, south.ID, *west.ParentID)
+	assert.Equal(t, 1, west.Depth)
+	assert.Equal(t, []uint{south.ID, west.ID}, sapa.AncestorIDs())
+	assert.Equal(t, 2, sapa.Depth)
+
+	trees, err := svc.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, trees, 2)
+	assert.Empty(t, trees[0].Children)
+	require.Len(t, trees[1].Children, 1)
+	assert.Equal(t, "Sa Pa", trees[1].Children[0].Children[0].Name)
+}
+
+func TestReorderCategories_SetsDisplayOrder(t *testing.T) {
+	svc, db := setupCategoryServiceDB(t)
+	ctx := context.Background()
+	north := createCategory(t, svc, db, "Miền Bắc", 0)
+	a := createCategory(t, svc, db, "Hà Giang", north.ID)
+	b := createCategory(t, svc, db, "Sa Pa", north.ID)
+	c := createCategory(t, svc, db, "Mộc Châu", north.ID)
+
+	require.NoError(t, svc.ReorderCategories(ctx, north.ID, []uint{c.ID, a.ID, b.ID}))
+
+	trees, err := svc.ListCategories(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, child := range trees[0].Children {
+		names = append(names, child.Name)
+	}
+	assert.Equal(t, []string{"Mộc Châu", "Hà Giang", "Sa Pa"}, names)
+}
+
+func TestBreadcrumbs_RootFirst(t *testing.T) {
+	svc, db := setupCategoryServiceDB(t)
+	north := createCategory(t, svc, db, "Miền Bắc", 0)
+	west := createCategory(t, svc, db, "Tây Bắc", north.ID)
+	sapa := createCategory(t, svc, db, "Sa Pa", west.ID)
+
+	crumbs, err := svc.Breadcrumbs(context.Background(), &sapa)
+
+	require.NoError(t, err)
+	require.Len(t, crumbs, 2)
+	assert.Equal(t, "Miền Bắc", crumbs[0].Name)
+	assert.Equal(t, "Tây Bắc", crumbs[1].Name)
+}
+
+// --- Merge ----------------------------------------------------------
+
+func TestMergeCategory_MovesToursChildrenAndSlug(t *testing.T) {
+	svc, db := setupCategoryServiceDB(t)
+	ctx := context.Background()
+	beach := createCategory(t, svc, db, "Biển", 0)
+	dup := createCategory(t, svc, db, "Du lịch biển", 0)
+	island := createCategory(t, svc, db, "Đảo", dup.ID)
+	both := models.Tour{Title: "Phu Quoc", Slug: "phu-quoc"}
+	only := models.Tour{Title: "Con Dao", Slug: "con-dao"}
+	require.NoError(t, db.Create(&both).Error)
+	require.NoError(t, db.Create(&only).Error)
+	require.NoError(t, db.Exec("INSERT INTO tour_categories (tour_id, category_id) VALUES (?, ?), (?, ?), (?, ?)",
+		both.ID, beach.ID, both.ID, dup.ID, only.ID, dup.ID).Error)
+
+	merged, err := svc.MergeCategory(ctx, dup.ID, beach.ID)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, merged.Tours)
+	assert.Equal(t, 1, merged.Children)
+	var links []uint
+	require.NoError(t, db.Table("tour_categories").Where("category_id = ?", beach.ID).Order("tour_id").Pluck("tour_id", &links).Error)
+	assert.Equal(t, []uint{both.ID, only.ID}, links)
+	island = reloadCategory(t, db, island.ID)
+	require.NotNil(t, island.ParentID)
+	assert.Equal(t, beach.ID, *island.ParentID)
+	assert.Equal(t, []uint{beach.ID}, island.AncestorIDs())
+
+	got, err := svc.GetCategoryBySlug(ctx, dup.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, beach.ID, got.ID)
+	assert.Error(t, db.First(&models.Category{}, dup.ID).Error)
+}
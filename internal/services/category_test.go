@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupCategoryService() (*CategoryService, *testutil.MockCategoryRepo, *testutil.MockSlugHistoryRepo) {
+	repo := new(testutil.MockCategoryRepo)
+	slugRepo := new(testutil.MockSlugHistoryRepo)
+	return NewCategoryService(repo, slugRepo), repo, slugRepo
+}
+
+func childOf(parentID uint) *uint {
+	return &parentID
+}
+
+// --- tree -------------------------------------------------------------
+
+func TestListCategories_NestsInSiblingOrder(t *testing.T) {
+	svc, repo, _ := setupCategoryService()
+	repo.On("FindAll", mock.Anything).Return([]models.Category{
+		{ID: 1, Name: "Miền Bắc"},
+		{ID: 3, Name: "Sa Pa", ParentID: childOf(1)},
+		{ID: 2, Name: "Miền Nam"},
+		{ID: 4, Name: "Hà Giang", ParentID: childOf(1)},
+		{ID: 5, Name: "Côn Đảo", ParentID: childOf(9)},
+	}, nil)
+
+	trees, err := svc.ListCategories(context.Background())
+
+	require.NoError(t, err)
+	var roots []string
+	for _, tree := range trees {
+		roots = append(roots, tree.Name)
+	}
+	// Côn Đảo's parent is not listed, so it shows at the top level.
+	assert.Equal(t, []string{"Miền Bắc", "Miền Nam", "Côn Đảo"}, roots)
+	require.Len(t, trees[0].Children, 2)
+	assert.Equal(t, "Sa Pa", trees[0].Children[0].Name)
+	assert.Equal(t, "Hà Giang", trees[0].Children[1].Name)
+}
+
+func TestAllFlatCategories_SubtreeFollowsParent(t *testing.T) {
+	svc, repo, _ := setupCategoryService()
+	repo.On("FindAll", mock.Anything).Return([]models.Category{
+		{ID: 1, Name: "Miền Bắc"},
+		{ID: 2, Name: "Miền Nam"},
+		{ID: 3, Name: "Tây Bắc", ParentID: childOf(1)},
+		{ID: 4, Name: "Sa Pa", ParentID: childOf(3)},
+	}, nil)
+
+	flat, err := svc.AllFlatCategories(context.Background())
+
+	require.NoError(t, err)
+	var names []string
+	for _, c := range flat {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"Miền Bắc", "Tây Bắc", "Sa Pa", "Miền Nam"}, names)
+}
+
+// --- CreateCategory ---------------------------------------------------
+
+func TestCreateCategory_SlugTaken(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    CategoryForm
+		slug    string
+		wantMsg string
+	}{
+		{"generated from the name", CategoryForm{Name: "Miền Bắc"}, "mien-bac", appErrors.ErrMsgCategoryNameDuplicate},
+		{"pinned by the admin", CategoryForm{Name: "Miền Bắc", Slug: "bac", SlugPinned: true}, "bac", appErrors.ErrMsgCategorySlugDuplicate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := setupCategoryService()
+			repo.On("ExistsBySlug", mock.Anything, tt.slug).Return(true, nil)
+
+			err := svc.CreateCategory(context.Background(), &tt.form)
+
+			assertAppError(t, err, tt.wantMsg)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateCategory_UnknownParent(t *testing.T) {
+	svc, repo, _ := setupCategoryService()
+	repo.On("ExistsBySlug", mock.Anything, "sa-pa").Return(false, nil)
+	repo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := svc.CreateCategory(context.Background(), &CategoryForm{Name: "Sa Pa", ParentID: 9})
+
+	assertAppError(t, err, appErrors.ErrMsgCategoryParentNotFound)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// --- DeleteCategory ---------------------------------------------------
+
+func TestDeleteCategory_KeepsUsedCategory(t *testing.T) {
+	t.Run("has subcategories", func(t *testing.T) {
+		svc, repo, _ := setupCategoryService()
+		repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1}, nil)
+		repo.On("HasChildren", mock.Anything, uint(1)).Return(true, nil)
+
+		err := svc.DeleteCategory(context.Background(), 1)
+
+		assertAppError(t, err, appErrors.ErrMsgCategoryCannotDeleteWithChildren)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("has tours", func(t *testing.T) {
+		svc, repo, _ := setupCategoryService()
+		repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1}, nil)
+		repo.On("HasChildren", mock.Anything, uint(1)).Return(false, nil)
+		repo.On("HasTours", mock.Anything, uint(1)).Return(true, nil)
+
+		err := svc.DeleteCategory(context.Background(), 1)
+
+		assert.ErrorIs(t, err, appErrors.ErrCategoryHasTours)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+// --- MoveCategory -----------------------------------------------------
+
+func TestMoveCategory_RejectsInvalidParent(t *testing.T) {
+	tests := []struct {
+		name     string
+		parentID uint
+		wantMsg  string
+	}{
+		{"itself", 1, appErrors.ErrMsgCategorySelfParent},
+		{"own descendant", 3, appErrors.ErrMsgCategoryChildAsParent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := setupCategoryService()
+			repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1}, nil)
+			repo.On("GetDescendantIDs", mock.Anything, uint(1)).Return([]uint{2, 3}, nil)
+
+			err := svc.MoveCategory(context.Background(), 1, tt.parentID)
+
+			assertAppError(t, err, tt.wantMsg)
+			repo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMoveCategory_SameParentIsNoOp(t *testing.T) {
+	svc, repo, _ := setupCategoryService()
+	repo.On("FindByID", mock.Anything, uint(3)).Return(&models.Category{ID: 3, ParentID: childOf(1)}, nil)
+	repo.On("GetDescendantIDs", mock.Anything, uint(3)).Return([]uint{}, nil)
+	repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1}, nil)
+
+	require.NoError(t, svc.MoveCategory(context.Background(), 3, 1))
+	repo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMoveCategory_ToTopLevel(t *testing.T) {
+	svc, repo, _ := setupCategoryService()
+	repo.On("FindByID", mock.Anything, uint(3)).Return(&models.Category{ID: 3, ParentID: childOf(1)}, nil)
+	repo.On("Move", mock.Anything, uint(3), (*uint)(nil)).Return(nil)
+
+	require.NoError(t, svc.MoveCategory(context.Background(), 3, 0))
+	repo.AssertExpectations(t)
+}
+
+// --- ReorderCategories ------------------------------------------------
+
+func TestReorderCategories_MustListExactlyTheChildren(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []uint
+	}{
+		{"missing a child", []uint{4, 2}},
+		{"foreign category", []uint{4, 2, 7}},
+		{"repeated child", []uint{4, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := setupCategoryService()
+			repo.On("ChildIDs", mock.Anything, childOf(1)).Return([]uint{2, 3, 4}, nil)
+
+			err := svc.ReorderCategories(context.Background(), 1, tt.ids)
+
+			assertAppError(t, err, appErrors.ErrMsgCategoryReorderInvalid)
+			repo.AssertNotCalled(t, "UpdatePositions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReorderCategories_TopLevel(t *testing.T) {
+	svc, repo, _ := setupCategoryService()
+	repo.On("ChildIDs", mock.Anything, (*uint)(nil)).Return([]uint{1, 2}, nil)
+	repo.On("UpdatePositions", mock.Anything, []uint{2, 1}).Return(nil)
+
+	require.NoError(t, svc.ReorderCategories(context.Background(), 0, []uint{2, 1}))
+	repo.AssertExpectations(t)
+}
+
+// --- MergeCategory ----------------------------------------------------
+
+func TestMergeCategory_RejectedMerges(t *testing.T) {
+	t.Run("into itself", func(t *testing.T) {
+		svc, repo, _ := setupCategoryService()
+
+		_, err := svc.MergeCategory(context.Background(), 1, 1)
+
+		assertAppError(t, err, appErrors.ErrMsgCategoryMergeSelf)
+		repo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		svc, repo, _ := setupCategoryService()
+		repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1}, nil)
+		repo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.MergeCategory(context.Background(), 1, 9)
+
+		assertAppError(t, err, appErrors.ErrMsgCategoryMergeTargetNotFound)
+	})
+
+	t.Run("into own descendant", func(t *testing.T) {
+		svc, repo, _ := setupCategoryService()
+		repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1}, nil)
+		repo.On("FindByID", mock.Anything, uint(3)).Return(&models.Category{ID: 3, ParentID: childOf(1)}, nil)
+		repo.On("GetDescendantIDs", mock.Anything, uint(1)).Return([]uint{3}, nil)
+
+		_, err := svc.MergeCategory(context.Background(), 1, 3)
+
+		assertAppError(t, err, appErrors.ErrMsgCategoryMergeIntoChild)
+		repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMergeCategory_RedirectsEverySourceSlug(t *testing.T) {
+	svc, repo, slugRepo := setupCategoryService()
+	source := &models.Category{ID: 2, Name: "Du lịch biển", Slug: "du-lich-bien", Translations: []models.CategoryTranslation{
+		{Locale: "en", Slug: "beach-holidays"},
+	}}
+	repo.On("FindByID", mock.Anything, uint(2)).Return(source, nil)
+	repo.On("FindByID", mock.Anything, uint(1)).Return(&models.Category{ID: 1, Name: "Biển", Slug: "bien"}, nil)
+	repo.On("GetDescendantIDs", mock.Anything, uint(2)).Return([]uint{}, nil)
+	repo.On("Merge", mock.Anything, uint(2), uint(1)).Return(&repository.CategoryMergeResult{Tours: 3, Children: 1}, nil)
+	slugRepo.On("RecordChange", mock.Anything, constants.SlugEntityCategory, uint(1), "du-lich-bien", "bien").Return(nil)
+	slugRepo.On("RecordChange", mock.Anything, constants.SlugEntityCategory, uint(1), "beach-holidays", "bien").Return(nil)
+
+	merged, err := svc.MergeCategory(context.Background(), 2, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, &CategoryMerge{Source: "Du lịch biển", Target: "Biển", Tours: 3, Children: 1}, merged)
+	slugRepo.AssertExpectations(t)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// DepartureService enforces tour participant minimums: schedules become
+// guaranteed once enough travellers are confirmed, and those still short at
+// the cutoff before departure are cancelled and refunded.
+type DepartureService struct {
+	db           *gorm.DB
+	scheduleRepo repository.ScheduleRepo
+	bookingRepo  repository.BookingRepo
+	emailService *EmailService
+	baseURL      string
+	cutoff       time.Duration
+}
+
+func NewDepartureService(db *gorm.DB, scheduleRepo repository.ScheduleRepo, bookingRepo repository.BookingRepo, emailService *EmailService, baseURL string, cutoff time.Duration) *DepartureService {
+	return &DepartureService{
+		db:           db,
+		scheduleRepo: scheduleRepo,
+		bookingRepo:  bookingRepo,
+		emailService: emailService,
+		baseURL:      baseURL,
+		cutoff:       cutoff,
+	}
+}
+
+// RunCutoff checks every schedule departing within the cutoff window and
+// cancels the ones that have not reached their minimum. It returns the number
+// of schedules cancelled.
+func (s *DepartureService) RunCutoff(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.scheduleRepo.FindDueForCutoff(ctx, now, now.Add(s.cutoff))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxDepartureServiceCutoff, err)
+	}
+
+	cancelled := 0
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		guaranteed, err := refreshGuaranteed(ctx, s.scheduleRepo, schedule)
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogDepartureCutoffFailed, "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		if guaranteed {
+			continue
+		}
+
+		if err := s.cancelUnderSubscribed(ctx, schedule); err != nil {
+			slog.ErrorContext(ctx, messages.LogDepartureCutoffFailed, "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// cancelUnderSubscribed cancels the schedule and its open bookings, refunds
+// successful payments, then emails every affected traveller.
+func (s *DepartureService) cancelUnderSubscribed(ctx context.Context, schedule *models.TourSchedule) error {
+	passengers, err := s.bookingRepo.FindPassengersBySchedule(ctx, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxDepartureServiceCancel, err)
+	}
+
+	var refundedIDs []uint
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TourSchedule{}).
+			Where("id = ? AND guaranteed = ? AND status IN ?", schedule.ID, false, []string{
+				constants.ScheduleStatusOpen,
+				constants.ScheduleStatusFull,
+			}).
+			Update("status", constants.ScheduleStatusCancelled)
+		if result.Error != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleUpdate, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return appErrors.ErrScheduleNotOpen
+		}
+
+		openBookings := tx.Model(&models.Booking{}).Select("id").
+			Where("schedule_id = ? AND status IN ?", schedule.ID, []string{
+				constants.BookingStatusPending,
+				constants.BookingStatusConfirmed,
+			})
+
+		if err := tx.Model(&models.Payment{}).
+			Where("status = ? AND booking_id IN (?)", constants.PaymentStatusSuccess, openBookings).
+			Pluck("booking_id", &refundedIDs).Error; err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxDepartureServiceCancel, err)
+		}
+		if err := tx.Model(&models.Payment{}).
+			Where("status = ? AND booking_id IN (?)", constants.PaymentStatusSuccess, openBookings).
+			Update("status", constants.PaymentStatusRefunded).Error; err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxDepartureServiceCancel, err)
+		}
+
+		if err := tx.Model(&models.Booking{}).
+			Where("schedule_id = ? AND status IN ?", schedule.ID, []string{
+				constants.BookingStatusPending,
+				constants.BookingStatusConfirmed,
+			}).
+			Update("status", constants.BookingStatusCancelled).Error; err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxBookingUpdateStatus, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, messages.LogDepartureScheduleCancelled, "schedule_id", schedule.ID, "bookings", len(passengers))
+
+	refunded := make(map[uint]bool, len(refundedIDs))
+	for _, id := range refundedIDs {
+		refunded[id] = true
+	}
+	tourURL := s.baseURL + constants.RoutePublicTours
+	if schedule.Tour != nil {
+		tourURL += "/" + schedule.Tour.Slug
+	}
+	for i := range passengers {
+		b := &passengers[i]
+		if b.Status != constants.BookingStatusPending && b.Status != constants.BookingStatusConfirmed {
+			continue
+		}
+		if err := s.emailService.SendScheduleCancelledEmail(b, schedule, tourURL, refunded[b.ID]); err != nil {
+			slog.ErrorContext(ctx, messages.LogDepartureNotifyFailed, "booking_id", b.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// refreshGuaranteed recomputes the schedule's guaranteed flag from its
+// confirmed participants and reports whether it is guaranteed. The flag is
+// cleared again when cancellations drop the count below the tour minimum, so
+// the cutoff job picks the schedule up again. The schedule must have its Tour
+// preloaded.
+func refreshGuaranteed(ctx context.Context, repo repository.ScheduleRepo, schedule *models.TourSchedule) (bool, error) {
+	if schedule.Tour == nil || schedule.Tour.MinParticipants <= 0 {
+		return schedule.Guaranteed, nil
+	}
+
+	confirmed, err := repo.CountConfirmedParticipants(ctx, schedule.ID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxDepartureServiceRefresh, err)
+	}
+	guaranteed := confirmed >= schedule.Tour.MinParticipants
+	if guaranteed == schedule.Guaranteed {
+		return guaranteed, nil
+	}
+
+	if err := repo.SetGuaranteed(ctx, schedule.ID, guaranteed); err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxDepartureServiceRefresh, err)
+	}
+	schedule.Guaranteed = guaranteed
+	return guaranteed, nil
+}
@@ -0,0 +1,114 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupDepartureServiceDB(t *testing.T) (*DepartureService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+
+	svc := NewDepartureService(db,
+		repository.NewScheduleRepository(db),
+		repository.NewBookingRepository(db),
+		NewEmailService(&config.Config{}),
+		"http://localhost",
+		72*time.Hour,
+	)
+	return svc, db
+}
+
+func seedDeparture(t *testing.T, db *gorm.DB, minParticipants int, departure time.Time, confirmed int) (*models.TourSchedule, *models.Booking) {
+	t.Helper()
+	user := models.User{Email: "traveller@example.com", FullName: "Traveller", Role: constants.RoleUser, Status: constants.StatusActive}
+	require.NoError(t, db.Create(&user).Error)
+
+	tour := models.Tour{Title: "Tour", Slug: "tour", Price: 100, DurationDays: 2, MaxParticipants: 20, MinParticipants: minParticipants, Status: constants.TourStatusActive}
+	require.NoError(t, db.Create(&tour).Error)
+
+	schedule := models.TourSchedule{TourID: tour.ID, DepartureDate: departure, ReturnDate: departure.AddDate(0, 0, 2), AvailableSlots: 20, Status: constants.ScheduleStatusOpen}
+	require.NoError(t, db.Create(&schedule).Error)
+
+	booking := models.Booking{UserID: user.ID, TourID: tour.ID, ScheduleID: schedule.ID, NumParticipants: confirmed, TotalPrice: 100, Status: constants.BookingStatusConfirmed}
+	require.NoError(t, db.Create(&booking).Error)
+
+	payment := models.Payment{BookingID: booking.ID, Amount: 100, TransactionID: "tx-1", Status: constants.PaymentStatusSuccess}
+	require.NoError(t, db.Create(&payment).Error)
+
+	return &schedule, &booking
+}
+
+// --- RunCutoff --------------------------------------------------------
+
+func TestRunCutoff_UnderSubscribed_CancelsAndRefunds(t *testing.T) {
+	svc, db := setupDepartureServiceDB(t)
+	now := time.Now()
+	schedule, booking := seedDeparture(t, db, 5, now.Add(24*time.Hour), 2)
+
+	cancelled, err := svc.RunCutoff(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelled)
+
+	var gotSchedule models.TourSchedule
+	require.NoError(t, db.First(&gotSchedule, schedule.ID).Error)
+	assert.Equal(t, constants.ScheduleStatusCancelled, gotSchedule.Status)
+
+	var gotBooking models.Booking
+	require.NoError(t, db.Preload("Payments").First(&gotBooking, booking.ID).Error)
+	assert.Equal(t, constants.BookingStatusCancelled, gotBooking.Status)
+	require.Len(t, gotBooking.Payments, 1)
+	assert.Equal(t, constants.PaymentStatusRefunded, gotBooking.Payments[0].Status)
+}
+
+func TestRunCutoff_OutsideWindow_Untouched(t *testing.T) {
+	svc, db := setupDepartureServiceDB(t)
+	now := time.Now()
+	schedule, _ := seedDeparture(t, db, 5, now.Add(10*24*time.Hour), 1)
+
+	cancelled, err := svc.RunCutoff(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, cancelled)
+
+	var got models.TourSchedule
+	require.NoError(t, db.First(&got, schedule.ID).Error)
+	assert.Equal(t, constants.ScheduleStatusOpen, got.Status)
+}
+
+func TestCancelAfterGuarantee_ClearsFlagAndCutoffCancels(t *testing.T) {
+	svc, db := setupDepartureServiceDB(t)
+	ctx := context.Background()
+	now := time.Now()
+	schedule, booking := seedDeparture(t, db, 5, now.Add(24*time.Hour), 5)
+	require.NoError(t, db.Model(&models.TourSchedule{}).Where("id = ?", schedule.ID).Update("guaranteed", true).Error)
+
+	bookingSvc := NewBookingService(db, repository.NewBookingRepository(db), repository.NewScheduleRepository(db))
+	require.NoError(t, bookingSvc.CancelBooking(ctx, booking.ID, booking.UserID))
+
+	var got models.TourSchedule
+	require.NoError(t, db.First(&got, schedule.ID).Error)
+	assert.False(t, got.Guaranteed)
+
+	cancelled, err := svc.RunCutoff(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelled)
+	require.NoError(t, db.First(&got, schedule.ID).Error)
+	assert.Equal(t, constants.ScheduleStatusCancelled, got.Status)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupDepartureService(scheduleRepo *testutil.MockScheduleRepo, bookingRepo *testutil.MockBookingRepo) *DepartureService {
+	return NewDepartureService(nil, scheduleRepo, bookingRepo, NewEmailService(&config.Config{}), "http://localhost", 72*time.Hour)
+}
+
+func dueSchedule(minParticipants int, guaranteed bool) models.TourSchedule {
+	return models.TourSchedule{
+		ID:         4,
+		Guaranteed: guaranteed,
+		Tour:       &models.Tour{ID: 2, Slug: "tour", MinParticipants: minParticipants},
+	}
+}
+
+// --- RunCutoff --------------------------------------------------------
+
+func TestRunCutoff_LooksAheadByTheCutoff(t *testing.T) {
+	scheduleRepo := new(testutil.MockScheduleRepo)
+	svc := setupDepartureService(scheduleRepo, nil)
+	now := time.Now()
+	scheduleRepo.On("FindDueForCutoff", mock.Anything, now, now.Add(72*time.Hour)).Return([]models.TourSchedule{}, nil)
+
+	cancelled, err := svc.RunCutoff(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+	scheduleRepo.AssertExpectations(t)
+}
+
+func TestRunCutoff_ThresholdReached_MarksGuaranteed(t *testing.T) {
+	scheduleRepo := new(testutil.MockScheduleRepo)
+	svc := setupDepartureService(scheduleRepo, nil)
+	scheduleRepo.On("FindDueForCutoff", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.TourSchedule{dueSchedule(5, false)}, nil)
+	scheduleRepo.On("CountConfirmedParticipants", mock.Anything, uint(4)).Return(5, nil)
+	scheduleRepo.On("SetGuaranteed", mock.Anything, uint(4), true).Return(nil)
+
+	cancelled, err := svc.RunCutoff(context.Background(), time.Now())
+
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+	scheduleRepo.AssertExpectations(t)
+}
+
+func TestRunCutoff_AlreadyGuaranteed_LeavesFlag(t *testing.T) {
+	scheduleRepo := new(testutil.MockScheduleRepo)
+	svc := setupDepartureService(scheduleRepo, nil)
+	scheduleRepo.On("FindDueForCutoff", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.TourSchedule{dueSchedule(5, true)}, nil)
+	scheduleRepo.On("CountConfirmedParticipants", mock.Anything, uint(4)).Return(6, nil)
+
+	cancelled, err := svc.RunCutoff(context.Background(), time.Now())
+
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+	scheduleRepo.AssertNotCalled(t, "SetGuaranteed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunCutoff_NoMinimum_NeverCancels(t *testing.T) {
+	scheduleRepo := new(testutil.MockScheduleRepo)
+	bookingRepo := new(testutil.MockBookingRepo)
+	svc := setupDepartureService(scheduleRepo, bookingRepo)
+	scheduleRepo.On("FindDueForCutoff", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.TourSchedule{dueSchedule(0, true)}, nil)
+
+	cancelled, err := svc.RunCutoff(context.Background(), time.Now())
+
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+	scheduleRepo.AssertNotCalled(t, "CountConfirmedParticipants", mock.Anything, mock.Anything)
+	bookingRepo.AssertNotCalled(t, "FindPassengersBySchedule", mock.Anything, mock.Anything)
+}
+
+func TestRunCutoff_SkipsScheduleThatFails(t *testing.T) {
+	scheduleRepo := new(testutil.MockScheduleRepo)
+	bookingRepo := new(testutil.MockBookingRepo)
+	svc := setupDepartureService(scheduleRepo, bookingRepo)
+	scheduleRepo.On("FindDueForCutoff", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.TourSchedule{dueSchedule(5, false)}, nil)
+	scheduleRepo.On("CountConfirmedParticipants", mock.Anything, uint(4)).Return(0, errors.New("db down"))
+
+	cancelled, err := svc.RunCutoff(context.Background(), time.Now())
+
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+	bookingRepo.AssertNotCalled(t, "FindPassengersBySchedule", mock.Anything, mock.Anything)
+}
@@ -12,6 +12,8 @@ import (
 
 	"sun-booking-tours/internal/config"
 	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/utils"
 )
 
 //go:embed email_templates/*.html
@@ -21,11 +23,61 @@ var verifyEmailTmpl = template.Must(
 	template.ParseFS(emailTemplatesFS, "email_templates/verify_email.html"),
 )
 
+var scheduleCancelledTmpl = template.Must(
+	template.ParseFS(emailTemplatesFS, "email_templates/schedule_cancelled.html"),
+)
+
+var wishlistUpdateTmpl = template.Must(
+	template.ParseFS(emailTemplatesFS, "email_templates/wishlist_update.html"),
+)
+
+var ratingReplyTmpl = template.Must(
+	template.ParseFS(emailTemplatesFS, "email_templates/rating_reply.html"),
+)
+
+var contentWarningTmpl = template.Must(
+	template.ParseFS(emailTemplatesFS, "email_templates/content_warning.html"),
+)
+
 type verifyEmailData struct {
 	FullName  string
 	VerifyURL string
 }
 
+type scheduleCancelledData struct {
+	FullName      string
+	TourTitle     string
+	TourURL       string
+	DepartureDate string
+	BookingID     uint
+	Refunded      bool
+}
+
+type wishlistUpdateData struct {
+	FullName       string
+	TourTitle      string
+	TourURL        string
+	PriceDropped   bool
+	OldPrice       string
+	NewPrice       string
+	DepartureDates []string
+}
+
+type ratingReplyData struct {
+	FullName  string
+	TourTitle string
+	TourURL   string
+	Score     int
+	Comment   string
+	Reply     string
+}
+
+type contentWarningData struct {
+	FullName string
+	Excerpt  string
+	Reasons  string
+}
+
 type EmailService struct {
 	host     string
 	port     string
@@ -77,6 +129,109 @@ func (s *EmailService) SendVerificationEmail(toEmail, fullName, verifyURL string
 	return s.sendHTML(toEmail, subject, buf.String())
 }
 
+// SendScheduleCancelledEmail tells a traveller their departure was cancelled
+// for not reaching the minimum number of participants.
+// The booking must have its User preloaded.
+func (s *EmailService) SendScheduleCancelledEmail(booking *models.Booking, schedule *models.TourSchedule, tourURL string, refunded bool) error {
+	if !s.enabled || booking.User == nil {
+		return nil
+	}
+
+	subject := "SUN Booking Tours — Chuyến đi của bạn đã bị hủy"
+
+	data := scheduleCancelledData{
+		FullName:      booking.User.FullName,
+		TourURL:       tourURL,
+		DepartureDate: schedule.DepartureDate.Format("02/01/2006"),
+		BookingID:     booking.ID,
+		Refunded:      refunded,
+	}
+	if schedule.Tour != nil {
+		data.TourTitle = schedule.Tour.Title
+	}
+
+	var buf bytes.Buffer
+	if err := scheduleCancelledTmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("render email template: %w", err)
+	}
+
+	return s.sendHTML(booking.User.Email, subject, buf.String())
+}
+
+// SendWishlistUpdateEmail tells a user that a tour they saved got cheaper
+// or has new departures. The item must have its User and Tour preloaded.
+func (s *EmailService) SendWishlistUpdateEmail(item *models.Wishlist, tourURL string, newSchedules []models.TourSchedule) error {
+	if !s.enabled || item.User == nil || item.Tour == nil {
+		return nil
+	}
+
+	subject := "SUN Booking Tours — Tour bạn yêu thích có cập nhật mới"
+
+	data := wishlistUpdateData{
+		FullName:     item.User.FullName,
+		TourTitle:    item.Tour.Title,
+		TourURL:      tourURL,
+		PriceDropped: item.Tour.Price < item.NotifiedPrice,
+		OldPrice:     utils.FormatPrice(item.NotifiedPrice),
+		NewPrice:     utils.FormatPrice(item.Tour.Price),
+	}
+	for _, sc := range newSchedules {
+		data.DepartureDates = append(data.DepartureDates, sc.DepartureDate.Format("02/01/2006"))
+	}
+
+	var buf bytes.Buffer
+	if err := wishlistUpdateTmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("render email template: %w", err)
+	}
+
+	return s.sendHTML(item.User.Email, subject, buf.String())
+}
+
+// SendRatingReplyEmail tells the author of a rating that the operator
+// answered it. The rating must have its User and Tour preloaded.
+func (s *EmailService) SendRatingReplyEmail(rating *models.Rating, reply *models.RatingReply, tourURL string) error {
+	if !s.enabled || rating.User == nil || rating.Tour == nil {
+		return nil
+	}
+
+	subject := "SUN Booking Tours — Đánh giá của bạn đã được phản hồi"
+
+	var buf bytes.Buffer
+	if err := ratingReplyTmpl.Execute(&buf, ratingReplyData{
+		FullName:  rating.User.FullName,
+		TourTitle: rating.Tour.Title,
+		TourURL:   tourURL,
+		Score:     rating.Score,
+		Comment:   rating.Comment,
+		Reply:     reply.Content,
+	}); err != nil {
+		return fmt.Errorf("render email template: %w", err)
+	}
+
+	return s.sendHTML(rating.User.Email, subject, buf.String())
+}
+
+// SendContentWarningEmail warns a user that a review or comment of theirs
+// was reported by other users and breaks the community guidelines.
+func (s *EmailService) SendContentWarningEmail(user *models.User, excerpt, reasons string) error {
+	if !s.enabled || user == nil {
+		return nil
+	}
+
+	subject := "SUN Booking Tours — Cảnh cáo vi phạm tiêu chuẩn cộng đồng"
+
+	var buf bytes.Buffer
+	if err := contentWarningTmpl.Execute(&buf, contentWarningData{
+		FullName: user.FullName,
+		Excerpt:  excerpt,
+		Reasons:  reasons,
+	}); err != nil {
+		return fmt.Errorf("render email template: %w", err)
+	}
+
+	return s.sendHTML(user.Email, subject, buf.String())
+}
+
 func sanitizeHeaderValue(s string) string {
 	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
 }
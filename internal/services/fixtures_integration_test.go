@@ -0,0 +1,43 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedUser creates an active user; name must be unique within the test.
+func seedUser(t *testing.T, db *gorm.DB, name, role string) models.User {
+	t.Helper()
+	email := name + "@example.com"
+	user := models.User{Email: email, FullName: name, Role: role, Status: constants.StatusActive, VerifyToken: email}
+	require.NoError(t, db.Create(&user).Error)
+	return user
+}
+
+// seedBookingOn books one seat of a new schedule of the tour, departing
+// at departure.
+func seedBookingOn(t *testing.T, db *gorm.DB, userID, tourID uint, departure time.Time, status string) models.Booking {
+	t.Helper()
+	schedule := models.TourSchedule{TourID: tourID, DepartureDate: departure, ReturnDate: departure.AddDate(0, 0, 2), AvailableSlots: 10, Status: constants.ScheduleStatusOpen}
+	require.NoError(t, db.Create(&schedule).Error)
+	booking := models.Booking{UserID: userID, TourID: tourID, ScheduleID: schedule.ID, NumParticipants: 1, TotalPrice: 100, Status: status}
+	require.NoError(t, db.Create(&booking).Error)
+	return booking
+}
+
+func createActiveTour(t *testing.T, svc *TourService, db *gorm.DB, title string) *models.Tour {
+	t.Helper()
+	require.NoError(t, svc.CreateTour(context.Background(), tourForm(title)))
+	var tour models.Tour
+	require.NoError(t, db.Where("title = ?", title).First(&tour).Error)
+	return &tour
+}
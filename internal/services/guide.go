@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type GuideService struct {
+	repo         repository.ScheduleGuideRepo
+	scheduleRepo repository.ScheduleRepo
+	userRepo     repository.UserRepo
+	bookingRepo  repository.BookingRepo
+}
+
+func NewGuideService(repo repository.ScheduleGuideRepo, scheduleRepo repository.ScheduleRepo, userRepo repository.UserRepo, bookingRepo repository.BookingRepo) *GuideService {
+	return &GuideService{repo: repo, scheduleRepo: scheduleRepo, userRepo: userRepo, bookingRepo: bookingRepo}
+}
+
+func (s *GuideService) ListGuides(ctx context.Context) ([]models.User, error) {
+	guides, err := s.userRepo.FindActiveByRole(ctx, constants.RoleGuide)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceList, err)
+	}
+	return guides, nil
+}
+
+func (s *GuideService) ListAssignments(ctx context.Context, scheduleID uint) ([]models.ScheduleGuide, error) {
+	assignments, err := s.repo.FindByScheduleID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceAssignments, err)
+	}
+	return assignments, nil
+}
+
+// AssignGuide links an active guide to a schedule, rejecting the assignment
+// when the guide already leads another schedule with overlapping dates.
+func (s *GuideService) AssignGuide(ctx context.Context, scheduleID, guideID uint) error {
+	schedule, err := s.scheduleRepo.FindByID(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.ErrScheduleNotFound
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceAssign, err)
+	}
+	if schedule.Status == constants.ScheduleStatusCancelled {
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgGuideScheduleCancelled)
+	}
+
+	guide, err := s.userRepo.FindByID(ctx, guideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgGuideNotFound)
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceAssign, err)
+	}
+	if guide.Role != constants.RoleGuide || guide.Status != constants.StatusActive {
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgGuideNotFound)
+	}
+
+	assigned, err := s.repo.IsAssigned(ctx, scheduleID, guideID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceAssign, err)
+	}
+	if assigned {
+		return appErrors.NewAppError(http.StatusConflict, appErrors.ErrMsgGuideAlreadyAssigned)
+	}
+
+	conflict, err := s.repo.HasConflict(ctx, guideID, scheduleID, schedule.DepartureDate, schedule.ReturnDate)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceAssign, err)
+	}
+	if conflict {
+		return appErrors.NewAppError(http.StatusConflict, fmt.Sprintf(appErrors.ErrMsgGuideScheduleConflict, guide.FullName))
+	}
+
+	// The (schedule_id, guide_id) primary key rejects a concurrent request that
+	// passed the IsAssigned check at the same time.
+	if err := s.repo.Assign(ctx, &models.ScheduleGuide{ScheduleID: scheduleID, GuideID: guideID}); err != nil {
+		if appErrors.IsDuplicateEntryError(err) {
+			return appErrors.NewAppError(http.StatusConflict, appErrors.ErrMsgGuideAlreadyAssigned)
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceAssign, err)
+	}
+	return nil
+}
+
+func (s *GuideService) UnassignGuide(ctx context.Context, scheduleID, guideID uint) error {
+	assigned, err := s.repo.IsAssigned(ctx, scheduleID, guideID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceUnassign, err)
+	}
+	if !assigned {
+		return appErrors.NewAppError(http.StatusNotFound, appErrors.ErrMsgGuideNotAssigned)
+	}
+
+	if err := s.repo.Unassign(ctx, scheduleID, guideID); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceUnassign, err)
+	}
+	return nil
+}
+
+// ListMySchedules returns the schedules a guide leads that have not yet ended.
+func (s *GuideService) ListMySchedules(ctx context.Context, guideID uint) ([]models.TourSchedule, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	schedules, err := s.repo.FindSchedulesByGuide(ctx, guideID, startOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceMySchedules, err)
+	}
+	return schedules, nil
+}
+
+// GetMySchedule returns a schedule and its passenger list, provided the guide
+// is assigned to it. Unassigned schedules are reported as not found.
+func (s *GuideService) GetMySchedule(ctx context.Context, guideID, scheduleID uint) (*models.TourSchedule, []models.Booking, error) {
+	assigned, err := s.repo.IsAssigned(ctx, scheduleID, guideID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceScheduleView, err)
+	}
+	if !assigned {
+		return nil, nil, appErrors.ErrScheduleNotFound
+	}
+
+	schedule, err := s.scheduleRepo.FindByID(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, appErrors.ErrScheduleNotFound
+		}
+		return nil, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceScheduleView, err)
+	}
+
+	passengers, err := s.bookingRepo.FindPassengersBySchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxGuideServiceScheduleView, err)
+	}
+	return schedule, passengers, nil
+}
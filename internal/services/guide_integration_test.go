@@ -0,0 +1,126 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupGuideServiceDB(t *testing.T) (*GuideService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+	svc := NewGuideService(
+		repository.NewScheduleGuideRepository(db),
+		repository.NewScheduleRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewBookingRepository(db),
+	)
+	return svc, db
+}
+
+func seedGuide(t *testing.T, db *gorm.DB, name, role, status string) models.User {
+	t.Helper()
+	email := name + "@example.com"
+	user := models.User{Email: email, FullName: name, Role: role, Status: status, VerifyToken: email}
+	require.NoError(t, db.Create(&user).Error)
+	return user
+}
+
+func seedGuideSchedule(t *testing.T, db *gorm.DB, departure time.Time, status string) models.TourSchedule {
+	t.Helper()
+	tour := models.Tour{Title: "Tour", Slug: "tour-" + departure.Format("20060102"), Price: 100, DurationDays: 2, MaxParticipants: 10, Status: constants.TourStatusActive}
+	require.NoError(t, db.Create(&tour).Error)
+	schedule := models.TourSchedule{TourID: tour.ID, DepartureDate: departure, ReturnDate: departure.AddDate(0, 0, 2), AvailableSlots: 10, Status: status}
+	require.NoError(t, db.Create(&schedule).Error)
+	return schedule
+}
+
+func TestListGuides_OnlyActiveGuides(t *testing.T) {
+	svc, db := setupGuideServiceDB(t)
+	seedGuide(t, db, "Binh", constants.RoleGuide, constants.StatusActive)
+	seedGuide(t, db, "An", constants.RoleGuide, constants.StatusActive)
+	seedGuide(t, db, "Chi", constants.RoleGuide, constants.StatusBanned)
+	seedGuide(t, db, "Dung", constants.RoleUser, constants.StatusActive)
+
+	guides, err := svc.ListGuides(context.Background())
+	require.NoError(t, err)
+	require.Len(t, guides, 2)
+	assert.Equal(t, "An", guides[0].FullName)
+	assert.Equal(t, "Binh", guides[1].FullName)
+}
+
+func TestAssignGuide_ListsAssignment(t *testing.T) {
+	svc, db := setupGuideServiceDB(t)
+	ctx := context.Background()
+	guide := seedGuide(t, db, "An", constants.RoleGuide, constants.StatusActive)
+	schedule := seedGuideSchedule(t, db, time.Now().AddDate(0, 0, 10), constants.ScheduleStatusOpen)
+
+	require.NoError(t, svc.AssignGuide(ctx, schedule.ID, guide.ID))
+	assertAppError(t, svc.AssignGuide(ctx, schedule.ID, guide.ID), appErrors.ErrMsgGuideAlreadyAssigned)
+
+	assignments, err := svc.ListAssignments(ctx, schedule.ID)
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	require.NotNil(t, assignments[0].Guide)
+	assert.Equal(t, guide.ID, assignments[0].Guide.ID)
+}
+
+func TestAssignGuide_RejectsOverlappingSchedule(t *testing.T) {
+	svc, db := setupGuideServiceDB(t)
+	ctx := context.Background()
+	departure := time.Now().AddDate(0, 0, 10)
+	guide := seedGuide(t, db, "An", constants.RoleGuide, constants.StatusActive)
+	first := seedGuideSchedule(t, db, departure, constants.ScheduleStatusOpen)
+	overlapping := seedGuideSchedule(t, db, departure.AddDate(0, 0, 1), constants.ScheduleStatusOpen)
+
+	require.NoError(t, svc.AssignGuide(ctx, first.ID, guide.ID))
+	err := svc.AssignGuide(ctx, overlapping.ID, guide.ID)
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Contains(t, appErr.Message, guide.FullName)
+}
+
+func TestAssignGuide_DuplicateInsertIsDuplicateEntry(t *testing.T) {
+	_, db := setupGuideServiceDB(t)
+	ctx := context.Background()
+	repo := repository.NewScheduleGuideRepository(db)
+	guide := seedGuide(t, db, "An", constants.RoleGuide, constants.StatusActive)
+	schedule := seedGuideSchedule(t, db, time.Now().AddDate(0, 0, 10), constants.ScheduleStatusOpen)
+
+	// A concurrent request that slipped past IsAssigned hits the primary key.
+	require.NoError(t, repo.Assign(ctx, &models.ScheduleGuide{ScheduleID: schedule.ID, GuideID: guide.ID}))
+	err := repo.Assign(ctx, &models.ScheduleGuide{ScheduleID: schedule.ID, GuideID: guide.ID})
+	require.Error(t, err)
+	assert.True(t, appErrors.IsDuplicateEntryError(err))
+}
+
+func TestUpdateUserRole_DemotedGuideLosesAssignments(t *testing.T) {
+	svc, db := setupGuideServiceDB(t)
+	ctx := context.Background()
+	admin := seedGuide(t, db, "Admin", constants.RoleAdmin, constants.StatusActive)
+	guide := seedGuide(t, db, "An", constants.RoleGuide, constants.StatusActive)
+	other := seedGuide(t, db, "Binh", constants.RoleGuide, constants.StatusActive)
+	schedule := seedGuideSchedule(t, db, time.Now().AddDate(0, 0, 10), constants.ScheduleStatusOpen)
+	require.NoError(t, svc.AssignGuide(ctx, schedule.ID, guide.ID))
+	require.NoError(t, svc.AssignGuide(ctx, schedule.ID, other.ID))
+
+	users := NewAdminUserService(db, repository.NewUserRepository(db))
+	require.NoError(t, users.UpdateUserRole(ctx, &admin, guide.ID, constants.RoleUser))
+
+	assignments, err := svc.ListAssignments(ctx, schedule.ID)
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.Equal(t, other.ID, assignments[0].GuideID)
+}
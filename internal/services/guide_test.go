@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+type guideMocks struct {
+	repo         *testutil.MockScheduleGuideRepo
+	scheduleRepo *testutil.MockScheduleRepo
+	userRepo     *testutil.MockUserRepo
+	bookingRepo  *testutil.MockBookingRepo
+}
+
+func setupGuideService() (*GuideService, *guideMocks) {
+	m := &guideMocks{
+		repo:         new(testutil.MockScheduleGuideRepo),
+		scheduleRepo: new(testutil.MockScheduleRepo),
+		userRepo:     new(testutil.MockUserRepo),
+		bookingRepo:  new(testutil.MockBookingRepo),
+	}
+	return NewGuideService(m.repo, m.scheduleRepo, m.userRepo, m.bookingRepo), m
+}
+
+func assertAppError(t *testing.T, err error, msg string) {
+	t.Helper()
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, msg, appErr.Message)
+}
+
+func openGuideSchedule() *models.TourSchedule {
+	departure := time.Now().AddDate(0, 0, 10)
+	return &models.TourSchedule{ID: 7, DepartureDate: departure, ReturnDate: departure.AddDate(0, 0, 2), Status: constants.ScheduleStatusOpen}
+}
+
+func activeGuide() *models.User {
+	return &models.User{ID: 3, FullName: "An", Role: constants.RoleGuide, Status: constants.StatusActive}
+}
+
+// --- AssignGuide ------------------------------------------------------
+
+func TestAssignGuide_Success(t *testing.T) {
+	svc, m := setupGuideService()
+	schedule, guide := openGuideSchedule(), activeGuide()
+	m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+	m.userRepo.On("FindByID", mock.Anything, guide.ID).Return(guide, nil)
+	m.repo.On("IsAssigned", mock.Anything, schedule.ID, guide.ID).Return(false, nil)
+	m.repo.On("HasConflict", mock.Anything, guide.ID, schedule.ID, schedule.DepartureDate, schedule.ReturnDate).Return(false, nil)
+	m.repo.On("Assign", mock.Anything, &models.ScheduleGuide{ScheduleID: schedule.ID, GuideID: guide.ID}).Return(nil)
+
+	require.NoError(t, svc.AssignGuide(context.Background(), schedule.ID, guide.ID))
+	m.repo.AssertExpectations(t)
+}
+
+func TestAssignGuide_ErrorCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m *guideMocks, schedule *models.TourSchedule, guide *models.User)
+		wantMsg string
+	}{
+		{
+			name: "schedule not found",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, _ *models.User) {
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).
+					Return(nil, fmt.Errorf("find schedule: %w", gorm.ErrRecordNotFound))
+			},
+			wantMsg: appErrors.ErrScheduleNotFound.Message,
+		},
+		{
+			name: "schedule cancelled",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, _ *models.User) {
+				schedule.Status = constants.ScheduleStatusCancelled
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+			},
+			wantMsg: appErrors.ErrMsgGuideScheduleCancelled,
+		},
+		{
+			name: "user is not a guide",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, guide *models.User) {
+				guide.Role = constants.RoleUser
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+				m.userRepo.On("FindByID", mock.Anything, guide.ID).Return(guide, nil)
+			},
+			wantMsg: appErrors.ErrMsgGuideNotFound,
+		},
+		{
+			name: "guide banned",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, guide *models.User) {
+				guide.Status = constants.StatusBanned
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+				m.userRepo.On("FindByID", mock.Anything, guide.ID).Return(guide, nil)
+			},
+			wantMsg: appErrors.ErrMsgGuideNotFound,
+		},
+		{
+			name: "already assigned",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, guide *models.User) {
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+				m.userRepo.On("FindByID", mock.Anything, guide.ID).Return(guide, nil)
+				m.repo.On("IsAssigned", mock.Anything, schedule.ID, guide.ID).Return(true, nil)
+			},
+			wantMsg: appErrors.ErrMsgGuideAlreadyAssigned,
+		},
+		{
+			name: "overlapping schedule",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, guide *models.User) {
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+				m.userRepo.On("FindByID", mock.Anything, guide.ID).Return(guide, nil)
+				m.repo.On("IsAssigned", mock.Anything, schedule.ID, guide.ID).Return(false, nil)
+				m.repo.On("HasConflict", mock.Anything, guide.ID, schedule.ID, schedule.DepartureDate, schedule.ReturnDate).Return(true, nil)
+			},
+			wantMsg: fmt.Sprintf(appErrors.ErrMsgGuideScheduleConflict, "An"),
+		},
+		{
+			name: "concurrent assignment hits the primary key",
+			setup: func(m *guideMocks, schedule *models.TourSchedule, guide *models.User) {
+				m.scheduleRepo.On("FindByID", mock.Anything, schedule.ID).Return(schedule, nil)
+				m.userRepo.On("FindByID", mock.Anything, guide.ID).Return(guide, nil)
+				m.repo.On("IsAssigned", mock.Anything, schedule.ID, guide.ID).Return(false, nil)
+				m.repo.On("HasConflict", mock.Anything, guide.ID, schedule.ID, schedule.DepartureDate, schedule.ReturnDate).Return(false, nil)
+				m.repo.On("Assign", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("assign guide: %w", &pgconn.PgError{Code: "23505"}))
+			},
+			wantMsg: appErrors.ErrMsgGuideAlreadyAssigned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupGuideService()
+			schedule, guide := openGuideSchedule(), activeGuide()
+			tt.setup(m, schedule, guide)
+
+			assertAppError(t, svc.AssignGuide(context.Background(), schedule.ID, guide.ID), tt.wantMsg)
+		})
+	}
+}
+
+// --- UnassignGuide ----------------------------------------------------
+
+func TestUnassignGuide_NotAssigned(t *testing.T) {
+	svc, m := setupGuideService()
+	m.repo.On("IsAssigned", mock.Anything, uint(7), uint(3)).Return(false, nil)
+
+	assertAppError(t, svc.UnassignGuide(context.Background(), 7, 3), appErrors.ErrMsgGuideNotAssigned)
+	m.repo.AssertNotCalled(t, "Unassign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUnassignGuide_Success(t *testing.T) {
+	svc, m := setupGuideService()
+	m.repo.On("IsAssigned", mock.Anything, uint(7), uint(3)).Return(true, nil)
+	m.repo.On("Unassign", mock.Anything, uint(7), uint(3)).Return(nil)
+
+	require.NoError(t, svc.UnassignGuide(context.Background(), 7, 3))
+	m.repo.AssertExpectations(t)
+}
+
+// --- GetMySchedule ----------------------------------------------------
+
+func TestGetMySchedule_UnassignedIsNotFound(t *testing.T) {
+	svc, m := setupGuideService()
+	m.repo.On("IsAssigned", mock.Anything, uint(7), uint(3)).Return(false, nil)
+
+	_, _, err := svc.GetMySchedule(context.Background(), 3, 7)
+
+	assert.ErrorIs(t, err, appErrors.ErrScheduleNotFound)
+	m.scheduleRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/storage"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	mediaMaxFiles     = 10
+	mediaMaxPixels    = 40_000_000
+	mediaMaxDimension = 1920
+	mediaThumbSize    = 480
+	mediaJPEGQuality  = 85
+	mediaWebPQuality  = "80"
+)
+
+var mediaAllowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// MediaService validates uploaded images, re-encodes them without metadata,
+// and stores the original, a thumbnail and their WebP variants.
+type MediaService struct {
+	storage  storage.Storage
+	maxBytes int64
+	webpBin  string
+}
+
+// NewMediaService resolves webpEncoder (a cwebp-compatible binary) on PATH.
+// When it is missing, uploads still succeed without WebP variants.
+func NewMediaService(store storage.Storage, maxBytes int64, webpEncoder string) *MediaService {
+	s := &MediaService{storage: store, maxBytes: maxBytes}
+	if webpEncoder != "" {
+		if bin, err := exec.LookPath(webpEncoder); err == nil {
+			s.webpBin = bin
+		} else {
+			slog.Warn(messages.LogMediaWebPUnavailable, "encoder", webpEncoder)
+		}
+	}
+	return s
+}
+
+// UploadImages processes files in order and stores them under prefix. If
+// any file fails, everything stored by this call is removed again.
+func (s *MediaService) UploadImages(ctx context.Context, prefix string, files []*multipart.FileHeader) ([]models.ImageAsset, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if len(files) > mediaMaxFiles {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgMediaTooMany, mediaMaxFiles))
+	}
+
+	var stored []string
+	assets := make([]models.ImageAsset, 0, len(files))
+	for _, fh := range files {
+		asset, keys, err := s.uploadImage(ctx, prefix, fh)
+		stored = append(stored, keys...)
+		if err != nil {
+			s.deleteKeys(ctx, stored)
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+func (s *MediaService) uploadImage(ctx context.Context, prefix string, fh *multipart.FileHeader) (models.ImageAsset, []string, error) {
+	data, err := s.readUpload(fh)
+	if err != nil {
+		return models.ImageAsset{}, nil, err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !mediaAllowedTypes[contentType] {
+		return models.ImageAsset{}, nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgMediaUnsupportedType, fh.Filename))
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > mediaMaxPixels {
+		return models.ImageAsset{}, nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgMediaInvalidImage, fh.Filename))
+	}
+	// Decoding and re-encoding drops EXIF and any other embedded metadata;
+	// the orientation tag is applied to the pixels first so photos taken
+	// sideways still display upright.
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return models.ImageAsset{}, nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgMediaInvalidImage, fh.Filename))
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	full := applyOrientation(fitImage(img, mediaMaxDimension), orientation)
+	thumb := applyOrientation(fitImage(img, mediaThumbSize), orientation)
+
+	ext, mimeType := "jpg", "image/jpeg"
+	if format == "png" || format == "gif" || !isOpaque(full) {
+		ext, mimeType = "png", "image/png"
+	}
+
+	base, err := mediaBaseKey(prefix)
+	if err != nil {
+		return models.ImageAsset{}, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxMediaStore, err)
+	}
+
+	bounds := full.Bounds()
+	asset := models.ImageAsset{Width: bounds.Dx(), Height: bounds.Dy()}
+	var keys []string
+
+	variants := []struct {
+		img     image.Image
+		key     string
+		url     *string
+		webpKey string
+		webpURL *string
+	}{
+		{full, base + "." + ext, &asset.URL, base + ".webp", &asset.WebP},
+		{thumb, base + "_thumb." + ext, &asset.Thumbnail, base + "_thumb.webp", &asset.ThumbnailWebP},
+	}
+	for _, v := range variants {
+		encoded, err := encodeImage(v.img, ext)
+		if err != nil {
+			return models.ImageAsset{}, keys, fmt.Errorf("%s: %w", appErrors.ErrCtxMediaEncode, err)
+		}
+		url, err := s.storage.Put(ctx, v.key, encoded, mimeType)
+		if err != nil {
+			return models.ImageAsset{}, keys, fmt.Errorf("%s: %w", appErrors.ErrCtxMediaStore, err)
+		}
+		keys = append(keys, v.key)
+		*v.url = url
+
+		webp, ok := s.encodeWebP(ctx, encoded, ext)
+		if !ok {
+			continue
+		}
+		url, err = s.storage.Put(ctx, v.webpKey, webp, "image/webp")
+		if err != nil {
+			return models.ImageAsset{}, keys, fmt.Errorf("%s: %w", appErrors.ErrCtxMediaStore, err)
+		}
+		keys = append(keys, v.webpKey)
+		*v.webpURL = url
+	}
+
+	return asset, keys, nil
+}
+
+func (s *MediaService) readUpload(fh *multipart.FileHeader) ([]byte, error) {
+	tooLarge := appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgMediaTooLarge, fh.Filename, s.maxBytes>>20))
+	if fh.Size > s.maxBytes {
+		return nil, tooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxMediaRead, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, s.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxMediaRead, err)
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, tooLarge
+	}
+	return data, nil
+}
+
+// encodeWebP pipes the already-encoded variant through the external
+// encoder. Failures are logged and the variant is skipped.
+func (s *MediaService) encodeWebP(ctx context.Context, encoded []byte, ext string) ([]byte, bool) {
+	if s.webpBin == "" {
+		return nil, false
+	}
+
+	dir, err := os.MkdirTemp("", "media-webp-")
+	if err != nil {
+		slog.WarnContext(ctx, messages.LogMediaWebPFailed, "error", err)
+		return nil, false
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in."+ext)
+	out := filepath.Join(dir, "out.webp")
+	if err := os.WriteFile(in, encoded, 0o600); err != nil {
+		slog.WarnContext(ctx, messages.LogMediaWebPFailed, "error", err)
+		return nil, false
+	}
+
+	cmd := exec.CommandContext(ctx, s.webpBin, "-quiet", "-q", mediaWebPQuality, in, "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		slog.WarnContext(ctx, messages.LogMediaWebPFailed, "error", err, "output", string(output))
+		return nil, false
+	}
+
+	webp, err := os.ReadFile(out)
+	if err != nil {
+		slog.WarnContext(ctx, messages.LogMediaWebPFailed, "error", err)
+		return nil, false
+	}
+	return webp, true
+}
+
+// DeleteImages removes the stored files of assets together with their
+// thumbnail and WebP variants, which are derived from the URL so that assets
+// saved as a bare link are cleaned up too. Only files stored under prefix are
+// touched; manually entered links and other kinds of content are left alone.
+func (s *MediaService) DeleteImages(ctx context.Context, prefix string, assets []models.ImageAsset) {
+	if len(assets) == 0 {
+		return
+	}
+	root := s.storage.URL(prefix + "/")
+	var keys []string
+	for _, a := range assets {
+		rest, ok := strings.CutPrefix(a.URL, root)
+		if !ok || rest == "" {
+			continue
+		}
+		key := prefix + "/" + rest
+		ext := path.Ext(key)
+		base := strings.TrimSuffix(key, ext)
+		keys = append(keys, key, base+"_thumb"+ext, base+".webp", base+"_thumb.webp")
+	}
+	s.deleteKeys(ctx, keys)
+}
+
+func (s *MediaService) deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, messages.LogMediaCleanupFailed, "key", key, "error", err)
+		}
+	}
+}
+
+// mediaBaseKey returns "<prefix>/<yyyy>/<mm>/<random>" without extension.
+func mediaBaseKey(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/%s", prefix, time.Now().Format("2006/01"), hex.EncodeToString(b)), nil
+}
+
+// fitImage scales img down so neither side exceeds maxSide. Smaller images
+// are copied unchanged into an NRGBA canvas.
+func fitImage(img image.Image, maxSide int) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			h = max(1, h*maxSide/w)
+			w = maxSide
+		} else {
+			w = max(1, w*maxSide/h)
+			h = maxSide
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if w == b.Dx() && h == b.Dy() {
+		draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+		return dst
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func isOpaque(img *image.NRGBA) bool {
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 0xff {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeImage(img image.Image, ext string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if ext == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: mediaJPEGQuality})
+	}
+	return buf.Bytes(), err
+}
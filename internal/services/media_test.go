@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupMediaService(t *testing.T, maxBytes int64) (*MediaService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	return NewMediaService(storage.NewLocalStorage(dir, "/uploads"), maxBytes, ""), dir
+}
+
+func fileHeaders(t *testing.T, files map[string][]byte) []*multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, data := range files {
+		part, err := w.CreateFormFile("image_files", name)
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(10 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = form.RemoveAll() })
+	return form.File["image_files"]
+}
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// --- UploadImages -----------------------------------------------------
+
+func TestUploadImages_StoresOriginalAndThumbnail(t *testing.T) {
+	svc, dir := setupMediaService(t, 5<<20)
+
+	assets, err := svc.UploadImages(context.Background(), "tours", fileHeaders(t, map[string][]byte{"a.png": pngBytes(t, 1000, 500)}))
+
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	asset := assets[0]
+	assert.Equal(t, 1000, asset.Width)
+	assert.Equal(t, 500, asset.Height)
+	assert.True(t, strings.HasPrefix(asset.URL, "/uploads/tours/"))
+	assert.True(t, strings.HasSuffix(asset.URL, ".png"))
+	assert.True(t, strings.HasSuffix(asset.Thumbnail, "_thumb.png"))
+	assert.Empty(t, asset.WebP)
+
+	thumbFile, err := os.Open(filepath.Join(dir, strings.TrimPrefix(asset.Thumbnail, "/uploads/")))
+	require.NoError(t, err)
+	defer thumbFile.Close()
+	cfg, err := png.DecodeConfig(thumbFile)
+	require.NoError(t, err)
+	assert.Equal(t, mediaThumbSize, cfg.Width)
+	assert.Equal(t, mediaThumbSize/2, cfg.Height)
+}
+
+func TestUploadImages_RejectsNonImage(t *testing.T) {
+	svc, dir := setupMediaService(t, 5<<20)
+
+	_, err := svc.UploadImages(context.Background(), "reviews", fileHeaders(t, map[string][]byte{"notes.txt": []byte("plain text, not an image")}))
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Contains(t, appErr.Message, "notes.txt")
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries)
+}
+
+func TestUploadImages_RejectsOversizedFile(t *testing.T) {
+	svc, _ := setupMediaService(t, 64)
+
+	_, err := svc.UploadImages(context.Background(), "reviews", fileHeaders(t, map[string][]byte{"big.png": pngBytes(t, 200, 200)}))
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Contains(t, appErr.Message, "big.png")
+}
+
+// --- EXIF orientation -------------------------------------------------
+
+// --- DeleteImages -----------------------------------------------------
+
+func TestDeleteImages_OnlyRemovesFilesUnderPrefix(t *testing.T) {
+	svc, dir := setupMediaService(t, 5<<20)
+	ctx := context.Background()
+	assets, err := svc.UploadImages(ctx, "tours", fileHeaders(t, map[string][]byte{"a.png": pngBytes(t, 600, 400)}))
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	stored := func(url string) bool {
+		_, err := os.Stat(filepath.Join(dir, strings.TrimPrefix(url, "/uploads/")))
+		return err == nil
+	}
+	require.True(t, stored(assets[0].URL))
+
+	svc.DeleteImages(ctx, "reviews", assets)
+	assert.True(t, stored(assets[0].URL))
+
+	svc.DeleteImages(ctx, "tours", append(assets, models.ImageAsset{URL: "https://cdn.example.com/tours/x.jpg"}))
+	assert.False(t, stored(assets[0].URL))
+	assert.False(t, stored(assets[0].Thumbnail))
+}
+
+func TestApplyOrientation_Rotate90(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	src.Set(1, 0, color.NRGBA{B: 255, A: 255})
+
+	got := applyOrientation(src, 6)
+
+	assert.Equal(t, image.Rect(0, 0, 1, 2), got.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, got.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{B: 255, A: 255}, got.NRGBAAt(0, 1))
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	// SOI, APP1 with a big-endian TIFF header and a single orientation entry.
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+	data := append([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, byte(len(exif) + 2)}, exif...)
+	data = append(data, 0xFF, 0xDA)
+
+	assert.Equal(t, 6, jpegOrientation(data))
+	assert.Equal(t, 1, jpegOrientation([]byte{0xFF, 0xD8, 0xFF, 0xDA}))
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tuning of the default rule chain.
+const (
+	bannedWordScore     = 50
+	linkScore           = 25
+	phoneScore          = 40
+	duplicateScore      = 50
+	velocityScore       = 40
+	duplicateWindow     = 7 * 24 * time.Hour
+	velocityWindow      = time.Hour
+	reviewVelocityMax   = 3
+	commentVelocityMax  = 10
+	maxModerationReason = 500
+)
+
+// defaultBannedWords are matched as whole words or phrases, case-insensitively.
+var defaultBannedWords = []string{
+	// Vietnamese
+	"địt", "đụ", "đéo", "lồn", "cặc", "buồi", "đĩ", "đm", "đmm", "dcm", "vcl", "vkl", "clgt", "óc chó",
+	// English
+	"fuck", "fucking", "shit", "bitch", "asshole", "bastard", "cunt", "dickhead", "motherfucker",
+}
+
+var (
+	reModerationLink  = regexp.MustCompile(`(?i)\bhttps?://\S+|\bwww\.\S+|\b[a-z0-9-]+\.(?:com|net|org|vn|info|xyz|top|io|biz|link|click)\b`)
+	reModerationPhone = regexp.MustCompile(`(?:\+84|\b84|\b0)(?:[\s.\-]?\d){8,10}\b`)
+)
+
+// ModerationInput is a piece of user content run through the rule chain.
+// ExcludeID is the review or comment being edited, so it is not compared
+// with itself.
+type ModerationInput struct {
+	Kind      string
+	UserID    uint
+	ExcludeID uint
+	Text      string
+}
+
+// ModerationHit is what a single rule found; a zero Score means it passed.
+type ModerationHit struct {
+	Score  int
+	Reason string
+}
+
+// ModerationRule is one step of the moderation chain. Rules only score
+// content; ModerationService adds the scores up and decides.
+type ModerationRule interface {
+	Check(ctx context.Context, input ModerationInput) (ModerationHit, error)
+}
+
+// ModerationVerdict is the combined result of the rule chain.
+type ModerationVerdict struct {
+	Score   int
+	Reasons []string
+}
+
+// Rejected reports whether the content should be rejected without review.
+func (v ModerationVerdict) Rejected() bool {
+	return v.Score >= constants.ModerationRejectScore
+}
+
+// Flagged reports whether the content needs an admin before it is shown.
+func (v ModerationVerdict) Flagged() bool {
+	return v.Score >= constants.ModerationFlagScore && !v.Rejected()
+}
+
+// Status maps the verdict onto the review and comment statuses, which
+// share the same values.
+func (v ModerationVerdict) Status() string {
+	switch {
+	case v.Rejected():
+		return constants.ReviewStatusRejected
+	case v.Flagged():
+		return constants.ReviewStatusPending
+	}
+	return constants.ReviewStatusApproved
+}
+
+// Apply records the verdict on the content; automatically rejected content
+// gets the rule reasons as its reject reason.
+func (v ModerationVerdict) Apply(m *models.Moderation) {
+	m.ModerationScore = v.Score
+	m.ModerationFlags = truncateReason(strings.Join(v.Reasons, models.ModerationFlagSeparator), 1000)
+	m.RejectReason = ""
+	if v.Rejected() {
+		m.RejectReason = truncateReason(fmt.Sprintf(messages.ModerationAutoRejectReason, strings.Join(v.Reasons, models.ModerationFlagSeparator)), maxModerationReason)
+	}
+}
+
+type ModerationService struct {
+	rules []ModerationRule
+}
+
+// NewModerationService runs the given rules in order on every submission.
+func NewModerationService(rules ...ModerationRule) *ModerationService {
+	return &ModerationService{rules: rules}
+}
+
+// DefaultModerationRules is the rule chain used by the site: banned words,
+// link and phone-number spam, duplicate content and posting velocity.
+func DefaultModerationRules(repo repository.ModerationRepo) []ModerationRule {
+	return []ModerationRule{
+		NewBannedWordsRule(defaultBannedWords),
+		NewLinkSpamRule(),
+		NewPhoneSpamRule(),
+		NewDuplicateContentRule(repo, duplicateWindow),
+		NewPostingVelocityRule(repo, velocityWindow, map[string]int{
+			constants.ModerationKindReview:  reviewVelocityMax,
+			constants.ModerationKindComment: commentVelocityMax,
+		}),
+	}
+}
+
+// Check runs every rule and sums their scores.
+func (s *ModerationService) Check(ctx context.Context, input ModerationInput) (ModerationVerdict, error) {
+	var verdict ModerationVerdict
+	for _, rule := range s.rules {
+		hit, err := rule.Check(ctx, input)
+		if err != nil {
+			return ModerationVerdict{}, fmt.Errorf("%s: %w", appErrors.ErrCtxModerationCheck, err)
+		}
+		if hit.Score <= 0 {
+			continue
+		}
+		verdict.Score += hit.Score
+		verdict.Reasons = append(verdict.Reasons, hit.Reason)
+	}
+	return verdict, nil
+}
+
+type bannedWordsRule struct {
+	phrases []string
+}
+
+// NewBannedWordsRule scores bannedWordScore for every distinct banned word
+// or phrase in the text.
+func NewBannedWordsRule(words []string) ModerationRule {
+	phrases := make([]string, 0, len(words))
+	for _, w := range words {
+		if p := normalizeModerationText(w); p != "" {
+			phrases = append(phrases, p)
+		}
+	}
+	return &bannedWordsRule{phrases: phrases}
+}
+
+func (r *bannedWordsRule) Check(_ context.Context, input ModerationInput) (ModerationHit, error) {
+	text := " " + normalizeModerationText(input.Text) + " "
+	var found []string
+	for _, p := range r.phrases {
+		if strings.Contains(text, " "+p+" ") {
+			found = append(found, p)
+		}
+	}
+	if len(found) == 0 {
+		return ModerationHit{}, nil
+	}
+	return ModerationHit{
+		Score:  bannedWordScore * len(found),
+		Reason: fmt.Sprintf(messages.ModerationReasonBannedWords, strings.Join(found, ", ")),
+	}, nil
+}
+
+type linkSpamRule struct{}
+
+// NewLinkSpamRule scores linkScore for every link in the text.
+func NewLinkSpamRule() ModerationRule {
+	return linkSpamRule{}
+}
+
+func (linkSpamRule) Check(_ context.Context, input ModerationInput) (ModerationHit, error) {
+	links := len(reModerationLink.FindAllString(input.Text, -1))
+	if links == 0 {
+		return ModerationHit{}, nil
+	}
+	return ModerationHit{Score: linkScore * links, Reason: fmt.Sprintf(messages.ModerationReasonLinks, links)}, nil
+}
+
+type phoneSpamRule struct{}
+
+// NewPhoneSpamRule flags text containing a Vietnamese phone number.
+func NewPhoneSpamRule() ModerationRule {
+	return phoneSpamRule{}
+}
+
+func (phoneSpamRule) Check(_ context.Context, input ModerationInput) (ModerationHit, error) {
+	if !reModerationPhone.MatchString(input.Text) {
+		return ModerationHit{}, nil
+	}
+	return ModerationHit{Score: phoneScore, Reason: messages.ModerationReasonPhone}, nil
+}
+
+type duplicateContentRule struct {
+	repo   repository.ModerationRepo
+	window time.Duration
+}
+
+// NewDuplicateContentRule holds text the same user already posted within
+// the window, ignoring case, spacing and punctuation. It scores below
+// ModerationRejectScore so a repeated "Cảm ơn!" waits for an admin instead
+// of being rejected.
+func NewDuplicateContentRule(repo repository.ModerationRepo, window time.Duration) ModerationRule {
+	return &duplicateContentRule{repo: repo, window: window}
+}
+
+func (r *duplicateContentRule) Check(ctx context.Context, input ModerationInput) (ModerationHit, error) {
+	text := normalizeModerationText(input.Text)
+	if text == "" {
+		return ModerationHit{}, nil
+	}
+	recent, err := r.repo.RecentContents(ctx, input.Kind, input.UserID, input.ExcludeID, time.Now().Add(-r.window))
+	if err != nil {
+		return ModerationHit{}, err
+	}
+	for _, previous := range recent {
+		if normalizeModerationText(previous) == text {
+			return ModerationHit{Score: duplicateScore, Reason: messages.ModerationReasonDuplicate}, nil
+		}
+	}
+	return ModerationHit{}, nil
+}
+
+type postingVelocityRule struct {
+	repo   repository.ModerationRepo
+	window time.Duration
+	limits map[string]int
+}
+
+// NewPostingVelocityRule flags a submission once the user has already
+// posted limits[kind] items of that kind within the window.
+func NewPostingVelocityRule(repo repository.ModerationRepo, window time.Duration, limits map[string]int) ModerationRule {
+	return &postingVelocityRule{repo: repo, window: window, limits: limits}
+}
+
+func (r *postingVelocityRule) Check(ctx context.Context, input ModerationInput) (ModerationHit, error) {
+	limit, ok := r.limits[input.Kind]
+	if !ok || limit <= 0 {
+		return ModerationHit{}, nil
+	}
+	recent, err := r.repo.RecentContents(ctx, input.Kind, input.UserID, input.ExcludeID, time.Now().Add(-r.window))
+	if err != nil {
+		return ModerationHit{}, err
+	}
+	if len(recent) < limit {
+		return ModerationHit{}, nil
+	}
+	return ModerationHit{Score: velocityScore, Reason: fmt.Sprintf(messages.ModerationReasonVelocity, len(recent), int(r.window.Minutes()))}, nil
+}
+
+// normalizeModerationText lowercases the text, composes it to NFC so
+// decomposed Vietnamese diacritics match precomposed ones, and reduces it
+// to words separated by single spaces.
+func normalizeModerationText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(norm.NFC.String(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func truncateReason(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,108 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReview_ModerationDecidesStatus(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh")
+
+	clean, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReviewStatusApproved, clean.Status)
+
+	// Editing the clean review is not a duplicate of itself.
+	updated, err := svc.UpdateReview(ctx, clean.ID, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReviewStatusApproved, updated.Status)
+
+	// A repeat is held for an admin, not rejected: short stock phrases are
+	// often posted twice in good faith.
+	duplicate, err := svc.CreateReview(ctx, u[0].ID, storyInput("  bay CRUISE! "))
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReviewStatusPending, duplicate.Status)
+	assert.Len(t, duplicate.Flags(), 1)
+	assert.Empty(t, duplicate.RejectReason)
+
+	input := storyInput("Sa Pa trek")
+	input.Content = "Book the same guide directly: 0912345678 or saparoutes.com"
+	flagged, err := svc.CreateReview(ctx, u[1].ID, input)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReviewStatusPending, flagged.Status)
+	assert.Len(t, flagged.Flags(), 2)
+	assert.Empty(t, flagged.RejectReason)
+
+	require.NoError(t, svc.AdminRejectReview(ctx, flagged.ID, " Please keep it civil. "))
+	var stored models.Review
+	require.NoError(t, db.First(&stored, flagged.ID).Error)
+	assert.Equal(t, constants.ReviewStatusRejected, stored.Status)
+	assert.Equal(t, "Please keep it civil.", stored.RejectReason)
+
+	require.NoError(t, svc.AdminApproveReview(ctx, flagged.ID))
+	require.NoError(t, db.First(&stored, flagged.ID).Error)
+	assert.Empty(t, stored.RejectReason)
+}
+
+func TestCreateReview_PostingVelocityFlags(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	author := seedUser(t, db, "an", constants.RoleUser)
+
+	for _, title := range []string{"Hanoi", "Hue", "Hoi An"} {
+		review, err := svc.CreateReview(ctx, author.ID, storyInput(title))
+		require.NoError(t, err)
+		require.Equal(t, constants.ReviewStatusApproved, review.Status)
+	}
+	review, err := svc.CreateReview(ctx, author.ID, storyInput("Da Lat"))
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReviewStatusPending, review.Status)
+}
+
+func TestAddComment_ModeratedAndHiddenFromOthers(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh", "chi")
+	review, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	require.Equal(t, constants.ReviewStatusApproved, review.Status)
+
+	ok, err := svc.AddComment(ctx, u[1].ID, review.ID, nil, "Looks lovely, thanks for sharing!")
+	require.NoError(t, err)
+	assert.Equal(t, constants.CommentStatusApproved, ok.Status)
+	flagged, err := svc.AddComment(ctx, u[2].ID, review.ID, nil, "Cheap tours, call 0912 345 678")
+	require.NoError(t, err)
+	assert.Equal(t, constants.CommentStatusPending, flagged.Status)
+
+	_, guestView, _, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, guestView, 1)
+	assert.Equal(t, ok.ID, guestView[0].ID)
+
+	_, authorView, _, err := svc.GetPublicReview(ctx, review.ID, u[2].ID, 1)
+	require.NoError(t, err)
+	assert.Len(t, authorView, 2)
+
+	queue, total, err := svc.AdminListComments(ctx, repository.CommentFilter{Status: constants.CommentStatusPending})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, queue, 1)
+	assert.Equal(t, flagged.ID, queue[0].ID)
+
+	require.NoError(t, svc.AdminRejectComment(ctx, flagged.ID, "No advertising"))
+	rejected, _, err := svc.AdminListComments(ctx, repository.CommentFilter{Status: constants.CommentStatusRejected})
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "No advertising", rejected[0].RejectReason)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestModerationRules_ScoreContent(t *testing.T) {
+	ctx := context.Background()
+	svc := NewModerationService(NewBannedWordsRule(defaultBannedWords), NewLinkSpamRule(), NewPhoneSpamRule())
+
+	cases := []struct {
+		name   string
+		text   string
+		status string
+	}{
+		{"clean Vietnamese", "Chuyến đi Hạ Long rất tuyệt, hướng dẫn viên nhiệt tình.", constants.ReviewStatusApproved},
+		{"single link", "Ảnh chuyến đi ở https://photos.example.com/halong", constants.ReviewStatusApproved},
+		{"Vietnamese profanity", "Dịch vụ như LỒN, không bao giờ quay lại", constants.ReviewStatusPending},
+		{"English profanity", "What a shit hotel", constants.ReviewStatusPending},
+		{"word inside another word", "The scenery was shitake-free and classic", constants.ReviewStatusApproved},
+		{"phone number", "Liên hệ em giá rẻ: 0912 345 678", constants.ReviewStatusPending},
+		{"link spam", "Mua ngay www.cheap.xyz http://a.io/x http://b.io/y giamgia.vn", constants.ReviewStatusRejected},
+		{"two banned words", "fuck this, đéo bao giờ đi nữa", constants.ReviewStatusRejected},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			verdict, err := svc.Check(ctx, ModerationInput{Kind: constants.ModerationKindReview, UserID: 1, Text: tc.text})
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, verdict.Status(), "score %d, reasons %v", verdict.Score, verdict.Reasons)
+		})
+	}
+}
+
+func TestNormalizeModerationText_ComposesDiacritics(t *testing.T) {
+	precomposed := "Cảm ơn, Hạ Long!"
+	decomposed := norm.NFD.String(precomposed)
+	require.NotEqual(t, precomposed, decomposed)
+
+	assert.Equal(t, "cảm ơn hạ long", normalizeModerationText(decomposed))
+	assert.Equal(t, normalizeModerationText(precomposed), normalizeModerationText(decomposed))
+}
+
+func TestDuplicateContentRule_IgnoresCaseAndPunctuation(t *testing.T) {
+	repo := new(testutil.MockModerationRepo)
+	rule := NewDuplicateContentRule(repo, duplicateWindow)
+	repo.On("RecentContents", mock.Anything, constants.ModerationKindReview, uint(1), uint(4), mock.Anything).
+		Return([]string{"Sa Pa trek\nGreat views", "Bay cruise\nA long enough story about the trip."}, nil)
+
+	hit, err := rule.Check(context.Background(), ModerationInput{
+		Kind: constants.ModerationKindReview, UserID: 1, ExcludeID: 4, Text: "  bay CRUISE!\nA long enough story about the trip",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, duplicateScore, hit.Score)
+	repo.AssertExpectations(t)
+}
+
+func TestPostingVelocityRule_FlagsOnceLimitReached(t *testing.T) {
+	tests := []struct {
+		name   string
+		recent int
+		want   int
+	}{
+		{"below limit", reviewVelocityMax - 1, 0},
+		{"at limit", reviewVelocityMax, velocityScore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(testutil.MockModerationRepo)
+			rule := NewPostingVelocityRule(repo, velocityWindow, map[string]int{constants.ModerationKindReview: reviewVelocityMax})
+			repo.On("RecentContents", mock.Anything, constants.ModerationKindReview, uint(1), uint(0), mock.Anything).
+				Return(make([]string, tt.recent), nil)
+
+			hit, err := rule.Check(context.Background(), ModerationInput{Kind: constants.ModerationKindReview, UserID: 1, Text: "Da Lat"})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hit.Score)
+		})
+	}
+}
+
+func TestPostingVelocityRule_UnlimitedKindNeverQueries(t *testing.T) {
+	repo := new(testutil.MockModerationRepo)
+	rule := NewPostingVelocityRule(repo, velocityWindow, map[string]int{constants.ModerationKindReview: reviewVelocityMax})
+
+	hit, err := rule.Check(context.Background(), ModerationInput{Kind: constants.ModerationKindComment, UserID: 1, Text: "Thanks!"})
+
+	require.NoError(t, err)
+	assert.Zero(t, hit.Score)
+	repo.AssertNotCalled(t, "RecentContents", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -10,6 +10,7 @@ import (
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 
@@ -17,23 +18,97 @@ import (
 )
 
 type RatingService struct {
-	ratingRepo repository.RatingRepo
-	tourRepo   repository.TourRepo
+	ratingRepo   repository.RatingRepo
+	tourRepo     repository.TourRepo
+	bookingRepo  repository.BookingRepo
+	emailService *EmailService
+	baseURL      string
 }
 
-func NewRatingService(ratingRepo repository.RatingRepo, tourRepo repository.TourRepo) *RatingService {
-	return &RatingService{ratingRepo: ratingRepo, tourRepo: tourRepo}
+func NewRatingService(ratingRepo repository.RatingRepo, tourRepo repository.TourRepo, bookingRepo repository.BookingRepo, emailService *EmailService, baseURL string) *RatingService {
+	return &RatingService{
+		ratingRepo:   ratingRepo,
+		tourRepo:     tourRepo,
+		bookingRepo:  bookingRepo,
+		emailService: emailService,
+		baseURL:      baseURL,
+	}
 }
 
+// RatingInput is a submitted rating. Aspects are optional sub-scores.
 type RatingInput struct {
 	Score   int
 	Comment string
+	Aspects models.RatingAspects
+}
+
+// RatingSummary is the rating breakdown shown on a tour page. Buckets run
+// from 5 stars down to 1; Aspects only lists sub-scores someone has given.
+type RatingSummary struct {
+	Total   int64
+	Buckets []RatingBucket
+	Aspects []RatingAspectAverage
+}
+
+// RatingBucket is one bar of the star distribution histogram.
+type RatingBucket struct {
+	Stars   int
+	Count   int64
+	Percent int
+}
+
+// RatingAspectAverage is a tour's average for one sub-score.
+type RatingAspectAverage struct {
+	Label   string
+	Average float64
+	Count   int64
+}
+
+// RatingAspectField is one sub-score input of the rating form; Value is the
+// user's current score, or 0 when unrated.
+type RatingAspectField struct {
+	Name  string
+	Label string
+	Value int
+}
+
+var ratingAspectLabels = map[string]string{
+	models.RatingAspectGuide:         messages.RatingAspectGuide,
+	models.RatingAspectItinerary:     messages.RatingAspectItinerary,
+	models.RatingAspectAccommodation: messages.RatingAspectAccommodation,
+	models.RatingAspectTransport:     messages.RatingAspectTransport,
+	models.RatingAspectValue:         messages.RatingAspectValue,
+}
+
+// RatingAspectFields returns the sub-score inputs of the rating form,
+// pre-filled from the user's current rating when there is one.
+func RatingAspectFields(current *models.Rating) []RatingAspectField {
+	fields := make([]RatingAspectField, 0, len(models.RatingAspectKeys))
+	for _, aspect := range models.RatingAspectKeys {
+		field := RatingAspectField{Name: models.RatingAspectColumn(aspect), Label: ratingAspectLabels[aspect]}
+		if current != nil {
+			if v := current.Get(aspect); v != nil {
+				field.Value = *v
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func validScore(score int) bool {
+	return score >= constants.RatingMinScore && score <= constants.RatingMaxScore
 }
 
 func (s *RatingService) RateOrUpdate(ctx context.Context, userID, tourID uint, input RatingInput) (isNew bool, err error) {
-	if input.Score < constants.RatingMinScore || input.Score > constants.RatingMaxScore {
+	if !validScore(input.Score) {
 		return false, appErrors.ErrInvalidScore
 	}
+	for _, aspect := range models.RatingAspectKeys {
+		if v := input.Aspects.Get(aspect); v != nil && !validScore(*v) {
+			return false, appErrors.ErrInvalidScore
+		}
+	}
 
 	if _, err := s.tourRepo.FindByIDPublic(ctx, tourID); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -42,17 +117,35 @@ func (s *RatingService) RateOrUpdate(ctx context.Context, userID, tourID uint, i
 		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceRate, err)
 	}
 
-	_, findErr := s.ratingRepo.FindByUserAndTour(ctx, userID, tourID)
+	existing, findErr := s.ratingRepo.FindByUserAndTour(ctx, userID, tourID)
 	if findErr != nil && !errors.Is(findErr, gorm.ErrRecordNotFound) {
 		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceRate, findErr)
 	}
 	isNew = errors.Is(findErr, gorm.ErrRecordNotFound)
 
+	bookingID, err := s.completedBookingID(ctx, userID, tourID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceRate, err)
+	}
+	if bookingID == nil {
+		// Editing never needs a booking again, and keeps any link the
+		// rating already has.
+		if !isNew {
+			bookingID = existing.BookingID
+		} else if granted, err := s.ratingRepo.HasGrant(ctx, userID, tourID); err != nil {
+			return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceRate, err)
+		} else if !granted {
+			return false, appErrors.ErrRatingNotTaken
+		}
+	}
+
 	rating := &models.Rating{
-		UserID:  userID,
-		TourID:  tourID,
-		Score:   input.Score,
-		Comment: strings.TrimSpace(input.Comment),
+		UserID:        userID,
+		TourID:        tourID,
+		BookingID:     bookingID,
+		Score:         input.Score,
+		Comment:       strings.TrimSpace(input.Comment),
+		RatingAspects: input.Aspects,
 	}
 
 	if err := s.ratingRepo.Upsert(ctx, rating); err != nil {
@@ -64,13 +157,98 @@ func (s *RatingService) RateOrUpdate(ctx context.Context, userID, tourID uint, i
 		return isNew, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceRate, err)
 	}
 	avg = math.Round(avg*100) / 100
-	if err := s.tourRepo.UpdateAvgRating(ctx, tourID, avg); err != nil {
+	count, err := s.tourRepo.CountRatingsByTourID(ctx, tourID)
+	if err != nil {
+		return isNew, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceRate, err)
+	}
+	if err := s.tourRepo.UpdateRatingStats(ctx, tourID, avg, count); err != nil {
 		slog.Error(appErrors.ErrCtxRatingUpdateTourAvg, "context", appErrors.ErrCtxRatingUpdateTourAvg, "tour_id", tourID, "error", err)
 	}
 
 	return isNew, nil
 }
 
+// CanRate reports whether the user may rate the tour: they need a completed
+// booking of it or an admin's grant, or already have a rating to edit.
+func (s *RatingService) CanRate(ctx context.Context, userID, tourID uint) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	bookingID, err := s.completedBookingID(ctx, userID, tourID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceCanRate, err)
+	}
+	if bookingID != nil {
+		return true, nil
+	}
+	rating, err := s.GetUserRating(ctx, userID, tourID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceCanRate, err)
+	}
+	if rating != nil {
+		return true, nil
+	}
+	granted, err := s.ratingRepo.HasGrant(ctx, userID, tourID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceCanRate, err)
+	}
+	return granted, nil
+}
+
+// AllowRating lets the user rate the tour with the given slug without a
+// completed booking. The rating is stored unverified.
+func (s *RatingService) AllowRating(ctx context.Context, adminID, userID uint, tourSlug string) error {
+	tour, err := s.tourRepo.FindBySlug(ctx, strings.TrimSpace(tourSlug))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.ErrTourNotFound
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceGrant, err)
+	}
+	grant := &models.RatingGrant{UserID: userID, TourID: tour.ID, AdminID: adminID}
+	if err := s.ratingRepo.CreateGrant(ctx, grant); err != nil {
+		if appErrors.IsDuplicateEntryError(err) {
+			return appErrors.ErrRatingGrantExists
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceGrant, err)
+	}
+	return nil
+}
+
+// RevokeRatingGrant removes a grant. A rating already left stays.
+func (s *RatingService) RevokeRatingGrant(ctx context.Context, userID, tourID uint) error {
+	deleted, err := s.ratingRepo.DeleteGrant(ctx, userID, tourID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceGrant, err)
+	}
+	if deleted == 0 {
+		return appErrors.ErrRatingGrantNotFound
+	}
+	return nil
+}
+
+// ListRatingGrants lists the tours the user may rate through a grant.
+func (s *RatingService) ListRatingGrants(ctx context.Context, userID uint) ([]models.RatingGrant, error) {
+	grants, err := s.ratingRepo.FindGrantsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceGrant, err)
+	}
+	return grants, nil
+}
+
+// completedBookingID returns the user's latest completed booking of the
+// tour, or nil when they have not taken it.
+func (s *RatingService) completedBookingID(ctx context.Context, userID, tourID uint) (*uint, error) {
+	booking, err := s.bookingRepo.FindLatestCompleted(ctx, userID, tourID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &booking.ID, nil
+}
+
 func (s *RatingService) GetUserRating(ctx context.Context, userID, tourID uint) (*models.Rating, error) {
 	if userID == 0 {
 		return nil, nil
@@ -92,3 +270,76 @@ func (s *RatingService) ListByTour(ctx context.Context, tourID uint, page, limit
 	}
 	return ratings, total, nil
 }
+
+// Summary returns the star distribution and sub-score averages of a tour.
+func (s *RatingService) Summary(ctx context.Context, tourID uint) (*RatingSummary, error) {
+	stats, err := s.ratingRepo.StatsByTourID(ctx, tourID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceSummary, err)
+	}
+
+	summary := &RatingSummary{}
+	for _, count := range stats.Distribution {
+		summary.Total += count
+	}
+	for stars := constants.RatingMaxScore; stars >= constants.RatingMinScore; stars-- {
+		bucket := RatingBucket{Stars: stars, Count: stats.Distribution[stars]}
+		if summary.Total > 0 {
+			bucket.Percent = int(math.Round(float64(bucket.Count) * 100 / float64(summary.Total)))
+		}
+		summary.Buckets = append(summary.Buckets, bucket)
+	}
+	for _, aspect := range models.RatingAspectKeys {
+		stat := stats.Aspects[aspect]
+		if stat.Count == 0 {
+			continue
+		}
+		summary.Aspects = append(summary.Aspects, RatingAspectAverage{
+			Label:   ratingAspectLabels[aspect],
+			Average: math.Round(stat.Average*10) / 10,
+			Count:   stat.Count,
+		})
+	}
+	return summary, nil
+}
+
+// AdminListRatings lists ratings for the admin panel, e.g. the queue of
+// unanswered low scores.
+func (s *RatingService) AdminListRatings(ctx context.Context, filter repository.RatingFilter) ([]models.Rating, int64, error) {
+	ratings, total, err := s.ratingRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceAdmin, err)
+	}
+	return ratings, total, nil
+}
+
+// Reply posts or edits the operator's public reply to a rating. The author
+// is emailed when the reply is first posted, not on later edits.
+func (s *RatingService) Reply(ctx context.Context, ratingID, adminID uint, content string) (isNew bool, err error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return false, appErrors.ErrReplyEmpty
+	}
+
+	rating, err := s.ratingRepo.FindByID(ctx, ratingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, appErrors.ErrRatingNotFound
+		}
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceReply, err)
+	}
+
+	reply := &models.RatingReply{RatingID: rating.ID, AdminID: adminID, Content: content}
+	if err := s.ratingRepo.SaveReply(ctx, reply); err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxRatingServiceReply, err)
+	}
+
+	isNew = rating.Reply == nil
+	if isNew && rating.Tour != nil {
+		tourURL := s.baseURL + constants.RoutePublicTours + "/" + rating.Tour.Slug
+		if err := s.emailService.SendRatingReplyEmail(rating, reply, tourURL); err != nil {
+			slog.Error(messages.LogRatingReplyEmailFailed, "rating_id", rating.ID, "error", err)
+		}
+	}
+	return isNew, nil
+}
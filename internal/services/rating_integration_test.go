@@ -0,0 +1,277 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupRatingServiceDB(t *testing.T) (*RatingService, *TourService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+	tourSvc := NewTourService(db, repository.NewTourRepository(db), repository.NewCategoryRepository(db), repository.NewSlugHistoryRepository(db), nil)
+	svc := NewRatingService(repository.NewRatingRepository(db), repository.NewTourRepository(db), repository.NewBookingRepository(db),
+		NewEmailService(&config.Config{}), "http://localhost")
+	return svc, tourSvc, db
+}
+
+func TestRateOrUpdate_RequiresCompletedBooking(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	user := seedUser(t, db, "an", constants.RoleUser)
+	seedBookingOn(t, db, user.ID, tour.ID, time.Now().AddDate(0, 1, 0), constants.BookingStatusConfirmed)
+
+	_, err := svc.RateOrUpdate(ctx, user.ID, tour.ID, RatingInput{Score: 5})
+	assert.ErrorIs(t, err, appErrors.ErrRatingNotTaken)
+
+	ok, err := svc.CanRate(ctx, user.ID, tour.ID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	var count int64
+	db.Model(&models.Rating{}).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestRateOrUpdate_StoresLatestCompletedBooking(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	user := seedUser(t, db, "an", constants.RoleUser)
+	seedBookingOn(t, db, user.ID, tour.ID, time.Now().AddDate(-1, 0, 0), constants.BookingStatusCompleted)
+	latest := seedBookingOn(t, db, user.ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+
+	isNew, err := svc.RateOrUpdate(ctx, user.ID, tour.ID, RatingInput{Score: 4, Comment: " great "})
+	require.NoError(t, err)
+	assert.True(t, isNew)
+
+	ratings, _, err := svc.ListByTour(ctx, tour.ID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, ratings, 1)
+	assert.True(t, ratings[0].Verified())
+	assert.Equal(t, latest.ID, *ratings[0].BookingID)
+	assert.Equal(t, "great", ratings[0].Comment)
+	assert.False(t, ratings[0].TripDate().IsZero())
+
+	var updated models.Tour
+	require.NoError(t, db.First(&updated, tour.ID).Error)
+	assert.InDelta(t, 4.0, updated.AvgRating, 0.001)
+}
+
+func TestRateOrUpdate_AdminGrantIsUnverified(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	other := createActiveTour(t, tourSvc, db, "Sa Pa")
+	admin := seedUser(t, db, "admin", constants.RoleAdmin)
+	user := seedUser(t, db, "an", constants.RoleUser)
+
+	require.NoError(t, svc.AllowRating(ctx, admin.ID, user.ID, tour.Slug))
+	assert.ErrorIs(t, svc.AllowRating(ctx, admin.ID, user.ID, tour.Slug), appErrors.ErrRatingGrantExists)
+	assert.ErrorIs(t, svc.AllowRating(ctx, admin.ID, user.ID, "khong-co"), appErrors.ErrTourNotFound)
+
+	ok, err := svc.CanRate(ctx, user.ID, tour.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	_, err = svc.RateOrUpdate(ctx, user.ID, other.ID, RatingInput{Score: 3})
+	assert.ErrorIs(t, err, appErrors.ErrRatingNotTaken)
+
+	_, err = svc.RateOrUpdate(ctx, user.ID, tour.ID, RatingInput{Score: 3})
+	require.NoError(t, err)
+
+	rating, err := svc.GetUserRating(ctx, user.ID, tour.ID)
+	require.NoError(t, err)
+	require.NotNil(t, rating)
+	assert.False(t, rating.Verified())
+}
+
+func TestRateOrUpdate_EditNeedsNoBookingAndKeepsLink(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	admin := seedUser(t, db, "admin", constants.RoleAdmin)
+	booked := seedUser(t, db, "an", constants.RoleUser)
+	granted := seedUser(t, db, "binh", constants.RoleUser)
+	trip := seedBookingOn(t, db, booked.ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+	_, err := svc.RateOrUpdate(ctx, booked.ID, tour.ID, RatingInput{Score: 4})
+	require.NoError(t, err)
+	require.NoError(t, svc.AllowRating(ctx, admin.ID, granted.ID, tour.Slug))
+	_, err = svc.RateOrUpdate(ctx, granted.ID, tour.ID, RatingInput{Score: 4})
+	require.NoError(t, err)
+
+	// Neither the booking nor the grant is needed any more once rated.
+	require.NoError(t, db.Model(&trip).Update("status", constants.BookingStatusCancelled).Error)
+	require.NoError(t, svc.RevokeRatingGrant(ctx, granted.ID, tour.ID))
+	assert.ErrorIs(t, svc.RevokeRatingGrant(ctx, granted.ID, tour.ID), appErrors.ErrRatingGrantNotFound)
+
+	isNew, err := svc.RateOrUpdate(ctx, booked.ID, tour.ID, RatingInput{Score: 2})
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	_, err = svc.RateOrUpdate(ctx, granted.ID, tour.ID, RatingInput{Score: 5})
+	require.NoError(t, err)
+
+	rating, err := svc.GetUserRating(ctx, booked.ID, tour.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rating.Score)
+	require.NotNil(t, rating.BookingID)
+	assert.Equal(t, trip.ID, *rating.BookingID)
+	ok, err := svc.CanRate(ctx, granted.ID, tour.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRateOrUpdate_SubScoresAndSummary(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	var users []models.User
+	for _, name := range []string{"an", "binh", "chi"} {
+		user := seedUser(t, db, name, constants.RoleUser)
+		seedBookingOn(t, db, user.ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+		users = append(users, user)
+	}
+
+	guide, value := 5, 3
+	var aspects models.RatingAspects
+	aspects.Set(models.RatingAspectGuide, &guide)
+	aspects.Set(models.RatingAspectValue, &value)
+	_, err := svc.RateOrUpdate(ctx, users[0].ID, tour.ID, RatingInput{Score: 5, Aspects: aspects})
+	require.NoError(t, err)
+	guide = 4
+	_, err = svc.RateOrUpdate(ctx, users[1].ID, tour.ID, RatingInput{Score: 5, Aspects: models.RatingAspects{GuideScore: &guide}})
+	require.NoError(t, err)
+	_, err = svc.RateOrUpdate(ctx, users[2].ID, tour.ID, RatingInput{Score: 2})
+	require.NoError(t, err)
+
+	bad := 6
+	_, err = svc.RateOrUpdate(ctx, users[2].ID, tour.ID, RatingInput{Score: 2, Aspects: models.RatingAspects{TransportScore: &bad}})
+	assert.ErrorIs(t, err, appErrors.ErrInvalidScore)
+
+	summary, err := svc.Summary(ctx, tour.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), summary.Total)
+	require.Len(t, summary.Buckets, 5)
+	assert.Equal(t, RatingBucket{Stars: 5, Count: 2, Percent: 67}, summary.Buckets[0])
+	assert.Equal(t, RatingBucket{Stars: 2, Count: 1, Percent: 33}, summary.Buckets[3])
+	assert.Zero(t, summary.Buckets[4].Count)
+
+	require.Len(t, summary.Aspects, 2)
+	assert.Equal(t, RatingAspectAverage{Label: ratingAspectLabels[models.RatingAspectGuide], Average: 4.5, Count: 2}, summary.Aspects[0])
+	assert.Equal(t, RatingAspectAverage{Label: ratingAspectLabels[models.RatingAspectValue], Average: 3, Count: 1}, summary.Aspects[1])
+
+	var updated models.Tour
+	require.NoError(t, db.First(&updated, tour.ID).Error)
+	assert.Equal(t, 3, updated.RatingCount)
+}
+
+func seedRatings(t *testing.T, db *gorm.DB, tourID uint, scores ...int) {
+	t.Helper()
+	sum := 0
+	for i, score := range scores {
+		user := seedUser(t, db, fmt.Sprintf("rater-%d-%d", tourID, i), constants.RoleUser)
+		require.NoError(t, db.Create(&models.Rating{UserID: user.ID, TourID: tourID, Score: score}).Error)
+		sum += score
+	}
+	require.NoError(t, db.Model(&models.Tour{}).Where("id = ?", tourID).
+		Updates(map[string]any{"avg_rating": float64(sum) / float64(len(scores)), "rating_count": len(scores)}).Error)
+}
+
+func TestListTours_SortByRatingUsesBayesianAverage(t *testing.T) {
+	_, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	single := createActiveTour(t, tourSvc, db, "Single Review")
+	popular := createActiveTour(t, tourSvc, db, "Popular Tour")
+	average := createActiveTour(t, tourSvc, db, "Average Tour")
+
+	seedRatings(t, db, single.ID, 5)
+	popularScores := make([]int, 0, 20)
+	for i := 0; i < 20; i++ {
+		score := 5
+		if i%5 == 0 {
+			score = 4
+		}
+		popularScores = append(popularScores, score)
+	}
+	seedRatings(t, db, popular.ID, popularScores...)
+	seedRatings(t, db, average.ID, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3)
+
+	tours, _, _, err := tourSvc.ListTours(ctx, repository.TourFilter{SortBy: "avg_rating", SortOrder: "desc"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Popular Tour", "Single Review", "Average Tour"}, tourTitles(tours))
+}
+
+func TestReply_PostsThenEditsSingleReply(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	seedRatings(t, db, tour.ID, 2)
+	admin := seedUser(t, db, "admin", constants.RoleAdmin)
+	editor := seedUser(t, db, "editor", constants.RoleAdmin)
+	var rating models.Rating
+	require.NoError(t, db.First(&rating).Error)
+
+	_, err := svc.Reply(ctx, rating.ID, admin.ID, "   ")
+	assert.ErrorIs(t, err, appErrors.ErrReplyEmpty)
+	_, err = svc.Reply(ctx, rating.ID+100, admin.ID, "Sorry")
+	assert.ErrorIs(t, err, appErrors.ErrRatingNotFound)
+
+	isNew, err := svc.Reply(ctx, rating.ID, admin.ID, " Sorry about the bus. ")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	isNew, err = svc.Reply(ctx, rating.ID, editor.ID, "Sorry about the bus, refund sent.")
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	var replies []models.RatingReply
+	require.NoError(t, db.Find(&replies).Error)
+	require.Len(t, replies, 1)
+	assert.Equal(t, "Sorry about the bus, refund sent.", replies[0].Content)
+	assert.Equal(t, editor.ID, replies[0].AdminID)
+
+	ratings, _, err := svc.ListByTour(ctx, tour.ID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, ratings, 1)
+	require.NotNil(t, ratings[0].Reply)
+	assert.Equal(t, replies[0].Content, ratings[0].Reply.Content)
+}
+
+func TestAdminListRatings_QueueHoldsUnansweredLowScores(t *testing.T) {
+	svc, tourSvc, db := setupRatingServiceDB(t)
+	ctx := context.Background()
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	seedRatings(t, db, tour.ID, 1, 3, 5, 2)
+	admin := seedUser(t, db, "admin", constants.RoleAdmin)
+	var answered models.Rating
+	require.NoError(t, db.Where("score = ?", 2).First(&answered).Error)
+	_, err := svc.Reply(ctx, answered.ID, admin.ID, "Thanks for the feedback")
+	require.NoError(t, err)
+
+	queue, total, err := svc.AdminListRatings(ctx, repository.RatingFilter{Unanswered: true, MaxScore: constants.RatingLowScoreMax})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	scores := []int{}
+	for _, r := range queue {
+		scores = append(scores, r.Score)
+	}
+	assert.ElementsMatch(t, []int{1, 3}, scores)
+
+	all, total, err := svc.AdminListRatings(ctx, repository.RatingFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Len(t, all, 4)
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sun-booking-tours/internal/config"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+type ratingMocks struct {
+	ratingRepo  *testutil.MockRatingRepo
+	tourRepo    *testutil.MockTourRepo
+	bookingRepo *testutil.MockBookingRepo
+}
+
+func setupRatingService() (*RatingService, ratingMocks) {
+	m := ratingMocks{
+		ratingRepo:  new(testutil.MockRatingRepo),
+		tourRepo:    new(testutil.MockTourRepo),
+		bookingRepo: new(testutil.MockBookingRepo),
+	}
+	svc := NewRatingService(m.ratingRepo, m.tourRepo, m.bookingRepo, NewEmailService(&config.Config{}), "http://localhost")
+	return svc, m
+}
+
+// expectRatable sets up a public tour 5 that user 1 has or has not rated
+// before, with the latest completed booking given (nil for none).
+func (m ratingMocks) expectRatable(existing *models.Rating, booking *models.Booking) {
+	m.tourRepo.On("FindByIDPublic", mock.Anything, uint(5)).Return(&models.Tour{ID: 5}, nil)
+	if existing != nil {
+		m.ratingRepo.On("FindByUserAndTour", mock.Anything, uint(1), uint(5)).Return(existing, nil)
+	} else {
+		m.ratingRepo.On("FindByUserAndTour", mock.Anything, uint(1), uint(5)).Return(nil, gorm.ErrRecordNotFound)
+	}
+	if booking != nil {
+		m.bookingRepo.On("FindLatestCompleted", mock.Anything, uint(1), uint(5)).Return(booking, nil)
+	} else {
+		m.bookingRepo.On("FindLatestCompleted", mock.Anything, uint(1), uint(5)).Return(nil, gorm.ErrRecordNotFound)
+	}
+}
+
+// expectStatsRefresh accepts the tour average update that follows a save.
+func (m ratingMocks) expectStatsRefresh(avg float64, count int64) {
+	m.ratingRepo.On("CalcAvgByTourID", mock.Anything, uint(5)).Return(avg, nil)
+	m.tourRepo.On("CountRatingsByTourID", mock.Anything, uint(5)).Return(count, nil)
+	m.tourRepo.On("UpdateRatingStats", mock.Anything, uint(5), mock.Anything, count).Return(nil)
+}
+
+func bookingIDIs(want *uint) func(*models.Rating) bool {
+	return func(r *models.Rating) bool {
+		if want == nil {
+			return r.BookingID == nil
+		}
+		return r.BookingID != nil && *r.BookingID == *want
+	}
+}
+
+// --- RateOrUpdate -----------------------------------------------------
+
+func TestRateOrUpdate_RejectsInvalidScores(t *testing.T) {
+	bad := 6
+	tests := []struct {
+		name  string
+		input RatingInput
+	}{
+		{"score below range", RatingInput{Score: 0}},
+		{"score above range", RatingInput{Score: 6}},
+		{"sub-score above range", RatingInput{Score: 4, Aspects: models.RatingAspects{TransportScore: &bad}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupRatingService()
+
+			_, err := svc.RateOrUpdate(context.Background(), 1, 5, tt.input)
+
+			assert.ErrorIs(t, err, appErrors.ErrInvalidScore)
+			m.tourRepo.AssertNotCalled(t, "FindByIDPublic", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRateOrUpdate_RequiresCompletedBookingOrGrant(t *testing.T) {
+	svc, m := setupRatingService()
+	m.expectRatable(nil, nil)
+	m.ratingRepo.On("HasGrant", mock.Anything, uint(1), uint(5)).Return(false, nil)
+
+	_, err := svc.RateOrUpdate(context.Background(), 1, 5, RatingInput{Score: 5})
+
+	assert.ErrorIs(t, err, appErrors.ErrRatingNotTaken)
+	m.ratingRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestRateOrUpdate_LinksLatestCompletedBooking(t *testing.T) {
+	svc, m := setupRatingService()
+	bookingID := uint(7)
+	m.expectRatable(nil, &models.Booking{ID: bookingID})
+	m.ratingRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *models.Rating) bool {
+		return bookingIDIs(&bookingID)(r) && r.Comment == "great" && r.Score == 4
+	})).Return(nil)
+	m.ratingRepo.On("CalcAvgByTourID", mock.Anything, uint(5)).Return(4.256, nil)
+	m.tourRepo.On("CountRatingsByTourID", mock.Anything, uint(5)).Return(int64(3), nil)
+	m.tourRepo.On("UpdateRatingStats", mock.Anything, uint(5), 4.26, int64(3)).Return(nil)
+
+	isNew, err := svc.RateOrUpdate(context.Background(), 1, 5, RatingInput{Score: 4, Comment: " great "})
+
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	m.ratingRepo.AssertExpectations(t)
+	m.tourRepo.AssertExpectations(t)
+	m.ratingRepo.AssertNotCalled(t, "HasGrant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateOrUpdate_GrantedRatingIsUnverified(t *testing.T) {
+	svc, m := setupRatingService()
+	m.expectRatable(nil, nil)
+	m.ratingRepo.On("HasGrant", mock.Anything, uint(1), uint(5)).Return(true, nil)
+	m.ratingRepo.On("Upsert", mock.Anything, mock.MatchedBy(bookingIDIs(nil))).Return(nil)
+	m.expectStatsRefresh(3, 1)
+
+	isNew, err := svc.RateOrUpdate(context.Background(), 1, 5, RatingInput{Score: 3})
+
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	m.ratingRepo.AssertExpectations(t)
+}
+
+func TestRateOrUpdate_EditKeepsBookingLink(t *testing.T) {
+	svc, m := setupRatingService()
+	bookingID := uint(7)
+	m.expectRatable(&models.Rating{ID: 3, BookingID: &bookingID}, nil)
+	m.ratingRepo.On("Upsert", mock.Anything, mock.MatchedBy(bookingIDIs(&bookingID))).Return(nil)
+	m.expectStatsRefresh(2, 1)
+
+	isNew, err := svc.RateOrUpdate(context.Background(), 1, 5, RatingInput{Score: 2})
+
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	m.ratingRepo.AssertNotCalled(t, "HasGrant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateOrUpdate_UnknownTour(t *testing.T) {
+	svc, m := setupRatingService()
+	m.tourRepo.On("FindByIDPublic", mock.Anything, uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.RateOrUpdate(context.Background(), 1, 5, RatingInput{Score: 4})
+
+	assert.ErrorIs(t, err, appErrors.ErrTourNotFound)
+}
+
+// --- CanRate ----------------------------------------------------------
+
+func TestCanRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		booking  *models.Booking
+		existing *models.Rating
+		granted  bool
+		want     bool
+	}{
+		{"completed booking", &models.Booking{ID: 7}, nil, false, true},
+		{"existing rating", nil, &models.Rating{ID: 3}, false, true},
+		{"admin grant", nil, nil, true, true},
+		{"nothing", nil, nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupRatingService()
+			m.expectRatable(tt.existing, tt.booking)
+			m.ratingRepo.On("HasGrant", mock.Anything, uint(1), uint(5)).Return(tt.granted, nil)
+
+			ok, err := svc.CanRate(context.Background(), 1, 5)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestCanRate_GuestNeverQueries(t *testing.T) {
+	svc, m := setupRatingService()
+
+	ok, err := svc.CanRate(context.Background(), 0, 5)
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+	m.bookingRepo.AssertNotCalled(t, "FindLatestCompleted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// --- grants -----------------------------------------------------------
+
+func TestAllowRating_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		findErr   error
+		createErr error
+		wantErr   error
+	}{
+		{"unknown tour", gorm.ErrRecordNotFound, nil, appErrors.ErrTourNotFound},
+		{"grant exists", nil, &pgconn.PgError{Code: "23505"}, appErrors.ErrRatingGrantExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupRatingService()
+			if tt.findErr != nil {
+				m.tourRepo.On("FindBySlug", mock.Anything, "ha-long").Return(nil, tt.findErr)
+			} else {
+				m.tourRepo.On("FindBySlug", mock.Anything, "ha-long").Return(&models.Tour{ID: 5}, nil)
+				m.ratingRepo.On("CreateGrant", mock.Anything, mock.Anything).Return(tt.createErr)
+			}
+
+			err := svc.AllowRating(context.Background(), 99, 1, " ha-long ")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRevokeRatingGrant_MissingGrant(t *testing.T) {
+	svc, m := setupRatingService()
+	m.ratingRepo.On("DeleteGrant", mock.Anything, uint(1), uint(5)).Return(int64(0), nil)
+
+	assert.ErrorIs(t, svc.RevokeRatingGrant(context.Background(), 1, 5), appErrors.ErrRatingGrantNotFound)
+}
+
+// --- Summary ----------------------------------------------------------
+
+func TestSummary_BucketsAndAspects(t *testing.T) {
+	svc, m := setupRatingService()
+	m.ratingRepo.On("StatsByTourID", mock.Anything, uint(5)).Return(&repository.RatingStats{
+		Distribution: map[int]int64{5: 2, 2: 1},
+		Aspects: map[string]repository.RatingAspectStat{
+			models.RatingAspectGuide: {Average: 4.5, Count: 2},
+			models.RatingAspectValue: {Average: 3.04, Count: 1},
+		},
+	}, nil)
+
+	summary, err := svc.Summary(context.Background(), 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), summary.Total)
+	require.Len(t, summary.Buckets, 5)
+	assert.Equal(t, RatingBucket{Stars: 5, Count: 2, Percent: 67}, summary.Buckets[0])
+	assert.Equal(t, RatingBucket{Stars: 2, Count: 1, Percent: 33}, summary.Buckets[3])
+	assert.Zero(t, summary.Buckets[4].Count)
+	assert.Equal(t, []RatingAspectAverage{
+		{Label: ratingAspectLabels[models.RatingAspectGuide], Average: 4.5, Count: 2},
+		{Label: ratingAspectLabels[models.RatingAspectValue], Average: 3, Count: 1},
+	}, summary.Aspects)
+}
+
+// --- Reply ------------------------------------------------------------
+
+func TestReply_Errors(t *testing.T) {
+	svc, m := setupRatingService()
+	m.ratingRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.Reply(context.Background(), 9, 99, "   ")
+	assert.ErrorIs(t, err, appErrors.ErrReplyEmpty)
+	_, err = svc.Reply(context.Background(), 9, 99, "Sorry")
+	assert.ErrorIs(t, err, appErrors.ErrRatingNotFound)
+	m.ratingRepo.AssertNotCalled(t, "SaveReply", mock.Anything, mock.Anything)
+}
+
+func TestReply_NewOnlyWithoutExistingReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		rating  *models.Rating
+		wantNew bool
+	}{
+		{"first reply", &models.Rating{ID: 3}, true},
+		{"edited reply", &models.Rating{ID: 3, Reply: &models.RatingReply{ID: 1}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupRatingService()
+			m.ratingRepo.On("FindByID", mock.Anything, uint(3)).Return(tt.rating, nil)
+			m.ratingRepo.On("SaveReply", mock.Anything, mock.MatchedBy(func(r *models.RatingReply) bool {
+				return r.RatingID == 3 && r.AdminID == 99 && r.Content == "Sorry about the bus."
+			})).Return(nil)
+
+			isNew, err := svc.Reply(context.Background(), 3, 99, " Sorry about the bus. ")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNew, isNew)
+			m.ratingRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/utils"
+)
+
+// Weights of the related-tour signals; they sum to 1.
+const (
+	recWeightCategory = 0.45
+	recWeightLocation = 0.25
+	recWeightCoBooked = 0.30
+)
+
+const (
+	// recPerTour is how many suggestions of each kind are kept per tour.
+	recPerTour = 6
+	// recPerUser is how many "recommended for you" tours are kept per user.
+	recPerUser = 12
+	// recSeedNeighbours is how many related tours of each booked or rated
+	// tour feed the user recommendations.
+	recSeedNeighbours = 20
+	// recNearbyKm is the distance at which location similarity drops to 0.
+	recNearbyKm = 150.0
+)
+
+// RecommendationService precomputes related tours, "customers also booked"
+// lists and per-user recommendations, and serves them to the public pages.
+type RecommendationService struct {
+	repo repository.RecommendationRepo
+}
+
+func NewRecommendationService(repo repository.RecommendationRepo) *RecommendationService {
+	return &RecommendationService{repo: repo}
+}
+
+// RelatedTours returns the precomputed related tours for a tour page.
+func (s *RecommendationService) RelatedTours(ctx context.Context, tourID uint) ([]models.Tour, error) {
+	tours, err := s.repo.FindForTour(ctx, tourID, constants.RecommendationKindRelated, recPerTour)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceForTour, err)
+	}
+	return tours, nil
+}
+
+// AlsoBooked returns the tours most often booked by people who booked
+// tourID.
+func (s *RecommendationService) AlsoBooked(ctx context.Context, tourID uint) ([]models.Tour, error) {
+	tours, err := s.repo.FindForTour(ctx, tourID, constants.RecommendationKindAlsoBooked, recPerTour)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceForTour, err)
+	}
+	return tours, nil
+}
+
+// ForUser returns the precomputed "recommended for you" tours.
+func (s *RecommendationService) ForUser(ctx context.Context, userID uint, limit int) ([]models.Tour, error) {
+	tours, err := s.repo.FindForUser(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceForUser, err)
+	}
+	return tours, nil
+}
+
+// Recompute rebuilds every recommendation from the current catalogue,
+// bookings and ratings. It returns how many tour and user rows were stored.
+func (s *RecommendationService) Recompute(ctx context.Context, now time.Time) (int, int, error) {
+	tours, err := s.repo.FindCandidateTours(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceRecompute, err)
+	}
+	coBookings, err := s.repo.CoBookingCounts(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceRecompute, err)
+	}
+	booked, err := s.repo.UserBookedTours(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceRecompute, err)
+	}
+	ratings, err := s.repo.UserRatings(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceRecompute, err)
+	}
+
+	related, tourRecs := buildTourRecommendations(tours, coBookings, now)
+	userRecs := buildUserRecommendations(related, booked, ratings, now)
+
+	if err := s.repo.ReplaceAll(ctx, tourRecs, userRecs); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxRecommendationServiceRecompute, err)
+	}
+	return len(tourRecs), len(userRecs), nil
+}
+
+type scoredTour struct {
+	id    uint
+	score float64
+}
+
+// buildTourRecommendations scores every pair of active tours. It returns
+// the top recSeedNeighbours related tours per tour (for user scoring) and
+// the rows to store.
+func buildTourRecommendations(tours []models.Tour, coBookings []repository.CoBooking, now time.Time) (map[uint][]scoredTour, []models.TourRecommendation) {
+	active := make(map[uint]bool, len(tours))
+	cats := make(map[uint]map[uint]bool, len(tours))
+	terms := make(map[uint][]string, len(tours))
+	for _, t := range tours {
+		active[t.ID] = true
+		set := make(map[uint]bool, len(t.Categories))
+		for _, c := range t.Categories {
+			set[c.ID] = true
+		}
+		cats[t.ID] = set
+		terms[t.ID] = utils.SearchTerms(t.Location)
+	}
+
+	co := make(map[uint]map[uint]int64)
+	coMax := make(map[uint]int64)
+	for _, cb := range coBookings {
+		if !active[cb.TourID] || !active[cb.OtherTourID] {
+			continue
+		}
+		if co[cb.TourID] == nil {
+			co[cb.TourID] = make(map[uint]int64)
+		}
+		co[cb.TourID][cb.OtherTourID] = cb.Users
+		coMax[cb.TourID] = max(coMax[cb.TourID], cb.Users)
+	}
+
+	related := make(map[uint][]scoredTour, len(tours))
+	var rows []models.TourRecommendation
+	for i := range tours {
+		a := &tours[i]
+		var scored []scoredTour
+		for j := range tours {
+			b := &tours[j]
+			if a.ID == b.ID {
+				continue
+			}
+			score := recWeightCategory*jaccardIDs(cats[a.ID], cats[b.ID]) +
+				recWeightLocation*locationSimilarity(a, b, terms[a.ID], terms[b.ID])
+			if n := co[a.ID][b.ID]; n > 0 {
+				score += recWeightCoBooked * float64(n) / float64(coMax[a.ID])
+			}
+			if score > 0 {
+				scored = append(scored, scoredTour{id: b.ID, score: score})
+			}
+		}
+		sortScored(scored)
+		related[a.ID] = scored[:min(len(scored), recSeedNeighbours)]
+		rows = appendTourRows(rows, a.ID, constants.RecommendationKindRelated, scored, now)
+
+		var alsoBooked []scoredTour
+		for other, n := range co[a.ID] {
+			alsoBooked = append(alsoBooked, scoredTour{id: other, score: float64(n)})
+		}
+		sortScored(alsoBooked)
+		rows = appendTourRows(rows, a.ID, constants.RecommendationKindAlsoBooked, alsoBooked, now)
+	}
+	return related, rows
+}
+
+// buildUserRecommendations spreads each user's interest from the tours they
+// booked or rated onto related tours. Bookings and 5-star ratings count
+// fully, 4 stars half; 1-2 star ratings push similar tours down. Tours the
+// user already booked or rated are never recommended.
+func buildUserRecommendations(related map[uint][]scoredTour, booked []repository.UserTour, ratings []models.Rating, now time.Time) []models.UserRecommendation {
+	seeds := make(map[uint]map[uint]float64)
+	addSeed := func(userID, tourID uint, weight float64) {
+		if seeds[userID] == nil {
+			seeds[userID] = make(map[uint]float64)
+		}
+		seeds[userID][tourID] = weight
+	}
+	for _, b := range booked {
+		addSeed(b.UserID, b.TourID, 1)
+	}
+	for _, r := range ratings {
+		weight := float64(r.Score-3) / 2
+		if current, ok := seeds[r.UserID][r.TourID]; ok {
+			// A low rating outweighs having booked the tour.
+			weight = math.Min(current, weight)
+		}
+		addSeed(r.UserID, r.TourID, weight)
+	}
+
+	userIDs := make([]uint, 0, len(seeds))
+	for id := range seeds {
+		userIDs = append(userIDs, id)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	var rows []models.UserRecommendation
+	for _, userID := range userIDs {
+		scores := make(map[uint]float64)
+		for seedID, weight := range seeds[userID] {
+			for _, st := range related[seedID] {
+				scores[st.id] += weight * st.score
+			}
+		}
+
+		var ranked []scoredTour
+		for id, score := range scores {
+			if _, seen := seeds[userID][id]; seen || score <= 0 {
+				continue
+			}
+			ranked = append(ranked, scoredTour{id: id, score: score})
+		}
+		sortScored(ranked)
+		for i, st := range ranked[:min(len(ranked), recPerUser)] {
+			rows = append(rows, models.UserRecommendation{
+				UserID:    userID,
+				Rank:      i + 1,
+				TourID:    st.id,
+				Score:     st.score,
+				CreatedAt: now,
+			})
+		}
+	}
+	return rows
+}
+
+func appendTourRows(rows []models.TourRecommendation, tourID uint, kind string, scored []scoredTour, now time.Time) []models.TourRecommendation {
+	for i, st := range scored[:min(len(scored), recPerTour)] {
+		rows = append(rows, models.TourRecommendation{
+			TourID:            tourID,
+			Kind:              kind,
+			Rank:              i + 1,
+			RecommendedTourID: st.id,
+			Score:             st.score,
+			CreatedAt:         now,
+		})
+	}
+	return rows
+}
+
+// sortScored orders by score, highest first; ties go to the lower ID so
+// results are stable between runs.
+func sortScored(s []scoredTour) {
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].score != s[j].score {
+			return s[i].score > s[j].score
+		}
+		return s[i].id < s[j].id
+	})
+}
+
+func jaccardIDs(a, b map[uint]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for id := range a {
+		if b[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// locationSimilarity is 1 for the same spot, falling linearly to 0 at
+// recNearbyKm. Without coordinates it compares the words of the location
+// strings.
+func locationSimilarity(a, b *models.Tour, termsA, termsB []string) float64 {
+	if a.HasCoordinates() && b.HasCoordinates() {
+		d := utils.HaversineKm(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude)
+		return math.Max(0, 1-d/recNearbyKm)
+	}
+	if len(termsA) == 0 || len(termsB) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(termsA))
+	for _, t := range termsA {
+		set[t] = true
+	}
+	shared := 0
+	for _, t := range termsB {
+		if set[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(termsA)+len(termsB)-shared)
+}
@@ -0,0 +1,124 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupRecommendationServiceDB(t *testing.T) (*RecommendationService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+	return NewRecommendationService(repository.NewRecommendationRepository(db)), db
+}
+
+func seedRecTour(t *testing.T, db *gorm.DB, slug, location string, cats ...models.Category) models.Tour {
+	t.Helper()
+	tour := models.Tour{
+		Title:           slug,
+		Slug:            slug,
+		Location:        location,
+		Price:           100,
+		DurationDays:    2,
+		MaxParticipants: 10,
+		Status:          constants.TourStatusActive,
+		Categories:      cats,
+	}
+	require.NoError(t, db.Create(&tour).Error)
+	return tour
+}
+
+func seedRecBooking(t *testing.T, db *gorm.DB, userID, tourID uint) {
+	t.Helper()
+	seedBookingOn(t, db, userID, tourID, time.Now().AddDate(0, 0, 10), constants.BookingStatusConfirmed)
+}
+
+func tourIDs(tours []models.Tour) []uint {
+	ids := make([]uint, len(tours))
+	for i, t := range tours {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func TestRelatedTours_ServesRecomputedRanking(t *testing.T) {
+	svc, db := setupRecommendationServiceDB(t)
+	ctx := context.Background()
+	beach := models.Category{Name: "Beach", Slug: "beach"}
+	trek := models.Category{Name: "Trek", Slug: "trek"}
+	require.NoError(t, db.Create(&beach).Error)
+	require.NoError(t, db.Create(&trek).Error)
+
+	base := seedRecTour(t, db, "nha-trang", "Nha Trang", beach)
+	sameBoth := seedRecTour(t, db, "nha-trang-island", "Nha Trang", beach)
+	sameCat := seedRecTour(t, db, "phu-quoc", "Phú Quốc", beach)
+	seedRecTour(t, db, "sa-pa", "Sa Pa", trek)
+
+	_, _, err := svc.Recompute(ctx, time.Now())
+	require.NoError(t, err)
+
+	related, err := svc.RelatedTours(ctx, base.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{sameBoth.ID, sameCat.ID}, tourIDs(related))
+}
+
+func TestAlsoBooked_CountsCoBookingUsers(t *testing.T) {
+	svc, db := setupRecommendationServiceDB(t)
+	ctx := context.Background()
+	a := seedRecTour(t, db, "a", "Hà Nội")
+	b := seedRecTour(t, db, "b", "Huế")
+	c := seedRecTour(t, db, "c", "Đà Lạt")
+
+	for _, name := range []string{"an", "binh"} {
+		user := seedUser(t, db, name, constants.RoleUser)
+		seedRecBooking(t, db, user.ID, a.ID)
+		seedRecBooking(t, db, user.ID, b.ID)
+	}
+	chi := seedUser(t, db, "chi", constants.RoleUser)
+	seedRecBooking(t, db, chi.ID, a.ID)
+	seedRecBooking(t, db, chi.ID, c.ID)
+
+	_, _, err := svc.Recompute(ctx, time.Now())
+	require.NoError(t, err)
+
+	also, err := svc.AlsoBooked(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{b.ID, c.ID}, tourIDs(also))
+}
+
+func TestForUser_ServesRecomputedRanking(t *testing.T) {
+	svc, db := setupRecommendationServiceDB(t)
+	ctx := context.Background()
+	beach := models.Category{Name: "Beach", Slug: "beach"}
+	trek := models.Category{Name: "Trek", Slug: "trek"}
+	require.NoError(t, db.Create(&beach).Error)
+	require.NoError(t, db.Create(&trek).Error)
+
+	booked := seedRecTour(t, db, "booked", "Nha Trang", beach)
+	similar := seedRecTour(t, db, "similar", "Quy Nhơn", beach)
+	disliked := seedRecTour(t, db, "disliked", "Sa Pa", trek)
+	seedRecTour(t, db, "also-trek", "Hà Giang", trek)
+
+	userID := seedUser(t, db, "an", constants.RoleUser).ID
+	seedRecBooking(t, db, userID, booked.ID)
+	require.NoError(t, db.Create(&models.Rating{UserID: userID, TourID: disliked.ID, Score: 1}).Error)
+
+	_, userRows, err := svc.Recompute(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, userRows)
+
+	recs, err := svc.ForUser(ctx, userID, constants.HomeRecommendedLimit)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{similar.ID}, tourIDs(recs))
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- helpers ----------------------------------------------------------
+
+type recommendationInput struct {
+	tours      []models.Tour
+	coBookings []repository.CoBooking
+	booked     []repository.UserTour
+	ratings    []models.Rating
+}
+
+// recompute runs Recompute over the input and returns the rows it stored.
+func recompute(t *testing.T, in recommendationInput) ([]models.TourRecommendation, []models.UserRecommendation) {
+	t.Helper()
+	repo := new(testutil.MockRecommendationRepo)
+	svc := NewRecommendationService(repo)
+	repo.On("FindCandidateTours", mock.Anything).Return(in.tours, nil)
+	repo.On("CoBookingCounts", mock.Anything).Return(in.coBookings, nil)
+	repo.On("UserBookedTours", mock.Anything).Return(in.booked, nil)
+	repo.On("UserRatings", mock.Anything).Return(in.ratings, nil)
+
+	var tourRecs []models.TourRecommendation
+	var userRecs []models.UserRecommendation
+	repo.On("ReplaceAll", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tourRecs = args.Get(1).([]models.TourRecommendation)
+		userRecs = args.Get(2).([]models.UserRecommendation)
+	}).Return(nil)
+
+	tourRows, userRows, err := svc.Recompute(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, len(tourRecs), tourRows)
+	assert.Equal(t, len(userRecs), userRows)
+	return tourRecs, userRecs
+}
+
+func recTour(id uint, location string, categoryIDs ...uint) models.Tour {
+	tour := models.Tour{ID: id, Location: location}
+	for _, c := range categoryIDs {
+		tour.Categories = append(tour.Categories, models.Category{ID: c})
+	}
+	return tour
+}
+
+func recommendedFor(rows []models.TourRecommendation, tourID uint, kind string) []uint {
+	var ids []uint
+	for _, r := range rows {
+		if r.TourID == tourID && r.Kind == kind {
+			ids = append(ids, r.RecommendedTourID)
+		}
+	}
+	return ids
+}
+
+func recommendedForUser(rows []models.UserRecommendation, userID uint) []uint {
+	var ids []uint
+	for _, r := range rows {
+		if r.UserID == userID {
+			ids = append(ids, r.TourID)
+		}
+	}
+	return ids
+}
+
+// --- Recompute --------------------------------------------------------
+
+func TestRecompute_RelatedTours_RankBySharedCategoryAndLocation(t *testing.T) {
+	const beach, trek = 1, 2
+	tourRecs, _ := recompute(t, recommendationInput{tours: []models.Tour{
+		recTour(1, "Nha Trang", beach),
+		recTour(2, "Nha Trang", beach),
+		recTour(3, "Phú Quốc", beach),
+		recTour(4, "Sa Pa", trek),
+	}})
+
+	assert.Equal(t, []uint{2, 3}, recommendedFor(tourRecs, 1, constants.RecommendationKindRelated))
+}
+
+func TestRecompute_AlsoBooked_OrdersByCoBookingUsers(t *testing.T) {
+	tourRecs, _ := recompute(t, recommendationInput{
+		tours: []models.Tour{recTour(1, "Hà Nội"), recTour(2, "Huế"), recTour(3, "Đà Lạt")},
+		coBookings: []repository.CoBooking{
+			{TourID: 1, OtherTourID: 3, Users: 1},
+			{TourID: 1, OtherTourID: 2, Users: 2},
+		},
+	})
+
+	assert.Equal(t, []uint{2, 3}, recommendedFor(tourRecs, 1, constants.RecommendationKindAlsoBooked))
+}
+
+func TestRecompute_AlsoBooked_SkipsInactiveTours(t *testing.T) {
+	tourRecs, _ := recompute(t, recommendationInput{
+		tours:      []models.Tour{recTour(1, "Hà Nội"), recTour(2, "Huế")},
+		coBookings: []repository.CoBooking{{TourID: 1, OtherTourID: 9, Users: 5}, {TourID: 1, OtherTourID: 2, Users: 1}},
+	})
+
+	assert.Equal(t, []uint{2}, recommendedFor(tourRecs, 1, constants.RecommendationKindAlsoBooked))
+}
+
+func TestRecompute_ForUser_ExcludesBookedAndPenalisesLowRatings(t *testing.T) {
+	const beach, trek, userID = 1, 2, 7
+	_, userRecs := recompute(t, recommendationInput{
+		tours: []models.Tour{
+			recTour(1, "Nha Trang", beach),
+			recTour(2, "Quy Nhơn", beach),
+			recTour(3, "Sa Pa", trek),
+			recTour(4, "Hà Giang", trek),
+		},
+		booked:  []repository.UserTour{{UserID: userID, TourID: 1}},
+		ratings: []models.Rating{{UserID: userID, TourID: 3, Score: 1}},
+	})
+
+	assert.Equal(t, []uint{2}, recommendedForUser(userRecs, userID))
+}
+
+func TestRecompute_StopsWhenLoadingFails(t *testing.T) {
+	repo := new(testutil.MockRecommendationRepo)
+	svc := NewRecommendationService(repo)
+	repo.On("FindCandidateTours", mock.Anything).Return([]models.Tour{}, errors.New("db down"))
+
+	_, _, err := svc.Recompute(context.Background(), time.Now())
+
+	require.Error(t, err)
+	repo.AssertNotCalled(t, "ReplaceAll", mock.Anything, mock.Anything, mock.Anything)
+}
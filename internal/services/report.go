@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const reportExcerptLength = 160
+
+var reportReasonLabels = map[string]string{
+	constants.ReportReasonSpam:          messages.ReportReasonSpam,
+	constants.ReportReasonAbusive:       messages.ReportReasonAbusive,
+	constants.ReportReasonMisleading:    messages.ReportReasonMisleading,
+	constants.ReportReasonInappropriate: messages.ReportReasonInappropriate,
+	constants.ReportReasonOther:         messages.ReportReasonOther,
+}
+
+// ReportReasonOption is a report category for the report form.
+type ReportReasonOption struct {
+	Value string
+	Label string
+}
+
+// ReportReasonOptions lists the report categories in display order.
+func ReportReasonOptions() []ReportReasonOption {
+	options := make([]ReportReasonOption, 0, len(constants.ReportReasons))
+	for _, reason := range constants.ReportReasons {
+		options = append(options, ReportReasonOption{Value: reason, Label: reportReasonLabels[reason]})
+	}
+	return options
+}
+
+// ReportInput is a user's report of a review or comment.
+type ReportInput struct {
+	Target repository.ReportTarget
+	Reason string
+	Note   string
+}
+
+// ReportReasonCount is how many open reports of an item gave a reason.
+type ReportReasonCount struct {
+	Label string
+	Count int
+}
+
+// ReportGroup is one reported item in the admin queue. Review is the
+// reported review, or the review the reported comment belongs to; both are
+// nil when the content has since been deleted.
+type ReportGroup struct {
+	Target         repository.ReportTarget
+	Count          int64
+	Review         *models.Review
+	Comment        *models.Comment
+	Reports        []models.Report
+	Reasons        []ReportReasonCount
+	LastReportedAt time.Time
+}
+
+// Author is the user who wrote the reported content.
+func (g ReportGroup) Author() *models.User {
+	if g.Comment != nil {
+		return g.Comment.User
+	}
+	if g.Review != nil {
+		return g.Review.User
+	}
+	return nil
+}
+
+// Status is the current status of the reported content.
+func (g ReportGroup) Status() string {
+	if g.Comment != nil {
+		return g.Comment.Status
+	}
+	if g.Review != nil {
+		return g.Review.Status
+	}
+	return ""
+}
+
+// reportedItem is the part of a review or comment that reporting needs.
+// published and hidden are computed from the status constants of the
+// item's own type.
+type reportedItem struct {
+	authorID  uint
+	author    *models.User
+	published bool
+	hidden    bool
+	excerpt   string
+}
+
+type ReportService struct {
+	db           *gorm.DB
+	reportRepo   repository.ReportRepo
+	reviewRepo   repository.ReviewRepo
+	commentRepo  repository.CommentRepo
+	emailService *EmailService
+}
+
+func NewReportService(db *gorm.DB, reportRepo repository.ReportRepo, reviewRepo repository.ReviewRepo, commentRepo repository.CommentRepo, emailService *EmailService) *ReportService {
+	return &ReportService{db: db, reportRepo: reportRepo, reviewRepo: reviewRepo, commentRepo: commentRepo, emailService: emailService}
+}
+
+// withTx returns the service with its repositories bound to tx.
+func (s *ReportService) withTx(tx *gorm.DB) *ReportService {
+	return &ReportService{
+		db:           tx,
+		reportRepo:   repository.NewReportRepository(tx),
+		reviewRepo:   repository.NewReviewRepository(tx),
+		commentRepo:  repository.NewCommentRepository(tx),
+		emailService: s.emailService,
+	}
+}
+
+// Report files a user's report. Each user reports an item once, and only
+// published content of other users can be reported. Once the item has
+// constants.ReportAutoHideThreshold open reports it is hidden until an
+// admin resolves them; hidden tells whether this report did that.
+func (s *ReportService) Report(ctx context.Context, userID uint, input ReportInput) (hidden bool, err error) {
+	if _, ok := reportReasonLabels[input.Reason]; !ok {
+		return false, appErrors.ErrReportInvalidReason
+	}
+
+	item, err := s.loadTarget(ctx, input.Target)
+	if err != nil {
+		return false, err
+	}
+	if !item.published {
+		return false, appErrors.ErrReportTargetNotFound
+	}
+	if item.authorID == userID {
+		return false, appErrors.ErrReportOwnContent
+	}
+
+	exists, err := s.reportRepo.Exists(ctx, userID, input.Target)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceReport, err)
+	}
+	if exists {
+		return false, appErrors.ErrAlreadyReported
+	}
+
+	report := &models.Report{
+		UserID:     userID,
+		TargetType: input.Target.Type,
+		TargetID:   input.Target.ID,
+		Reason:     input.Reason,
+		Note:       truncateReason(strings.TrimSpace(input.Note), maxModerationReason),
+		Status:     constants.ReportStatusOpen,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		// A concurrent report by the same user got in after the check.
+		if appErrors.IsDuplicateEntryError(err) {
+			return false, appErrors.ErrAlreadyReported
+		}
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceReport, err)
+	}
+
+	count, err := s.reportRepo.CountOpen(ctx, input.Target)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceReport, err)
+	}
+	if count < constants.ReportAutoHideThreshold {
+		return false, nil
+	}
+	if err := s.setStatus(ctx, input.Target, true, messages.ReportHiddenReason); err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceReport, err)
+	}
+	return true, nil
+}
+
+// Queue lists reported items with open reports, most reported first.
+func (s *ReportService) Queue(ctx context.Context, page, limit int) ([]ReportGroup, int64, error) {
+	rows, total, err := s.reportRepo.FindOpenGroups(ctx, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceQueue, err)
+	}
+
+	targets := make([]repository.ReportTarget, len(rows))
+	groups := make([]ReportGroup, len(rows))
+	index := make(map[repository.ReportTarget]int, len(rows))
+	for i, row := range rows {
+		targets[i] = repository.ReportTarget{Type: row.TargetType, ID: row.TargetID}
+		groups[i] = ReportGroup{Target: targets[i], Count: row.ReportCount}
+		index[targets[i]] = i
+	}
+
+	reports, err := s.reportRepo.FindOpenByTargets(ctx, targets)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceQueue, err)
+	}
+	for _, report := range reports {
+		i := index[repository.ReportTarget{Type: report.TargetType, ID: report.TargetID}]
+		groups[i].Reports = append(groups[i].Reports, report)
+		if report.CreatedAt.After(groups[i].LastReportedAt) {
+			groups[i].LastReportedAt = report.CreatedAt
+		}
+	}
+
+	for i := range groups {
+		groups[i].Reasons = countReportReasons(groups[i].Reports)
+		if err := s.attachContent(ctx, &groups[i]); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceQueue, err)
+		}
+	}
+	return groups, total, nil
+}
+
+// Resolve closes every open report of an item with the admin's action:
+// dismiss restores content hidden by reports; warn does the same and emails
+// the author a warning, keeping the content published; hide takes the
+// content down; ban also bans the author through
+// AdminUserService.UpdateUserStatus. The status changes and closing the
+// reports commit together; the warning email is sent after.
+func (s *ReportService) Resolve(ctx context.Context, admin *models.User, target repository.ReportTarget, action string) error {
+	switch action {
+	case constants.ReportActionDismiss, constants.ReportActionHide, constants.ReportActionWarn, constants.ReportActionBan:
+	default:
+		return appErrors.ErrReportInvalidAction
+	}
+
+	item, err := s.loadTarget(ctx, target)
+	if errors.Is(err, appErrors.ErrReportTargetNotFound) && action == constants.ReportActionDismiss {
+		// The content was deleted; just close its reports.
+		return s.closeReports(ctx, target, action, admin.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	var reasons string
+	if action != constants.ReportActionDismiss {
+		reports, err := s.reportRepo.FindOpenByTargets(ctx, []repository.ReportTarget{target})
+		if err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceResolve, err)
+		}
+		reasons = reportReasonSummary(countReportReasons(reports))
+	}
+	keep := action == constants.ReportActionDismiss || action == constants.ReportActionWarn
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if action == constants.ReportActionBan {
+			users := NewAdminUserService(tx, repository.NewUserRepository(tx))
+			if err := users.UpdateUserStatus(ctx, admin, item.authorID, constants.StatusBanned); err != nil {
+				return err
+			}
+		}
+		txs := s.withTx(tx)
+		switch {
+		case keep && item.hidden:
+			if err := txs.setStatus(ctx, target, false, ""); err != nil {
+				return fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceResolve, err)
+			}
+		case !keep:
+			if err := txs.setStatus(ctx, target, true, fmt.Sprintf(messages.ReportRemovedReason, reasons)); err != nil {
+				return fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceResolve, err)
+			}
+		}
+		return txs.closeReports(ctx, target, action, admin.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if action == constants.ReportActionWarn {
+		if err := s.emailService.SendContentWarningEmail(item.author, item.excerpt, reasons); err != nil {
+			slog.Error(messages.LogContentWarningEmailFailed, "target_type", target.Type, "target_id", target.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *ReportService) closeReports(ctx context.Context, target repository.ReportTarget, action string, adminID uint) error {
+	if err := s.reportRepo.Resolve(ctx, target, action, adminID); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceResolve, err)
+	}
+	return nil
+}
+
+func (s *ReportService) loadTarget(ctx context.Context, target repository.ReportTarget) (*reportedItem, error) {
+	switch target.Type {
+	case constants.ReportTargetReview:
+		review, err := s.reviewRepo.FindByID(ctx, target.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, appErrors.ErrReportTargetNotFound
+			}
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceReport, err)
+		}
+		return &reportedItem{
+			authorID:  review.UserID,
+			author:    review.User,
+			published: review.Status == constants.ReviewStatusApproved,
+			hidden:    review.Status == constants.ReviewStatusHidden,
+			excerpt:   review.Title,
+		}, nil
+	case constants.ReportTargetComment:
+		comment, err := s.commentRepo.FindByID(ctx, target.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, appErrors.ErrReportTargetNotFound
+			}
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReportServiceReport, err)
+		}
+		if comment.IsRemoved() {
+			return nil, appErrors.ErrReportTargetNotFound
+		}
+		return &reportedItem{
+			authorID:  comment.UserID,
+			author:    comment.User,
+			published: comment.Status == constants.CommentStatusApproved,
+			hidden:    comment.Status == constants.CommentStatusHidden,
+			excerpt:   truncateReason(comment.Content, reportExcerptLength),
+		}, nil
+	}
+	return nil, appErrors.ErrReportTargetNotFound
+}
+
+func (s *ReportService) attachContent(ctx context.Context, group *ReportGroup) error {
+	switch group.Target.Type {
+	case constants.ReportTargetReview:
+		review, err := s.reviewRepo.FindByID(ctx, group.Target.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		group.Review = review
+	case constants.ReportTargetComment:
+		comment, err := s.commentRepo.FindByID(ctx, group.Target.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if comment != nil && !comment.IsRemoved() {
+			group.Comment = comment
+			group.Review = comment.Review
+		}
+	}
+	return nil
+}
+
+// setStatus hides the reported item, or publishes it again when hidden is
+// false, using the status constants of its type.
+func (s *ReportService) setStatus(ctx context.Context, target repository.ReportTarget, hidden bool, reason string) error {
+	if target.Type == constants.ReportTargetComment {
+		status := constants.CommentStatusApproved
+		if hidden {
+			status = constants.CommentStatusHidden
+		}
+		return s.commentRepo.UpdateStatus(ctx, target.ID, status, reason)
+	}
+	status := constants.ReviewStatusApproved
+	if hidden {
+		status = constants.ReviewStatusHidden
+	}
+	return s.reviewRepo.UpdateStatus(ctx, target.ID, status, reason)
+}
+
+// countReportReasons tallies the reasons of the reports, most common first.
+func countReportReasons(reports []models.Report) []ReportReasonCount {
+	counts := map[string]int{}
+	for _, r := range reports {
+		counts[r.Reason]++
+	}
+	result := make([]ReportReasonCount, 0, len(counts))
+	for _, reason := range constants.ReportReasons {
+		if counts[reason] > 0 {
+			result = append(result, ReportReasonCount{Label: reportReasonLabels[reason], Count: counts[reason]})
+		}
+	}
+	slices.SortStableFunc(result, func(a, b ReportReasonCount) int { return b.Count - a.Count })
+	return result
+}
+
+func reportReasonSummary(reasons []ReportReasonCount) string {
+	labels := make([]string, len(reasons))
+	for i, r := range reasons {
+		labels[i] = r.Label
+	}
+	return strings.Join(labels, ", ")
+}
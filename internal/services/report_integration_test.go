@@ -0,0 +1,196 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupReportServiceDB(t *testing.T) (*ReportService, *ReviewService, *gorm.DB) {
+	t.Helper()
+	reviewSvc, _, db := setupReviewServiceDB(t)
+	svc := NewReportService(db, repository.NewReportRepository(db), repository.NewReviewRepository(db),
+		repository.NewCommentRepository(db), NewEmailService(&config.Config{}))
+	return svc, reviewSvc, db
+}
+
+func seedReportUsers(t *testing.T, db *gorm.DB, n int) []models.User {
+	t.Helper()
+	users := make([]models.User, n)
+	for i := range users {
+		users[i] = seedUser(t, db, fmt.Sprintf("user-%d", i), constants.RoleUser)
+	}
+	return users
+}
+
+func TestReport_ValidatesAndAutoHides(t *testing.T) {
+	svc, reviewSvc, db := setupReportServiceDB(t)
+	ctx := context.Background()
+	users := seedReportUsers(t, db, 4)
+	review, err := reviewSvc.CreateReview(ctx, users[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	target := reviewTarget(review.ID)
+
+	_, err = svc.Report(ctx, users[1].ID, ReportInput{Target: target, Reason: "boring"})
+	assert.ErrorIs(t, err, appErrors.ErrReportInvalidReason)
+	_, err = svc.Report(ctx, users[0].ID, ReportInput{Target: target, Reason: constants.ReportReasonSpam})
+	assert.ErrorIs(t, err, appErrors.ErrReportOwnContent)
+	_, err = svc.Report(ctx, users[1].ID, ReportInput{Target: reviewTarget(review.ID + 100), Reason: constants.ReportReasonSpam})
+	assert.ErrorIs(t, err, appErrors.ErrReportTargetNotFound)
+
+	hidden, err := svc.Report(ctx, users[1].ID, ReportInput{Target: target, Reason: constants.ReportReasonSpam, Note: " ads "})
+	require.NoError(t, err)
+	assert.False(t, hidden)
+	_, err = svc.Report(ctx, users[1].ID, ReportInput{Target: target, Reason: constants.ReportReasonAbusive})
+	assert.ErrorIs(t, err, appErrors.ErrAlreadyReported)
+
+	hidden, err = svc.Report(ctx, users[2].ID, ReportInput{Target: target, Reason: constants.ReportReasonAbusive})
+	require.NoError(t, err)
+	assert.False(t, hidden)
+	hidden, err = svc.Report(ctx, users[3].ID, ReportInput{Target: target, Reason: constants.ReportReasonSpam})
+	require.NoError(t, err)
+	assert.True(t, hidden)
+
+	var stored models.Review
+	require.NoError(t, db.First(&stored, review.ID).Error)
+	assert.Equal(t, constants.ReviewStatusHidden, stored.Status)
+
+	// Hidden content cannot be reported again, and editing it does not
+	// republish it.
+	_, err = svc.Report(ctx, users[0].ID+10, ReportInput{Target: target, Reason: constants.ReportReasonSpam})
+	assert.ErrorIs(t, err, appErrors.ErrReportTargetNotFound)
+	updated, err := reviewSvc.UpdateReview(ctx, review.ID, users[0].ID, storyInput("Bay cruise, revised"))
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReviewStatusPending, updated.Status)
+
+	groups, total, err := svc.Queue(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, groups, 1)
+	assert.Equal(t, int64(3), groups[0].Count)
+	assert.Len(t, groups[0].Reports, 3)
+	require.Len(t, groups[0].Reasons, 2)
+	assert.Equal(t, 2, groups[0].Reasons[0].Count)
+	require.NotNil(t, groups[0].Author())
+	assert.Equal(t, users[0].ID, groups[0].Author().ID)
+}
+
+func TestResolveReports_Actions(t *testing.T) {
+	svc, reviewSvc, db := setupReportServiceDB(t)
+	ctx := context.Background()
+	users := seedReportUsers(t, db, 3)
+	admin := seedUser(t, db, "admin", constants.RoleAdmin)
+
+	review, err := reviewSvc.CreateReview(ctx, users[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	comment, err := reviewSvc.AddComment(ctx, users[1].ID, review.ID, nil, "Looks lovely, thanks for sharing!")
+	require.NoError(t, err)
+	commentTarget := repository.ReportTarget{Type: constants.ReportTargetComment, ID: comment.ID}
+
+	_, err = svc.Report(ctx, users[2].ID, ReportInput{Target: reviewTarget(review.ID), Reason: constants.ReportReasonMisleading})
+	require.NoError(t, err)
+	_, err = svc.Report(ctx, users[2].ID, ReportInput{Target: commentTarget, Reason: constants.ReportReasonAbusive})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.Resolve(ctx, &admin, reviewTarget(review.ID), "delete"), appErrors.ErrReportInvalidAction)
+
+	require.NoError(t, svc.Resolve(ctx, &admin, reviewTarget(review.ID), constants.ReportActionDismiss))
+	var storedReview models.Review
+	require.NoError(t, db.First(&storedReview, review.ID).Error)
+	assert.Equal(t, constants.ReviewStatusApproved, storedReview.Status)
+
+	// Warning the author keeps the content published.
+	_, err = svc.Report(ctx, users[1].ID, ReportInput{Target: reviewTarget(review.ID), Reason: constants.ReportReasonSpam})
+	require.NoError(t, err)
+	require.NoError(t, svc.Resolve(ctx, &admin, reviewTarget(review.ID), constants.ReportActionWarn))
+	require.NoError(t, db.First(&storedReview, review.ID).Error)
+	assert.Equal(t, constants.ReviewStatusApproved, storedReview.Status)
+
+	require.NoError(t, svc.Resolve(ctx, &admin, commentTarget, constants.ReportActionBan))
+	var storedComment models.Comment
+	require.NoError(t, db.First(&storedComment, comment.ID).Error)
+	assert.Equal(t, constants.CommentStatusHidden, storedComment.Status)
+	assert.NotEmpty(t, storedComment.RejectReason)
+	var author models.User
+	require.NoError(t, db.First(&author, users[1].ID).Error)
+	assert.Equal(t, constants.StatusBanned, author.Status)
+
+	var open int64
+	require.NoError(t, db.Model(&models.Report{}).Where("status = ?", constants.ReportStatusOpen).Count(&open).Error)
+	assert.Zero(t, open)
+	var banned models.Report
+	require.NoError(t, db.Where("target_type = ?", constants.ReportTargetComment).First(&banned).Error)
+	assert.Equal(t, constants.ReportActionBan, banned.Resolution)
+	require.NotNil(t, banned.ResolvedBy)
+	assert.Equal(t, admin.ID, *banned.ResolvedBy)
+
+	groups, total, err := svc.Queue(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, groups)
+}
+
+// racingReportRepo misses an existing report, as a concurrent request
+// would between the check and the insert.
+type racingReportRepo struct {
+	repository.ReportRepo
+}
+
+func (racingReportRepo) Exists(context.Context, uint, repository.ReportTarget) (bool, error) {
+	return false, nil
+}
+
+func TestReport_ConcurrentDuplicateIsAlreadyReported(t *testing.T) {
+	svc, reviewSvc, db := setupReportServiceDB(t)
+	ctx := context.Background()
+	users := seedReportUsers(t, db, 2)
+	review, err := reviewSvc.CreateReview(ctx, users[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	input := ReportInput{Target: reviewTarget(review.ID), Reason: constants.ReportReasonSpam}
+	_, err = svc.Report(ctx, users[1].ID, input)
+	require.NoError(t, err)
+
+	svc.reportRepo = racingReportRepo{svc.reportRepo}
+	_, err = svc.Report(ctx, users[1].ID, input)
+
+	assert.ErrorIs(t, err, appErrors.ErrAlreadyReported)
+}
+
+func TestResolveReports_BanRollsBackWhenClosingFails(t *testing.T) {
+	svc, reviewSvc, db := setupReportServiceDB(t)
+	ctx := context.Background()
+	users := seedReportUsers(t, db, 2)
+	admin := seedUser(t, db, "admin", constants.RoleAdmin)
+	review, err := reviewSvc.CreateReview(ctx, users[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	_, err = svc.Report(ctx, users[1].ID, ReportInput{Target: reviewTarget(review.ID), Reason: constants.ReportReasonSpam})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Callback().Update().Before("gorm:update").Register("fail_reports", func(tx *gorm.DB) {
+		if tx.Statement.Table == "reports" {
+			_ = tx.AddError(errors.New("reports unavailable"))
+		}
+	}))
+	err = svc.Resolve(ctx, &admin, reviewTarget(review.ID), constants.ReportActionBan)
+	require.Error(t, err)
+
+	var author models.User
+	require.NoError(t, db.First(&author, users[0].ID).Error)
+	assert.Equal(t, constants.StatusActive, author.Status)
+	var stored models.Review
+	require.NoError(t, db.First(&stored, review.ID).Error)
+	assert.Equal(t, constants.ReviewStatusApproved, stored.Status)
+}
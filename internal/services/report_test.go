@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+type reportMocks struct {
+	reportRepo  *testutil.MockReportRepo
+	reviewRepo  *testutil.MockReviewRepo
+	commentRepo *testutil.MockCommentRepo
+}
+
+func setupReportService() (*ReportService, reportMocks) {
+	m := reportMocks{
+		reportRepo:  new(testutil.MockReportRepo),
+		reviewRepo:  new(testutil.MockReviewRepo),
+		commentRepo: new(testutil.MockCommentRepo),
+	}
+	svc := NewReportService(nil, m.reportRepo, m.reviewRepo, m.commentRepo, NewEmailService(&config.Config{}))
+	return svc, m
+}
+
+func reviewTarget(id uint) repository.ReportTarget {
+	return repository.ReportTarget{Type: constants.ReportTargetReview, ID: id}
+}
+
+// expectReview makes review 3 by user 1 loadable with the given status.
+func (m reportMocks) expectReview(status string) {
+	m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&models.Review{ID: 3, UserID: 1, Status: status}, nil)
+}
+
+// --- Report -----------------------------------------------------------
+
+func TestReport_RejectedReports(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		userID  uint
+		reason  string
+		wantErr error
+	}{
+		{"unknown reason", constants.ReviewStatusApproved, 2, "boring", appErrors.ErrReportInvalidReason},
+		{"own content", constants.ReviewStatusApproved, 1, constants.ReportReasonSpam, appErrors.ErrReportOwnContent},
+		{"unpublished content", constants.ReviewStatusPending, 2, constants.ReportReasonSpam, appErrors.ErrReportTargetNotFound},
+		{"hidden content", constants.ReviewStatusHidden, 2, constants.ReportReasonSpam, appErrors.ErrReportTargetNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReportService()
+			m.expectReview(tt.status)
+
+			_, err := svc.Report(context.Background(), tt.userID, ReportInput{Target: reviewTarget(3), Reason: tt.reason})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			m.reportRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReport_UnknownTarget(t *testing.T) {
+	svc, m := setupReportService()
+	m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.Report(context.Background(), 2, ReportInput{
+		Target: repository.ReportTarget{Type: constants.ReportTargetComment, ID: 4},
+		Reason: constants.ReportReasonSpam,
+	})
+
+	assert.ErrorIs(t, err, appErrors.ErrReportTargetNotFound)
+}
+
+func TestReport_AlreadyReported(t *testing.T) {
+	tests := []struct {
+		name      string
+		exists    bool
+		createErr error
+	}{
+		{"found by the check", true, nil},
+		{"concurrent insert", false, &pgconn.PgError{Code: "23505"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReportService()
+			m.expectReview(constants.ReviewStatusApproved)
+			m.reportRepo.On("Exists", mock.Anything, uint(2), reviewTarget(3)).Return(tt.exists, nil)
+			m.reportRepo.On("Create", mock.Anything, mock.Anything).Return(tt.createErr)
+
+			_, err := svc.Report(context.Background(), 2, ReportInput{Target: reviewTarget(3), Reason: constants.ReportReasonSpam})
+
+			assert.ErrorIs(t, err, appErrors.ErrAlreadyReported)
+			m.reportRepo.AssertNotCalled(t, "CountOpen", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReport_HidesAtThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		open       int64
+		wantHidden bool
+	}{
+		{"below threshold", constants.ReportAutoHideThreshold - 1, false},
+		{"at threshold", constants.ReportAutoHideThreshold, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReportService()
+			m.expectReview(constants.ReviewStatusApproved)
+			m.reportRepo.On("Exists", mock.Anything, uint(2), reviewTarget(3)).Return(false, nil)
+			m.reportRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *models.Report) bool {
+				return r.UserID == 2 && r.Note == "ads" && r.Status == constants.ReportStatusOpen
+			})).Return(nil)
+			m.reportRepo.On("CountOpen", mock.Anything, reviewTarget(3)).Return(tt.open, nil)
+			m.reviewRepo.On("UpdateStatus", mock.Anything, uint(3), constants.ReviewStatusHidden, mock.Anything).Return(nil)
+
+			hidden, err := svc.Report(context.Background(), 2, ReportInput{Target: reviewTarget(3), Reason: constants.ReportReasonSpam, Note: " ads "})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHidden, hidden)
+			if !tt.wantHidden {
+				m.reviewRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.reportRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReport_HidesCommentWithCommentStatus(t *testing.T) {
+	svc, m := setupReportService()
+	target := repository.ReportTarget{Type: constants.ReportTargetComment, ID: 4}
+	m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(&models.Comment{ID: 4, UserID: 1, Status: constants.CommentStatusApproved}, nil)
+	m.reportRepo.On("Exists", mock.Anything, uint(2), target).Return(false, nil)
+	m.reportRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	m.reportRepo.On("CountOpen", mock.Anything, target).Return(int64(constants.ReportAutoHideThreshold), nil)
+	m.commentRepo.On("UpdateStatus", mock.Anything, uint(4), constants.CommentStatusHidden, mock.Anything).Return(nil)
+
+	hidden, err := svc.Report(context.Background(), 2, ReportInput{Target: target, Reason: constants.ReportReasonAbusive})
+
+	require.NoError(t, err)
+	assert.True(t, hidden)
+	m.commentRepo.AssertExpectations(t)
+}
+
+// --- Queue ------------------------------------------------------------
+
+func TestQueue_GroupsReportsByTarget(t *testing.T) {
+	svc, m := setupReportService()
+	now := time.Now()
+	m.reportRepo.On("FindOpenGroups", mock.Anything, 1, 10).Return([]repository.ReportGroupRow{
+		{TargetType: constants.ReportTargetReview, TargetID: 3, ReportCount: 3},
+	}, int64(1), nil)
+	m.reportRepo.On("FindOpenByTargets", mock.Anything, []repository.ReportTarget{reviewTarget(3)}).Return([]models.Report{
+		{TargetType: constants.ReportTargetReview, TargetID: 3, Reason: constants.ReportReasonAbusive, CreatedAt: now.Add(-time.Hour)},
+		{TargetType: constants.ReportTargetReview, TargetID: 3, Reason: constants.ReportReasonSpam, CreatedAt: now},
+		{TargetType: constants.ReportTargetReview, TargetID: 3, Reason: constants.ReportReasonSpam, CreatedAt: now.Add(-2 * time.Hour)},
+	}, nil)
+	m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&models.Review{ID: 3, UserID: 1, User: &models.User{ID: 1}}, nil)
+
+	groups, total, err := svc.Queue(context.Background(), 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, groups, 1)
+	assert.Equal(t, int64(3), groups[0].Count)
+	assert.Len(t, groups[0].Reports, 3)
+	assert.True(t, groups[0].LastReportedAt.Equal(now))
+	require.Len(t, groups[0].Reasons, 2)
+	assert.Equal(t, reportReasonLabels[constants.ReportReasonSpam], groups[0].Reasons[0].Label)
+	assert.Equal(t, 2, groups[0].Reasons[0].Count)
+	require.NotNil(t, groups[0].Author())
+	assert.Equal(t, uint(1), groups[0].Author().ID)
+}
+
+// --- Resolve ----------------------------------------------------------
+
+func TestResolve_InvalidAction(t *testing.T) {
+	svc, m := setupReportService()
+
+	err := svc.Resolve(context.Background(), &models.User{ID: 9}, reviewTarget(3), "delete")
+
+	assert.ErrorIs(t, err, appErrors.ErrReportInvalidAction)
+	m.reviewRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestResolve_DeletedContent(t *testing.T) {
+	t.Run("dismiss closes the reports", func(t *testing.T) {
+		svc, m := setupReportService()
+		m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(nil, gorm.ErrRecordNotFound)
+		m.reportRepo.On("Resolve", mock.Anything, reviewTarget(3), constants.ReportActionDismiss, uint(9)).Return(nil)
+
+		require.NoError(t, svc.Resolve(context.Background(), &models.User{ID: 9}, reviewTarget(3), constants.ReportActionDismiss))
+		m.reportRepo.AssertExpectations(t)
+	})
+
+	t.Run("other actions need the content", func(t *testing.T) {
+		svc, m := setupReportService()
+		m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+		err := svc.Resolve(context.Background(), &models.User{ID: 9}, reviewTarget(3), constants.ReportActionHide)
+
+		assert.ErrorIs(t, err, appErrors.ErrReportTargetNotFound)
+		m.reportRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
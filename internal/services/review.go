@@ -2,59 +2,88 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"mime/multipart"
 	"strings"
+	"time"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
 
-	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+const reviewImagePrefix = "reviews"
+
 type ReviewService struct {
-	db       *gorm.DB
-	repo     repository.ReviewRepo
-	likeRepo repository.ReviewLikeRepo
-	cmtRepo  repository.CommentRepo
+	db          *gorm.DB
+	repo        repository.ReviewRepo
+	likeRepo    repository.ReviewLikeRepo
+	cmtRepo     repository.CommentRepo
+	bookingRepo repository.BookingRepo
+	media       *MediaService
+	moderation  *ModerationService
 }
 
-func NewReviewService(db *gorm.DB, repo repository.ReviewRepo, likeRepo repository.ReviewLikeRepo, cmtRepo repository.CommentRepo) *ReviewService {
-	return &ReviewService{db: db, repo: repo, likeRepo: likeRepo, cmtRepo: cmtRepo}
+// NewReviewService returns the review service. moderation is required: every
+// review and comment is scored by it before it is stored.
+func NewReviewService(db *gorm.DB, repo repository.ReviewRepo, likeRepo repository.ReviewLikeRepo, cmtRepo repository.CommentRepo, bookingRepo repository.BookingRepo, media *MediaService, moderation *ModerationService) *ReviewService {
+	return &ReviewService{db: db, repo: repo, likeRepo: likeRepo, cmtRepo: cmtRepo, bookingRepo: bookingRepo, media: media, moderation: moderation}
 }
 
+// ReviewCreateInput carries the image URLs to keep in Images and new
+// uploads in Files; uploads are appended after the kept URLs.
+// TourID and BookingID optionally link the review to a trip of the author;
+// either is enough, and a BookingID implies its tour.
 type ReviewCreateInput struct {
-	Title   string
-	Content string
-	Type    string
-	Images  []string
+	Title     string
+	Content   string
+	Type      string
+	Images    []string
+	Files     []*multipart.FileHeader
+	TourID    uint
+	BookingID uint
 }
 
+// CreateReview stores a new review with the status chosen by the moderation
+// rules: approved, pending for an admin, or rejected with a reason.
 func (s *ReviewService) CreateReview(ctx context.Context, userID uint, input ReviewCreateInput) (*models.Review, error) {
 	if err := s.validateReviewInput(input); err != nil {
 		return nil, err
 	}
+	tourID, bookingID, err := s.resolveTrip(ctx, userID, 0, input)
+	if err != nil {
+		return nil, err
+	}
+	verdict, err := s.moderateReview(ctx, userID, 0, input)
+	if err != nil {
+		return nil, err
+	}
 
-	var imagesJSON datatypes.JSON
-	if len(input.Images) > 0 {
-		b, _ := json.Marshal(input.Images)
-		imagesJSON = datatypes.JSON(b)
+	uploaded, err := s.media.UploadImages(ctx, reviewImagePrefix, input.Files)
+	if err != nil {
+		return nil, err
 	}
 
 	review := &models.Review{
-		UserID:  userID,
-		Title:   strings.TrimSpace(input.Title),
-		Content: strings.TrimSpace(input.Content),
-		Type:    input.Type,
-		Status:  constants.ReviewStatusPending,
-		Images:  imagesJSON,
+		UserID:    userID,
+		TourID:    tourID,
+		BookingID: bookingID,
+		Title:     strings.TrimSpace(input.Title),
+		Content:   strings.TrimSpace(input.Content),
+		Type:      input.Type,
+		Status:    verdict.Status(),
+		Images:    models.MarshalImageAssets(models.MergeImageAssets(nil, input.Images, uploaded)),
 	}
+	verdict.Apply(&review.Moderation)
 
 	if err := s.repo.Create(ctx, review); err != nil {
+		s.media.DeleteImages(ctx, reviewImagePrefix, uploaded)
 		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceCreate, err)
 	}
 	return review, nil
@@ -71,24 +100,26 @@ func (s *ReviewService) GetReview(ctx context.Context, id uint) (*models.Review,
 	return review, nil
 }
 
-func (s *ReviewService) GetPublicReview(ctx context.Context, id uint) (*models.Review, []models.Comment, error) {
+// GetPublicReview returns an approved review with the given page of its
+// comment threads and the number of top-level comments.
+func (s *ReviewService) GetPublicReview(ctx context.Context, id, viewerID uint, commentPage int) (*models.Review, []models.Comment, int64, error) {
 	review, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil, appErrors.ErrReviewNotFound
+			return nil, nil, 0, appErrors.ErrReviewNotFound
 		}
-		return nil, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceGet, err)
+		return nil, nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceGet, err)
 	}
 	if review.Status != constants.ReviewStatusApproved {
-		return nil, nil, appErrors.ErrReviewNotFound
+		return nil, nil, 0, appErrors.ErrReviewNotFound
 	}
 
-	comments, err := s.cmtRepo.FindByReviewID(ctx, id)
+	comments, total, err := s.cmtRepo.FindByReviewID(ctx, id, viewerID, commentPage, constants.CommentPageLimit)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceGet, err)
+		return nil, nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceGet, err)
 	}
 
-	return review, comments, nil
+	return review, comments, total, nil
 }
 
 func (s *ReviewService) ListPublicReviews(ctx context.Context, filter repository.ReviewFilter) ([]models.Review, int64, error) {
@@ -107,38 +138,84 @@ func (s *ReviewService) ListMyReviews(ctx context.Context, userID uint, page, li
 	return reviews, total, nil
 }
 
-func (s *ReviewService) UpdateReview(ctx context.Context, id, userID uint, input ReviewCreateInput) error {
+// UpdateReview edits the author's review and runs it through moderation
+// again; the returned review carries the new status.
+func (s *ReviewService) UpdateReview(ctx context.Context, id, userID uint, input ReviewCreateInput) (*models.Review, error) {
 	if err := s.validateReviewInput(input); err != nil {
-		return err
+		return nil, err
 	}
 
 	review, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return appErrors.ErrReviewNotFound
+			return nil, appErrors.ErrReviewNotFound
 		}
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceUpdate, err)
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceUpdate, err)
 	}
 	if review.UserID != userID {
-		return appErrors.ErrReviewNotOwner
+		return nil, appErrors.ErrReviewNotOwner
+	}
+	var keepID uint
+	if review.BookingID != nil {
+		keepID = *review.BookingID
+	}
+	tourID, bookingID, err := s.resolveTrip(ctx, userID, keepID, input)
+	if err != nil {
+		return nil, err
+	}
+	verdict, err := s.moderateReview(ctx, userID, review.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := s.media.UploadImages(ctx, reviewImagePrefix, input.Files)
+	if err != nil {
+		return nil, err
 	}
 
+	// Drop the preloaded tour so Save does not write its ID back over TourID.
+	review.Tour = nil
+	review.TourID = tourID
+	review.BookingID = bookingID
 	review.Title = strings.TrimSpace(input.Title)
 	review.Content = strings.TrimSpace(input.Content)
 	review.Type = input.Type
-	review.Status = constants.ReviewStatusPending
+	// A review taken down by reports or by an admin goes back to the queue
+	// instead of being republished by the edit.
+	takenDown := review.Status == constants.ReviewStatusHidden ||
+		(review.Status == constants.ReviewStatusRejected && review.ModerationScore < constants.ModerationRejectScore)
+	review.Status = verdict.Status()
+	if takenDown && review.Status == constants.ReviewStatusApproved {
+		review.Status = constants.ReviewStatusPending
+	}
+	oldImages := models.ParseImageAssets(review.Images)
+	newImages := models.MergeImageAssets(oldImages, input.Images, uploaded)
+	review.Images = models.MarshalImageAssets(newImages)
+	verdict.Apply(&review.Moderation)
 
-	if len(input.Images) > 0 {
-		b, _ := json.Marshal(input.Images)
-		review.Images = datatypes.JSON(b)
-	} else {
-		review.Images = nil
+	if err := s.repo.Update(ctx, review); err != nil {
+		s.media.DeleteImages(ctx, reviewImagePrefix, uploaded)
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceUpdate, err)
 	}
+	s.deleteUnusedImages(ctx, models.RemovedImageAssets(oldImages, newImages))
+	return review, nil
+}
 
-	if err := s.repo.Update(ctx, review); err != nil {
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceUpdate, err)
+// deleteUnusedImages removes the files of assets that no review uses any
+// more. A link pasted into another review keeps its file.
+func (s *ReviewService) deleteUnusedImages(ctx context.Context, assets []models.ImageAsset) {
+	var unused []models.ImageAsset
+	for _, a := range assets {
+		count, err := s.repo.CountImageReferences(ctx, a.URL)
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogMediaUsageFailed, "url", a.URL, "error", err)
+			continue
+		}
+		if count == 0 {
+			unused = append(unused, a)
+		}
 	}
-	return nil
+	s.media.DeleteImages(ctx, reviewImagePrefix, unused)
 }
 
 func (s *ReviewService) DeleteReview(ctx context.Context, id, userID uint) error {
@@ -231,45 +308,133 @@ func (s *ReviewService) GetLikedMap(ctx context.Context, userID uint, reviews []
 	return result
 }
 
-func (s *ReviewService) AddComment(ctx context.Context, userID, reviewID uint, parentID *uint, content string) error {
+// AddComment stores a comment with the status chosen by the moderation
+// rules; only approved comments are shown to other users. Only published
+// comments can be replied to; a reply to a comment at the deepest level
+// (constants.CommentMaxDepth) is added next to it instead of under it.
+func (s *ReviewService) AddComment(ctx context.Context, userID, reviewID uint, parentID *uint, content string) (*models.Comment, error) {
 	content = strings.TrimSpace(content)
 	if content == "" {
-		return appErrors.ErrInvalidInput
+		return nil, appErrors.ErrInvalidInput
 	}
 
 	review, err := s.repo.FindByID(ctx, reviewID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return appErrors.ErrReviewNotFound
+			return nil, appErrors.ErrReviewNotFound
 		}
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceAddComment, err)
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceAddComment, err)
 	}
 	if review.Status != constants.ReviewStatusApproved {
-		return appErrors.ErrReviewNotFound
+		return nil, appErrors.ErrReviewNotFound
 	}
 
+	comment := &models.Comment{UserID: userID, ReviewID: reviewID, Content: content}
 	if parentID != nil {
 		parent, err := s.cmtRepo.FindByID(ctx, *parentID)
 		if err != nil {
-			return appErrors.ErrCommentNotFound
+			return nil, appErrors.ErrCommentNotFound
 		}
 		if parent.ReviewID != reviewID {
-			return appErrors.ErrInvalidInput
+			return nil, appErrors.ErrInvalidInput
+		}
+		if parent.IsRemoved() || parent.Status != constants.CommentStatusApproved {
+			return nil, appErrors.ErrCommentNotFound
 		}
+		placeReply(comment, parent)
 	}
 
-	comment := &models.Comment{
-		UserID:   userID,
-		ReviewID: reviewID,
-		ParentID: parentID,
-		Content:  content,
+	verdict, err := s.moderation.Check(ctx, ModerationInput{Kind: constants.ModerationKindComment, UserID: userID, Text: content})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceModerate, err)
 	}
+
+	comment.Status = verdict.Status()
+	verdict.Apply(&comment.Moderation)
 	if err := s.cmtRepo.Create(ctx, comment); err != nil {
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceAddComment, err)
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceAddComment, err)
 	}
-	return nil
+	return comment, nil
+}
+
+// placeReply puts the reply under parent, or next to it when parent is
+// already at the deepest level.
+func placeReply(reply, parent *models.Comment) {
+	rootID := parent.ID
+	if parent.RootID != nil {
+		rootID = *parent.RootID
+	}
+	reply.RootID = &rootID
+	reply.ParentID = &parent.ID
+	reply.Depth = parent.Depth + 1
+	if reply.Depth >= constants.CommentMaxDepth && parent.ParentID != nil {
+		reply.ParentID = parent.ParentID
+		reply.Depth = parent.Depth
+	}
+}
+
+// EditComment changes the content of the user's own comment. The previous
+// content is kept in the public edit history only if it was approved, so
+// text moderation held back never surfaces there. The new content goes
+// through moderation again; a comment taken down by an admin or by reports
+// goes back to the queue instead of being republished by the edit.
+func (s *ReviewService) EditComment(ctx context.Context, commentID, userID uint, content string) (*models.Comment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, appErrors.ErrInvalidInput
+	}
+
+	comment, err := s.cmtRepo.FindByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceEdComment, err)
+	}
+	if comment.IsRemoved() {
+		return nil, appErrors.ErrCommentNotFound
+	}
+	if comment.UserID != userID {
+		return nil, appErrors.ErrCommentNotOwner
+	}
+	if comment.Content == content {
+		return comment, nil
+	}
+
+	verdict, err := s.moderation.Check(ctx, ModerationInput{
+		Kind:      constants.ModerationKindComment,
+		UserID:    userID,
+		ExcludeID: comment.ID,
+		Text:      content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceModerate, err)
+	}
+
+	takenDown := comment.Status == constants.CommentStatusHidden ||
+		(comment.Status == constants.CommentStatusRejected && comment.ModerationScore < constants.ModerationRejectScore)
+	var previous string
+	if comment.Status == constants.CommentStatusApproved {
+		previous = comment.Content
+	}
+	now := time.Now()
+	comment.Content = content
+	comment.EditedAt = &now
+	comment.Status = verdict.Status()
+	if takenDown && comment.Status == constants.CommentStatusApproved {
+		comment.Status = constants.CommentStatusPending
+	}
+	verdict.Apply(&comment.Moderation)
+
+	if err := s.cmtRepo.UpdateContent(ctx, comment, previous); err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceEdComment, err)
+	}
+	return comment, nil
 }
 
+// DeleteComment removes a comment. A comment with replies is kept as a
+// "[deleted]" placeholder so the thread stays intact; deleting the last
+// reply of a placeholder removes the placeholder too.
 func (s *ReviewService) DeleteComment(ctx context.Context, commentID, userID uint, isAdmin bool) error {
 	comment, err := s.cmtRepo.FindByID(ctx, commentID)
 	if err != nil {
@@ -278,14 +443,49 @@ func (s *ReviewService) DeleteComment(ctx context.Context, commentID, userID uin
 		}
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceDelComment, err)
 	}
+	if comment.IsRemoved() {
+		return appErrors.ErrCommentNotFound
+	}
 	if !isAdmin && comment.UserID != userID {
 		return appErrors.ErrCommentNotOwner
 	}
 
-	if err := s.cmtRepo.Delete(ctx, commentID); err != nil {
+	hasReplies, err := s.cmtRepo.HasReplies(ctx, commentID)
+	if err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceDelComment, err)
 	}
-	return nil
+	if hasReplies {
+		if err := s.cmtRepo.SoftDelete(ctx, commentID); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceDelComment, err)
+		}
+		return nil
+	}
+
+	for {
+		if err := s.cmtRepo.Delete(ctx, comment.ID); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceDelComment, err)
+		}
+		if comment.ParentID == nil {
+			return nil
+		}
+		parent, err := s.cmtRepo.FindByID(ctx, *comment.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceDelComment, err)
+		}
+		if !parent.IsRemoved() {
+			return nil
+		}
+		if hasReplies, err = s.cmtRepo.HasReplies(ctx, parent.ID); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceDelComment, err)
+		}
+		if hasReplies {
+			return nil
+		}
+		comment = parent
+	}
 }
 
 func (s *ReviewService) AdminListReviews(ctx context.Context, filter repository.ReviewFilter) ([]models.Review, int64, error) {
@@ -308,13 +508,14 @@ func (s *ReviewService) AdminApproveReview(ctx context.Context, id uint) error {
 		return appErrors.ErrReviewCannotApprove
 	}
 
-	if err := s.repo.UpdateStatus(ctx, id, constants.ReviewStatusApproved); err != nil {
+	if err := s.repo.UpdateStatus(ctx, id, constants.ReviewStatusApproved, ""); err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceApprove, err)
 	}
 	return nil
 }
 
-func (s *ReviewService) AdminRejectReview(ctx context.Context, id uint) error {
+// AdminRejectReview rejects a review; the reason is shown to the author.
+func (s *ReviewService) AdminRejectReview(ctx context.Context, id uint, reason string) error {
 	review, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -326,12 +527,105 @@ func (s *ReviewService) AdminRejectReview(ctx context.Context, id uint) error {
 		return appErrors.ErrReviewCannotReject
 	}
 
-	if err := s.repo.UpdateStatus(ctx, id, constants.ReviewStatusRejected); err != nil {
+	if err := s.repo.UpdateStatus(ctx, id, constants.ReviewStatusRejected, truncateReason(strings.TrimSpace(reason), maxModerationReason)); err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceReject, err)
 	}
 	return nil
 }
 
+func (s *ReviewService) AdminListComments(ctx context.Context, filter repository.CommentFilter) ([]models.Comment, int64, error) {
+	comments, total, err := s.cmtRepo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceComments, err)
+	}
+	return comments, total, nil
+}
+
+func (s *ReviewService) AdminApproveComment(ctx context.Context, id uint) error {
+	comment, err := s.cmtRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.ErrCommentNotFound
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceCmtStatus, err)
+	}
+	if comment.Status == constants.CommentStatusApproved {
+		return appErrors.ErrCommentCannotApprove
+	}
+
+	if err := s.cmtRepo.UpdateStatus(ctx, id, constants.CommentStatusApproved, ""); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceCmtStatus, err)
+	}
+	return nil
+}
+
+// AdminRejectComment hides a comment; the reason is shown to its author.
+func (s *ReviewService) AdminRejectComment(ctx context.Context, id uint, reason string) error {
+	comment, err := s.cmtRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.ErrCommentNotFound
+		}
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceCmtStatus, err)
+	}
+	if comment.Status == constants.CommentStatusRejected {
+		return appErrors.ErrCommentCannotReject
+	}
+
+	if err := s.cmtRepo.UpdateStatus(ctx, id, constants.CommentStatusRejected, truncateReason(strings.TrimSpace(reason), maxModerationReason)); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceCmtStatus, err)
+	}
+	return nil
+}
+
+// ReviewableBookings lists the user's trips a review can be linked to.
+// keepID is the booking the edited review is already linked to, kept even
+// if it was cancelled since; 0 when there is none.
+func (s *ReviewService) ReviewableBookings(ctx context.Context, userID, keepID uint) ([]models.Booking, error) {
+	bookings, err := s.bookingRepo.FindReviewable(ctx, userID, keepID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceTrip, err)
+	}
+	return bookings, nil
+}
+
+// resolveTrip checks that the author took the trip the review is linked
+// to and returns the tour and booking to store. With only a TourID the
+// author's most recent reviewable booking of that tour is used. keepID is
+// the booking an edited review is already linked to.
+func (s *ReviewService) resolveTrip(ctx context.Context, userID, keepID uint, input ReviewCreateInput) (tourID, bookingID *uint, err error) {
+	if input.TourID == 0 && input.BookingID == 0 {
+		return nil, nil, nil
+	}
+	bookings, err := s.ReviewableBookings(ctx, userID, keepID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, b := range bookings {
+		if input.BookingID != 0 && b.ID != input.BookingID {
+			continue
+		}
+		if input.TourID != 0 && b.TourID != input.TourID {
+			continue
+		}
+		return &b.TourID, &b.ID, nil
+	}
+	return nil, nil, appErrors.ErrReviewTourNotBooked
+}
+
+func (s *ReviewService) moderateReview(ctx context.Context, userID, reviewID uint, input ReviewCreateInput) (ModerationVerdict, error) {
+	verdict, err := s.moderation.Check(ctx, ModerationInput{
+		Kind:      constants.ModerationKindReview,
+		UserID:    userID,
+		ExcludeID: reviewID,
+		Text:      strings.TrimSpace(input.Title) + "\n" + strings.TrimSpace(input.Content),
+	})
+	if err != nil {
+		return ModerationVerdict{}, fmt.Errorf("%s: %w", appErrors.ErrCtxReviewServiceModerate, err)
+	}
+	return verdict, nil
+}
+
 func (s *ReviewService) validateReviewInput(input ReviewCreateInput) error {
 	if strings.TrimSpace(input.Title) == "" {
 		return appErrors.NewAppError(400, appErrors.ErrInvalidInput.Message)
@@ -0,0 +1,390 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupReviewServiceDB(t *testing.T) (*ReviewService, *TourService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+	tourSvc := NewTourService(db, repository.NewTourRepository(db), repository.NewCategoryRepository(db), repository.NewSlugHistoryRepository(db), nil)
+	svc := NewReviewService(db, repository.NewReviewRepository(db), repository.NewReviewLikeRepository(db),
+		repository.NewCommentRepository(db), repository.NewBookingRepository(db), NewMediaService(nil, 0, ""),
+		NewModerationService(DefaultModerationRules(repository.NewModerationRepository(db))...))
+	return svc, tourSvc, db
+}
+
+// seedReviewers creates the users a review test acts as.
+func seedReviewers(t *testing.T, db *gorm.DB, names ...string) []models.User {
+	t.Helper()
+	users := make([]models.User, len(names))
+	for i, name := range names {
+		users[i] = seedUser(t, db, name, constants.RoleUser)
+	}
+	return users
+}
+
+func TestCreateReview_LinksTourOnlyForTheAuthorsBooking(t *testing.T) {
+	svc, tourSvc, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh", "chi")
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	other := createActiveTour(t, tourSvc, db, "Sa Pa Trek")
+	booking := seedBookingOn(t, db, u[0].ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+	seedBookingOn(t, db, u[0].ID, other.ID, time.Now().AddDate(0, 1, 0), constants.BookingStatusCancelled)
+	strangers := seedBookingOn(t, db, u[1].ID, other.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+
+	input := storyInput("Cancelled trip")
+	input.TourID = other.ID
+	_, err := svc.CreateReview(ctx, u[0].ID, input)
+	assert.ErrorIs(t, err, appErrors.ErrReviewTourNotBooked)
+
+	input = storyInput("Someone else's booking")
+	input.BookingID = strangers.ID
+	_, err = svc.CreateReview(ctx, u[0].ID, input)
+	assert.ErrorIs(t, err, appErrors.ErrReviewTourNotBooked)
+
+	input = storyInput("Mismatched tour")
+	input.TourID, input.BookingID = other.ID, booking.ID
+	_, err = svc.CreateReview(ctx, u[0].ID, input)
+	assert.ErrorIs(t, err, appErrors.ErrReviewTourNotBooked)
+
+	input = storyInput("Bay cruise")
+	input.TourID = tour.ID
+	review, err := svc.CreateReview(ctx, u[0].ID, input)
+	require.NoError(t, err)
+	require.NotNil(t, review.TourID)
+	require.NotNil(t, review.BookingID)
+	assert.Equal(t, tour.ID, *review.TourID)
+	assert.Equal(t, booking.ID, *review.BookingID)
+
+	review, err = svc.CreateReview(ctx, u[0].ID, storyInput("Street food"))
+	require.NoError(t, err)
+	assert.Nil(t, review.TourID)
+}
+
+func TestUpdateReview_ChangesAndClearsLinkedTour(t *testing.T) {
+	svc, tourSvc, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	author := seedUser(t, db, "an", constants.RoleUser)
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	other := createActiveTour(t, tourSvc, db, "Sa Pa Trek")
+	seedBookingOn(t, db, author.ID, tour.ID, time.Now().AddDate(0, -2, 0), constants.BookingStatusCompleted)
+	otherBooking := seedBookingOn(t, db, author.ID, other.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusConfirmed)
+
+	input := storyInput("Bay cruise")
+	input.TourID = tour.ID
+	review, err := svc.CreateReview(ctx, author.ID, input)
+	require.NoError(t, err)
+
+	input.TourID, input.BookingID = 0, otherBooking.ID
+	_, err = svc.UpdateReview(ctx, review.ID, author.ID, input)
+	require.NoError(t, err)
+	updated, err := svc.GetReview(ctx, review.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.TourID)
+	assert.Equal(t, other.ID, *updated.TourID)
+
+	input.BookingID = 0
+	_, err = svc.UpdateReview(ctx, review.ID, author.ID, input)
+	require.NoError(t, err)
+	updated, err = svc.GetReview(ctx, review.ID)
+	require.NoError(t, err)
+	assert.Nil(t, updated.TourID)
+	assert.Nil(t, updated.BookingID)
+}
+
+func TestCreateReview_UpcomingConfirmedTripIsNotTravelled(t *testing.T) {
+	svc, tourSvc, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	author := seedUser(t, db, "an", constants.RoleUser)
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	upcoming := seedBookingOn(t, db, author.ID, tour.ID, time.Now().AddDate(0, 1, 0), constants.BookingStatusConfirmed)
+
+	input := storyInput("Not there yet")
+	input.BookingID = upcoming.ID
+	_, err := svc.CreateReview(ctx, author.ID, input)
+	assert.ErrorIs(t, err, appErrors.ErrReviewTourNotBooked)
+
+	bookings, err := svc.ReviewableBookings(ctx, author.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, bookings)
+}
+
+func TestUpdateReview_KeepsLinkedTripCancelledSince(t *testing.T) {
+	svc, tourSvc, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	author := seedUser(t, db, "an", constants.RoleUser)
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	trip := seedBookingOn(t, db, author.ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusConfirmed)
+	input := storyInput("Bay cruise")
+	input.BookingID = trip.ID
+	review, err := svc.CreateReview(ctx, author.ID, input)
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&trip).Update("status", constants.BookingStatusCancelled).Error)
+
+	input.Content = "A long enough story about the trip, now edited."
+	_, err = svc.UpdateReview(ctx, review.ID, author.ID, input)
+	require.NoError(t, err)
+
+	updated, err := svc.GetReview(ctx, review.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.BookingID)
+	assert.Equal(t, trip.ID, *updated.BookingID)
+	// A new review cannot pick the cancelled trip.
+	_, err = svc.CreateReview(ctx, author.ID, input)
+	assert.ErrorIs(t, err, appErrors.ErrReviewTourNotBooked)
+}
+
+func TestListPublicReviews_FiltersByTour(t *testing.T) {
+	svc, tourSvc, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	author := seedUser(t, db, "an", constants.RoleUser)
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	seedBookingOn(t, db, author.ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+
+	input := storyInput("Bay cruise")
+	input.TourID = tour.ID
+	linked, err := svc.CreateReview(ctx, author.ID, input)
+	require.NoError(t, err)
+	unlinked, err := svc.CreateReview(ctx, author.ID, storyInput("Street food"))
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.Review{}).Where("id IN ?", []uint{linked.ID, unlinked.ID}).
+		Update("status", constants.ReviewStatusApproved).Error)
+
+	reviews, total, err := svc.ListPublicReviews(ctx, repository.ReviewFilter{TourID: tour.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "Bay cruise", reviews[0].Title)
+	require.NotNil(t, reviews[0].Tour)
+	assert.Equal(t, "Ha Long Bay", reviews[0].Tour.Title)
+
+	_, total, err = svc.ListPublicReviews(ctx, repository.ReviewFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestAddComment_NestsUpToMaxDepth(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh")
+	review, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+
+	root, err := svc.AddComment(ctx, u[1].ID, review.ID, nil, "How long was the cruise?")
+	require.NoError(t, err)
+	reply, err := svc.AddComment(ctx, u[0].ID, review.ID, &root.ID, "Two days and one night.")
+	require.NoError(t, err)
+	nested, err := svc.AddComment(ctx, u[1].ID, review.ID, &reply.ID, "Was one night enough?")
+	require.NoError(t, err)
+	require.Equal(t, constants.CommentMaxDepth-1, nested.Depth)
+	tooDeep, err := svc.AddComment(ctx, u[0].ID, review.ID, &nested.ID, "Yes, plenty.")
+	require.NoError(t, err)
+	assert.Equal(t, nested.Depth, tooDeep.Depth)
+	assert.Equal(t, reply.ID, *tooDeep.ParentID)
+	assert.Equal(t, root.ID, *tooDeep.RootID)
+
+	_, tree, total, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, tree, 1)
+	require.Len(t, tree[0].Children, 1)
+	siblings := tree[0].Children[0].Children
+	require.Len(t, siblings, 2)
+	assert.Equal(t, nested.ID, siblings[0].ID)
+	assert.Equal(t, tooDeep.ID, siblings[1].ID)
+}
+
+func TestGetPublicReview_PaginatesTopLevelComments(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	author := seedUser(t, db, "an", constants.RoleUser)
+	review, err := svc.CreateReview(ctx, author.ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+
+	var last *models.Comment
+	for i := range constants.CommentPageLimit + 1 {
+		reader := seedUser(t, db, fmt.Sprintf("reader-%d", i), constants.RoleUser)
+		last, err = svc.AddComment(ctx, reader.ID, review.ID, nil, "Thanks for the tips!")
+		require.NoError(t, err)
+	}
+	_, err = svc.AddComment(ctx, author.ID, review.ID, &last.ID, "You're welcome.")
+	require.NoError(t, err)
+
+	_, first, total, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(constants.CommentPageLimit+1), total)
+	require.Len(t, first, constants.CommentPageLimit)
+	assert.Equal(t, last.ID, first[0].ID, "newest comment first")
+	assert.Len(t, first[0].Children, 1)
+
+	_, second, _, err := svc.GetPublicReview(ctx, review.ID, 0, 2)
+	require.NoError(t, err)
+	assert.Len(t, second, 1)
+}
+
+func TestEditComment_KeepsHistory(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh", "chi")
+	review, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	comment, err := svc.AddComment(ctx, u[1].ID, review.ID, nil, "Lovely photos!")
+	require.NoError(t, err)
+
+	_, err = svc.EditComment(ctx, comment.ID, u[2].ID, "Hijacked")
+	assert.ErrorIs(t, err, appErrors.ErrCommentNotOwner)
+
+	edited, err := svc.EditComment(ctx, comment.ID, u[1].ID, "Lovely photos, which camera?")
+	require.NoError(t, err)
+	assert.NotNil(t, edited.EditedAt)
+	assert.Equal(t, constants.CommentStatusApproved, edited.Status)
+
+	_, tree, _, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.Equal(t, "Lovely photos, which camera?", tree[0].Content)
+	require.Len(t, tree[0].Revisions, 1)
+	assert.Equal(t, "Lovely photos!", tree[0].Revisions[0].Content)
+
+	// An edit does not republish a comment an admin rejected.
+	require.NoError(t, svc.AdminRejectComment(ctx, comment.ID, "Off topic"))
+	edited, err = svc.EditComment(ctx, comment.ID, u[1].ID, "Lovely photos of the bay.")
+	require.NoError(t, err)
+	assert.Equal(t, constants.CommentStatusPending, edited.Status)
+}
+
+func TestEditComment_HistoryOmitsTextThatWasNeverPublished(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh")
+	review, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	comment, err := svc.AddComment(ctx, u[1].ID, review.ID, nil, "fuck this, đéo bao giờ đi nữa")
+	require.NoError(t, err)
+	require.Equal(t, constants.CommentStatusRejected, comment.Status)
+
+	edited, err := svc.EditComment(ctx, comment.ID, u[1].ID, "Not my favourite trip, sadly.")
+	require.NoError(t, err)
+	require.Equal(t, constants.CommentStatusApproved, edited.Status)
+
+	_, tree, _, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.Empty(t, tree[0].Revisions)
+	var revisions int64
+	require.NoError(t, db.Model(&models.CommentRevision{}).Count(&revisions).Error)
+	assert.Zero(t, revisions)
+}
+
+func TestGetPublicReview_CountSkipsPlaceholdersWithoutVisibleReplies(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh", "chi")
+	review, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	root, err := svc.AddComment(ctx, u[1].ID, review.ID, nil, "How long was the cruise?")
+	require.NoError(t, err)
+	reply, err := svc.AddComment(ctx, u[2].ID, review.ID, &root.ID, "Two days and one night.")
+	require.NoError(t, err)
+	require.NoError(t, db.Model(reply).Update("status", constants.CommentStatusPending).Error)
+	require.NoError(t, svc.DeleteComment(ctx, root.ID, u[1].ID, false))
+
+	_, tree, total, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	assert.Empty(t, tree)
+	assert.Zero(t, total)
+
+	_, tree, total, err = svc.GetPublicReview(ctx, review.ID, u[2].ID, 1)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestDeleteComment_LeavesPlaceholderForReplies(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh", "chi")
+	review, err := svc.CreateReview(ctx, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	root, err := svc.AddComment(ctx, u[1].ID, review.ID, nil, "How long was the cruise?")
+	require.NoError(t, err)
+	reply, err := svc.AddComment(ctx, u[0].ID, review.ID, &root.ID, "Two days and one night.")
+	require.NoError(t, err)
+	_, err = svc.EditComment(ctx, root.ID, u[1].ID, "How long was the cruise, and the price?")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteComment(ctx, root.ID, u[1].ID, false))
+	_, tree, _, err := svc.GetPublicReview(ctx, review.ID, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	assert.True(t, tree[0].IsRemoved())
+	assert.Empty(t, tree[0].Content)
+	assert.Empty(t, tree[0].Revisions)
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, reply.ID, tree[0].Children[0].ID)
+
+	_, err = svc.AddComment(ctx, u[2].ID, review.ID, &root.ID, "Replying to nothing")
+	assert.ErrorIs(t, err, appErrors.ErrCommentNotFound)
+	assert.ErrorIs(t, svc.DeleteComment(ctx, root.ID, u[1].ID, false), appErrors.ErrCommentNotFound)
+
+	// Deleting the last reply takes the placeholder with it.
+	require.NoError(t, svc.DeleteComment(ctx, reply.ID, u[0].ID, false))
+	var count int64
+	require.NoError(t, db.Model(&models.Comment{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUpdateReview_DeletesRemovedImagesUnlessShared(t *testing.T) {
+	svc, _, db := setupReviewServiceDB(t)
+	ctx := context.Background()
+	u := seedReviewers(t, db, "an", "binh")
+	media, dir := setupMediaService(t, 5<<20)
+	svc.media = media
+	stored := func(url string) bool {
+		_, err := os.Stat(filepath.Join(dir, strings.TrimPrefix(url, "/uploads/")))
+		return err == nil
+	}
+
+	input := storyInput("Bay cruise")
+	input.Files = fileHeaders(t, map[string][]byte{"a.png": pngBytes(t, 300, 200)})
+	review, err := svc.CreateReview(ctx, u[0].ID, input)
+	require.NoError(t, err)
+	assets := models.ParseImageAssets(review.Images)
+	require.Len(t, assets, 1)
+	url := assets[0].URL
+
+	// Another review pasted the same link, so removing it here keeps the file.
+	shared := storyInput("Same bay")
+	shared.Images = []string{url}
+	other, err := svc.CreateReview(ctx, u[1].ID, shared)
+	require.NoError(t, err)
+
+	_, err = svc.UpdateReview(ctx, review.ID, u[0].ID, storyInput("Bay cruise"))
+	require.NoError(t, err)
+	assert.True(t, stored(url))
+
+	_, err = svc.UpdateReview(ctx, other.ID, u[1].ID, storyInput("Same bay"))
+	require.NoError(t, err)
+	assert.False(t, stored(url))
+	assert.False(t, stored(assets[0].Thumbnail))
+}
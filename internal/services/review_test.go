@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+type reviewMocks struct {
+	reviewRepo  *testutil.MockReviewRepo
+	commentRepo *testutil.MockCommentRepo
+	bookingRepo *testutil.MockBookingRepo
+}
+
+// setupReviewService moderates with the content rules only, so no rule
+// looks at earlier posts.
+func setupReviewService() (*ReviewService, reviewMocks) {
+	m := reviewMocks{
+		reviewRepo:  new(testutil.MockReviewRepo),
+		commentRepo: new(testutil.MockCommentRepo),
+		bookingRepo: new(testutil.MockBookingRepo),
+	}
+	moderation := NewModerationService(NewBannedWordsRule(defaultBannedWords), NewLinkSpamRule(), NewPhoneSpamRule())
+	svc := NewReviewService(nil, m.reviewRepo, new(testutil.MockReviewLikeRepo), m.commentRepo, m.bookingRepo,
+		NewMediaService(nil, 0, ""), moderation)
+	return svc, m
+}
+
+func storyInput(title string) ReviewCreateInput {
+	return ReviewCreateInput{Title: title, Content: "A long enough story about the trip.", Type: constants.ReviewTypePlace}
+}
+
+func removedAt() *time.Time {
+	now := time.Now()
+	return &now
+}
+
+// --- CreateReview -----------------------------------------------------
+
+func TestCreateReview_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ReviewCreateInput)
+	}{
+		{"blank title", func(in *ReviewCreateInput) { in.Title = "  " }},
+		{"short content", func(in *ReviewCreateInput) { in.Content = "Too short" }},
+		{"unknown type", func(in *ReviewCreateInput) { in.Type = "diary" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReviewService()
+			input := storyInput("Bay cruise")
+			tt.modify(&input)
+
+			_, err := svc.CreateReview(context.Background(), 1, input)
+
+			assertAppError(t, err, appErrors.ErrInvalidInput.Message)
+			m.reviewRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateReview_LinksOnlyTheAuthorsReviewableTrip(t *testing.T) {
+	trips := []models.Booking{{ID: 7, TourID: 3}, {ID: 8, TourID: 4}}
+	tests := []struct {
+		name        string
+		tourID      uint
+		bookingID   uint
+		wantTour    uint
+		wantBooking uint
+		wantErr     error
+	}{
+		{"tour picks its booking", 4, 0, 4, 8, nil},
+		{"booking implies its tour", 0, 7, 3, 7, nil},
+		{"tour not travelled", 5, 0, 0, 0, appErrors.ErrReviewTourNotBooked},
+		{"booking not the author's", 0, 9, 0, 0, appErrors.ErrReviewTourNotBooked},
+		{"booking of another tour", 4, 7, 0, 0, appErrors.ErrReviewTourNotBooked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReviewService()
+			m.bookingRepo.On("FindReviewable", mock.Anything, uint(1), uint(0)).Return(trips, nil)
+			m.reviewRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			input := storyInput("Bay cruise")
+			input.TourID, input.BookingID = tt.tourID, tt.bookingID
+
+			review, err := svc.CreateReview(context.Background(), 1, input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				m.reviewRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, review.TourID)
+			require.NotNil(t, review.BookingID)
+			assert.Equal(t, tt.wantTour, *review.TourID)
+			assert.Equal(t, tt.wantBooking, *review.BookingID)
+		})
+	}
+}
+
+func TestCreateReview_WithoutTripSkipsBookings(t *testing.T) {
+	svc, m := setupReviewService()
+	m.reviewRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	review, err := svc.CreateReview(context.Background(), 1, storyInput("Street food"))
+
+	require.NoError(t, err)
+	assert.Nil(t, review.TourID)
+	assert.Equal(t, constants.ReviewStatusApproved, review.Status)
+	m.bookingRepo.AssertNotCalled(t, "FindReviewable", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// --- UpdateReview -----------------------------------------------------
+
+func TestUpdateReview_NotOwner(t *testing.T) {
+	svc, m := setupReviewService()
+	m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&models.Review{ID: 3, UserID: 2}, nil)
+
+	_, err := svc.UpdateReview(context.Background(), 3, 1, storyInput("Bay cruise"))
+
+	assert.ErrorIs(t, err, appErrors.ErrReviewNotOwner)
+	m.reviewRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateReview_KeepsLinkedTripEvenIfCancelled(t *testing.T) {
+	svc, m := setupReviewService()
+	linked := uint(7)
+	m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&models.Review{ID: 3, UserID: 1, BookingID: &linked}, nil)
+	// The linked booking is passed along so the repository keeps it.
+	m.bookingRepo.On("FindReviewable", mock.Anything, uint(1), linked).Return([]models.Booking{{ID: linked, TourID: 4}}, nil)
+	m.reviewRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	input := storyInput("Bay cruise")
+	input.BookingID = linked
+
+	review, err := svc.UpdateReview(context.Background(), 3, 1, input)
+
+	require.NoError(t, err)
+	require.NotNil(t, review.BookingID)
+	assert.Equal(t, linked, *review.BookingID)
+	m.bookingRepo.AssertExpectations(t)
+}
+
+func TestUpdateReview_TakenDownReviewReturnsToQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored models.Review
+		want   string
+	}{
+		{"hidden by reports", models.Review{Status: constants.ReviewStatusHidden}, constants.ReviewStatusPending},
+		{"rejected by an admin", models.Review{Status: constants.ReviewStatusRejected}, constants.ReviewStatusPending},
+		{"rejected by the rules", models.Review{Status: constants.ReviewStatusRejected, Moderation: models.Moderation{ModerationScore: constants.ModerationRejectScore}}, constants.ReviewStatusApproved},
+		{"published", models.Review{Status: constants.ReviewStatusApproved}, constants.ReviewStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReviewService()
+			stored := tt.stored
+			stored.ID, stored.UserID = 3, 1
+			m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&stored, nil)
+			m.reviewRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+			review, err := svc.UpdateReview(context.Background(), 3, 1, storyInput("Bay cruise"))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, review.Status)
+		})
+	}
+}
+
+// --- comments ---------------------------------------------------------
+
+func TestAddComment_ParentChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		parent  *models.Comment
+		wantErr error
+	}{
+		{"parent on another review", &models.Comment{ID: 4, ReviewID: 9, Status: constants.CommentStatusApproved}, appErrors.ErrInvalidInput},
+		{"parent removed", &models.Comment{ID: 4, ReviewID: 3, Status: constants.CommentStatusApproved, RemovedAt: removedAt()}, appErrors.ErrCommentNotFound},
+		{"parent not published", &models.Comment{ID: 4, ReviewID: 3, Status: constants.CommentStatusPending}, appErrors.ErrCommentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReviewService()
+			m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&models.Review{ID: 3, Status: constants.ReviewStatusApproved}, nil)
+			m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(tt.parent, nil)
+			parentID := uint(4)
+
+			_, err := svc.AddComment(context.Background(), 1, 3, &parentID, "Two days and one night.")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			m.commentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddComment_UnpublishedReview(t *testing.T) {
+	svc, m := setupReviewService()
+	m.reviewRepo.On("FindByID", mock.Anything, uint(3)).Return(&models.Review{ID: 3, Status: constants.ReviewStatusPending}, nil)
+
+	_, err := svc.AddComment(context.Background(), 1, 3, nil, "Lovely photos!")
+
+	assert.ErrorIs(t, err, appErrors.ErrReviewNotFound)
+}
+
+func TestPlaceReply_CapsDepth(t *testing.T) {
+	root := &models.Comment{ID: 1}
+	reply := &models.Comment{}
+	placeReply(reply, root)
+	assert.Equal(t, uint(1), *reply.RootID)
+	assert.Equal(t, uint(1), *reply.ParentID)
+	assert.Equal(t, 1, reply.Depth)
+
+	parentID, rootID := uint(5), uint(1)
+	deepest := &models.Comment{ID: 6, ParentID: &parentID, RootID: &rootID, Depth: constants.CommentMaxDepth - 1}
+	tooDeep := &models.Comment{}
+	placeReply(tooDeep, deepest)
+	assert.Equal(t, parentID, *tooDeep.ParentID, "added next to the deepest comment")
+	assert.Equal(t, rootID, *tooDeep.RootID)
+	assert.Equal(t, deepest.Depth, tooDeep.Depth)
+}
+
+func TestEditComment_NotOwner(t *testing.T) {
+	svc, m := setupReviewService()
+	m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(&models.Comment{ID: 4, UserID: 2, Content: "Lovely photos!"}, nil)
+
+	_, err := svc.EditComment(context.Background(), 4, 3, "Hijacked")
+
+	assert.ErrorIs(t, err, appErrors.ErrCommentNotOwner)
+	m.commentRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEditComment_HistoryAndStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		stored       models.Comment
+		wantPrevious string
+		wantStatus   string
+	}{
+		{"published comment keeps its text", models.Comment{Status: constants.CommentStatusApproved}, "Lovely photos!", constants.CommentStatusApproved},
+		{"held text is not kept", models.Comment{Status: constants.CommentStatusPending}, "", constants.CommentStatusApproved},
+		{"rejected by an admin", models.Comment{Status: constants.CommentStatusRejected}, "", constants.CommentStatusPending},
+		{"hidden by reports", models.Comment{Status: constants.CommentStatusHidden}, "", constants.CommentStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReviewService()
+			stored := tt.stored
+			stored.ID, stored.UserID, stored.Content = 4, 2, "Lovely photos!"
+			m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(&stored, nil)
+			m.commentRepo.On("UpdateContent", mock.Anything, mock.Anything, tt.wantPrevious).Return(nil)
+
+			edited, err := svc.EditComment(context.Background(), 4, 2, "Lovely photos, which camera?")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, edited.Status)
+			assert.NotNil(t, edited.EditedAt)
+			m.commentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEditComment_UnchangedContentIsNoop(t *testing.T) {
+	svc, m := setupReviewService()
+	m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(&models.Comment{ID: 4, UserID: 2, Content: "Lovely photos!"}, nil)
+
+	edited, err := svc.EditComment(context.Background(), 4, 2, " Lovely photos! ")
+
+	require.NoError(t, err)
+	assert.Nil(t, edited.EditedAt)
+	m.commentRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteComment_WithRepliesLeavesPlaceholder(t *testing.T) {
+	svc, m := setupReviewService()
+	m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(&models.Comment{ID: 4, UserID: 2}, nil)
+	m.commentRepo.On("HasReplies", mock.Anything, uint(4)).Return(true, nil)
+	m.commentRepo.On("SoftDelete", mock.Anything, uint(4)).Return(nil)
+
+	require.NoError(t, svc.DeleteComment(context.Background(), 4, 2, false))
+
+	m.commentRepo.AssertExpectations(t)
+	m.commentRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteComment_LastReplyTakesPlaceholder(t *testing.T) {
+	svc, m := setupReviewService()
+	parentID := uint(4)
+	m.commentRepo.On("FindByID", mock.Anything, uint(5)).Return(&models.Comment{ID: 5, UserID: 1, ParentID: &parentID}, nil)
+	m.commentRepo.On("FindByID", mock.Anything, parentID).Return(&models.Comment{ID: parentID, UserID: 2, RemovedAt: removedAt()}, nil)
+	m.commentRepo.On("HasReplies", mock.Anything, uint(5)).Return(false, nil).Once()
+	m.commentRepo.On("HasReplies", mock.Anything, parentID).Return(false, nil).Once()
+	m.commentRepo.On("Delete", mock.Anything, uint(5)).Return(nil)
+	m.commentRepo.On("Delete", mock.Anything, parentID).Return(nil)
+
+	require.NoError(t, svc.DeleteComment(context.Background(), 5, 1, false))
+
+	m.commentRepo.AssertExpectations(t)
+}
+
+func TestDeleteComment_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  *models.Comment
+		findErr error
+		wantErr error
+	}{
+		{"missing", nil, gorm.ErrRecordNotFound, appErrors.ErrCommentNotFound},
+		{"already removed", &models.Comment{ID: 4, UserID: 1, RemovedAt: removedAt()}, nil, appErrors.ErrCommentNotFound},
+		{"someone else's", &models.Comment{ID: 4, UserID: 2}, nil, appErrors.ErrCommentNotOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupReviewService()
+			if tt.stored != nil {
+				m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(tt.stored, nil)
+			} else {
+				m.commentRepo.On("FindByID", mock.Anything, uint(4)).Return(nil, tt.findErr)
+			}
+
+			err := svc.DeleteComment(context.Background(), 4, 1, false)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			m.commentRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sun-booking-tours/internal/utils"
+)
+
+// buildSlug derives the slug for a tour or category. A pinned entity keeps
+// the admin's custom slug (normalized); otherwise the slug follows name.
+// ok is false when a pinned custom slug normalizes to nothing.
+func buildSlug(name, custom string, pinned bool) (slug string, isPinned, ok bool) {
+	custom = strings.TrimSpace(custom)
+	if !pinned || custom == "" {
+		return utils.Slugify(name), false, true
+	}
+	slug = utils.Slugify(custom)
+	return slug, true, slug != ""
+}
+
+// slugSuffixLimit bounds the numbered variants uniqueSlug tries.
+const slugSuffixLimit = 100
+
+var errNoFreeSlug = errors.New("no free slug")
+
+// uniqueSlug returns base, or base-2, base-3, ... for the first variant
+// taken reports as free.
+func uniqueSlug(base string, taken func(string) (bool, error)) (string, error) {
+	for n := 1; n <= slugSuffixLimit; n++ {
+		slug := base
+		if n > 1 {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		used, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return slug, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errNoFreeSlug, base)
+}
@@ -5,45 +5,108 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"strings"
 
 	"sun-booking-tours/internal/constants"
 	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
-	"sun-booking-tours/internal/utils"
 
 	"gorm.io/gorm"
 )
 
+const tourImagePrefix = "tours"
+
 type TourForm struct {
 	Title           string   `form:"title" binding:"required,max=500"`
+	Slug            string   `form:"slug" binding:"max=500"`
+	SlugPinned      bool     `form:"slug_pinned"`
 	Description     string   `form:"description"`
 	Price           float64  `form:"price" binding:"required,gt=0"`
 	DurationDays    int      `form:"duration_days" binding:"required,gt=0"`
 	Location        string   `form:"location" binding:"max=500"`
+	Latitude        string   `form:"latitude"`
+	Longitude       string   `form:"longitude"`
+	MeetingLat      string   `form:"meeting_latitude"`
+	MeetingLng      string   `form:"meeting_longitude"`
 	MaxParticipants int      `form:"max_participants" binding:"required,gt=0"`
+	MinParticipants int      `form:"min_participants" binding:"gte=0"`
 	Status          string   `form:"status" binding:"required"`
 	CategoryIDs     []uint   `form:"category_ids"`
 	ImageURLs       []string `form:"image_urls"`
+
+	// ImageFiles are new uploads, appended after the kept ImageURLs.
+	ImageFiles []*multipart.FileHeader `form:"image_files"`
+
+	// Itinerary days arrive as parallel arrays in day order. Meals hold a
+	// comma-separated subset of breakfast, lunch and dinner; images hold one
+	// URL per line.
+	ItineraryTitles         []string `form:"itinerary_title"`
+	ItineraryDescriptions   []string `form:"itinerary_description"`
+	ItineraryMeals          []string `form:"itinerary_meals"`
+	ItineraryAccommodations []string `form:"itinerary_accommodation"`
+	ItineraryImages         []string `form:"itinerary_images"`
+
+	// Inclusions, meeting points and FAQs also arrive as parallel arrays in
+	// display order; fully blank rows are ignored.
+	InclusionKinds    []string `form:"inclusion_kind"`
+	InclusionTexts    []string `form:"inclusion_text"`
+	MeetingPointNames []string `form:"meeting_point_name"`
+	MeetingPointAddrs []string `form:"meeting_point_address"`
+	MeetingPointTimes []string `form:"meeting_point_time"`
+	MeetingPointNotes []string `form:"meeting_point_note"`
+	FAQQuestions      []string `form:"faq_question"`
+	FAQAnswers        []string `form:"faq_answer"`
+
+	// Translations hold the text in the other supported locales; handlers
+	// fill them from the language tabs.
+	Translations []TranslationForm `form:"-"`
 }
 
 type TourService struct {
-	repo    repository.TourRepo
-	catRepo repository.CategoryRepo
+	db       *gorm.DB
+	repo     repository.TourRepo
+	catRepo  repository.CategoryRepo
+	slugRepo repository.SlugHistoryRepo
+	media    *MediaService
+}
+
+func NewTourService(db *gorm.DB, repo repository.TourRepo, catRepo repository.CategoryRepo, slugRepo repository.SlugHistoryRepo, media *MediaService) *TourService {
+	return &TourService{db: db, repo: repo, catRepo: catRepo, slugRepo: slugRepo, media: media}
 }
 
-func NewTourService(repo repository.TourRepo, catRepo repository.CategoryRepo) *TourService {
-	return &TourService{repo: repo, catRepo: catRepo}
+// withTx returns the service with its repositories bound to tx.
+func (s *TourService) withTx(tx *gorm.DB) *TourService {
+	return &TourService{
+		db:       tx,
+		repo:     repository.NewTourRepository(tx),
+		catRepo:  repository.NewCategoryRepository(tx),
+		slugRepo: repository.NewSlugHistoryRepository(tx),
+		media:    s.media,
+	}
 }
 
-func (s *TourService) ListTours(ctx context.Context, filter repository.TourFilter) ([]models.Tour, int64, error) {
+// ListTours returns one page of tours matching filter, in filter.Locale.
+// Facet counts are only computed when filter.IncludeFacets is set;
+// otherwise they are nil.
+func (s *TourService) ListTours(ctx context.Context, filter repository.TourFilter) ([]models.Tour, int64, *TourFacets, error) {
 	tours, total, err := s.repo.FindAll(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceList, err)
+		return nil, 0, nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceList, err)
+	}
+	models.LocalizeTours(tours, filter.Locale)
+	if !filter.IncludeFacets {
+		return tours, total, nil, nil
+	}
+	facets, err := s.listFacets(ctx, filter)
+	if err != nil {
+		return nil, 0, nil, err
 	}
-	return tours, total, nil
+	return tours, total, facets, nil
 }
 
 func (s *TourService) GetTour(ctx context.Context, id uint) (*models.Tour, error) {
@@ -58,57 +121,126 @@ func (s *TourService) GetTour(ctx context.Context, id uint) (*models.Tour, error
 }
 
 func (s *TourService) CreateTour(ctx context.Context, form *TourForm) error {
+	_, err := s.createTour(ctx, form)
+	return err
+}
+
+// createTour is CreateTour returning the new tour, for callers that attach
+// more rows to it.
+func (s *TourService) createTour(ctx context.Context, form *TourForm) (*models.Tour, error) {
 	title := strings.TrimSpace(form.Title)
 	if title == "" {
-		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourTitleRequired)
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourTitleRequired)
 	}
 
 	if !isValidTourStatus(form.Status) {
-		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourInvalidStatus)
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourInvalidStatus)
 	}
 
-	slug := utils.Slugify(title)
+	if err := validateTourNumbers(form); err != nil {
+		return nil, err
+	}
+
+	itinerary, err := buildItinerary(form)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := buildTourDetails(form)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, itineraryTranslations, err := buildTourTranslations(form.Translations, len(itinerary))
+	if err != nil {
+		return nil, err
+	}
+
+	coords, err := parseTourCoordinates(form)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, pinned, ok := buildSlug(title, form.Slug, form.SlugPinned)
+	if !ok {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourSlugInvalid)
+	}
 
 	exists, err := s.repo.ExistsBySlug(ctx, slug)
 	if err != nil {
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreateCheckSlug, err)
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreateCheckSlug, err)
 	}
 	if exists {
-		return appErrors.NewAppError(http.StatusConflict, appErrors.ErrMsgTourTitleDuplicate)
+		return nil, appErrors.NewAppError(http.StatusConflict, tourSlugDuplicateMsg(pinned))
+	}
+	if err := s.checkTourTranslationSlugs(ctx, 0, translations); err != nil {
+		return nil, err
 	}
 
-	imagesJSON, _ := json.Marshal(filterNonEmpty(form.ImageURLs))
+	uploaded, err := s.media.UploadImages(ctx, tourImagePrefix, form.ImageFiles)
+	if err != nil {
+		return nil, err
+	}
 
 	tour := models.Tour{
 		Title:           title,
 		Slug:            slug,
+		SlugPinned:      pinned,
 		Description:     strings.TrimSpace(form.Description),
 		Price:           form.Price,
 		DurationDays:    form.DurationDays,
 		Location:        strings.TrimSpace(form.Location),
 		MaxParticipants: form.MaxParticipants,
-		Images:          imagesJSON,
+		MinParticipants: form.MinParticipants,
+		Images:          models.MarshalImageAssets(models.MergeImageAssets(nil, form.ImageURLs, uploaded)),
 		Status:          form.Status,
 	}
+	tour.Latitude, tour.Longitude = coords.lat, coords.lng
+	tour.MeetingLatitude, tour.MeetingLongitude = coords.meetingLat, coords.meetingLng
 
-	if err := s.repo.Create(ctx, &tour); err != nil {
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreate, err)
-	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txs := s.withTx(tx)
+		if err := txs.repo.Create(ctx, &tour); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreate, err)
+		}
 
-	if len(form.CategoryIDs) > 0 {
-		if err := s.validateCategoryIDs(ctx, form.CategoryIDs); err != nil {
-			return err
+		if len(form.CategoryIDs) > 0 {
+			if err := txs.validateCategoryIDs(ctx, form.CategoryIDs); err != nil {
+				return err
+			}
+			cats := make([]models.Category, len(form.CategoryIDs))
+			for i, cid := range form.CategoryIDs {
+				cats[i] = models.Category{ID: cid}
+			}
+			if err := txs.repo.ReplaceCategories(ctx, &tour, cats); err != nil {
+				return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreate, err)
+			}
 		}
-		cats := make([]models.Category, len(form.CategoryIDs))
-		for i, cid := range form.CategoryIDs {
-			cats[i] = models.Category{ID: cid}
+
+		if err := txs.repo.ReplaceItinerary(ctx, tour.ID, itinerary); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreate, err)
 		}
-		if err := s.repo.ReplaceCategories(ctx, &tour, cats); err != nil {
+
+		if err := txs.repo.ReplaceDetails(ctx, tour.ID, details); err != nil {
 			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceCreate, err)
 		}
+
+		if err := txs.saveTourTranslations(ctx, tour.ID, nil, translations, itineraryTranslations); err != nil {
+			return err
+		}
+
+		// Claim the slug in case another tour used to have it.
+		if err := txs.slugRepo.RecordChange(ctx, constants.SlugEntityTour, tour.ID, "", slug); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceSlugHistory, err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.media.DeleteImages(ctx, tourImagePrefix, uploaded)
+		return nil, err
 	}
 
-	return nil
+	return &tour, nil
 }
 
 func (s *TourService) UpdateTour(ctx context.Context, id uint, form *TourForm) error {
@@ -129,46 +261,155 @@ func (s *TourService) UpdateTour(ctx context.Context, id uint, form *TourForm) e
 		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourInvalidStatus)
 	}
 
-	slug := utils.Slugify(title)
+	if err := validateTourNumbers(form); err != nil {
+		return err
+	}
+
+	// The itinerary is only replaced when the form carries it; otherwise the
+	// saved days are kept and must still cover the tour.
+	itinerarySubmitted := len(form.ItineraryTitles) > 0
+	itinerary := tour.Itinerary
+	if itinerarySubmitted {
+		if itinerary, err = buildItinerary(form); err != nil {
+			return err
+		}
+	} else if len(itinerary) > 0 && len(itinerary) != form.DurationDays {
+		return appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourItineraryDayCount, form.DurationDays))
+	}
+
+	details, err := buildTourDetails(form)
+	if err != nil {
+		return err
+	}
+
+	translations, itineraryTranslations, err := buildTourTranslations(form.Translations, len(itinerary))
+	if err != nil {
+		return err
+	}
+	if !itinerarySubmitted {
+		itineraryTranslations = keptItineraryTranslations(tour.ItineraryTranslations, translations)
+	}
+
+	coords, err := parseTourCoordinates(form)
+	if err != nil {
+		return err
+	}
+
+	slug, pinned, ok := buildSlug(title, form.Slug, form.SlugPinned)
+	if !ok {
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourSlugInvalid)
+	}
 
 	slugExists, err := s.repo.ExistsBySlugExcluding(ctx, slug, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdateCheckSlug, err)
 	}
 	if slugExists {
-		return appErrors.NewAppError(http.StatusConflict, appErrors.ErrMsgTourTitleDuplicate)
+		return appErrors.NewAppError(http.StatusConflict, tourSlugDuplicateMsg(pinned))
+	}
+	if err := s.checkTourTranslationSlugs(ctx, id, translations); err != nil {
+		return err
 	}
 
-	imagesJSON, _ := json.Marshal(filterNonEmpty(form.ImageURLs))
+	uploaded, err := s.media.UploadImages(ctx, tourImagePrefix, form.ImageFiles)
+	if err != nil {
+		return err
+	}
 
+	oldSlug := tour.Slug
 	tour.Title = title
 	tour.Slug = slug
+	tour.SlugPinned = pinned
 	tour.Description = strings.TrimSpace(form.Description)
 	tour.Price = form.Price
 	tour.DurationDays = form.DurationDays
 	tour.Location = strings.TrimSpace(form.Location)
+	tour.Latitude, tour.Longitude = coords.lat, coords.lng
+	tour.MeetingLatitude, tour.MeetingLongitude = coords.meetingLat, coords.meetingLng
 	tour.MaxParticipants = form.MaxParticipants
-	tour.Images = imagesJSON
+	tour.MinParticipants = form.MinParticipants
+	oldImages := models.ParseImageAssets(tour.Images)
+	newImages := models.MergeImageAssets(oldImages, form.ImageURLs, uploaded)
+	tour.Images = models.MarshalImageAssets(newImages)
 	tour.Status = form.Status
+	// The itinerary, details and translations are replaced separately; keep
+	// Save from upserting the old rows.
+	tour.Itinerary = nil
+	tour.Inclusions, tour.MeetingPoints, tour.FAQs = nil, nil, nil
+	oldTranslations := tour.Translations
+	tour.Translations, tour.ItineraryTranslations = nil, nil
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txs := s.withTx(tx)
+		if err := txs.repo.Update(ctx, tour); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdate, err)
+		}
 
-	if err := s.repo.Update(ctx, tour); err != nil {
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdate, err)
-	}
+		if oldSlug != slug {
+			if err := txs.slugRepo.RecordChange(ctx, constants.SlugEntityTour, tour.ID, oldSlug, slug); err != nil {
+				return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceSlugHistory, err)
+			}
+		}
 
-	if len(form.CategoryIDs) > 0 {
-		if err := s.validateCategoryIDs(ctx, form.CategoryIDs); err != nil {
-			return err
+		if len(form.CategoryIDs) > 0 {
+			if err := txs.validateCategoryIDs(ctx, form.CategoryIDs); err != nil {
+				return err
+			}
 		}
+		cats := make([]models.Category, len(form.CategoryIDs))
+		for i, cid := range form.CategoryIDs {
+			cats[i] = models.Category{ID: cid}
+		}
+		if err := txs.repo.ReplaceCategories(ctx, tour, cats); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdate, err)
+		}
+
+		if itinerarySubmitted {
+			if err := txs.repo.ReplaceItinerary(ctx, tour.ID, itinerary); err != nil {
+				return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdate, err)
+			}
+		}
+
+		if err := txs.repo.ReplaceDetails(ctx, tour.ID, details); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdate, err)
+		}
+
+		return txs.saveTourTranslations(ctx, tour.ID, oldTranslations, translations, itineraryTranslations)
+	})
+	if err != nil {
+		s.media.DeleteImages(ctx, tourImagePrefix, uploaded)
+		return err
 	}
-	cats := make([]models.Category, len(form.CategoryIDs))
-	for i, cid := range form.CategoryIDs {
-		cats[i] = models.Category{ID: cid}
+
+	s.deleteUnusedImages(ctx, models.RemovedImageAssets(oldImages, newImages))
+	return nil
+}
+
+// keptItineraryTranslations returns the saved itinerary translations of the
+// locales that still have a translation, for an update that leaves the
+// itinerary as it is.
+func keptItineraryTranslations(saved []models.TourItineraryTranslation, translations []models.TourTranslation) []models.TourItineraryTranslation {
+	locales := make(map[string]bool, len(translations))
+	for _, tr := range translations {
+		locales[tr.Locale] = true
 	}
-	if err := s.repo.ReplaceCategories(ctx, tour, cats); err != nil {
-		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceUpdate, err)
+	var kept []models.TourItineraryTranslation
+	for _, row := range saved {
+		if locales[row.Locale] {
+			kept = append(kept, row)
+		}
 	}
+	return kept
+}
 
-	return nil
+// SlugHistory lists the slugs the tour was reachable under before, newest
+// first.
+func (s *TourService) SlugHistory(ctx context.Context, id uint) ([]models.SlugHistory, error) {
+	history, err := s.slugRepo.FindByEntity(ctx, constants.SlugEntityTour, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceSlugHistory, err)
+	}
+	return history, nil
 }
 
 func (s *TourService) DeleteTour(ctx context.Context, id uint) error {
@@ -194,6 +435,68 @@ func (s *TourService) DeleteTour(ctx context.Context, id uint) error {
 	return nil
 }
 
+// deleteUnusedImages removes the files of assets that no tour uses any more.
+// Clones and pasted links can share a file, so each URL is checked first.
+func (s *TourService) deleteUnusedImages(ctx context.Context, assets []models.ImageAsset) {
+	var unused []models.ImageAsset
+	for _, a := range assets {
+		count, err := s.repo.CountImageReferences(ctx, a.URL)
+		if err != nil {
+			slog.ErrorContext(ctx, messages.LogMediaUsageFailed, "url", a.URL, "error", err)
+			continue
+		}
+		if count == 0 {
+			unused = append(unused, a)
+		}
+	}
+	s.media.DeleteImages(ctx, tourImagePrefix, unused)
+}
+
+// buildItinerary turns the parallel itinerary form arrays into day rows. An
+// empty itinerary is allowed; otherwise it must cover every day of the tour.
+func buildItinerary(form *TourForm) ([]models.TourItineraryDay, error) {
+	n := len(form.ItineraryTitles)
+	if n == 0 {
+		return nil, nil
+	}
+	if len(form.ItineraryDescriptions) != n || len(form.ItineraryMeals) != n ||
+		len(form.ItineraryAccommodations) != n || len(form.ItineraryImages) != n {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourItineraryInvalid)
+	}
+	if n != form.DurationDays {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourItineraryDayCount, form.DurationDays))
+	}
+
+	days := make([]models.TourItineraryDay, n)
+	for i := range days {
+		title := strings.TrimSpace(form.ItineraryTitles[i])
+		if title == "" {
+			return nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourItineraryDayTitle, i+1))
+		}
+
+		imagesJSON, _ := json.Marshal(filterNonEmpty(strings.Split(form.ItineraryImages[i], "\n")))
+
+		day := models.TourItineraryDay{
+			Title:         title,
+			Description:   strings.TrimSpace(form.ItineraryDescriptions[i]),
+			Accommodation: strings.TrimSpace(form.ItineraryAccommodations[i]),
+			Images:        imagesJSON,
+		}
+		for _, meal := range strings.Split(form.ItineraryMeals[i], ",") {
+			switch strings.TrimSpace(meal) {
+			case constants.MealBreakfast:
+				day.Breakfast = true
+			case constants.MealLunch:
+				day.Lunch = true
+			case constants.MealDinner:
+				day.Dinner = true
+			}
+		}
+		days[i] = day
+	}
+	return days, nil
+}
+
 func (s *TourService) validateCategoryIDs(ctx context.Context, ids []uint) error {
 	count, err := s.catRepo.CountByIDs(ctx, ids)
 	if err != nil {
@@ -205,6 +508,22 @@ func (s *TourService) validateCategoryIDs(ctx context.Context, ids []uint) error
 	return nil
 }
 
+// validateTourNumbers repeats the form's binding rules so callers that do
+// not bind a request, such as the bulk import, get the same checks.
+func validateTourNumbers(form *TourForm) error {
+	switch {
+	case form.Price <= 0:
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourPricePositive)
+	case form.DurationDays <= 0:
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourDurationPositive)
+	case form.MaxParticipants <= 0:
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMaxParticipants)
+	case form.MinParticipants < 0 || form.MinParticipants > form.MaxParticipants:
+		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMinParticipants)
+	}
+	return nil
+}
+
 func isValidTourStatus(status string) bool {
 	return status == constants.TourStatusDraft ||
 		status == constants.TourStatusActive ||
@@ -239,6 +558,13 @@ func (s *TourService) GetPublicTourByID(ctx context.Context, id uint) (*models.T
 	return tour, nil
 }
 
+func tourSlugDuplicateMsg(pinned bool) string {
+	if pinned {
+		return appErrors.ErrMsgTourSlugDuplicate
+	}
+	return appErrors.ErrMsgTourTitleDuplicate
+}
+
 func filterNonEmpty(ss []string) []string {
 	result := make([]string, 0, len(ss))
 	for _, s := range ss {
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	appErrors "sun-booking-tours/internal/errors"
+)
+
+// Bulk category actions on the admin tours list.
+const (
+	BulkCategoryAdd    = "add"
+	BulkCategoryRemove = "remove"
+)
+
+// BulkCategoryForm adds the categories to, or removes them from, every
+// selected tour.
+type BulkCategoryForm struct {
+	Action      string `form:"action"`
+	TourIDs     []uint `form:"tour_ids"`
+	CategoryIDs []uint `form:"category_ids"`
+}
+
+// BulkUpdateCategories applies form and returns how many tour-category
+// links were added or removed. Links that already match are left alone.
+func (s *TourService) BulkUpdateCategories(ctx context.Context, form *BulkCategoryForm) (int64, error) {
+	switch {
+	case form.Action != BulkCategoryAdd && form.Action != BulkCategoryRemove:
+		return 0, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourBulkAction)
+	case len(form.TourIDs) == 0:
+		return 0, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourBulkNoTours)
+	case len(form.CategoryIDs) == 0:
+		return 0, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourBulkNoCategories)
+	}
+	form.CategoryIDs = slices.Compact(slices.Sorted(slices.Values(form.CategoryIDs)))
+	if err := s.validateCategoryIDs(ctx, form.CategoryIDs); err != nil {
+		return 0, err
+	}
+
+	var changed int64
+	var err error
+	if form.Action == BulkCategoryAdd {
+		changed, err = s.repo.AddCategories(ctx, form.TourIDs, form.CategoryIDs)
+	} else {
+		changed, err = s.repo.RemoveCategories(ctx, form.TourIDs, form.CategoryIDs)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceBulkCategories, err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// cloneMaxOffsetDays bounds how far copied schedules can be moved.
+const cloneMaxOffsetDays = 3650
+
+// CloneForm holds the options of the duplicate action. Future schedules
+// are copied only when IncludeSchedules is set, moved by OffsetDays.
+type CloneForm struct {
+	IncludeSchedules bool `form:"include_schedules"`
+	OffsetDays       int  `form:"offset_days"`
+}
+
+// CloneTour copies the tour with its categories, images, itinerary, details
+// and translations into a new draft with unique slugs. Copied schedules get
+// back the seats bookings took from the source and start open.
+func (s *TourService) CloneTour(ctx context.Context, id uint, form *CloneForm) (*models.Tour, error) {
+	if form.OffsetDays < -cloneMaxOffsetDays || form.OffsetDays > cloneMaxOffsetDays {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourCloneOffset, cloneMaxOffsetDays, cloneMaxOffsetDays))
+	}
+
+	src, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.ErrTourNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceClone, err)
+	}
+
+	taken := func(slug string) (bool, error) { return s.repo.ExistsBySlug(ctx, slug) }
+	title := fmt.Sprintf(messages.TourCloneTitle, src.Title)
+	slug, err := uniqueSlug(utils.Slugify(title), taken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceClone, err)
+	}
+
+	clone := &models.Tour{
+		Title:            title,
+		Slug:             slug,
+		Description:      src.Description,
+		Price:            src.Price,
+		DurationDays:     src.DurationDays,
+		Location:         src.Location,
+		Latitude:         src.Latitude,
+		Longitude:        src.Longitude,
+		MeetingLatitude:  src.MeetingLatitude,
+		MeetingLongitude: src.MeetingLongitude,
+		MaxParticipants:  src.MaxParticipants,
+		MinParticipants:  src.MinParticipants,
+		Images:           slices.Clone(src.Images),
+		Status:           constants.TourStatusDraft,
+	}
+	for _, cat := range src.Categories {
+		clone.Categories = append(clone.Categories, models.Category{ID: cat.ID})
+	}
+	for _, day := range src.Itinerary {
+		day.ID, day.TourID, day.CreatedAt, day.UpdatedAt = 0, 0, time.Time{}, time.Time{}
+		clone.Itinerary = append(clone.Itinerary, day)
+	}
+	for _, inc := range src.Inclusions {
+		inc.ID, inc.TourID = 0, 0
+		clone.Inclusions = append(clone.Inclusions, inc)
+	}
+	for _, mp := range src.MeetingPoints {
+		mp.ID, mp.TourID = 0, 0
+		clone.MeetingPoints = append(clone.MeetingPoints, mp)
+	}
+	for _, faq := range src.FAQs {
+		faq.ID, faq.TourID = 0, 0
+		clone.FAQs = append(clone.FAQs, faq)
+	}
+	used := map[string]bool{slug: true}
+	for _, tr := range src.Translations {
+		trSlug, err := uniqueSlug(tr.Slug, func(s string) (bool, error) {
+			if used[s] {
+				return true, nil
+			}
+			return taken(s)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceClone, err)
+		}
+		used[trSlug] = true
+		clone.Translations = append(clone.Translations, models.TourTranslation{
+			Locale:      tr.Locale,
+			Title:       tr.Title,
+			Slug:        trSlug,
+			Description: tr.Description,
+		})
+	}
+	for _, tr := range src.ItineraryTranslations {
+		tr.ID, tr.TourID, tr.CreatedAt, tr.UpdatedAt = 0, 0, time.Time{}, time.Time{}
+		clone.ItineraryTranslations = append(clone.ItineraryTranslations, tr)
+	}
+
+	if form.IncludeSchedules {
+		schedules, err := s.cloneSchedules(ctx, src, form.OffsetDays)
+		if err != nil {
+			return nil, err
+		}
+		clone.Schedules = schedules
+	}
+
+	if err := s.repo.CreateClone(ctx, clone); err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceClone, err)
+	}
+
+	// Claim the new slugs in case other tours used to have them.
+	for claimed := range used {
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityTour, clone.ID, "", claimed); err != nil {
+			return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceSlugHistory, err)
+		}
+	}
+	return clone, nil
+}
+
+// cloneSchedules copies the source's schedules that are not cancelled and,
+// once moved by offsetDays, still depart today or later.
+func (s *TourService) cloneSchedules(ctx context.Context, src *models.Tour, offsetDays int) ([]models.TourSchedule, error) {
+	booked, err := s.repo.CountBookedBySchedule(ctx, src.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceClone, err)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var schedules []models.TourSchedule
+	for _, sc := range src.Schedules {
+		departure := sc.DepartureDate.AddDate(0, 0, offsetDays)
+		if sc.Status == constants.ScheduleStatusCancelled || departure.Before(today) {
+			continue
+		}
+		schedules = append(schedules, models.TourSchedule{
+			DepartureDate:  departure,
+			ReturnDate:     sc.ReturnDate.AddDate(0, 0, offsetDays),
+			AvailableSlots: sc.AvailableSlots + booked[sc.ID],
+			PriceOverride:  sc.PriceOverride,
+			Status:         constants.ScheduleStatusOpen,
+		})
+	}
+	return schedules, nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+)
+
+var pickupTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// buildTourDetails turns the parallel inclusion, meeting point and FAQ form
+// arrays into rows. Fully blank rows are skipped.
+func buildTourDetails(form *TourForm) (repository.TourDetails, error) {
+	var details repository.TourDetails
+
+	if len(form.InclusionKinds) != len(form.InclusionTexts) {
+		return details, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourInclusionsInvalid)
+	}
+	for i, kind := range form.InclusionKinds {
+		text := strings.TrimSpace(form.InclusionTexts[i])
+		if text == "" {
+			continue
+		}
+		if !isValidInclusionKind(kind) {
+			return details, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourInclusionsInvalid)
+		}
+		details.Inclusions = append(details.Inclusions, models.TourInclusion{Kind: kind, Text: text})
+	}
+
+	n := len(form.MeetingPointNames)
+	if len(form.MeetingPointAddrs) != n || len(form.MeetingPointTimes) != n || len(form.MeetingPointNotes) != n {
+		return details, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMeetingInvalid)
+	}
+	for i := range n {
+		point := models.TourMeetingPoint{
+			Name:       strings.TrimSpace(form.MeetingPointNames[i]),
+			Address:    strings.TrimSpace(form.MeetingPointAddrs[i]),
+			PickupTime: strings.TrimSpace(form.MeetingPointTimes[i]),
+			Note:       strings.TrimSpace(form.MeetingPointNotes[i]),
+		}
+		if point.Name == "" && point.Address == "" && point.PickupTime == "" && point.Note == "" {
+			continue
+		}
+		row := len(details.MeetingPoints) + 1
+		if point.Name == "" {
+			return details, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourMeetingName, row))
+		}
+		if point.PickupTime != "" && !pickupTimePattern.MatchString(point.PickupTime) {
+			return details, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourMeetingTime, row))
+		}
+		details.MeetingPoints = append(details.MeetingPoints, point)
+	}
+
+	if len(form.FAQQuestions) != len(form.FAQAnswers) {
+		return details, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourFAQInvalid)
+	}
+	for i := range form.FAQQuestions {
+		question := strings.TrimSpace(form.FAQQuestions[i])
+		answer := strings.TrimSpace(form.FAQAnswers[i])
+		if question == "" && answer == "" {
+			continue
+		}
+		if question == "" || answer == "" {
+			return details, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourFAQIncomplete, len(details.FAQs)+1))
+		}
+		details.FAQs = append(details.FAQs, models.TourFAQ{Question: question, Answer: answer})
+	}
+
+	return details, nil
+}
+
+func isValidInclusionKind(kind string) bool {
+	switch kind {
+	case constants.InclusionKindIncluded, constants.InclusionKindExcluded, constants.InclusionKindBring:
+		return true
+	}
+	return false
+}
+
+// PickupPoint resolves the meeting point chosen in the booking form to the
+// label stored on the booking. A tour without meeting points needs none;
+// otherwise one of its points must be chosen. The tour must have its
+// MeetingPoints preloaded.
+func PickupPoint(tour *models.Tour, pointID uint) (string, error) {
+	if len(tour.MeetingPoints) == 0 {
+		return "", nil
+	}
+	point, ok := tour.FindMeetingPoint(pointID)
+	if !ok {
+		return "", appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgBookingPickupRequired)
+	}
+	return point.Label(), nil
+}
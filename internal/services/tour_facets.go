@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+)
+
+// FacetOption is one filter chip on the tours listing. Params holds the
+// query parameters the chip sets; an empty value removes the parameter.
+// A Toggle chip adds its values to a repeated parameter, or takes them out
+// when active, instead of replacing it.
+type FacetOption struct {
+	Label  string
+	Count  int64
+	Active bool
+	Toggle bool
+	Params map[string]string
+	// URL is filled in by the handler from the current query and Params.
+	URL string
+}
+
+// TourFacets groups the filter chips of the tours listing by dimension.
+// Subcategories lists the children of the one selected category, and
+// CategoryMatch switches between any/all once several are selected.
+type TourFacets struct {
+	Categories    []FacetOption
+	Subcategories []FacetOption
+	CategoryMatch []FacetOption
+	Prices        []FacetOption
+	Durations     []FacetOption
+	Locations     []FacetOption
+	Ratings       []FacetOption
+}
+
+// All returns every option in display order, so handlers can fill in URLs
+// without caring about dimensions.
+func (f *TourFacets) All() []*FacetOption {
+	if f == nil {
+		return nil
+	}
+	var all []*FacetOption
+	for _, group := range [][]FacetOption{f.Categories, f.Subcategories, f.CategoryMatch, f.Prices, f.Durations, f.Locations, f.Ratings} {
+		for i := range group {
+			all = append(all, &group[i])
+		}
+	}
+	return all
+}
+
+func (s *TourService) listFacets(ctx context.Context, filter repository.TourFilter) (*TourFacets, error) {
+	counts, err := s.repo.CountFacets(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceFacets, err)
+	}
+	cats, err := s.catRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceFacets, err)
+	}
+
+	models.LocalizeCategories(cats, filter.Locale)
+
+	facets := &TourFacets{}
+
+	var selected *models.Category
+	for i, cat := range cats {
+		active := slices.Contains(filter.CategorySlugs, cat.Slug) || filter.CategoryID == cat.ID
+		if active && len(filter.CategorySlugs) == 1 {
+			selected = &cats[i]
+		}
+		count := counts.Categories[cat.ID]
+		if count == 0 && !active {
+			continue
+		}
+		facets.Categories = append(facets.Categories, FacetOption{
+			Label:  cat.Name,
+			Count:  count,
+			Active: active,
+			Toggle: true,
+			Params: map[string]string{"category": cat.Slug},
+		})
+	}
+
+	if selected != nil {
+		for _, cat := range cats {
+			if cat.ParentID == nil || *cat.ParentID != selected.ID {
+				continue
+			}
+			facets.Subcategories = append(facets.Subcategories, FacetOption{
+				Label:  cat.Name,
+				Count:  counts.Categories[cat.ID],
+				Params: map[string]string{"category": cat.Slug},
+			})
+		}
+	}
+
+	if len(filter.CategorySlugs) > 1 {
+		matchAll := filter.CategoryMatch == repository.CategoryMatchAll
+		facets.CategoryMatch = []FacetOption{
+			{Label: messages.FacetCategoryMatchAny, Active: !matchAll, Params: map[string]string{"match": ""}},
+			{Label: messages.FacetCategoryMatchAll, Active: matchAll, Params: map[string]string{"match": repository.CategoryMatchAll}},
+		}
+	}
+
+	for i, rg := range repository.TourPriceRanges {
+		facets.Prices = append(facets.Prices, FacetOption{
+			Label:  priceRangeLabel(rg),
+			Count:  counts.Prices[i],
+			Active: filter.MinPrice == rg.Min && filter.MaxPrice == rg.Max,
+			Params: map[string]string{
+				"min_price": facetParam(rg.Min),
+				"max_price": facetParam(rg.Max),
+			},
+		})
+	}
+
+	for i, rg := range repository.TourDurationRanges {
+		facets.Durations = append(facets.Durations, FacetOption{
+			Label:  durationRangeLabel(rg),
+			Count:  counts.Durations[i],
+			Active: float64(filter.MinDuration) == rg.Min && float64(filter.MaxDuration) == rg.Max,
+			Params: map[string]string{
+				"min_duration": facetParam(rg.Min),
+				"max_duration": facetParam(rg.Max),
+			},
+		})
+	}
+
+	for _, loc := range counts.Locations {
+		facets.Locations = append(facets.Locations, FacetOption{
+			Label:  loc.Location,
+			Count:  loc.Count,
+			Active: strings.EqualFold(filter.Location, loc.Location),
+			Params: map[string]string{"location": loc.Location},
+		})
+	}
+
+	for i, band := range repository.TourRatingBands {
+		facets.Ratings = append(facets.Ratings, FacetOption{
+			Label:  fmt.Sprintf(messages.FacetRatingFrom, band),
+			Count:  counts.Ratings[i],
+			Active: filter.MinRating == band,
+			Params: map[string]string{"min_rating": facetParam(band)},
+		})
+	}
+
+	return facets, nil
+}
+
+// facetParam formats a bound as a query value; 0 (unbounded) clears it.
+func facetParam(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func priceRangeLabel(rg repository.FacetRange) string {
+	millions := func(v float64) string {
+		return fmt.Sprintf(messages.FacetPriceMillions, v/1_000_000)
+	}
+	switch {
+	case rg.Min == 0:
+		return fmt.Sprintf(messages.FacetPriceBelow, millions(rg.Max))
+	case rg.Max == 0:
+		return fmt.Sprintf(messages.FacetPriceFrom, millions(rg.Min))
+	default:
+		return fmt.Sprintf(messages.FacetPriceBetween, millions(rg.Min), millions(rg.Max))
+	}
+}
+
+func durationRangeLabel(rg repository.FacetRange) string {
+	switch {
+	case rg.Min == rg.Max:
+		return fmt.Sprintf(messages.FacetDurationExact, int(rg.Min))
+	case rg.Max == 0:
+		return fmt.Sprintf(messages.FacetDurationFrom, int(rg.Min))
+	default:
+		return fmt.Sprintf(messages.FacetDurationBetween, int(rg.Min), int(rg.Max))
+	}
+}
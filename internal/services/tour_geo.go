@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/utils"
+)
+
+// maxTourPins caps the number of features a single GeoJSON response holds.
+const maxTourPins = 500
+
+// GeoJSONFeatureCollection is an RFC 7946 feature collection of points.
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a point feature. Coordinates are [longitude, latitude].
+type GeoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   GeoJSONPoint      `json:"geometry"`
+	Properties TourPinProperties `json:"properties"`
+}
+
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// TourPinProperties is what the map popup needs to render a tour.
+type TourPinProperties struct {
+	ID           uint        `json:"id"`
+	Title        string      `json:"title"`
+	URL          string      `json:"url"`
+	Price        float64     `json:"price"`
+	Location     string      `json:"location"`
+	AvgRating    float64     `json:"avg_rating"`
+	Thumbnail    string      `json:"thumbnail,omitempty"`
+	MeetingPoint *[2]float64 `json:"meeting_point,omitempty"`
+}
+
+// TourPins returns located tours matching filter as GeoJSON, for map views.
+// Paging and sorting fields of filter are ignored.
+func (s *TourService) TourPins(ctx context.Context, filter repository.TourFilter) (*GeoJSONFeatureCollection, error) {
+	tours, err := s.repo.FindPins(ctx, filter, maxTourPins)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServicePins, err)
+	}
+
+	fc := &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]GeoJSONFeature, 0, len(tours))}
+	for _, t := range tours {
+		props := TourPinProperties{
+			ID:        t.ID,
+			Title:     t.Title,
+			URL:       "/tours/" + t.Slug,
+			Price:     t.Price,
+			Location:  t.Location,
+			AvgRating: t.AvgRating,
+		}
+		if assets := models.ParseImageAssets(t.Images); len(assets) > 0 {
+			props.Thumbnail = assets[0].ThumbnailURL()
+		}
+		if t.HasMeetingPoint() {
+			props.MeetingPoint = &[2]float64{*t.MeetingLongitude, *t.MeetingLatitude}
+		}
+		fc.Features = append(fc.Features, GeoJSONFeature{
+			Type:       "Feature",
+			Geometry:   GeoJSONPoint{Type: "Point", Coordinates: [2]float64{*t.Longitude, *t.Latitude}},
+			Properties: props,
+		})
+	}
+	return fc, nil
+}
+
+// ParseBBox parses a GeoJSON-order "minLng,minLat,maxLng,maxLat" string.
+func ParseBBox(raw string) (*repository.GeoBBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMapBBox)
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMapBBox)
+		}
+		v[i] = f
+	}
+	bbox := &repository.GeoBBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	if !bbox.Valid() {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMapBBox)
+	}
+	return bbox, nil
+}
+
+type tourCoordinates struct {
+	lat, lng               *float64
+	meetingLat, meetingLng *float64
+}
+
+// parseTourCoordinates reads the optional coordinate pairs of the tour form.
+// Each pair must be given in full or left empty.
+func parseTourCoordinates(form *TourForm) (tourCoordinates, error) {
+	var c tourCoordinates
+	var ok bool
+	if c.lat, c.lng, ok = parseLatLng(form.Latitude, form.Longitude); !ok {
+		return c, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourCoordinates)
+	}
+	if c.meetingLat, c.meetingLng, ok = parseLatLng(form.MeetingLat, form.MeetingLng); !ok {
+		return c, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourMeetingCoordinates)
+	}
+	return c, nil
+}
+
+func parseLatLng(rawLat, rawLng string) (lat, lng *float64, ok bool) {
+	rawLat, rawLng = strings.TrimSpace(rawLat), strings.TrimSpace(rawLng)
+	if rawLat == "" && rawLng == "" {
+		return nil, nil, true
+	}
+	la, errLat := strconv.ParseFloat(rawLat, 64)
+	lo, errLng := strconv.ParseFloat(rawLng, 64)
+	if errLat != nil || errLng != nil || !utils.ValidLatLng(la, lo) {
+		return nil, nil, false
+	}
+	return &la, &lo, true
+}
@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// Bulk import and export file formats.
+const (
+	TourFileCSV  = "csv"
+	TourFileJSON = "json"
+)
+
+// tourImportMaxRows caps one import so a single request stays short.
+const tourImportMaxRows = 500
+
+// TourRecord is one tour in an import or export file. Categories are
+// category slugs and Images are image URLs. The itinerary, inclusions,
+// meeting points, FAQs and translations have no CSV columns, so only JSON
+// files carry them; a CSV export leaves them out.
+type TourRecord struct {
+	Title           string           `json:"title"`
+	Slug            string           `json:"slug,omitempty"`
+	SlugPinned      bool             `json:"slug_pinned,omitempty"`
+	Description     string           `json:"description,omitempty"`
+	Price           float64          `json:"price"`
+	DurationDays    int              `json:"duration_days"`
+	Location        string           `json:"location,omitempty"`
+	Latitude        *float64         `json:"latitude,omitempty"`
+	Longitude       *float64         `json:"longitude,omitempty"`
+	MeetingLat      *float64         `json:"meeting_latitude,omitempty"`
+	MeetingLng      *float64         `json:"meeting_longitude,omitempty"`
+	MaxParticipants int              `json:"max_participants"`
+	MinParticipants int              `json:"min_participants,omitempty"`
+	Status          string           `json:"status"`
+	Categories      []string         `json:"categories,omitempty"`
+	Images          []string         `json:"images,omitempty"`
+	Schedules       []ScheduleRecord `json:"schedules,omitempty"`
+
+	Itinerary     []ItineraryDayRecord `json:"itinerary,omitempty"`
+	Inclusions    []InclusionRecord    `json:"inclusions,omitempty"`
+	MeetingPoints []MeetingPointRecord `json:"meeting_points,omitempty"`
+	FAQs          []FAQRecord          `json:"faqs,omitempty"`
+	Translations  []TranslationRecord  `json:"translations,omitempty"`
+}
+
+// ScheduleRecord is one departure of a TourRecord. Dates are YYYY-MM-DD.
+// AvailableSlots is the capacity: an export adds back the seats bookings
+// hold, since the imported copy starts without them.
+type ScheduleRecord struct {
+	DepartureDate  string   `json:"departure_date"`
+	ReturnDate     string   `json:"return_date"`
+	AvailableSlots int      `json:"available_slots"`
+	PriceOverride  *float64 `json:"price_override,omitempty"`
+	Status         string   `json:"status"`
+}
+
+// ItineraryDayRecord is one itinerary day, in day order. Meals is a subset
+// of breakfast, lunch and dinner.
+type ItineraryDayRecord struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Meals         []string `json:"meals,omitempty"`
+	Accommodation string   `json:"accommodation,omitempty"`
+	Images        []string `json:"images,omitempty"`
+}
+
+// InclusionRecord is one included, excluded or bring item.
+type InclusionRecord struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// MeetingPointRecord is one pickup point; PickupTime is HH:MM.
+type MeetingPointRecord struct {
+	Name       string `json:"name"`
+	Address    string `json:"address,omitempty"`
+	PickupTime string `json:"pickup_time,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+// FAQRecord is one question with its answer.
+type FAQRecord struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// TranslationRecord is the tour's text in a locale other than the default.
+// Itinerary runs parallel to the default-locale days.
+type TranslationRecord struct {
+	Locale      string                `json:"locale"`
+	Title       string                `json:"title"`
+	Slug        string                `json:"slug,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Itinerary   []ItineraryTextRecord `json:"itinerary,omitempty"`
+}
+
+// ItineraryTextRecord is the translated text of one itinerary day.
+type ItineraryTextRecord struct {
+	Title         string `json:"title,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Accommodation string `json:"accommodation,omitempty"`
+}
+
+// TourImportRow is the outcome of one record. Line is the CSV line number
+// or the position in the JSON array.
+type TourImportRow struct {
+	Line      int
+	Title     string
+	Schedules int
+	Error     string
+}
+
+// TourImportReport lists every record of an import. Nothing is written
+// unless the import was not a dry run and no row failed.
+type TourImportReport struct {
+	DryRun   bool
+	Rows     []TourImportRow
+	Imported int
+}
+
+// Failed counts the rows that did not pass validation.
+func (r *TourImportReport) Failed() int {
+	n := 0
+	for _, row := range r.Rows {
+		if row.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// errImportRollback undoes an import that is a dry run or has failed rows.
+var errImportRollback = errors.New("roll back tour import")
+
+type TourImportService struct {
+	db    *gorm.DB
+	repo  repository.TourRepo
+	media *MediaService
+}
+
+func NewTourImportService(db *gorm.DB, repo repository.TourRepo, media *MediaService) *TourImportService {
+	return &TourImportService{db: db, repo: repo, media: media}
+}
+
+// Import reads tours from r in the given format and creates them with the
+// same rules as the admin forms, all in one transaction. Each record is
+// tried in its own savepoint so the report shows every failing row; any
+// failure, or dryRun, rolls the whole import back.
+func (s *TourImportService) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*TourImportReport, error) {
+	records, err := decodeTourRecords(format, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourImportEmpty)
+	}
+	if len(records) > tourImportMaxRows {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourImportTooMany, tourImportMaxRows))
+	}
+
+	report := &TourImportReport{DryRun: dryRun, Rows: make([]TourImportRow, len(records))}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, rec := range records {
+			row := &report.Rows[i]
+			row.Line, row.Title, row.Error = rec.line, rec.Title, rec.err
+			if row.Error != "" {
+				continue
+			}
+			err := tx.Transaction(func(rowTx *gorm.DB) error {
+				return s.importTourRecord(ctx, rowTx, &rec.TourRecord)
+			})
+			var appErr *appErrors.AppError
+			switch {
+			case errors.As(err, &appErr):
+				row.Error = appErr.Message
+			case err != nil:
+				return err
+			default:
+				row.Schedules = len(rec.Schedules)
+			}
+		}
+		if dryRun || report.Failed() > 0 {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceImport, err)
+	}
+	if err == nil {
+		report.Imported = len(records)
+	}
+	return report, nil
+}
+
+// importTourRecord creates one tour and its schedules through the tour and
+// schedule services bound to tx.
+func (s *TourImportService) importTourRecord(ctx context.Context, tx *gorm.DB, rec *TourRecord) error {
+	tourRepo := repository.NewTourRepository(tx)
+	catRepo := repository.NewCategoryRepository(tx)
+	tours := NewTourService(tx, tourRepo, catRepo, repository.NewSlugHistoryRepository(tx), s.media)
+	schedules := NewScheduleService(repository.NewScheduleRepository(tx), tourRepo, repository.NewScheduleGuideRepository(tx))
+
+	form := &TourForm{
+		Title:           rec.Title,
+		Slug:            rec.Slug,
+		SlugPinned:      rec.SlugPinned,
+		Description:     rec.Description,
+		Price:           rec.Price,
+		DurationDays:    rec.DurationDays,
+		Location:        rec.Location,
+		Latitude:        formatDecimal(rec.Latitude),
+		Longitude:       formatDecimal(rec.Longitude),
+		MeetingLat:      formatDecimal(rec.MeetingLat),
+		MeetingLng:      formatDecimal(rec.MeetingLng),
+		MaxParticipants: rec.MaxParticipants,
+		MinParticipants: rec.MinParticipants,
+		Status:          rec.Status,
+		ImageURLs:       rec.Images,
+	}
+	fillRecordDetails(form, rec)
+	for _, slug := range rec.Categories {
+		cat, err := catRepo.FindBySlug(ctx, slug)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourImportCategory, slug))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceImport, err)
+		}
+		form.CategoryIDs = append(form.CategoryIDs, cat.ID)
+	}
+
+	tour, err := tours.createTour(ctx, form)
+	if err != nil {
+		return err
+	}
+
+	for i, sr := range rec.Schedules {
+		// The admin form enforces this through its binding tags, which an
+		// import does not go through.
+		if sr.AvailableSlots <= 0 {
+			return appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourImportSchedule, i+1, appErrors.ErrMsgScheduleSlotsPositive))
+		}
+		err := schedules.CreateSchedule(ctx, &ScheduleForm{
+			TourID:         tour.ID,
+			DepartureDate:  sr.DepartureDate,
+			ReturnDate:     sr.ReturnDate,
+			AvailableSlots: sr.AvailableSlots,
+			PriceOverride:  sr.PriceOverride,
+			Status:         sr.Status,
+		})
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			return appErrors.NewAppError(appErr.Status, fmt.Sprintf(appErrors.ErrMsgTourImportSchedule, i+1, appErr.Message))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillRecordDetails copies the itinerary, details and translations of rec
+// into the parallel arrays of form.
+func fillRecordDetails(form *TourForm, rec *TourRecord) {
+	for _, day := range rec.Itinerary {
+		form.ItineraryTitles = append(form.ItineraryTitles, day.Title)
+		form.ItineraryDescriptions = append(form.ItineraryDescriptions, day.Description)
+		form.ItineraryMeals = append(form.ItineraryMeals, strings.Join(day.Meals, ","))
+		form.ItineraryAccommodations = append(form.ItineraryAccommodations, day.Accommodation)
+		form.ItineraryImages = append(form.ItineraryImages, strings.Join(day.Images, "\n"))
+	}
+	for _, inc := range rec.Inclusions {
+		form.InclusionKinds = append(form.InclusionKinds, inc.Kind)
+		form.InclusionTexts = append(form.InclusionTexts, inc.Text)
+	}
+	for _, mp := range rec.MeetingPoints {
+		form.MeetingPointNames = append(form.MeetingPointNames, mp.Name)
+		form.MeetingPointAddrs = append(form.MeetingPointAddrs, mp.Address)
+		form.MeetingPointTimes = append(form.MeetingPointTimes, mp.PickupTime)
+		form.MeetingPointNotes = append(form.MeetingPointNotes, mp.Note)
+	}
+	for _, faq := range rec.FAQs {
+		form.FAQQuestions = append(form.FAQQuestions, faq.Question)
+		form.FAQAnswers = append(form.FAQAnswers, faq.Answer)
+	}
+	for _, tr := range rec.Translations {
+		tf := TranslationForm{Locale: tr.Locale, Name: tr.Title, Slug: tr.Slug, Description: tr.Description}
+		for _, day := range tr.Itinerary {
+			tf.ItineraryTitles = append(tf.ItineraryTitles, day.Title)
+			tf.ItineraryDescriptions = append(tf.ItineraryDescriptions, day.Description)
+			tf.ItineraryAccommodations = append(tf.ItineraryAccommodations, day.Accommodation)
+		}
+		form.Translations = append(form.Translations, tf)
+	}
+}
+
+// Export writes every tour with its categories, images, schedules,
+// itinerary, details and translations in a form Import accepts.
+func (s *TourImportService) Export(ctx context.Context, format string, w io.Writer) error {
+	tours, err := s.repo.FindForExport(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceExport, err)
+	}
+	records := make([]TourRecord, len(tours))
+	for i := range tours {
+		booked, err := s.repo.CountBookedBySchedule(ctx, tours[i].ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceExport, err)
+		}
+		records[i] = tourToRecord(&tours[i], booked)
+	}
+	if err := encodeTourRecords(format, w, records); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceExport, err)
+	}
+	return nil
+}
+
+// tourToRecord converts a tour loaded by FindForExport. booked maps schedule
+// IDs to the seats held by pending and confirmed bookings.
+func tourToRecord(t *models.Tour, booked map[uint]int) TourRecord {
+	rec := TourRecord{
+		Title:           t.Title,
+		Slug:            t.Slug,
+		SlugPinned:      t.SlugPinned,
+		Description:     t.Description,
+		Price:           t.Price,
+		DurationDays:    t.DurationDays,
+		Location:        t.Location,
+		Latitude:        t.Latitude,
+		Longitude:       t.Longitude,
+		MeetingLat:      t.MeetingLatitude,
+		MeetingLng:      t.MeetingLongitude,
+		MaxParticipants: t.MaxParticipants,
+		MinParticipants: t.MinParticipants,
+		Status:          t.Status,
+		Images:          models.ImageAssetURLs(models.ParseImageAssets(t.Images)),
+	}
+	for _, cat := range t.Categories {
+		rec.Categories = append(rec.Categories, cat.Slug)
+	}
+	for _, sc := range t.Schedules {
+		rec.Schedules = append(rec.Schedules, ScheduleRecord{
+			DepartureDate:  sc.DepartureDate.Format(time.DateOnly),
+			ReturnDate:     sc.ReturnDate.Format(time.DateOnly),
+			AvailableSlots: sc.AvailableSlots + booked[sc.ID],
+			PriceOverride:  sc.PriceOverride,
+			Status:         sc.Status,
+		})
+	}
+	for _, day := range t.Itinerary {
+		var images []string
+		_ = json.Unmarshal(day.Images, &images)
+		rec.Itinerary = append(rec.Itinerary, ItineraryDayRecord{
+			Title:         day.Title,
+			Description:   day.Description,
+			Meals:         itineraryMeals(&day),
+			Accommodation: day.Accommodation,
+			Images:        images,
+		})
+	}
+	for _, inc := range t.Inclusions {
+		rec.Inclusions = append(rec.Inclusions, InclusionRecord{Kind: inc.Kind, Text: inc.Text})
+	}
+	for _, mp := range t.MeetingPoints {
+		rec.MeetingPoints = append(rec.MeetingPoints, MeetingPointRecord{Name: mp.Name, Address: mp.Address, PickupTime: mp.PickupTime, Note: mp.Note})
+	}
+	for _, faq := range t.FAQs {
+		rec.FAQs = append(rec.FAQs, FAQRecord{Question: faq.Question, Answer: faq.Answer})
+	}
+	for _, tr := range t.Translations {
+		trRec := TranslationRecord{Locale: tr.Locale, Title: tr.Title, Slug: tr.Slug, Description: tr.Description}
+		translated := false
+		for _, day := range t.Itinerary {
+			var text ItineraryTextRecord
+			if dt := t.ItineraryTranslation(tr.Locale, day.DayNumber); dt != nil {
+				text = ItineraryTextRecord{Title: dt.Title, Description: dt.Description, Accommodation: dt.Accommodation}
+				translated = true
+			}
+			trRec.Itinerary = append(trRec.Itinerary, text)
+		}
+		if !translated {
+			trRec.Itinerary = nil
+		}
+		rec.Translations = append(rec.Translations, trRec)
+	}
+	return rec
+}
+
+// itineraryMeals lists the meals a day includes, in the order the form uses.
+func itineraryMeals(day *models.TourItineraryDay) []string {
+	var meals []string
+	if day.Breakfast {
+		meals = append(meals, constants.MealBreakfast)
+	}
+	if day.Lunch {
+		meals = append(meals, constants.MealLunch)
+	}
+	if day.Dinner {
+		meals = append(meals, constants.MealDinner)
+	}
+	return meals
+}
+
+// formatDecimal renders an optional number without exponent; nil is "".
+func formatDecimal(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// TourFileFormat picks the import format from a file name, "" if unknown.
+func TourFileFormat(filename string) string {
+	name := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return TourFileCSV
+	case strings.HasSuffix(name, ".json"):
+		return TourFileJSON
+	}
+	return ""
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appErrors "sun-booking-tours/internal/errors"
+)
+
+// CSV layout: one tour per line. List cells separate items with "|"; each
+// schedule is "departure;return;slots;status" with an optional ";price".
+const (
+	csvListSep  = "|"
+	csvFieldSep = ";"
+)
+
+var tourCSVHeader = []string{
+	"title", "slug", "slug_pinned", "description", "price", "duration_days",
+	"location", "latitude", "longitude", "meeting_latitude", "meeting_longitude",
+	"max_participants", "min_participants", "status", "categories", "images", "schedules",
+}
+
+// importRecord is a decoded record with its position in the file. err holds
+// a cell that could not be parsed; such records are reported, not tried.
+type importRecord struct {
+	TourRecord
+	line int
+	err  string
+}
+
+func decodeTourRecords(format string, r io.Reader) ([]importRecord, error) {
+	switch format {
+	case TourFileCSV:
+		return decodeTourCSV(r)
+	case TourFileJSON:
+		return decodeTourJSON(r)
+	}
+	return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourImportFormat)
+}
+
+func encodeTourRecords(format string, w io.Writer, records []TourRecord) error {
+	switch format {
+	case TourFileCSV:
+		return encodeTourCSV(w, records)
+	case TourFileJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+	return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourImportFormat)
+}
+
+func decodeTourJSON(r io.Reader) ([]importRecord, error) {
+	var records []TourRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourImportFile)
+	}
+	out := make([]importRecord, len(records))
+	for i, rec := range records {
+		out[i] = importRecord{TourRecord: rec, line: i + 1}
+	}
+	return out, nil
+}
+
+func decodeTourCSV(r io.Reader) ([]importRecord, error) {
+	fileErr := appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourImportFile)
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fileErr
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark.
+		name = strings.TrimPrefix(name, "\ufeff")
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["title"]; !ok {
+		return nil, appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTourImportTitleColumn)
+	}
+
+	var out []importRecord
+	for {
+		cells, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fileErr
+		}
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		row := csvRow{cols: cols, cells: cells}
+		rec := importRecord{line: line}
+		rec.TourRecord = TourRecord{
+			Title:           row.text("title"),
+			Slug:            row.text("slug"),
+			SlugPinned:      row.boolean("slug_pinned"),
+			Description:     row.text("description"),
+			Price:           row.float("price"),
+			DurationDays:    row.integer("duration_days"),
+			Location:        row.text("location"),
+			Latitude:        row.optFloat("latitude"),
+			Longitude:       row.optFloat("longitude"),
+			MeetingLat:      row.optFloat("meeting_latitude"),
+			MeetingLng:      row.optFloat("meeting_longitude"),
+			MaxParticipants: row.integer("max_participants"),
+			MinParticipants: row.integer("min_participants"),
+			Status:          row.text("status"),
+			Categories:      row.list("categories"),
+			Images:          row.list("images"),
+			Schedules:       row.schedules("schedules"),
+		}
+		if row.bad != "" {
+			rec.err = fmt.Sprintf(appErrors.ErrMsgTourImportValue, row.bad)
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// csvRow reads typed cells by column name. Missing columns read as empty;
+// bad names the first cell that failed to parse.
+type csvRow struct {
+	cols  map[string]int
+	cells []string
+	bad   string
+}
+
+func (r *csvRow) text(name string) string {
+	i, ok := r.cols[name]
+	if !ok || i >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[i])
+}
+
+func (r *csvRow) fail(name string) {
+	if r.bad == "" {
+		r.bad = name
+	}
+}
+
+func (r *csvRow) integer(name string) int {
+	s := r.text(name)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		r.fail(name)
+	}
+	return v
+}
+
+func (r *csvRow) float(name string) float64 {
+	if v := r.optFloat(name); v != nil {
+		return *v
+	}
+	return 0
+}
+
+func (r *csvRow) optFloat(name string) *float64 {
+	s := r.text(name)
+	if s == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		r.fail(name)
+		return nil
+	}
+	return &v
+}
+
+func (r *csvRow) boolean(name string) bool {
+	s := r.text(name)
+	if s == "" {
+		return false
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		r.fail(name)
+	}
+	return v
+}
+
+func (r *csvRow) list(name string) []string {
+	s := r.text(name)
+	if s == "" {
+		return nil
+	}
+	return filterNonEmpty(strings.Split(s, csvListSep))
+}
+
+func (r *csvRow) schedules(name string) []ScheduleRecord {
+	var out []ScheduleRecord
+	for _, item := range r.list(name) {
+		parts := strings.Split(item, csvFieldSep)
+		if len(parts) != 4 && len(parts) != 5 {
+			r.fail(name)
+			return nil
+		}
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		slots, err := strconv.Atoi(parts[2])
+		if err != nil {
+			r.fail(name)
+			return nil
+		}
+		sr := ScheduleRecord{DepartureDate: parts[0], ReturnDate: parts[1], AvailableSlots: slots, Status: parts[3]}
+		if len(parts) == 5 && parts[4] != "" {
+			price, err := strconv.ParseFloat(parts[4], 64)
+			if err != nil {
+				r.fail(name)
+				return nil
+			}
+			sr.PriceOverride = &price
+		}
+		out = append(out, sr)
+	}
+	return out
+}
+
+func encodeTourCSV(w io.Writer, records []TourRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(tourCSVHeader); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		schedules := make([]string, len(rec.Schedules))
+		for i, sr := range rec.Schedules {
+			fields := []string{sr.DepartureDate, sr.ReturnDate, strconv.Itoa(sr.AvailableSlots), sr.Status}
+			if sr.PriceOverride != nil {
+				fields = append(fields, formatDecimal(sr.PriceOverride))
+			}
+			schedules[i] = strings.Join(fields, csvFieldSep)
+		}
+		if err := cw.Write([]string{
+			rec.Title,
+			rec.Slug,
+			strconv.FormatBool(rec.SlugPinned),
+			rec.Description,
+			formatDecimal(&rec.Price),
+			strconv.Itoa(rec.DurationDays),
+			rec.Location,
+			formatDecimal(rec.Latitude),
+			formatDecimal(rec.Longitude),
+			formatDecimal(rec.MeetingLat),
+			formatDecimal(rec.MeetingLng),
+			strconv.Itoa(rec.MaxParticipants),
+			strconv.Itoa(rec.MinParticipants),
+			rec.Status,
+			strings.Join(rec.Categories, csvListSep),
+			strings.Join(rec.Images, csvListSep),
+			strings.Join(schedules, csvListSep),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
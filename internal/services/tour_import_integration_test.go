@@ -0,0 +1,182 @@
+//go:build integration
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupTourImportDB(t *testing.T) (*TourImportService, *gorm.DB) {
+	t.Helper()
+	_, db := setupTourServiceDB(t)
+	require.NoError(t, db.Create(&models.Category{Name: "Biển", Slug: "bien"}).Error)
+	return NewTourImportService(db, repository.NewTourRepository(db), nil), db
+}
+
+// --- Import ---------------------------------------------------------
+
+func TestImportTours_DryRunReportsEveryRowAndSavesNothing(t *testing.T) {
+	svc, db := setupTourImportDB(t)
+	csv := importCSVHeader +
+		"Nha Trang,1500000,3,20,active,bien,2030-06-01;2030-06-03;20;open\n" +
+		"Quy Nhon,abc,3,20,active,,\n" +
+		"Phu Yen,900000,2,15,active,nui,\n" +
+		"Nha Trang,1200000,3,20,draft,,\n"
+
+	report, err := svc.Import(context.Background(), TourFileCSV, strings.NewReader(csv), true)
+
+	require.NoError(t, err)
+	require.Len(t, report.Rows, 4)
+	assert.Equal(t, []int{2, 3, 4, 5}, []int{report.Rows[0].Line, report.Rows[1].Line, report.Rows[2].Line, report.Rows[3].Line})
+	assert.Empty(t, report.Rows[0].Error)
+	assert.Equal(t, 1, report.Rows[0].Schedules)
+	assert.Equal(t, fmt.Sprintf(appErrors.ErrMsgTourImportValue, "price"), report.Rows[1].Error)
+	assert.Equal(t, fmt.Sprintf(appErrors.ErrMsgTourImportCategory, "nui"), report.Rows[2].Error)
+	assert.Equal(t, appErrors.ErrMsgTourTitleDuplicate, report.Rows[3].Error)
+	assert.Equal(t, 3, report.Failed())
+	assert.Zero(t, report.Imported)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Tour{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestImportTours_FailedScheduleRollsBackWholeFile(t *testing.T) {
+	svc, db := setupTourImportDB(t)
+	csv := importCSVHeader +
+		"Da Nang,2000000,3,20,active,,\n" +
+		"Hue,1000000,2,20,active,,2030-06-05;2030-06-01;10;open\n"
+
+	report, err := svc.Import(context.Background(), TourFileCSV, strings.NewReader(csv), false)
+
+	require.NoError(t, err)
+	assert.Zero(t, report.Imported)
+	assert.Equal(t, fmt.Sprintf(appErrors.ErrMsgTourImportSchedule, 1, appErrors.ErrMsgScheduleReturnNotBeforeDepart), report.Rows[1].Error)
+	var count int64
+	require.NoError(t, db.Model(&models.Tour{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestImportTours_RejectsNumbersTheFormWouldReject(t *testing.T) {
+	svc, _ := setupTourImportDB(t)
+	csv := importCSVHeader + "Sa Pa,0,2,10,active,,\n"
+
+	report, err := svc.Import(context.Background(), TourFileCSV, strings.NewReader(csv), true)
+
+	require.NoError(t, err)
+	assert.Equal(t, appErrors.ErrMsgTourPricePositive, report.Rows[0].Error)
+}
+
+// --- Export ---------------------------------------------------------
+
+func TestExportTours_RoundTripsThroughImport(t *testing.T) {
+	svc, db := setupTourImportDB(t)
+	ctx := context.Background()
+	csv := "\ufefftitle,slug,slug_pinned,price,duration_days,latitude,longitude,max_participants,status,categories,images,schedules\n" +
+		"Con Dao,con-dao-dive,true,3500000,3,8.68,106.6,12,active,bien,https://cdn.example.com/a.jpg|https://cdn.example.com/b.jpg,2030-07-01;2030-07-03;12;open;3200000|2030-08-01;2030-08-03;12;open\n"
+	report, err := svc.Import(ctx, TourFileCSV, strings.NewReader(csv), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported, report.Rows)
+
+	for _, format := range []string{TourFileCSV, TourFileJSON} {
+		var out bytes.Buffer
+		require.NoError(t, svc.Export(ctx, format, &out))
+		records, err := decodeTourRecords(format, &out)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+
+		rec := records[0]
+		assert.Empty(t, rec.err, format)
+		assert.Equal(t, "con-dao-dive", rec.Slug, format)
+		assert.True(t, rec.SlugPinned, format)
+		assert.Equal(t, []string{"bien"}, rec.Categories, format)
+		assert.Equal(t, []string{"https://cdn.example.com/a.jpg", "https://cdn.example.com/b.jpg"}, rec.Images, format)
+		require.NotNil(t, rec.Latitude, format)
+		assert.Equal(t, 8.68, *rec.Latitude, format)
+		require.Len(t, rec.Schedules, 2, format)
+		assert.Equal(t, "2030-07-01", rec.Schedules[0].DepartureDate, format)
+		require.NotNil(t, rec.Schedules[0].PriceOverride, format)
+		assert.Equal(t, 3200000.0, *rec.Schedules[0].PriceOverride, format)
+		assert.Nil(t, rec.Schedules[1].PriceOverride, format)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.TourSchedule{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestExportTours_JSONDetailsSurviveImport(t *testing.T) {
+	svc, db := setupTourImportDB(t)
+	ctx := context.Background()
+	tours := NewTourService(db, repository.NewTourRepository(db), repository.NewCategoryRepository(db), repository.NewSlugHistoryRepository(db), nil)
+	form := itineraryForm("Phú Quốc", "Bãi Sao", "Hòn Thơm")
+	form.ItineraryMeals = []string{"breakfast,dinner", ""}
+	form.InclusionKinds = []string{constants.InclusionKindIncluded}
+	form.InclusionTexts = []string{"Vé cáp treo"}
+	form.MeetingPointNames = []string{"Sân bay Phú Quốc"}
+	form.MeetingPointAddrs = []string{""}
+	form.MeetingPointTimes = []string{"08:00"}
+	form.MeetingPointNotes = []string{""}
+	form.FAQQuestions = []string{"Có đón khách sạn không?"}
+	form.FAQAnswers = []string{"Có"}
+	en := englishTranslation("Phu Quoc Island")
+	en.ItineraryTitles = []string{"Sao Beach", ""}
+	en.ItineraryDescriptions = []string{"", ""}
+	en.ItineraryAccommodations = []string{"", ""}
+	form.Translations = []TranslationForm{en}
+	tour, err := tours.createTour(ctx, form)
+	require.NoError(t, err)
+	schedule := models.TourSchedule{TourID: tour.ID, DepartureDate: time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC),
+		ReturnDate: time.Date(2030, 5, 2, 0, 0, 0, 0, time.UTC), AvailableSlots: 0, Status: constants.ScheduleStatusFull}
+	require.NoError(t, db.Create(&schedule).Error)
+	traveller := seedUser(t, db, "traveller", constants.RoleUser)
+	require.NoError(t, db.Create(&models.Booking{UserID: traveller.ID, TourID: tour.ID, ScheduleID: schedule.ID, NumParticipants: 10,
+		TotalPrice: 1000, Status: constants.BookingStatusConfirmed}).Error)
+
+	var out bytes.Buffer
+	require.NoError(t, svc.Export(ctx, TourFileJSON, &out))
+
+	// Setting up again empties the database, so the file is imported into
+	// a fresh one.
+	target, targetDB := setupTourImportDB(t)
+	report, err := target.Import(ctx, TourFileJSON, &out, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported, report.Rows)
+
+	var imported models.Tour
+	require.NoError(t, targetDB.First(&imported).Error)
+	got, err := repository.NewTourRepository(targetDB).FindByID(ctx, imported.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Schedules, 1)
+	assert.Equal(t, 10, got.Schedules[0].AvailableSlots)
+	require.Len(t, got.Itinerary, 2)
+	assert.True(t, got.Itinerary[0].Breakfast)
+	assert.True(t, got.Itinerary[0].Dinner)
+	assert.False(t, got.Itinerary[0].Lunch)
+	require.Len(t, got.Inclusions, 1)
+	assert.Equal(t, "Vé cáp treo", got.Inclusions[0].Text)
+	require.Len(t, got.MeetingPoints, 1)
+	assert.Equal(t, "08:00", got.MeetingPoints[0].PickupTime)
+	require.Len(t, got.FAQs, 1)
+	assert.Equal(t, "Có", got.FAQs[0].Answer)
+	require.Len(t, got.Translations, 1)
+	assert.Equal(t, "phu-quoc-island", got.Translations[0].Slug)
+	require.Len(t, got.ItineraryTranslations, 1)
+	assert.Equal(t, "Sao Beach", got.ItineraryTranslations[0].Title)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importCSVHeader = "title,price,duration_days,max_participants,status,categories,schedules\n"
+
+// --- helpers ----------------------------------------------------------
+
+func setupTourImport() (*TourImportService, *testutil.MockTourRepo) {
+	repo := new(testutil.MockTourRepo)
+	return NewTourImportService(nil, repo, nil), repo
+}
+
+// exportTour is a tour as FindForExport loads it, with a sold-out schedule
+// holding ten booked seats.
+func exportTour() models.Tour {
+	lat := 8.68
+	override := 3200000.0
+	return models.Tour{
+		ID: 5, Title: "Con Dao", Slug: "con-dao-dive", SlugPinned: true, Price: 3500000, DurationDays: 2,
+		MaxParticipants: 12, Status: constants.TourStatusActive, Latitude: &lat,
+		Images:     []byte(`[{"url":"https://cdn.example.com/a.jpg"},{"url":"https://cdn.example.com/b.jpg"}]`),
+		Categories: []models.Category{{Slug: "bien"}},
+		Schedules: []models.TourSchedule{
+			{ID: 7, DepartureDate: time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC), ReturnDate: time.Date(2030, 7, 3, 0, 0, 0, 0, time.UTC),
+				AvailableSlots: 0, PriceOverride: &override, Status: constants.ScheduleStatusFull},
+			{ID: 8, DepartureDate: time.Date(2030, 8, 1, 0, 0, 0, 0, time.UTC), ReturnDate: time.Date(2030, 8, 3, 0, 0, 0, 0, time.UTC),
+				AvailableSlots: 12, Status: constants.ScheduleStatusOpen},
+		},
+		Itinerary: []models.TourItineraryDay{
+			{DayNumber: 1, Title: "Bãi Đầm Trầu", Breakfast: true, Dinner: true},
+			{DayNumber: 2, Title: "Hòn Bảy Cạnh"},
+		},
+		Inclusions:   []models.TourInclusion{{Kind: constants.InclusionKindIncluded, Text: "Vé lặn biển"}},
+		Translations: []models.TourTranslation{{Locale: "en", Title: "Con Dao diving", Slug: "con-dao-diving"}},
+	}
+}
+
+// --- Import -----------------------------------------------------------
+
+func TestImportTours_RejectedFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		body    string
+		wantMsg string
+	}{
+		{"csv without title column", TourFileCSV, "name,price\nA,1\n", appErrors.ErrMsgTourImportTitleColumn},
+		{"csv with header only", TourFileCSV, importCSVHeader, appErrors.ErrMsgTourImportEmpty},
+		{"malformed json", TourFileJSON, `{"title":`, appErrors.ErrMsgTourImportFile},
+		{"unknown format", "xlsx", "", appErrors.ErrMsgTourImportFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := setupTourImport()
+
+			_, err := svc.Import(context.Background(), tt.format, strings.NewReader(tt.body), true)
+
+			assertAppError(t, err, tt.wantMsg)
+		})
+	}
+}
+
+func TestDecodeTourCSV_ReportsBadCellPerRow(t *testing.T) {
+	csv := "\ufeff" + importCSVHeader +
+		"Nha Trang,1500000,3,20,active,bien|dao,2030-06-01;2030-06-03;20;open\n" +
+		",,,,,,\n" +
+		"Quy Nhon,abc,3,20,active,,\n"
+
+	records, err := decodeTourRecords(TourFileCSV, strings.NewReader(csv))
+
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 2, records[0].line)
+	assert.Empty(t, records[0].err)
+	assert.Equal(t, []string{"bien", "dao"}, records[0].Categories)
+	require.Len(t, records[0].Schedules, 1)
+	assert.Equal(t, 20, records[0].Schedules[0].AvailableSlots)
+	assert.Equal(t, 4, records[1].line)
+	assert.Equal(t, fmt.Sprintf(appErrors.ErrMsgTourImportValue, "price"), records[1].err)
+}
+
+func TestTourFileFormat(t *testing.T) {
+	assert.Equal(t, TourFileCSV, TourFileFormat("tours.CSV"))
+	assert.Equal(t, TourFileJSON, TourFileFormat("export.json"))
+	assert.Empty(t, TourFileFormat("tours.xlsx"))
+}
+
+// --- Export -----------------------------------------------------------
+
+func TestExportTours_JSONKeepsDetailsAndSoldOutCapacity(t *testing.T) {
+	svc, repo := setupTourImport()
+	repo.On("FindForExport", mock.Anything).Return([]models.Tour{exportTour()}, nil)
+	repo.On("CountBookedBySchedule", mock.Anything, uint(5)).Return(map[uint]int{7: 10}, nil)
+
+	var out bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), TourFileJSON, &out))
+	records, err := decodeTourRecords(TourFileJSON, &out)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	rec := records[0]
+	assert.Equal(t, "con-dao-dive", rec.Slug)
+	assert.True(t, rec.SlugPinned)
+	assert.Equal(t, []string{"bien"}, rec.Categories)
+	assert.Equal(t, []string{"https://cdn.example.com/a.jpg", "https://cdn.example.com/b.jpg"}, rec.Images)
+	require.Len(t, rec.Schedules, 2)
+	assert.Equal(t, "2030-07-01", rec.Schedules[0].DepartureDate)
+	assert.Equal(t, 10, rec.Schedules[0].AvailableSlots)
+	require.NotNil(t, rec.Schedules[0].PriceOverride)
+	assert.Equal(t, 3200000.0, *rec.Schedules[0].PriceOverride)
+	assert.Equal(t, 12, rec.Schedules[1].AvailableSlots)
+	require.Len(t, rec.Itinerary, 2)
+	assert.Equal(t, []string{constants.MealBreakfast, constants.MealDinner}, rec.Itinerary[0].Meals)
+	require.Len(t, rec.Inclusions, 1)
+	require.Len(t, rec.Translations, 1)
+	assert.Equal(t, "con-dao-diving", rec.Translations[0].Slug)
+	// No day is translated, so the translation carries no itinerary.
+	assert.Empty(t, rec.Translations[0].Itinerary)
+}
+
+func TestExportTours_CSVLeavesOutDetails(t *testing.T) {
+	svc, repo := setupTourImport()
+	repo.On("FindForExport", mock.Anything).Return([]models.Tour{exportTour()}, nil)
+	repo.On("CountBookedBySchedule", mock.Anything, uint(5)).Return(map[uint]int{}, nil)
+
+	var out bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), TourFileCSV, &out))
+	records, err := decodeTourRecords(TourFileCSV, &out)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].err)
+	require.NotNil(t, records[0].Latitude)
+	assert.Equal(t, 8.68, *records[0].Latitude)
+	assert.Len(t, records[0].Schedules, 2)
+	assert.Empty(t, records[0].Itinerary)
+	assert.Empty(t, records[0].Translations)
+}
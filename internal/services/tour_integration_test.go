@@ -0,0 +1,610 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupTourServiceDB(t *testing.T) (*TourService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+
+	svc := NewTourService(db, repository.NewTourRepository(db),
+		repository.NewCategoryRepository(db),
+		repository.NewSlugHistoryRepository(db),
+		nil,
+	)
+	return svc, db
+}
+
+// --- slug history -----------------------------------------------------
+
+func TestUpdateTour_Rename_OldSlugResolvesToCanonical(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	require.NoError(t, svc.CreateTour(ctx, tourForm("Ha Long Bay")))
+
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, tourForm("Ha Long Bay Cruise")))
+
+	got, _, err := svc.GetPublicTourBySlug(ctx, "ha-long-bay")
+
+	require.NoError(t, err)
+	assert.Equal(t, tour.ID, got.ID)
+	assert.Equal(t, "ha-long-bay-cruise", got.Slug)
+}
+
+func TestUpdateTour_RevertToOldSlug_DropsHistoryEntry(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	require.NoError(t, svc.CreateTour(ctx, tourForm("Sapa")))
+
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, tourForm("Sapa Trek")))
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, tourForm("Sapa")))
+
+	history, err := svc.SlugHistory(ctx, tour.ID)
+
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "sapa-trek", history[0].Slug)
+}
+
+func TestUpdateTour_PinnedSlug_SurvivesTitleChange(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	form := tourForm("Hue Heritage")
+	form.Slug = "Hue Classic"
+	form.SlugPinned = true
+	require.NoError(t, svc.CreateTour(ctx, form))
+
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+	assert.Equal(t, "hue-classic", tour.Slug)
+
+	form.Title = "Hue Heritage Walk"
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, form))
+
+	require.NoError(t, db.First(&tour, tour.ID).Error)
+	assert.Equal(t, "hue-classic", tour.Slug)
+	assert.True(t, tour.SlugPinned)
+}
+
+// --- facets -----------------------------------------------------------
+
+func TestListTours_FacetCountsIgnoreOwnDimension(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	beach := createTestCategory(t, db, "Biển", "bien", nil)
+	hills := createTestCategory(t, db, "Núi", "nui", nil)
+
+	for _, tc := range []struct {
+		title    string
+		price    float64
+		days     int
+		location string
+		category uint
+	}{
+		{"Nha Trang", 1_500_000, 1, "Khánh Hòa", beach.ID},
+		{"Phu Quoc", 6_000_000, 3, "Kiên Giang", beach.ID},
+		{"Sapa", 3_000_000, 3, "Lào Cai", hills.ID},
+	} {
+		form := tourForm(tc.title)
+		form.Price, form.DurationDays, form.Location = tc.price, tc.days, tc.location
+		form.CategoryIDs = []uint{tc.category}
+		require.NoError(t, svc.CreateTour(ctx, form))
+	}
+
+	tours, total, facets, err := svc.ListTours(ctx, repository.TourFilter{
+		Status:        constants.TourStatusActive,
+		CategorySlugs: []string{"bien"},
+		IncludeFacets: true,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, tours, 2)
+	assert.EqualValues(t, 2, total)
+
+	// The category dimension ignores the active category filter...
+	require.Len(t, facets.Categories, 2)
+	assert.EqualValues(t, 2, facets.Categories[0].Count)
+	assert.True(t, facets.Categories[0].Active)
+	assert.EqualValues(t, 1, facets.Categories[1].Count)
+
+	// ...while the other dimensions respect it.
+	assert.Equal(t, []int64{1, 0, 1, 0}, facetCounts(facets.Prices))
+	assert.Equal(t, []int64{1, 1, 0, 0}, facetCounts(facets.Durations))
+	assert.Len(t, facets.Locations, 2)
+	assert.Equal(t, "Dưới 2 triệu", facets.Prices[0].Label)
+	assert.Equal(t, map[string]string{"min_price": "", "max_price": "2000000"}, facets.Prices[0].Params)
+}
+
+func TestListTours_FacetCountsMatchTheirFilters(t *testing.T) {
+	svc, _ := setupTourServiceDB(t)
+	ctx := context.Background()
+	for _, tc := range []struct {
+		title    string
+		price    float64
+		location string
+	}{
+		{"Phố cổ", 1_999_999.5, "Hà Nội"},
+		{"Hồ Tây", 2_000_000, "hà nội"},
+		{"Tràng An", 3_000_000, "Hà Nội - Ninh Bình"},
+	} {
+		form := tourForm(tc.title)
+		form.Price, form.Location = tc.price, tc.location
+		require.NoError(t, svc.CreateTour(ctx, form))
+	}
+
+	_, _, facets, err := svc.ListTours(ctx, repository.TourFilter{Status: constants.TourStatusActive, IncludeFacets: true})
+	require.NoError(t, err)
+
+	// A decimal price just under a bound still lands in a bucket, and a
+	// price on a bound only in the bucket it starts.
+	assert.Equal(t, []int64{1, 2, 0, 0}, facetCounts(facets.Prices))
+
+	// The max_price filter itself stays inclusive.
+	_, total, _, err := svc.ListTours(ctx, repository.TourFilter{Status: constants.TourStatusActive, MaxPrice: 2_000_000})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+
+	// Locations differing only in case share an option, and each count is
+	// what the substring filter returns.
+	require.Len(t, facets.Locations, 2)
+	assert.Equal(t, "Hà Nội", facets.Locations[0].Label)
+	for _, opt := range facets.Locations {
+		_, total, _, err := svc.ListTours(ctx, repository.TourFilter{Status: constants.TourStatusActive, Location: opt.Params["location"]})
+		require.NoError(t, err)
+		assert.Equal(t, opt.Count, total, opt.Label)
+	}
+	assert.EqualValues(t, 3, facets.Locations[0].Count)
+}
+
+// createTestCategory stores a category through the repository so it gets
+// its tree path.
+func createTestCategory(t *testing.T, db *gorm.DB, name, slug string, parent *models.Category) models.Category {
+	t.Helper()
+	cat := models.Category{Name: name, Slug: slug}
+	if parent != nil {
+		cat.ParentID = &parent.ID
+	}
+	require.NoError(t, repository.NewCategoryRepository(db).Create(context.Background(), &cat))
+	return cat
+}
+
+// createCategorizedTour adds an active tour filed under cats.
+func createCategorizedTour(t *testing.T, svc *TourService, title string, cats ...models.Category) {
+	t.Helper()
+	form := tourForm(title)
+	for _, c := range cats {
+		form.CategoryIDs = append(form.CategoryIDs, c.ID)
+	}
+	require.NoError(t, svc.CreateTour(context.Background(), form))
+}
+
+func tourTitles(tours []models.Tour) []string {
+	out := make([]string, len(tours))
+	for i, tour := range tours {
+		out[i] = tour.Title
+	}
+	return out
+}
+
+func TestListTours_CategoryIncludesDescendants(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	beach := createTestCategory(t, db, "Biển", "bien", nil)
+	south := createTestCategory(t, db, "Biển phía Nam", "bien-phia-nam", &beach)
+	phuQuoc := createTestCategory(t, db, "Phú Quốc", "phu-quoc", &south)
+	nhaTrang := createTestCategory(t, db, "Nha Trang", "nha-trang", &beach)
+	hills := createTestCategory(t, db, "Núi", "nui", nil)
+	createCategorizedTour(t, svc, "Phu Quoc 3N2D", phuQuoc)
+	createCategorizedTour(t, svc, "Nha Trang 2N1D", nhaTrang)
+	createCategorizedTour(t, svc, "Sapa", hills)
+
+	tours, total, facets, err := svc.ListTours(ctx, repository.TourFilter{
+		CategorySlugs: []string{"bien"},
+		IncludeFacets: true,
+	})
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.ElementsMatch(t, []string{"Phu Quoc 3N2D", "Nha Trang 2N1D"}, tourTitles(tours))
+
+	// The landing page lists the direct children with subtree counts.
+	require.Len(t, facets.Subcategories, 2)
+	counts := map[string]int64{}
+	for _, sub := range facets.Subcategories {
+		counts[sub.Label] = sub.Count
+	}
+	assert.Equal(t, map[string]int64{"Biển phía Nam": 1, "Nha Trang": 1}, counts)
+	assert.Empty(t, facets.CategoryMatch)
+}
+
+func TestListTours_SeveralCategoriesAnyOrAll(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	beach := createTestCategory(t, db, "Biển", "bien", nil)
+	family := createTestCategory(t, db, "Gia đình", "gia-dinh", nil)
+	kids := createTestCategory(t, db, "Trẻ nhỏ", "tre-nho", &family)
+	createCategorizedTour(t, svc, "Phu Quoc", beach, kids)
+	createCategorizedTour(t, svc, "Nha Trang", beach)
+	createCategorizedTour(t, svc, "Ha Noi", family)
+
+	anyTours, _, facets, err := svc.ListTours(ctx, repository.TourFilter{
+		CategorySlugs: []string{"bien", "gia-dinh"},
+		IncludeFacets: true,
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Phu Quoc", "Nha Trang", "Ha Noi"}, tourTitles(anyTours))
+	require.Len(t, facets.CategoryMatch, 2)
+	assert.True(t, facets.CategoryMatch[0].Active)
+	assert.Empty(t, facets.Subcategories)
+
+	allTours, _, _, err := svc.ListTours(ctx, repository.TourFilter{
+		CategorySlugs: []string{"bien", "gia-dinh"},
+		CategoryMatch: repository.CategoryMatchAll,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Phu Quoc"}, tourTitles(allTours))
+}
+
+func TestBulkUpdateCategories_AddsAndRemovesLinks(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	beach := createTestCategory(t, db, "Biển", "bien", nil)
+	family := createTestCategory(t, db, "Gia đình", "gia-dinh", nil)
+	createCategorizedTour(t, svc, "Phu Quoc", beach)
+	createCategorizedTour(t, svc, "Ha Long")
+	var ids []uint
+	require.NoError(t, db.Model(&models.Tour{}).Order("id").Pluck("id", &ids).Error)
+
+	added, err := svc.BulkUpdateCategories(ctx, &BulkCategoryForm{
+		Action: BulkCategoryAdd, TourIDs: ids, CategoryIDs: []uint{beach.ID, family.ID},
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, added)
+
+	removed, err := svc.BulkUpdateCategories(ctx, &BulkCategoryForm{
+		Action: BulkCategoryRemove, TourIDs: ids[:1], CategoryIDs: []uint{beach.ID},
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, removed)
+
+	var links int64
+	require.NoError(t, db.Table("tour_categories").Count(&links).Error)
+	assert.EqualValues(t, 3, links)
+}
+
+func facetCounts(opts []FacetOption) []int64 {
+	out := make([]int64, len(opts))
+	for i, o := range opts {
+		out[i] = o.Count
+	}
+	return out
+}
+
+// --- geo --------------------------------------------------------------
+
+func createLocatedTour(t *testing.T, svc *TourService, title, lat, lng string) {
+	t.Helper()
+	form := tourForm(title)
+	form.Latitude, form.Longitude = lat, lng
+	require.NoError(t, svc.CreateTour(context.Background(), form))
+}
+
+func TestTourPins_BBoxLimitsResults(t *testing.T) {
+	svc, _ := setupTourServiceDB(t)
+	createLocatedTour(t, svc, "Da Nang", "16.0544", "108.2022")
+	createLocatedTour(t, svc, "Ha Noi", "21.0285", "105.8542")
+	require.NoError(t, svc.CreateTour(context.Background(), tourForm("Unlocated")))
+
+	bbox, err := ParseBBox("107,15,109,17")
+	require.NoError(t, err)
+	pins, err := svc.TourPins(context.Background(), repository.TourFilter{
+		Status: constants.TourStatusActive,
+		BBox:   bbox,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, pins.Features, 1)
+	assert.Equal(t, "Da Nang", pins.Features[0].Properties.Title)
+	assert.Equal(t, [2]float64{108.2022, 16.0544}, pins.Features[0].Geometry.Coordinates)
+}
+
+// --- inclusions, meeting points, FAQ ------------------------------------
+
+func TestCreateTour_DetailsKeepOrderAndSkipBlankRows(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	form := tourForm("Sa Pa")
+	form.InclusionKinds = []string{constants.InclusionKindIncluded, constants.InclusionKindExcluded, constants.InclusionKindIncluded}
+	form.InclusionTexts = []string{"Xe đưa đón", " ", "Bữa trưa"}
+	form.MeetingPointNames = []string{"Nhà hát lớn", "", "Hồ Gươm"}
+	form.MeetingPointAddrs = []string{"1 Tràng Tiền", "", ""}
+	form.MeetingPointTimes = []string{"07:30", "", "08:00"}
+	form.MeetingPointNotes = []string{"", "", ""}
+	form.FAQQuestions = []string{"Có hoàn tiền không?", ""}
+	form.FAQAnswers = []string{"Có, trước 48 giờ.", ""}
+	require.NoError(t, svc.CreateTour(ctx, form))
+
+	var created models.Tour
+	require.NoError(t, db.First(&created).Error)
+	tour, err := svc.GetTour(ctx, created.ID)
+	require.NoError(t, err)
+
+	included := tour.InclusionsOf(constants.InclusionKindIncluded)
+	require.Len(t, included, 2)
+	assert.Equal(t, "Xe đưa đón", included[0].Text)
+	assert.Equal(t, "Bữa trưa", included[1].Text)
+	assert.Empty(t, tour.InclusionsOf(constants.InclusionKindExcluded))
+	require.Len(t, tour.MeetingPoints, 2)
+	assert.Equal(t, "Hồ Gươm", tour.MeetingPoints[1].Name)
+	require.Len(t, tour.FAQs, 1)
+}
+
+// --- translations -------------------------------------------------------
+
+func TestCreateTour_TranslationResolvesByLocalizedSlug(t *testing.T) {
+	svc, _ := setupTourServiceDB(t)
+	ctx := context.Background()
+	form := tourForm("Vịnh Hạ Long")
+	form.ItineraryTitles = []string{"Hà Nội - Hạ Long", "Hang Sửng Sốt"}
+	form.ItineraryDescriptions = []string{"", ""}
+	form.ItineraryMeals = []string{"", ""}
+	form.ItineraryAccommodations = []string{"Du thuyền", ""}
+	form.ItineraryImages = []string{"", ""}
+	en := englishTranslation("Ha Long Bay")
+	en.ItineraryTitles = []string{"Hanoi - Ha Long", ""}
+	en.ItineraryDescriptions = []string{"", ""}
+	en.ItineraryAccommodations = []string{"Cruise ship", ""}
+	form.Translations = []TranslationForm{en}
+	require.NoError(t, svc.CreateTour(ctx, form))
+
+	tour, _, err := svc.GetPublicTourBySlug(ctx, "ha-long-bay")
+	require.NoError(t, err)
+	assert.Equal(t, "vinh-ha-long", tour.Slug)
+
+	tour.Localize(constants.LocaleEN)
+	assert.Equal(t, "Ha Long Bay", tour.Title)
+	assert.Equal(t, "ha-long-bay", tour.Slug)
+	require.Len(t, tour.Itinerary, 2)
+	assert.Equal(t, "Hanoi - Ha Long", tour.Itinerary[0].Title)
+	assert.Equal(t, "Cruise ship", tour.Itinerary[0].Accommodation)
+	assert.Equal(t, "Hang Sửng Sốt", tour.Itinerary[1].Title)
+}
+
+func TestUpdateTour_RenamedTranslationKeepsOldSlugRedirect(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	form := tourForm("Đà Lạt")
+	form.Translations = []TranslationForm{englishTranslation("Da Lat Getaway")}
+	require.NoError(t, svc.CreateTour(ctx, form))
+
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+	form.Translations = []TranslationForm{englishTranslation("Da Lat Highlands")}
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, form))
+
+	got, _, err := svc.GetPublicTourBySlug(ctx, "da-lat-getaway")
+	require.NoError(t, err)
+	assert.Equal(t, tour.ID, got.ID)
+	got.Localize(constants.LocaleEN)
+	assert.Equal(t, "da-lat-highlands", got.Slug)
+}
+
+// --- clone --------------------------------------------------------------
+
+func TestCloneTour_CopiesContentAsDraftWithUniqueSlug(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	cat := models.Category{Name: "Biển", Slug: "bien"}
+	require.NoError(t, db.Create(&cat).Error)
+	form := tourForm("Phú Quốc")
+	form.CategoryIDs = []uint{cat.ID}
+	form.ItineraryTitles = []string{"Bãi Sao", "Hòn Thơm"}
+	form.ItineraryDescriptions = []string{"", ""}
+	form.ItineraryMeals = []string{"", ""}
+	form.ItineraryAccommodations = []string{"", ""}
+	form.ItineraryImages = []string{"", ""}
+	form.FAQQuestions = []string{"Có đón sân bay không?"}
+	form.FAQAnswers = []string{"Có."}
+	form.Translations = []TranslationForm{englishTranslation("Phu Quoc Island")}
+	require.NoError(t, svc.CreateTour(ctx, form))
+	var src models.Tour
+	require.NoError(t, db.First(&src).Error)
+
+	first, err := svc.CloneTour(ctx, src.ID, &CloneForm{})
+	require.NoError(t, err)
+	second, err := svc.CloneTour(ctx, src.ID, &CloneForm{})
+	require.NoError(t, err)
+
+	got, err := svc.GetTour(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Phú Quốc (bản sao)", got.Title)
+	assert.Equal(t, "phu-quoc-ban-sao", got.Slug)
+	assert.Equal(t, "phu-quoc-ban-sao-2", second.Slug)
+	assert.Equal(t, constants.TourStatusDraft, got.Status)
+	require.Len(t, got.Categories, 1)
+	assert.Equal(t, cat.ID, got.Categories[0].ID)
+	require.Len(t, got.Itinerary, 2)
+	assert.Equal(t, "Hòn Thơm", got.Itinerary[1].Title)
+	require.Len(t, got.FAQs, 1)
+	require.Len(t, got.Translations, 1)
+	assert.Equal(t, "phu-quoc-island-2", got.Translations[0].Slug)
+
+	var source models.Tour
+	require.NoError(t, db.Preload("Itinerary").First(&source, src.ID).Error)
+	assert.Len(t, source.Itinerary, 2)
+	assert.Equal(t, "phu-quoc", source.Slug)
+}
+
+func TestCloneTour_ShiftsFutureSchedulesAndRestoresSeats(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	require.NoError(t, svc.CreateTour(ctx, tourForm("Đà Lạt")))
+	var src models.Tour
+	require.NoError(t, db.First(&src).Error)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	upcoming := models.TourSchedule{TourID: src.ID, DepartureDate: today.AddDate(0, 0, 10), ReturnDate: today.AddDate(0, 0, 12),
+		AvailableSlots: 4, Status: constants.ScheduleStatusFull}
+	soon := models.TourSchedule{TourID: src.ID, DepartureDate: today.AddDate(0, 0, 2), ReturnDate: today.AddDate(0, 0, 4),
+		AvailableSlots: 10, Status: constants.ScheduleStatusOpen}
+	cancelled := models.TourSchedule{TourID: src.ID, DepartureDate: today.AddDate(0, 0, 20), ReturnDate: today.AddDate(0, 0, 22),
+		AvailableSlots: 10, Status: constants.ScheduleStatusCancelled}
+	past := models.TourSchedule{TourID: src.ID, DepartureDate: today.AddDate(0, 0, -30), ReturnDate: today.AddDate(0, 0, -28),
+		AvailableSlots: 10, Status: constants.ScheduleStatusOpen}
+	require.NoError(t, db.Create([]*models.TourSchedule{&upcoming, &soon, &cancelled, &past}).Error)
+	an := seedUser(t, db, "an", constants.RoleUser)
+	binh := seedUser(t, db, "binh", constants.RoleUser)
+	require.NoError(t, db.Create(&[]models.Booking{
+		{UserID: an.ID, TourID: src.ID, ScheduleID: upcoming.ID, NumParticipants: 6, TotalPrice: 600, Status: constants.BookingStatusConfirmed},
+		{UserID: binh.ID, TourID: src.ID, ScheduleID: upcoming.ID, NumParticipants: 3, TotalPrice: 300, Status: constants.BookingStatusCancelled},
+	}).Error)
+
+	clone, err := svc.CloneTour(ctx, src.ID, &CloneForm{IncludeSchedules: true, OffsetDays: -5})
+	require.NoError(t, err)
+
+	var schedules []models.TourSchedule
+	require.NoError(t, db.Where("tour_id = ?", clone.ID).Find(&schedules).Error)
+	require.Len(t, schedules, 1)
+	assert.True(t, schedules[0].DepartureDate.Equal(upcoming.DepartureDate.AddDate(0, 0, -5)))
+	assert.Equal(t, 10, schedules[0].AvailableSlots)
+	assert.Equal(t, constants.ScheduleStatusOpen, schedules[0].Status)
+}
+
+// --- itinerary --------------------------------------------------------
+
+func TestUpdateTour_ReplacesItineraryInFormOrder(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	require.NoError(t, svc.CreateTour(ctx, itineraryForm("Sapa", "Lào Cai", "Cát Cát")))
+
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, itineraryForm("Sapa", "Fansipan", "Hàm Rồng", "Lào Cai")))
+
+	got, err := svc.GetTour(ctx, tour.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Itinerary, 3)
+	for i, title := range []string{"Fansipan", "Hàm Rồng", "Lào Cai"} {
+		assert.Equal(t, i+1, got.Itinerary[i].DayNumber)
+		assert.Equal(t, title, got.Itinerary[i].Title)
+	}
+	var count int64
+	require.NoError(t, db.Model(&models.TourItineraryDay{}).Where("tour_id = ?", tour.ID).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestUpdateTour_KeepsSavedItineraryWhenNotSubmitted(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	form := itineraryForm("Sapa", "Lào Cai", "Cát Cát")
+	en := englishTranslation("Sapa Trek")
+	en.ItineraryTitles = []string{"Lao Cai", "Cat Cat"}
+	en.ItineraryDescriptions = []string{"", ""}
+	en.ItineraryAccommodations = []string{"", ""}
+	form.Translations = []TranslationForm{en}
+	require.NoError(t, svc.CreateTour(ctx, form))
+
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+	update := tourForm("Sapa mùa lúa")
+	update.DurationDays = 2
+	update.Translations = []TranslationForm{englishTranslation("Sapa Rice Season")}
+	require.NoError(t, svc.UpdateTour(ctx, tour.ID, update))
+
+	got, err := svc.GetTour(ctx, tour.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Sapa mùa lúa", got.Title)
+	require.Len(t, got.Itinerary, 2)
+	assert.Equal(t, "Lào Cai", got.Itinerary[0].Title)
+	got.Localize(constants.LocaleEN)
+	assert.Equal(t, "Lao Cai", got.Itinerary[0].Title)
+}
+
+func TestUpdateTour_FailureRollsBackEveryChange(t *testing.T) {
+	svc, db := setupTourServiceDB(t)
+	ctx := context.Background()
+	require.NoError(t, svc.CreateTour(ctx, itineraryForm("Sapa", "Lào Cai", "Cát Cát")))
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+
+	// The unknown category is only caught inside the transaction, after the
+	// tour row has been saved.
+	form := itineraryForm("Sapa mới", "Fansipan")
+	form.CategoryIDs = []uint{99}
+	assertAppError(t, svc.UpdateTour(ctx, tour.ID, form), appErrors.ErrMsgTourCategoryNotFound)
+
+	got, err := svc.GetTour(ctx, tour.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Sapa", got.Title)
+	assert.Equal(t, "sapa", got.Slug)
+	assert.Len(t, got.Itinerary, 2)
+}
+
+// --- search -----------------------------------------------------------
+
+func createSearchTour(t *testing.T, svc *TourService, title, location, description string, price float64) {
+	t.Helper()
+	form := tourForm(title)
+	form.Location, form.Description, form.Price = location, description, price
+	require.NoError(t, svc.CreateTour(context.Background(), form))
+}
+
+func TestListTours_SearchIgnoresDiacriticsAndCase(t *testing.T) {
+	svc, _ := setupTourServiceDB(t)
+	createSearchTour(t, svc, "Vịnh Hạ Long", "Quảng Ninh", "", 100)
+	createSearchTour(t, svc, "Sa Pa", "Lào Cai", "", 100)
+
+	for _, q := range []string{"ha long", "HẠ LONG", "Hạ Long"} {
+		tours, total, _, err := svc.ListTours(context.Background(), repository.TourFilter{Search: q})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, total, q)
+		assert.Equal(t, []string{"Vịnh Hạ Long"}, tourTitles(tours), q)
+	}
+}
+
+func TestListTours_SearchOrdersByRelevanceFirst(t *testing.T) {
+	svc, _ := setupTourServiceDB(t)
+	// Created best match first, so newest-first order would reverse them.
+	createSearchTour(t, svc, "Hạ Long trong ngày", "Quảng Ninh", "", 300)
+	createSearchTour(t, svc, "Nghỉ dưỡng cuối tuần", "Hạ Long", "", 200)
+	createSearchTour(t, svc, "Du thuyền", "Cát Bà", "Ghé vịnh Hạ Long", 100)
+
+	tours, _, _, err := svc.ListTours(context.Background(), repository.TourFilter{Search: "Hạ Long"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hạ Long trong ngày", "Nghỉ dưỡng cuối tuần", "Du thuyền"}, tourTitles(tours))
+}
+
+func TestListTours_SearchWithExplicitSortSkipsRank(t *testing.T) {
+	svc, _ := setupTourServiceDB(t)
+	createSearchTour(t, svc, "Hạ Long trong ngày", "Quảng Ninh", "", 300)
+	createSearchTour(t, svc, "Nghỉ dưỡng cuối tuần", "Hạ Long", "", 200)
+	createSearchTour(t, svc, "Du thuyền", "Cát Bà", "Ghé vịnh Hạ Long", 100)
+	createSearchTour(t, svc, "Sa Pa", "Lào Cai", "", 50)
+
+	tours, _, _, err := svc.ListTours(context.Background(), repository.TourFilter{Search: "Hạ Long", SortBy: "price", SortOrder: "asc"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Du thuyền", "Nghỉ dưỡng cuối tuần", "Hạ Long trong ngày"}, tourTitles(tours))
+}
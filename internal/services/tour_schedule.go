@@ -25,12 +25,13 @@ type ScheduleForm struct {
 }
 
 type ScheduleService struct {
-	repo     repository.ScheduleRepo
-	tourRepo repository.TourRepo
+	repo      repository.ScheduleRepo
+	tourRepo  repository.TourRepo
+	guideRepo repository.ScheduleGuideRepo
 }
 
-func NewScheduleService(repo repository.ScheduleRepo, tourRepo repository.TourRepo) *ScheduleService {
-	return &ScheduleService{repo: repo, tourRepo: tourRepo}
+func NewScheduleService(repo repository.ScheduleRepo, tourRepo repository.TourRepo, guideRepo repository.ScheduleGuideRepo) *ScheduleService {
+	return &ScheduleService{repo: repo, tourRepo: tourRepo, guideRepo: guideRepo}
 }
 
 func (s *ScheduleService) ListByTour(ctx context.Context, tourID uint) ([]models.TourSchedule, error) {
@@ -127,6 +128,13 @@ func (s *ScheduleService) UpdateSchedule(ctx context.Context, id uint, form *Sch
 		return appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgScheduleInvalidStatus)
 	}
 
+	datesChanged := !departure.Equal(schedule.DepartureDate) || !returnDate.Equal(schedule.ReturnDate)
+	if datesChanged && form.Status != constants.ScheduleStatusCancelled {
+		if err := s.checkGuideConflicts(ctx, id, departure, returnDate); err != nil {
+			return err
+		}
+	}
+
 	schedule.DepartureDate = departure
 	schedule.ReturnDate = returnDate
 	schedule.AvailableSlots = form.AvailableSlots
@@ -162,6 +170,29 @@ func (s *ScheduleService) DeleteSchedule(ctx context.Context, id uint) error {
 	return nil
 }
 
+// checkGuideConflicts makes sure moving a schedule to new dates does not
+// double-book any of the guides already assigned to it.
+func (s *ScheduleService) checkGuideConflicts(ctx context.Context, scheduleID uint, departure, returnDate time.Time) error {
+	assignments, err := s.guideRepo.FindByScheduleID(ctx, scheduleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleServiceGuideCheck, err)
+	}
+	for _, a := range assignments {
+		conflict, err := s.guideRepo.HasConflict(ctx, a.GuideID, scheduleID, departure, returnDate)
+		if err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxScheduleServiceGuideCheck, err)
+		}
+		if conflict {
+			name := ""
+			if a.Guide != nil {
+				name = a.Guide.FullName
+			}
+			return appErrors.NewAppError(http.StatusConflict, fmt.Sprintf(appErrors.ErrMsgGuideScheduleConflict, name))
+		}
+	}
+	return nil
+}
+
 func isValidScheduleStatus(status string) bool {
 	return status == constants.ScheduleStatusOpen ||
 		status == constants.ScheduleStatusFull ||
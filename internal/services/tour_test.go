@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+type tourMocks struct {
+	tourRepo *testutil.MockTourRepo
+	catRepo  *testutil.MockCategoryRepo
+	slugRepo *testutil.MockSlugHistoryRepo
+}
+
+// setupTourService builds a TourService without a database; tests only
+// reach the paths that fail or return before a transaction opens.
+func setupTourService() (*TourService, tourMocks) {
+	m := tourMocks{
+		tourRepo: new(testutil.MockTourRepo),
+		catRepo:  new(testutil.MockCategoryRepo),
+		slugRepo: new(testutil.MockSlugHistoryRepo),
+	}
+	return NewTourService(nil, m.tourRepo, m.catRepo, m.slugRepo, nil), m
+}
+
+func tourForm(title string) *TourForm {
+	return &TourForm{
+		Title:           title,
+		Price:           100,
+		DurationDays:    2,
+		MaxParticipants: 10,
+		Status:          constants.TourStatusActive,
+	}
+}
+
+func itineraryForm(title string, days ...string) *TourForm {
+	form := tourForm(title)
+	form.DurationDays = len(days)
+	form.ItineraryTitles = days
+	form.ItineraryDescriptions = make([]string, len(days))
+	form.ItineraryMeals = make([]string, len(days))
+	form.ItineraryAccommodations = make([]string, len(days))
+	form.ItineraryImages = make([]string, len(days))
+	return form
+}
+
+func englishTranslation(title string) TranslationForm {
+	return TranslationForm{Locale: constants.LocaleEN, Name: title}
+}
+
+// --- lookups ----------------------------------------------------------
+
+func TestGetPublicTourBySlug_UnknownSlug_NotFound(t *testing.T) {
+	svc, m := setupTourService()
+	m.tourRepo.On("FindBySlugPublic", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := svc.GetPublicTourBySlug(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, appErrors.ErrTourNotFound)
+	m.tourRepo.AssertNotCalled(t, "CountRatingsByTourID", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTour_UnknownTour_NotFound(t *testing.T) {
+	svc, m := setupTourService()
+	m.tourRepo.On("FindByID", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := svc.UpdateTour(context.Background(), 9, tourForm("Huế"))
+
+	assert.ErrorIs(t, err, appErrors.ErrTourNotFound)
+}
+
+// --- facets -----------------------------------------------------------
+
+func TestListTours_NoFacetsUnlessRequested(t *testing.T) {
+	svc, m := setupTourService()
+	m.tourRepo.On("FindAll", mock.Anything, mock.Anything).Return([]models.Tour{}, int64(0), nil)
+
+	_, _, facets, err := svc.ListTours(context.Background(), repository.TourFilter{})
+
+	require.NoError(t, err)
+	assert.Nil(t, facets)
+	m.tourRepo.AssertNotCalled(t, "CountFacets", mock.Anything, mock.Anything)
+}
+
+func TestListTours_FacetsFromCounts(t *testing.T) {
+	svc, m := setupTourService()
+	filter := repository.TourFilter{IncludeFacets: true, CategorySlugs: []string{"nui"}, Location: "huế"}
+	m.tourRepo.On("FindAll", mock.Anything, filter).Return([]models.Tour{}, int64(0), nil)
+	m.tourRepo.On("CountFacets", mock.Anything, filter).Return(&repository.TourFacetCounts{
+		Categories: map[uint]int64{1: 3},
+		Locations:  []repository.LocationCount{{Location: "Huế", Count: 2}},
+		Prices:     make([]int64, len(repository.TourPriceRanges)),
+		Durations:  make([]int64, len(repository.TourDurationRanges)),
+		Ratings:    make([]int64, len(repository.TourRatingBands)),
+	}, nil)
+	m.catRepo.On("FindAll", mock.Anything).Return([]models.Category{
+		{ID: 1, Name: "Biển", Slug: "bien"},
+		{ID: 2, Name: "Núi", Slug: "nui"},
+		{ID: 3, Name: "Rừng", Slug: "rung"},
+	}, nil)
+
+	_, _, facets, err := svc.ListTours(context.Background(), filter)
+
+	require.NoError(t, err)
+	require.Len(t, facets.Categories, 2, "categories without tours are hidden unless selected")
+	assert.Equal(t, "Biển", facets.Categories[0].Label)
+	assert.Equal(t, int64(3), facets.Categories[0].Count)
+	assert.True(t, facets.Categories[1].Active)
+	require.Len(t, facets.Locations, 1)
+	assert.True(t, facets.Locations[0].Active)
+	assert.Len(t, facets.Prices, len(repository.TourPriceRanges))
+	assert.Empty(t, facets.CategoryMatch)
+}
+
+func TestBulkUpdateCategories_UnknownCategory(t *testing.T) {
+	svc, m := setupTourService()
+	m.catRepo.On("CountByIDs", mock.Anything, []uint{99}).Return(int64(0), nil)
+
+	_, err := svc.BulkUpdateCategories(context.Background(), &BulkCategoryForm{
+		Action: BulkCategoryAdd, TourIDs: []uint{1}, CategoryIDs: []uint{99},
+	})
+
+	assertAppError(t, err, appErrors.ErrMsgTourCategoryNotFound)
+	m.tourRepo.AssertNotCalled(t, "AddCategories", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// --- geo --------------------------------------------------------------
+
+func TestCreateTour_CoordinatesMustBeComplete(t *testing.T) {
+	svc, _ := setupTourService()
+	form := tourForm("Hoi An")
+	form.Latitude = "15.88"
+
+	err := svc.CreateTour(context.Background(), form)
+
+	assertAppError(t, err, appErrors.ErrMsgTourCoordinates)
+}
+
+func TestParseBBox_RejectsMalformed(t *testing.T) {
+	for _, raw := range []string{"1,2,3", "a,b,c,d", "0,50,10,40", "0,-91,10,10"} {
+		_, err := ParseBBox(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+// --- inclusions, meeting points, FAQ ------------------------------------
+
+func TestCreateTour_MeetingPointRejectsBadTime(t *testing.T) {
+	svc, _ := setupTourService()
+	form := tourForm("Mai Chau")
+	form.MeetingPointNames = []string{"Ben xe"}
+	form.MeetingPointAddrs = []string{""}
+	form.MeetingPointTimes = []string{"25:00"}
+	form.MeetingPointNotes = []string{""}
+
+	err := svc.CreateTour(context.Background(), form)
+
+	assertAppError(t, err, fmt.Sprintf(appErrors.ErrMsgTourMeetingTime, 1))
+}
+
+func TestPickupPoint_RequiresPointOnlyWhenTourHasThem(t *testing.T) {
+	label, err := PickupPoint(&models.Tour{}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, label)
+
+	tour := &models.Tour{MeetingPoints: []models.TourMeetingPoint{
+		{ID: 3, Name: "Nhà hát lớn", Address: "1 Tràng Tiền", PickupTime: "07:30"},
+	}}
+	_, err = PickupPoint(tour, 9)
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, appErrors.ErrMsgBookingPickupRequired, appErr.Message)
+
+	label, err = PickupPoint(tour, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "07:30 — Nhà hát lớn (1 Tràng Tiền)", label)
+}
+
+// --- translations -------------------------------------------------------
+
+func TestCreateTour_TranslatedSlugTakenByOtherTour(t *testing.T) {
+	svc, m := setupTourService()
+	m.tourRepo.On("ExistsBySlug", mock.Anything, "pho-co-hoi-an").Return(false, nil)
+	m.tourRepo.On("ExistsBySlugExcluding", mock.Anything, "hoi-an", uint(0)).Return(true, nil)
+
+	form := tourForm("Phố cổ Hội An")
+	form.Translations = []TranslationForm{englishTranslation("Hoi An")}
+	err := svc.CreateTour(context.Background(), form)
+
+	assertAppError(t, err, fmt.Sprintf(appErrors.ErrMsgTourTranslationSlugUsed, "EN"))
+	m.tourRepo.AssertExpectations(t)
+}
+
+func TestCreateTour_TranslationWithoutTitleRejected(t *testing.T) {
+	svc, _ := setupTourService()
+	form := tourForm("Phú Quốc")
+	form.Translations = []TranslationForm{{Locale: constants.LocaleEN, Description: "Island escape"}}
+
+	err := svc.CreateTour(context.Background(), form)
+
+	assertAppError(t, err, fmt.Sprintf(appErrors.ErrMsgTourTranslationTitle, "EN"))
+}
+
+// --- clone --------------------------------------------------------------
+
+func TestCloneTour_RejectsOffsetOutOfRange(t *testing.T) {
+	svc, m := setupTourService()
+
+	_, err := svc.CloneTour(context.Background(), 1, &CloneForm{IncludeSchedules: true, OffsetDays: cloneMaxOffsetDays + 1})
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	m.tourRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+// --- itinerary --------------------------------------------------------
+
+func TestBuildItinerary_DayCountMustMatchDuration(t *testing.T) {
+	form := itineraryForm("Sapa", "Lào Cai", "Cát Cát")
+	form.DurationDays = 3
+
+	_, err := buildItinerary(form)
+
+	assertAppError(t, err, fmt.Sprintf(appErrors.ErrMsgTourItineraryDayCount, 3))
+}
+
+func TestBuildItinerary_UnequalArraysRejected(t *testing.T) {
+	form := itineraryForm("Sapa", "Lào Cai", "Cát Cát")
+	form.ItineraryMeals = []string{"lunch"}
+
+	_, err := buildItinerary(form)
+
+	assertAppError(t, err, appErrors.ErrMsgTourItineraryInvalid)
+}
+
+func TestBuildItinerary_MissingDayTitleRejected(t *testing.T) {
+	form := itineraryForm("Sapa", "Lào Cai", "  ")
+
+	_, err := buildItinerary(form)
+
+	assertAppError(t, err, fmt.Sprintf(appErrors.ErrMsgTourItineraryDayTitle, 2))
+}
+
+func TestBuildItinerary_ParsesMealsAndImages(t *testing.T) {
+	form := itineraryForm("Sapa", "Lào Cai", "Cát Cát")
+	form.ItineraryMeals = []string{"breakfast, dinner,snack", ""}
+	form.ItineraryImages = []string{"https://a.example/1.jpg\n\nhttps://a.example/2.jpg", ""}
+
+	days, err := buildItinerary(form)
+
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.True(t, days[0].Breakfast)
+	assert.False(t, days[0].Lunch)
+	assert.True(t, days[0].Dinner)
+	assert.JSONEq(t, `["https://a.example/1.jpg","https://a.example/2.jpg"]`, string(days[0].Images))
+	assert.False(t, days[1].Breakfast || days[1].Lunch || days[1].Dinner)
+}
+
+func TestUpdateTour_KeptItineraryMustMatchDuration(t *testing.T) {
+	svc, m := setupTourService()
+	m.tourRepo.On("FindByID", mock.Anything, uint(1)).Return(&models.Tour{
+		ID:        1,
+		Itinerary: []models.TourItineraryDay{{DayNumber: 1, Title: "Lào Cai"}, {DayNumber: 2, Title: "Cát Cát"}},
+	}, nil)
+	form := tourForm("Sapa")
+	form.DurationDays = 3
+
+	err := svc.UpdateTour(context.Background(), 1, form)
+
+	assertAppError(t, err, fmt.Sprintf(appErrors.ErrMsgTourItineraryDayCount, 3))
+	m.tourRepo.AssertNotCalled(t, "ExistsBySlugExcluding", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/utils"
+)
+
+// TranslationForm holds the text of a tour or category in one locale other
+// than the default. Name is the tour title or the category name. The
+// itinerary arrays are used for tours only and run parallel to the
+// default-locale itinerary days.
+type TranslationForm struct {
+	Locale      string
+	Name        string
+	Slug        string
+	Description string
+
+	ItineraryTitles         []string
+	ItineraryDescriptions   []string
+	ItineraryAccommodations []string
+}
+
+// translatedText is a trimmed translation with its slug resolved.
+type translatedText struct {
+	name, slug, description string
+}
+
+// parseTranslation trims form and derives the slug from the custom slug or
+// the name. filled is false when nothing was entered for the locale.
+// nameMsg and slugMsg are the entity's error formats, taking the locale.
+func parseTranslation(form TranslationForm, hasRows bool, nameMsg, slugMsg string) (text translatedText, filled bool, err error) {
+	label := strings.ToUpper(form.Locale)
+	text = translatedText{
+		name:        strings.TrimSpace(form.Name),
+		description: strings.TrimSpace(form.Description),
+	}
+	custom := strings.TrimSpace(form.Slug)
+	if text.name == "" && text.description == "" && custom == "" && !hasRows {
+		return text, false, nil
+	}
+	if text.name == "" {
+		return text, true, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(nameMsg, label))
+	}
+	if custom == "" {
+		custom = text.name
+	}
+	if text.slug = utils.Slugify(custom); text.slug == "" {
+		return text, true, appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(slugMsg, label))
+	}
+	return text, true, nil
+}
+
+func isTranslatedLocale(locale string) bool {
+	return locale != constants.DefaultLocale && utils.IsSupportedLocale(locale)
+}
+
+// buildTourTranslations turns the per-locale form sections into rows. A
+// locale left blank is dropped; one with any text needs a title. days is the
+// number of default-locale itinerary days the translated days must fit in.
+func buildTourTranslations(forms []TranslationForm, days int) ([]models.TourTranslation, []models.TourItineraryTranslation, error) {
+	var translations []models.TourTranslation
+	var itinerary []models.TourItineraryTranslation
+
+	for _, form := range forms {
+		invalid := appErrors.NewAppError(http.StatusBadRequest, fmt.Sprintf(appErrors.ErrMsgTourTranslationInvalid, strings.ToUpper(form.Locale)))
+		if !isTranslatedLocale(form.Locale) {
+			return nil, nil, invalid
+		}
+		n := len(form.ItineraryTitles)
+		if len(form.ItineraryDescriptions) != n || len(form.ItineraryAccommodations) != n {
+			return nil, nil, invalid
+		}
+
+		var rows []models.TourItineraryTranslation
+		for i := range n {
+			row := models.TourItineraryTranslation{
+				Locale:        form.Locale,
+				DayNumber:     i + 1,
+				Title:         strings.TrimSpace(form.ItineraryTitles[i]),
+				Description:   strings.TrimSpace(form.ItineraryDescriptions[i]),
+				Accommodation: strings.TrimSpace(form.ItineraryAccommodations[i]),
+			}
+			if row.Title == "" && row.Description == "" && row.Accommodation == "" {
+				continue
+			}
+			if row.DayNumber > days {
+				return nil, nil, invalid
+			}
+			rows = append(rows, row)
+		}
+
+		text, filled, err := parseTranslation(form, len(rows) > 0, appErrors.ErrMsgTourTranslationTitle, appErrors.ErrMsgTourTranslationSlug)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !filled {
+			continue
+		}
+		translations = append(translations, models.TourTranslation{
+			Locale:      form.Locale,
+			Title:       text.name,
+			Slug:        text.slug,
+			Description: text.description,
+		})
+		itinerary = append(itinerary, rows...)
+	}
+	return translations, itinerary, nil
+}
+
+// buildCategoryTranslations is buildTourTranslations for categories.
+func buildCategoryTranslations(forms []TranslationForm) ([]models.CategoryTranslation, error) {
+	var translations []models.CategoryTranslation
+	for _, form := range forms {
+		if !isTranslatedLocale(form.Locale) {
+			continue
+		}
+		text, filled, err := parseTranslation(form, false, appErrors.ErrMsgCategoryTranslationName, appErrors.ErrMsgCategoryTranslationSlug)
+		if err != nil {
+			return nil, err
+		}
+		if !filled {
+			continue
+		}
+		translations = append(translations, models.CategoryTranslation{
+			Locale:      form.Locale,
+			Name:        text.name,
+			Slug:        text.slug,
+			Description: text.description,
+		})
+	}
+	return translations, nil
+}
+
+// checkTourTranslationSlugs rejects translated slugs that another tour
+// already uses in any locale. tourID is 0 for a new tour.
+func (s *TourService) checkTourTranslationSlugs(ctx context.Context, tourID uint, translations []models.TourTranslation) error {
+	for _, tr := range translations {
+		taken, err := s.repo.ExistsBySlugExcluding(ctx, tr.Slug, tourID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceTranslations, err)
+		}
+		if taken {
+			return appErrors.NewAppError(http.StatusConflict, fmt.Sprintf(appErrors.ErrMsgTourTranslationSlugUsed, strings.ToUpper(tr.Locale)))
+		}
+	}
+	return nil
+}
+
+// saveTourTranslations stores the tour's translations and keeps replaced
+// translated slugs in the slug history so their links keep redirecting.
+func (s *TourService) saveTourTranslations(ctx context.Context, tourID uint, old []models.TourTranslation, translations []models.TourTranslation, itinerary []models.TourItineraryTranslation) error {
+	if err := s.repo.ReplaceTranslations(ctx, tourID, translations, itinerary); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceTranslations, err)
+	}
+	oldSlugs := make(map[string]string, len(old))
+	for _, tr := range old {
+		oldSlugs[tr.Locale] = tr.Slug
+	}
+	newSlugs := make(map[string]string, len(translations))
+	for _, tr := range translations {
+		newSlugs[tr.Locale] = tr.Slug
+	}
+	for _, locale := range utils.TranslatedLocales() {
+		if oldSlugs[locale] == newSlugs[locale] {
+			continue
+		}
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityTour, tourID, oldSlugs[locale], newSlugs[locale]); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxTourServiceSlugHistory, err)
+		}
+	}
+	return nil
+}
+
+// checkCategoryTranslationSlugs rejects translated slugs that another
+// category already uses in any locale. categoryID is 0 for a new category.
+func (s *CategoryService) checkCategoryTranslationSlugs(ctx context.Context, categoryID uint, translations []models.CategoryTranslation) error {
+	for _, tr := range translations {
+		taken, err := s.repo.ExistsBySlugExcluding(ctx, tr.Slug, categoryID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceTranslations, err)
+		}
+		if taken {
+			return appErrors.NewAppError(http.StatusConflict, fmt.Sprintf(appErrors.ErrMsgCategoryTranslationSlugUsed, strings.ToUpper(tr.Locale)))
+		}
+	}
+	return nil
+}
+
+// saveCategoryTranslations is saveTourTranslations for categories.
+func (s *CategoryService) saveCategoryTranslations(ctx context.Context, categoryID uint, old []models.CategoryTranslation, translations []models.CategoryTranslation) error {
+	if err := s.repo.ReplaceTranslations(ctx, categoryID, translations); err != nil {
+		return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceTranslations, err)
+	}
+	oldSlugs := make(map[string]string, len(old))
+	for _, tr := range old {
+		oldSlugs[tr.Locale] = tr.Slug
+	}
+	newSlugs := make(map[string]string, len(translations))
+	for _, tr := range translations {
+		newSlugs[tr.Locale] = tr.Slug
+	}
+	for _, locale := range utils.TranslatedLocales() {
+		if oldSlugs[locale] == newSlugs[locale] {
+			continue
+		}
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityCategory, categoryID, oldSlugs[locale], newSlugs[locale]); err != nil {
+			return fmt.Errorf("%s: %w", appErrors.ErrCtxCategoryServiceSlugHistory, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// TrashItem is one soft-deleted record as the admin trash lists it. Detail
+// is the slug of a tour or category and the author of a review.
+type TrashItem struct {
+	ID        uint
+	Title     string
+	Detail    string
+	DeletedAt time.Time
+	PurgeAt   time.Time
+}
+
+// RestoredItem describes a restored record. SlugChanged is set when its
+// slug had been taken by a live record and Slug is the replacement.
+type RestoredItem struct {
+	Title       string
+	Slug        string
+	SlugChanged bool
+}
+
+type TrashService struct {
+	db        *gorm.DB
+	repo      repository.TrashRepo
+	tourRepo  repository.TourRepo
+	catRepo   repository.CategoryRepo
+	slugRepo  repository.SlugHistoryRepo
+	retention time.Duration
+}
+
+// NewTrashService returns a service whose deleted records are purged once
+// they have been in the trash for longer than retention.
+func NewTrashService(db *gorm.DB, repo repository.TrashRepo, tourRepo repository.TourRepo, catRepo repository.CategoryRepo, slugRepo repository.SlugHistoryRepo, retention time.Duration) *TrashService {
+	return &TrashService{db: db, repo: repo, tourRepo: tourRepo, catRepo: catRepo, slugRepo: slugRepo, retention: retention}
+}
+
+// withTx returns the service with its repositories bound to tx.
+func (s *TrashService) withTx(tx *gorm.DB) *TrashService {
+	return &TrashService{
+		db:        tx,
+		repo:      repository.NewTrashRepository(tx),
+		tourRepo:  repository.NewTourRepository(tx),
+		catRepo:   repository.NewCategoryRepository(tx),
+		slugRepo:  repository.NewSlugHistoryRepository(tx),
+		retention: s.retention,
+	}
+}
+
+// IsTrashKind reports whether kind names a trash listing.
+func IsTrashKind(kind string) bool {
+	switch kind {
+	case constants.TrashKindTours, constants.TrashKindCategories, constants.TrashKindReviews:
+		return true
+	}
+	return false
+}
+
+// List returns one page of the trashed records of kind, most recently
+// deleted first.
+func (s *TrashService) List(ctx context.Context, kind string, page, limit int) ([]TrashItem, int64, error) {
+	var items []TrashItem
+	var total int64
+	var err error
+	switch kind {
+	case constants.TrashKindTours:
+		tours, n, findErr := s.repo.FindTours(ctx, page, limit)
+		for _, t := range tours {
+			items = append(items, s.item(t.ID, t.Title, t.Slug, t.DeletedAt))
+		}
+		total, err = n, findErr
+	case constants.TrashKindCategories:
+		cats, n, findErr := s.repo.FindCategories(ctx, page, limit)
+		for _, c := range cats {
+			items = append(items, s.item(c.ID, c.Name, c.Slug, c.DeletedAt))
+		}
+		total, err = n, findErr
+	case constants.TrashKindReviews:
+		reviews, n, findErr := s.repo.FindReviews(ctx, page, limit)
+		for _, r := range reviews {
+			author := ""
+			if r.User != nil {
+				author = r.User.FullName
+			}
+			items = append(items, s.item(r.ID, r.Title, author, r.DeletedAt))
+		}
+		total, err = n, findErr
+	default:
+		return nil, 0, appErrors.ErrTrashKind
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashServiceList, err)
+	}
+	return items, total, nil
+}
+
+func (s *TrashService) item(id uint, title, detail string, deletedAt gorm.DeletedAt) TrashItem {
+	return TrashItem{
+		ID:        id,
+		Title:     title,
+		Detail:    detail,
+		DeletedAt: deletedAt.Time,
+		PurgeAt:   deletedAt.Time.Add(s.retention),
+	}
+}
+
+// Restore brings a record of kind back. A tour or category whose slugs were
+// taken by live records while it was deleted gets numbered variants, and a
+// category whose parent is gone moves to the top level. Picking the slugs
+// and restoring happen in one transaction.
+func (s *TrashService) Restore(ctx context.Context, kind string, id uint) (*RestoredItem, error) {
+	var restored *RestoredItem
+	var err error
+	switch kind {
+	case constants.TrashKindTours:
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			restored, err = s.withTx(tx).restoreTour(ctx, id)
+			return err
+		})
+	case constants.TrashKindCategories:
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			restored, err = s.withTx(tx).restoreCategory(ctx, id)
+			return err
+		})
+	case constants.TrashKindReviews:
+		restored, err = s.restoreReview(ctx, id)
+	default:
+		return nil, appErrors.ErrTrashKind
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErrors.ErrTrashNotFound
+	}
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashServiceRestore, err)
+	}
+	return restored, nil
+}
+
+// freeSlugs picks a free variant of every slug in turn. taken checks live
+// records; slugs picked earlier in the same call count as taken too.
+func freeSlugs(slugs []string, taken func(string) (bool, error)) ([]string, error) {
+	used := make(map[string]bool, len(slugs))
+	out := make([]string, len(slugs))
+	for i, slug := range slugs {
+		free, err := uniqueSlug(slug, func(s string) (bool, error) {
+			if used[s] {
+				return true, nil
+			}
+			return taken(s)
+		})
+		if err != nil {
+			return nil, err
+		}
+		used[free] = true
+		out[i] = free
+	}
+	return out, nil
+}
+
+func (s *TrashService) restoreTour(ctx context.Context, id uint) (*RestoredItem, error) {
+	tour, err := s.repo.FindTour(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	slugs := []string{tour.Slug}
+	for _, tr := range tour.Translations {
+		slugs = append(slugs, tr.Slug)
+	}
+	free, err := freeSlugs(slugs, func(slug string) (bool, error) {
+		return s.tourRepo.ExistsBySlugExcluding(ctx, slug, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	restored := &RestoredItem{Title: tour.Title, Slug: free[0], SlugChanged: free[0] != tour.Slug}
+	tour.Slug = free[0]
+	for i := range tour.Translations {
+		tour.Translations[i].Slug = free[i+1]
+	}
+
+	if err := s.repo.RestoreTour(ctx, tour); err != nil {
+		return nil, err
+	}
+	for _, slug := range free {
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityTour, id, "", slug); err != nil {
+			return nil, err
+		}
+	}
+	return restored, nil
+}
+
+func (s *TrashService) restoreCategory(ctx context.Context, id uint) (*RestoredItem, error) {
+	cat, err := s.repo.FindCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	slugs := []string{cat.Slug}
+	for _, tr := range cat.Translations {
+		slugs = append(slugs, tr.Slug)
+	}
+	free, err := freeSlugs(slugs, func(slug string) (bool, error) {
+		return s.catRepo.ExistsBySlugExcluding(ctx, slug, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	restored := &RestoredItem{Title: cat.Name, Slug: free[0], SlugChanged: free[0] != cat.Slug}
+	cat.Slug = free[0]
+	for i := range cat.Translations {
+		cat.Translations[i].Slug = free[i+1]
+	}
+
+	if err := s.repo.RestoreCategory(ctx, cat); err != nil {
+		return nil, err
+	}
+	if cat.ParentID != nil {
+		if _, err := s.catRepo.FindByID(ctx, *cat.ParentID); errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := s.catRepo.Move(ctx, id, nil); err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	for _, slug := range free {
+		if err := s.slugRepo.RecordChange(ctx, constants.SlugEntityCategory, id, "", slug); err != nil {
+			return nil, err
+		}
+	}
+	return restored, nil
+}
+
+func (s *TrashService) restoreReview(ctx context.Context, id uint) (*RestoredItem, error) {
+	review, err := s.repo.FindReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RestoreReview(ctx, id); err != nil {
+		return nil, err
+	}
+	return &RestoredItem{Title: review.Title}, nil
+}
+
+// Purge permanently deletes a trashed record of kind and returns its title.
+// Tours that were ever booked cannot be purged.
+func (s *TrashService) Purge(ctx context.Context, kind string, id uint) (string, error) {
+	var title string
+	var err error
+	switch kind {
+	case constants.TrashKindTours:
+		title, err = s.purgeTour(ctx, id)
+	case constants.TrashKindCategories:
+		title, err = s.purgeCategory(ctx, id)
+	case constants.TrashKindReviews:
+		title, err = s.purgeReview(ctx, id)
+	default:
+		return "", appErrors.ErrTrashKind
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", appErrors.ErrTrashNotFound
+	}
+	if err != nil {
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			return "", err
+		}
+		return "", fmt.Errorf("%s: %w", appErrors.ErrCtxTrashServicePurge, err)
+	}
+	return title, nil
+}
+
+func (s *TrashService) purgeTour(ctx context.Context, id uint) (string, error) {
+	tour, err := s.repo.FindTour(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	booked, err := s.repo.TourHasBookings(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if booked {
+		return "", appErrors.NewAppError(http.StatusBadRequest, appErrors.ErrMsgTrashTourHasBookings)
+	}
+	return tour.Title, s.repo.PurgeTour(ctx, id)
+}
+
+func (s *TrashService) purgeCategory(ctx context.Context, id uint) (string, error) {
+	cat, err := s.repo.FindCategory(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return cat.Name, s.repo.PurgeCategory(ctx, id)
+}
+
+func (s *TrashService) purgeReview(ctx context.Context, id uint) (string, error) {
+	review, err := s.repo.FindReview(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return review.Title, s.repo.PurgeReview(ctx, id)
+}
+
+// PurgeExpired permanently deletes the records that have been in the trash
+// for longer than the retention period and returns how many went. Booked
+// tours are left in the trash.
+func (s *TrashService) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	before := now.Add(-s.retention)
+	purged := 0
+	for _, kind := range []string{constants.TrashKindReviews, constants.TrashKindTours, constants.TrashKindCategories} {
+		ids, err := s.repo.ExpiredIDs(ctx, kind, before)
+		if err != nil {
+			return purged, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashServiceExpired, err)
+		}
+		for _, id := range ids {
+			_, err := s.Purge(ctx, kind, id)
+			var appErr *appErrors.AppError
+			if errors.As(err, &appErr) {
+				continue
+			}
+			if err != nil {
+				return purged, fmt.Errorf("%s: %w", appErrors.ErrCtxTrashServiceExpired, err)
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,197 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupTrashServiceDB(t *testing.T) (*TrashService, *TourService, *gorm.DB) {
+	t.Helper()
+	db := testutil.PostgresDB(t)
+
+	tourRepo := repository.NewTourRepository(db)
+	catRepo := repository.NewCategoryRepository(db)
+	slugRepo := repository.NewSlugHistoryRepository(db)
+	trash := NewTrashService(db, repository.NewTrashRepository(db), tourRepo, catRepo, slugRepo, testTrashRetention)
+	return trash, NewTourService(db, tourRepo, catRepo, slugRepo, nil), db
+}
+
+func createTrashedTour(t *testing.T, tours *TourService, db *gorm.DB, title string) models.Tour {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, tours.CreateTour(ctx, tourForm(title)))
+	var tour models.Tour
+	require.NoError(t, db.Order("id DESC").First(&tour).Error)
+	require.NoError(t, tours.DeleteTour(ctx, tour.ID))
+	return tour
+}
+
+// --- Restore --------------------------------------------------------
+
+func TestTrashRestore_TourWhoseSlugWasReusedGetsNewSlug(t *testing.T) {
+	trash, tours, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	old := createTrashedTour(t, tours, db, "Cat Ba")
+	require.NoError(t, tours.CreateTour(ctx, tourForm("Cat Ba")))
+
+	restored, err := trash.Restore(ctx, constants.TrashKindTours, old.ID)
+
+	require.NoError(t, err)
+	assert.True(t, restored.SlugChanged)
+	assert.Equal(t, "cat-ba-2", restored.Slug)
+	got, err := tours.GetTour(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "cat-ba-2", got.Slug)
+	items, total, err := trash.List(ctx, constants.TrashKindTours, 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, items)
+}
+
+func TestTrashRestore_TourKeepsFreeSlug(t *testing.T) {
+	trash, tours, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	old := createTrashedTour(t, tours, db, "Mu Cang Chai")
+
+	restored, err := trash.Restore(ctx, constants.TrashKindTours, old.ID)
+
+	require.NoError(t, err)
+	assert.False(t, restored.SlugChanged)
+	got, _, err := tours.GetPublicTourBySlug(ctx, "mu-cang-chai")
+	require.NoError(t, err)
+	assert.Equal(t, old.ID, got.ID)
+}
+
+func TestCreateTour_TranslatedSlugOfTrashedTourIsTaken(t *testing.T) {
+	_, tours, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	form := tourForm("Phố cổ Hội An")
+	form.Translations = []TranslationForm{englishTranslation("Hoi An Old Town")}
+	require.NoError(t, tours.CreateTour(ctx, form))
+	var old models.Tour
+	require.NoError(t, db.First(&old).Error)
+	require.NoError(t, tours.DeleteTour(ctx, old.ID))
+
+	// The translation slug index still holds the trashed tour's English
+	// slug, so the check must report it instead of the insert failing.
+	other := tourForm("Hội An về đêm")
+	other.Translations = form.Translations
+	err := tours.CreateTour(ctx, other)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, fmt.Sprintf(appErrors.ErrMsgTourTranslationSlugUsed, "EN"), appErr.Message)
+}
+
+func TestTrashRestore_CategoryWithDeletedParentMovesToTop(t *testing.T) {
+	trash, _, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	parent := models.Category{Name: "Miền Bắc", Slug: "mien-bac"}
+	require.NoError(t, db.Create(&parent).Error)
+	child := models.Category{Name: "Tây Bắc", Slug: "tay-bac", ParentID: &parent.ID}
+	require.NoError(t, db.Create(&child).Error)
+	require.NoError(t, db.Delete(&child).Error)
+	require.NoError(t, db.Delete(&parent).Error)
+
+	_, err := trash.Restore(ctx, constants.TrashKindCategories, child.ID)
+
+	require.NoError(t, err)
+	var got models.Category
+	require.NoError(t, db.First(&got, child.ID).Error)
+	assert.Nil(t, got.ParentID)
+}
+
+func TestTrashRestore_LiveRecordIsNotInTrash(t *testing.T) {
+	trash, tours, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	require.NoError(t, tours.CreateTour(ctx, tourForm("Ninh Binh")))
+	var tour models.Tour
+	require.NoError(t, db.First(&tour).Error)
+
+	_, err := trash.Restore(ctx, constants.TrashKindTours, tour.ID)
+
+	assert.ErrorIs(t, err, appErrors.ErrTrashNotFound)
+}
+
+// --- Purge ----------------------------------------------------------
+
+func TestTrashPurge_BookedTourIsKept(t *testing.T) {
+	trash, tours, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	tour := createTrashedTour(t, tours, db, "Con Dao")
+	traveller := seedUser(t, db, "traveller", constants.RoleUser)
+	seedBookingOn(t, db, traveller.ID, tour.ID, time.Now().AddDate(0, -1, 0), constants.BookingStatusCompleted)
+
+	_, err := trash.Purge(ctx, constants.TrashKindTours, tour.ID)
+
+	var appErr *appErrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, appErrors.ErrMsgTrashTourHasBookings, appErr.Message)
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&models.Tour{}).Count(&count).Error)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestTrashPurge_TourRemovesItsRows(t *testing.T) {
+	trash, tours, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	tour := createTrashedTour(t, tours, db, "Quy Nhon")
+	schedule := models.TourSchedule{TourID: tour.ID, DepartureDate: time.Now(), ReturnDate: time.Now(), AvailableSlots: 5}
+	require.NoError(t, db.Create(&schedule).Error)
+	guide := seedUser(t, db, "guide", constants.RoleGuide)
+	saver := seedUser(t, db, "saver", constants.RoleUser)
+	require.NoError(t, db.Create(&models.ScheduleGuide{ScheduleID: schedule.ID, GuideID: guide.ID}).Error)
+	require.NoError(t, db.Create(&models.Wishlist{UserID: saver.ID, TourID: tour.ID, NotifiedAt: time.Now()}).Error)
+
+	title, err := trash.Purge(ctx, constants.TrashKindTours, tour.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Quy Nhon", title)
+	for _, model := range []any{&models.Tour{}, &models.TourSchedule{}, &models.ScheduleGuide{}, &models.Wishlist{}} {
+		var count int64
+		require.NoError(t, db.Unscoped().Model(model).Count(&count).Error)
+		assert.Zero(t, count)
+	}
+}
+
+func TestTrashPurgeExpired_OnlyPastRetention(t *testing.T) {
+	trash, _, db := setupTrashServiceDB(t)
+	ctx := context.Background()
+	now := time.Now()
+	author := seedUser(t, db, "author", constants.RoleUser)
+	reader := seedUser(t, db, "reader", constants.RoleUser)
+	old := models.Review{UserID: author.ID, Title: "Cũ", Content: "x", Type: "place"}
+	recent := models.Review{UserID: author.ID, Title: "Mới", Content: "x", Type: "place"}
+	require.NoError(t, db.Create(&old).Error)
+	require.NoError(t, db.Create(&recent).Error)
+	require.NoError(t, db.Create(&models.Comment{UserID: reader.ID, ReviewID: old.ID, Content: "hay"}).Error)
+	require.NoError(t, db.Model(&old).Update("deleted_at", now.Add(-testTrashRetention-time.Hour)).Error)
+	require.NoError(t, db.Model(&recent).Update("deleted_at", now.Add(-time.Hour)).Error)
+
+	purged, err := trash.PurgeExpired(ctx, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	var ids []uint
+	require.NoError(t, db.Unscoped().Model(&models.Review{}).Order("id").Pluck("id", &ids).Error)
+	assert.Equal(t, []uint{recent.ID}, ids)
+	var comments int64
+	require.NoError(t, db.Model(&models.Comment{}).Count(&comments).Error)
+	assert.Zero(t, comments)
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const testTrashRetention = 30 * 24 * time.Hour
+
+// --- helpers ----------------------------------------------------------
+
+func setupTrashService() (*TrashService, *testutil.MockTrashRepo) {
+	repo := new(testutil.MockTrashRepo)
+	svc := NewTrashService(nil, repo, new(testutil.MockTourRepo), new(testutil.MockCategoryRepo), new(testutil.MockSlugHistoryRepo), testTrashRetention)
+	return svc, repo
+}
+
+func deletedAt(at time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: at, Valid: true}
+}
+
+// --- List -------------------------------------------------------------
+
+func TestTrashList_PurgeDateFollowsRetention(t *testing.T) {
+	svc, repo := setupTrashService()
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	repo.On("FindReviews", mock.Anything, 1, 10).Return([]models.Review{
+		{ID: 4, Title: "Bún chả", User: &models.User{FullName: "Lan"}, DeletedAt: deletedAt(at)},
+		{ID: 5, Title: "Phở", DeletedAt: deletedAt(at)},
+	}, int64(2), nil)
+
+	items, total, err := svc.List(context.Background(), constants.TrashKindReviews, 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Lan", items[0].Detail)
+	assert.Empty(t, items[1].Detail)
+	assert.Equal(t, at.Add(testTrashRetention), items[0].PurgeAt)
+}
+
+func TestTrash_UnknownKind(t *testing.T) {
+	svc, repo := setupTrashService()
+	ctx := context.Background()
+
+	_, _, listErr := svc.List(ctx, "bookings", 1, 10)
+	_, restoreErr := svc.Restore(ctx, "bookings", 1)
+	_, purgeErr := svc.Purge(ctx, "bookings", 1)
+
+	assert.ErrorIs(t, listErr, appErrors.ErrTrashKind)
+	assert.ErrorIs(t, restoreErr, appErrors.ErrTrashKind)
+	assert.ErrorIs(t, purgeErr, appErrors.ErrTrashKind)
+	assert.Empty(t, repo.Calls)
+}
+
+// --- Restore ----------------------------------------------------------
+
+func TestTrashRestore_Review(t *testing.T) {
+	t.Run("restores a trashed review", func(t *testing.T) {
+		svc, repo := setupTrashService()
+		repo.On("FindReview", mock.Anything, uint(4)).Return(&models.Review{ID: 4, Title: "Bún chả"}, nil)
+		repo.On("RestoreReview", mock.Anything, uint(4)).Return(nil)
+
+		restored, err := svc.Restore(context.Background(), constants.TrashKindReviews, 4)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bún chả", restored.Title)
+		assert.False(t, restored.SlugChanged)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("not in the trash", func(t *testing.T) {
+		svc, repo := setupTrashService()
+		repo.On("FindReview", mock.Anything, uint(4)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Restore(context.Background(), constants.TrashKindReviews, 4)
+
+		assert.ErrorIs(t, err, appErrors.ErrTrashNotFound)
+		repo.AssertNotCalled(t, "RestoreReview", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFreeSlugs_NumbersTakenAndRepeatedSlugs(t *testing.T) {
+	live := map[string]bool{"ha-long": true}
+
+	got, err := freeSlugs([]string{"ha-long", "ha-long-bay", "ha-long-bay"}, func(slug string) (bool, error) {
+		return live[slug], nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ha-long-2", "ha-long-bay", "ha-long-bay-2"}, got)
+}
+
+// --- Purge ------------------------------------------------------------
+
+func TestTrashPurge_Tour(t *testing.T) {
+	t.Run("booked tour is kept", func(t *testing.T) {
+		svc, repo := setupTrashService()
+		repo.On("FindTour", mock.Anything, uint(2)).Return(&models.Tour{ID: 2, Title: "Con Dao"}, nil)
+		repo.On("TourHasBookings", mock.Anything, uint(2)).Return(true, nil)
+
+		_, err := svc.Purge(context.Background(), constants.TrashKindTours, 2)
+
+		assertAppError(t, err, appErrors.ErrMsgTrashTourHasBookings)
+		repo.AssertNotCalled(t, "PurgeTour", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unbooked tour is purged", func(t *testing.T) {
+		svc, repo := setupTrashService()
+		repo.On("FindTour", mock.Anything, uint(2)).Return(&models.Tour{ID: 2, Title: "Quy Nhon"}, nil)
+		repo.On("TourHasBookings", mock.Anything, uint(2)).Return(false, nil)
+		repo.On("PurgeTour", mock.Anything, uint(2)).Return(nil)
+
+		title, err := svc.Purge(context.Background(), constants.TrashKindTours, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Quy Nhon", title)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestTrashPurgeExpired_SkipsBookedTours(t *testing.T) {
+	svc, repo := setupTrashService()
+	now := time.Now()
+	before := now.Add(-testTrashRetention)
+	repo.On("ExpiredIDs", mock.Anything, constants.TrashKindReviews, before).Return([]uint{4}, nil)
+	repo.On("ExpiredIDs", mock.Anything, constants.TrashKindTours, before).Return([]uint{2, 3}, nil)
+	repo.On("ExpiredIDs", mock.Anything, constants.TrashKindCategories, before).Return([]uint{}, nil)
+	repo.On("FindReview", mock.Anything, uint(4)).Return(&models.Review{ID: 4}, nil)
+	repo.On("PurgeReview", mock.Anything, uint(4)).Return(nil)
+	repo.On("FindTour", mock.Anything, uint(2)).Return(&models.Tour{ID: 2}, nil)
+	repo.On("TourHasBookings", mock.Anything, uint(2)).Return(true, nil)
+	repo.On("FindTour", mock.Anything, uint(3)).Return(&models.Tour{ID: 3}, nil)
+	repo.On("TourHasBookings", mock.Anything, uint(3)).Return(false, nil)
+	repo.On("PurgeTour", mock.Anything, uint(3)).Return(nil)
+
+	purged, err := svc.PurgeExpired(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	repo.AssertNotCalled(t, "PurgeTour", mock.Anything, uint(2))
+	repo.AssertExpectations(t)
+}
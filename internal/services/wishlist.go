@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"sun-booking-tours/internal/constants"
+	appErrors "sun-booking-tours/internal/errors"
+	"sun-booking-tours/internal/messages"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+)
+
+// WishlistService manages the tours users saved and emails them when a
+// saved tour gets a new schedule or a lower price.
+type WishlistService struct {
+	repo         repository.WishlistRepo
+	emailService *EmailService
+	baseURL      string
+}
+
+func NewWishlistService(repo repository.WishlistRepo, emailService *EmailService, baseURL string) *WishlistService {
+	return &WishlistService{repo: repo, emailService: emailService, baseURL: baseURL}
+}
+
+// Toggle saves the tour for the user, or removes it if already saved, and
+// reports whether it is saved afterwards.
+func (s *WishlistService) Toggle(ctx context.Context, userID uint, tour *models.Tour) (bool, error) {
+	exists, err := s.repo.Exists(ctx, userID, tour.ID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceToggle, err)
+	}
+
+	if exists {
+		if err := s.repo.Delete(ctx, userID, tour.ID); err != nil {
+			return false, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceToggle, err)
+		}
+		return false, nil
+	}
+
+	item := &models.Wishlist{
+		UserID:        userID,
+		TourID:        tour.ID,
+		NotifiedPrice: tour.Price,
+		NotifiedAt:    time.Now(),
+	}
+	if err := s.repo.Create(ctx, item); err != nil {
+		return false, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceToggle, err)
+	}
+	return true, nil
+}
+
+func (s *WishlistService) IsSaved(ctx context.Context, userID, tourID uint) bool {
+	if userID == 0 {
+		return false
+	}
+	exists, _ := s.repo.Exists(ctx, userID, tourID)
+	return exists
+}
+
+func (s *WishlistService) ListMine(ctx context.Context, userID uint, page, limit int) ([]models.Wishlist, int64, error) {
+	items, total, err := s.repo.FindByUser(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceList, err)
+	}
+	return items, total, nil
+}
+
+// NotifyChanges emails every user whose saved tour got cheaper or gained a
+// new upcoming schedule since they were last told, then records the current
+// price and time so each change is announced once. A price rise is not
+// announced and keeps the lower notified price. Failed emails are retried
+// on the next run. It returns how many notifications were handled.
+func (s *WishlistService) NotifyChanges(ctx context.Context, now time.Time) (int, error) {
+	items, err := s.repo.FindPendingNotifications(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceNotify, err)
+	}
+
+	sent := 0
+	for i := range items {
+		item := &items[i]
+		schedules, err := s.repo.FindNewSchedules(ctx, item.TourID, item.NotifiedAt, now)
+		if err != nil {
+			return sent, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceNotify, err)
+		}
+
+		tourURL := s.baseURL + constants.RoutePublicTours + "/" + item.Tour.Slug
+		if err := s.emailService.SendWishlistUpdateEmail(item, tourURL, schedules); err != nil {
+			slog.ErrorContext(ctx, messages.LogWishlistNotifyFailed, "user_id", item.UserID, "tour_id", item.TourID, "error", err)
+			continue
+		}
+
+		price := min(item.NotifiedPrice, item.Tour.Price)
+		if err := s.repo.MarkNotified(ctx, item.UserID, item.TourID, price, now); err != nil {
+			return sent, fmt.Errorf("%s: %w", appErrors.ErrCtxWishlistServiceNotify, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,103 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/constants"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupWishlistServiceDB(t *testing.T) (*WishlistService, *TourService, *gorm.DB) {
+	t.Helper()
+	tourSvc, db := setupTourServiceDB(t)
+	svc := NewWishlistService(repository.NewWishlistRepository(db), NewEmailService(&config.Config{}), "http://localhost")
+	return svc, tourSvc, db
+}
+
+func TestWishlistToggle_SavesThenRemoves(t *testing.T) {
+	svc, tourSvc, db := setupWishlistServiceDB(t)
+	ctx := context.Background()
+	user := seedUser(t, db, "an", constants.RoleUser)
+	tour := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+
+	saved, err := svc.Toggle(ctx, user.ID, tour)
+	require.NoError(t, err)
+	assert.True(t, saved)
+	assert.True(t, svc.IsSaved(ctx, user.ID, tour.ID))
+
+	saved, err = svc.Toggle(ctx, user.ID, tour)
+	require.NoError(t, err)
+	assert.False(t, saved)
+	assert.False(t, svc.IsSaved(ctx, user.ID, tour.ID))
+}
+
+func TestListTours_FlagsSavedTours(t *testing.T) {
+	svc, tourSvc, db := setupWishlistServiceDB(t)
+	ctx := context.Background()
+	user := seedUser(t, db, "an", constants.RoleUser)
+	other := seedUser(t, db, "binh", constants.RoleUser)
+	saved := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	createActiveTour(t, tourSvc, db, "Sa Pa Trek")
+	_, err := svc.Toggle(ctx, user.ID, saved)
+	require.NoError(t, err)
+
+	tours, _, _, err := tourSvc.ListTours(ctx, repository.TourFilter{SavedBy: user.ID})
+	require.NoError(t, err)
+	require.Len(t, tours, 2)
+	for _, tour := range tours {
+		assert.Equal(t, tour.ID == saved.ID, tour.Saved, tour.Title)
+	}
+
+	tours, _, _, err = tourSvc.ListTours(ctx, repository.TourFilter{SavedBy: other.ID})
+	require.NoError(t, err)
+	for _, tour := range tours {
+		assert.False(t, tour.Saved, tour.Title)
+	}
+}
+
+func TestWishlistNotifyChanges_PriceDropAndNewScheduleOnce(t *testing.T) {
+	svc, tourSvc, db := setupWishlistServiceDB(t)
+	ctx := context.Background()
+	user := seedUser(t, db, "an", constants.RoleUser)
+	cheaper := createActiveTour(t, tourSvc, db, "Ha Long Bay")
+	scheduled := createActiveTour(t, tourSvc, db, "Sa Pa Trek")
+	pricier := createActiveTour(t, tourSvc, db, "Hue Heritage")
+	for _, tour := range []*models.Tour{cheaper, scheduled, pricier} {
+		_, err := svc.Toggle(ctx, user.ID, tour)
+		require.NoError(t, err)
+	}
+
+	now := time.Now().Add(time.Minute)
+	require.NoError(t, db.Model(cheaper).Update("price", 80).Error)
+	require.NoError(t, db.Model(pricier).Update("price", 150).Error)
+	require.NoError(t, db.Create(&models.TourSchedule{
+		TourID:         scheduled.ID,
+		DepartureDate:  now.AddDate(0, 1, 0),
+		ReturnDate:     now.AddDate(0, 1, 2),
+		AvailableSlots: 10,
+		Status:         constants.ScheduleStatusOpen,
+		CreatedAt:      now.Add(-time.Second),
+	}).Error)
+
+	notified, err := svc.NotifyChanges(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, notified)
+
+	notified, err = svc.NotifyChanges(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, notified)
+
+	var item models.Wishlist
+	require.NoError(t, db.Where("user_id = ? AND tour_id = ?", user.ID, cheaper.ID).First(&item).Error)
+	assert.InDelta(t, 80, item.NotifiedPrice, 0.001)
+}
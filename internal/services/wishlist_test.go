@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sun-booking-tours/internal/config"
+	"sun-booking-tours/internal/models"
+	"sun-booking-tours/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- helpers ----------------------------------------------------------
+
+func setupWishlistService(repo *testutil.MockWishlistRepo) *WishlistService {
+	return NewWishlistService(repo, NewEmailService(&config.Config{}), "http://localhost")
+}
+
+// --- Toggle -----------------------------------------------------------
+
+func TestWishlistToggle_SavesAtCurrentPrice(t *testing.T) {
+	repo := new(testutil.MockWishlistRepo)
+	svc := setupWishlistService(repo)
+	tour := &models.Tour{ID: 5, Price: 120}
+	repo.On("Exists", mock.Anything, uint(1), tour.ID).Return(false, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(item *models.Wishlist) bool {
+		return item.UserID == 1 && item.TourID == tour.ID && item.NotifiedPrice == tour.Price
+	})).Return(nil)
+
+	saved, err := svc.Toggle(context.Background(), 1, tour)
+
+	require.NoError(t, err)
+	assert.True(t, saved)
+	repo.AssertExpectations(t)
+}
+
+func TestWishlistToggle_RemovesSavedTour(t *testing.T) {
+	repo := new(testutil.MockWishlistRepo)
+	svc := setupWishlistService(repo)
+	tour := &models.Tour{ID: 5, Price: 120}
+	repo.On("Exists", mock.Anything, uint(1), tour.ID).Return(true, nil)
+	repo.On("Delete", mock.Anything, uint(1), tour.ID).Return(nil)
+
+	saved, err := svc.Toggle(context.Background(), 1, tour)
+
+	require.NoError(t, err)
+	assert.False(t, saved)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWishlistIsSaved_GuestNeverQueries(t *testing.T) {
+	repo := new(testutil.MockWishlistRepo)
+	svc := setupWishlistService(repo)
+
+	assert.False(t, svc.IsSaved(context.Background(), 0, 5))
+	repo.AssertNotCalled(t, "Exists", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// --- NotifyChanges ----------------------------------------------------
+
+func TestWishlistNotifyChanges_KeepsLowestNotifiedPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		notified  float64
+		current   float64
+		wantPrice float64
+	}{
+		{"price drop is recorded", 100, 80, 80},
+		{"price rise keeps the lower price", 100, 150, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(testutil.MockWishlistRepo)
+			svc := setupWishlistService(repo)
+			now := time.Now()
+			since := now.Add(-time.Hour)
+			item := models.Wishlist{
+				UserID:        1,
+				TourID:        5,
+				NotifiedPrice: tt.notified,
+				NotifiedAt:    since,
+				Tour:          &models.Tour{ID: 5, Slug: "ha-long", Price: tt.current},
+			}
+			repo.On("FindPendingNotifications", mock.Anything, now).Return([]models.Wishlist{item}, nil)
+			repo.On("FindNewSchedules", mock.Anything, item.TourID, since, now).Return([]models.TourSchedule{}, nil)
+			repo.On("MarkNotified", mock.Anything, item.UserID, item.TourID, tt.wantPrice, now).Return(nil)
+
+			sent, err := svc.NotifyChanges(context.Background(), now)
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, sent)
+			repo.AssertExpectations(t)
+		})
+	}
+}
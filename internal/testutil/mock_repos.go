@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"context"
+	"time"
 
 	"sun-booking-tours/internal/models"
 	"sun-booking-tours/internal/repository"
@@ -65,6 +66,16 @@ func (m *MockUserRepo) UpdateStatus(ctx context.Context, id uint, status string)
 	return args.Error(0)
 }
 
+func (m *MockUserRepo) UpdateRole(ctx context.Context, id uint, role string) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) FindActiveByRole(ctx context.Context, role string) ([]models.User, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *MockUserRepo) FindByVerifyToken(ctx context.Context, token string) (*models.User, error) {
 	args := m.Called(ctx, token)
 	if args.Get(0) == nil {
@@ -92,3 +103,875 @@ func (m *MockSocialAccountRepo) Create(ctx context.Context, account *models.Soci
 	args := m.Called(ctx, account)
 	return args.Error(0)
 }
+
+// MockScheduleGuideRepo is a testify mock for repository.ScheduleGuideRepo.
+type MockScheduleGuideRepo struct {
+	mock.Mock
+}
+
+var _ repository.ScheduleGuideRepo = (*MockScheduleGuideRepo)(nil)
+
+func (m *MockScheduleGuideRepo) FindByScheduleID(ctx context.Context, scheduleID uint) ([]models.ScheduleGuide, error) {
+	args := m.Called(ctx, scheduleID)
+	return args.Get(0).([]models.ScheduleGuide), args.Error(1)
+}
+
+func (m *MockScheduleGuideRepo) IsAssigned(ctx context.Context, scheduleID, guideID uint) (bool, error) {
+	args := m.Called(ctx, scheduleID, guideID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduleGuideRepo) HasConflict(ctx context.Context, guideID, excludeScheduleID uint, departure, returnDate time.Time) (bool, error) {
+	args := m.Called(ctx, guideID, excludeScheduleID, departure, returnDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduleGuideRepo) Assign(ctx context.Context, assignment *models.ScheduleGuide) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockScheduleGuideRepo) Unassign(ctx context.Context, scheduleID, guideID uint) error {
+	args := m.Called(ctx, scheduleID, guideID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleGuideRepo) UnassignGuide(ctx context.Context, guideID uint) error {
+	args := m.Called(ctx, guideID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleGuideRepo) FindSchedulesByGuide(ctx context.Context, guideID uint, from time.Time) ([]models.TourSchedule, error) {
+	args := m.Called(ctx, guideID, from)
+	return args.Get(0).([]models.TourSchedule), args.Error(1)
+}
+
+// MockScheduleRepo is a testify mock for repository.ScheduleRepo.
+type MockScheduleRepo struct {
+	mock.Mock
+}
+
+var _ repository.ScheduleRepo = (*MockScheduleRepo)(nil)
+
+func (m *MockScheduleRepo) FindByTourID(ctx context.Context, tourID uint) ([]models.TourSchedule, error) {
+	args := m.Called(ctx, tourID)
+	return args.Get(0).([]models.TourSchedule), args.Error(1)
+}
+
+func (m *MockScheduleRepo) FindByID(ctx context.Context, id uint) (*models.TourSchedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TourSchedule), args.Error(1)
+}
+
+func (m *MockScheduleRepo) Create(ctx context.Context, schedule *models.TourSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) Update(ctx context.Context, schedule *models.TourSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) HasBookings(ctx context.Context, scheduleID uint) (bool, error) {
+	args := m.Called(ctx, scheduleID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduleRepo) CountConfirmedParticipants(ctx context.Context, scheduleID uint) (int, error) {
+	args := m.Called(ctx, scheduleID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockScheduleRepo) SetGuaranteed(ctx context.Context, id uint, guaranteed bool) error {
+	args := m.Called(ctx, id, guaranteed)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) FindDueForCutoff(ctx context.Context, from, deadline time.Time) ([]models.TourSchedule, error) {
+	args := m.Called(ctx, from, deadline)
+	return args.Get(0).([]models.TourSchedule), args.Error(1)
+}
+
+// MockBookingRepo is a testify mock for repository.BookingRepo.
+type MockBookingRepo struct {
+	mock.Mock
+}
+
+var _ repository.BookingRepo = (*MockBookingRepo)(nil)
+
+func (m *MockBookingRepo) Create(ctx context.Context, booking *models.Booking) error {
+	args := m.Called(ctx, booking)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepo) FindByID(ctx context.Context, id uint) (*models.Booking, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) FindByUserID(ctx context.Context, userID uint, page, limit int) ([]models.Booking, int64, error) {
+	args := m.Called(ctx, userID, page, limit)
+	return args.Get(0).([]models.Booking), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBookingRepo) FindAll(ctx context.Context, filter repository.BookingFilter) ([]models.Booking, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Booking), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBookingRepo) UpdateStatus(ctx context.Context, id uint, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepo) FindPassengersBySchedule(ctx context.Context, scheduleID uint) ([]models.Booking, error) {
+	args := m.Called(ctx, scheduleID)
+	return args.Get(0).([]models.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) FindLatestCompleted(ctx context.Context, userID, tourID uint) (*models.Booking, error) {
+	args := m.Called(ctx, userID, tourID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) FindReviewable(ctx context.Context, userID, keepID uint) ([]models.Booking, error) {
+	args := m.Called(ctx, userID, keepID)
+	return args.Get(0).([]models.Booking), args.Error(1)
+}
+
+// MockTourRepo is a testify mock for repository.TourRepo.
+type MockTourRepo struct {
+	mock.Mock
+}
+
+var _ repository.TourRepo = (*MockTourRepo)(nil)
+
+func (m *MockTourRepo) FindAll(ctx context.Context, filter repository.TourFilter) ([]models.Tour, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Tour), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTourRepo) FindByID(ctx context.Context, id uint) (*models.Tour, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) FindBySlug(ctx context.Context, slug string) (*models.Tour, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) FindBySlugPublic(ctx context.Context, slug string) (*models.Tour, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) FindByIDPublic(ctx context.Context, id uint) (*models.Tour, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	args := m.Called(ctx, slug)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTourRepo) ExistsBySlugExcluding(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	args := m.Called(ctx, slug, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTourRepo) Create(ctx context.Context, tour *models.Tour) error {
+	args := m.Called(ctx, tour)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) CreateClone(ctx context.Context, tour *models.Tour) error {
+	args := m.Called(ctx, tour)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) Update(ctx context.Context, tour *models.Tour) error {
+	args := m.Called(ctx, tour)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) HasActiveBookings(ctx context.Context, tourID uint) (bool, error) {
+	args := m.Called(ctx, tourID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTourRepo) CountBookedBySchedule(ctx context.Context, tourID uint) (map[uint]int, error) {
+	args := m.Called(ctx, tourID)
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+
+func (m *MockTourRepo) ReplaceCategories(ctx context.Context, tour *models.Tour, categories []models.Category) error {
+	args := m.Called(ctx, tour, categories)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) AddCategories(ctx context.Context, tourIDs, categoryIDs []uint) (int64, error) {
+	args := m.Called(ctx, tourIDs, categoryIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTourRepo) RemoveCategories(ctx context.Context, tourIDs, categoryIDs []uint) (int64, error) {
+	args := m.Called(ctx, tourIDs, categoryIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTourRepo) ReplaceItinerary(ctx context.Context, tourID uint, days []models.TourItineraryDay) error {
+	args := m.Called(ctx, tourID, days)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) CountImageReferences(ctx context.Context, url string) (int64, error) {
+	args := m.Called(ctx, url)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTourRepo) ReplaceDetails(ctx context.Context, tourID uint, details repository.TourDetails) error {
+	args := m.Called(ctx, tourID, details)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) ReplaceTranslations(ctx context.Context, tourID uint, translations []models.TourTranslation, itinerary []models.TourItineraryTranslation) error {
+	args := m.Called(ctx, tourID, translations, itinerary)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) CountRatingsByTourID(ctx context.Context, tourID uint) (int64, error) {
+	args := m.Called(ctx, tourID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTourRepo) UpdateRatingStats(ctx context.Context, tourID uint, avg float64, count int64) error {
+	args := m.Called(ctx, tourID, avg, count)
+	return args.Error(0)
+}
+
+func (m *MockTourRepo) FindFeatured(ctx context.Context, limit int) ([]models.Tour, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) FindLatest(ctx context.Context, limit int) ([]models.Tour, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) FindForExport(ctx context.Context) ([]models.Tour, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+func (m *MockTourRepo) CountFacets(ctx context.Context, filter repository.TourFilter) (*repository.TourFacetCounts, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TourFacetCounts), args.Error(1)
+}
+
+func (m *MockTourRepo) FindPins(ctx context.Context, filter repository.TourFilter, limit int) ([]models.Tour, error) {
+	args := m.Called(ctx, filter, limit)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+// MockCategoryRepo is a testify mock for repository.CategoryRepo.
+type MockCategoryRepo struct {
+	mock.Mock
+}
+
+var _ repository.CategoryRepo = (*MockCategoryRepo)(nil)
+
+func (m *MockCategoryRepo) FindAll(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepo) FindAllParents(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepo) FindByID(ctx context.Context, id uint) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepo) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepo) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	args := m.Called(ctx, slug)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepo) ExistsBySlugExcluding(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	args := m.Called(ctx, slug, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepo) CountByIDs(ctx context.Context, ids []uint) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCategoryRepo) Create(ctx context.Context, cat *models.Category) error {
+	args := m.Called(ctx, cat)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) Update(ctx context.Context, cat *models.Category) error {
+	args := m.Called(ctx, cat)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) HasTours(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepo) HasChildren(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepo) GetDescendantIDs(ctx context.Context, parentID uint) ([]uint, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockCategoryRepo) FindAncestors(ctx context.Context, cat *models.Category) ([]models.Category, error) {
+	args := m.Called(ctx, cat)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepo) ChildIDs(ctx context.Context, parentID *uint) ([]uint, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockCategoryRepo) Move(ctx context.Context, id uint, parentID *uint) error {
+	args := m.Called(ctx, id, parentID)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) UpdatePositions(ctx context.Context, ids []uint) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) Merge(ctx context.Context, sourceID, targetID uint) (*repository.CategoryMergeResult, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.CategoryMergeResult), args.Error(1)
+}
+
+func (m *MockCategoryRepo) ReplaceTranslations(ctx context.Context, categoryID uint, translations []models.CategoryTranslation) error {
+	args := m.Called(ctx, categoryID, translations)
+	return args.Error(0)
+}
+
+// MockSlugHistoryRepo is a testify mock for repository.SlugHistoryRepo.
+type MockSlugHistoryRepo struct {
+	mock.Mock
+}
+
+var _ repository.SlugHistoryRepo = (*MockSlugHistoryRepo)(nil)
+
+func (m *MockSlugHistoryRepo) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]models.SlugHistory, error) {
+	args := m.Called(ctx, entityType, entityID)
+	return args.Get(0).([]models.SlugHistory), args.Error(1)
+}
+
+func (m *MockSlugHistoryRepo) RecordChange(ctx context.Context, entityType string, entityID uint, oldSlug, newSlug string) error {
+	args := m.Called(ctx, entityType, entityID, oldSlug, newSlug)
+	return args.Error(0)
+}
+
+// MockRecommendationRepo is a testify mock for repository.RecommendationRepo.
+type MockRecommendationRepo struct {
+	mock.Mock
+}
+
+var _ repository.RecommendationRepo = (*MockRecommendationRepo)(nil)
+
+func (m *MockRecommendationRepo) FindCandidateTours(ctx context.Context) ([]models.Tour, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+func (m *MockRecommendationRepo) CoBookingCounts(ctx context.Context) ([]repository.CoBooking, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]repository.CoBooking), args.Error(1)
+}
+
+func (m *MockRecommendationRepo) UserBookedTours(ctx context.Context) ([]repository.UserTour, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]repository.UserTour), args.Error(1)
+}
+
+func (m *MockRecommendationRepo) UserRatings(ctx context.Context) ([]models.Rating, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Rating), args.Error(1)
+}
+
+func (m *MockRecommendationRepo) ReplaceAll(ctx context.Context, tourRecs []models.TourRecommendation, userRecs []models.UserRecommendation) error {
+	args := m.Called(ctx, tourRecs, userRecs)
+	return args.Error(0)
+}
+
+func (m *MockRecommendationRepo) FindForTour(ctx context.Context, tourID uint, kind string, limit int) ([]models.Tour, error) {
+	args := m.Called(ctx, tourID, kind, limit)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+func (m *MockRecommendationRepo) FindForUser(ctx context.Context, userID uint, limit int) ([]models.Tour, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]models.Tour), args.Error(1)
+}
+
+// MockWishlistRepo is a testify mock for repository.WishlistRepo.
+type MockWishlistRepo struct {
+	mock.Mock
+}
+
+var _ repository.WishlistRepo = (*MockWishlistRepo)(nil)
+
+func (m *MockWishlistRepo) Exists(ctx context.Context, userID, tourID uint) (bool, error) {
+	args := m.Called(ctx, userID, tourID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWishlistRepo) Create(ctx context.Context, item *models.Wishlist) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepo) Delete(ctx context.Context, userID, tourID uint) error {
+	args := m.Called(ctx, userID, tourID)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepo) FindByUser(ctx context.Context, userID uint, page, limit int) ([]models.Wishlist, int64, error) {
+	args := m.Called(ctx, userID, page, limit)
+	return args.Get(0).([]models.Wishlist), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWishlistRepo) FindPendingNotifications(ctx context.Context, now time.Time) ([]models.Wishlist, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]models.Wishlist), args.Error(1)
+}
+
+func (m *MockWishlistRepo) FindNewSchedules(ctx context.Context, tourID uint, since, now time.Time) ([]models.TourSchedule, error) {
+	args := m.Called(ctx, tourID, since, now)
+	return args.Get(0).([]models.TourSchedule), args.Error(1)
+}
+
+func (m *MockWishlistRepo) MarkNotified(ctx context.Context, userID, tourID uint, price float64, at time.Time) error {
+	args := m.Called(ctx, userID, tourID, price, at)
+	return args.Error(0)
+}
+
+// MockTrashRepo is a testify mock for repository.TrashRepo.
+type MockTrashRepo struct {
+	mock.Mock
+}
+
+var _ repository.TrashRepo = (*MockTrashRepo)(nil)
+
+func (m *MockTrashRepo) FindTours(ctx context.Context, page, limit int) ([]models.Tour, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.Tour), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTrashRepo) FindCategories(ctx context.Context, page, limit int) ([]models.Category, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.Category), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTrashRepo) FindReviews(ctx context.Context, page, limit int) ([]models.Review, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]models.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTrashRepo) FindTour(ctx context.Context, id uint) (*models.Tour, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tour), args.Error(1)
+}
+
+func (m *MockTrashRepo) FindCategory(ctx context.Context, id uint) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockTrashRepo) FindReview(ctx context.Context, id uint) (*models.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Review), args.Error(1)
+}
+
+func (m *MockTrashRepo) RestoreTour(ctx context.Context, tour *models.Tour) error {
+	args := m.Called(ctx, tour)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) RestoreCategory(ctx context.Context, cat *models.Category) error {
+	args := m.Called(ctx, cat)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) RestoreReview(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) TourHasBookings(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTrashRepo) PurgeTour(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) PurgeCategory(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) PurgeReview(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTrashRepo) ExpiredIDs(ctx context.Context, kind string, before time.Time) ([]uint, error) {
+	args := m.Called(ctx, kind, before)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+// MockRatingRepo is a testify mock for repository.RatingRepo.
+type MockRatingRepo struct {
+	mock.Mock
+}
+
+var _ repository.RatingRepo = (*MockRatingRepo)(nil)
+
+func (m *MockRatingRepo) Upsert(ctx context.Context, rating *models.Rating) error {
+	args := m.Called(ctx, rating)
+	return args.Error(0)
+}
+
+func (m *MockRatingRepo) FindByUserAndTour(ctx context.Context, userID, tourID uint) (*models.Rating, error) {
+	args := m.Called(ctx, userID, tourID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Rating), args.Error(1)
+}
+
+func (m *MockRatingRepo) FindByTourID(ctx context.Context, tourID uint, page, limit int) ([]models.Rating, int64, error) {
+	args := m.Called(ctx, tourID, page, limit)
+	return args.Get(0).([]models.Rating), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRatingRepo) CalcAvgByTourID(ctx context.Context, tourID uint) (float64, error) {
+	args := m.Called(ctx, tourID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockRatingRepo) StatsByTourID(ctx context.Context, tourID uint) (*repository.RatingStats, error) {
+	args := m.Called(ctx, tourID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.RatingStats), args.Error(1)
+}
+
+func (m *MockRatingRepo) FindByID(ctx context.Context, id uint) (*models.Rating, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Rating), args.Error(1)
+}
+
+func (m *MockRatingRepo) FindAll(ctx context.Context, filter repository.RatingFilter) ([]models.Rating, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Rating), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRatingRepo) SaveReply(ctx context.Context, reply *models.RatingReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *MockRatingRepo) HasGrant(ctx context.Context, userID, tourID uint) (bool, error) {
+	args := m.Called(ctx, userID, tourID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRatingRepo) CreateGrant(ctx context.Context, grant *models.RatingGrant) error {
+	args := m.Called(ctx, grant)
+	return args.Error(0)
+}
+
+func (m *MockRatingRepo) DeleteGrant(ctx context.Context, userID, tourID uint) (int64, error) {
+	args := m.Called(ctx, userID, tourID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRatingRepo) FindGrantsByUser(ctx context.Context, userID uint) ([]models.RatingGrant, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.RatingGrant), args.Error(1)
+}
+
+// MockReviewRepo is a testify mock for repository.ReviewRepo.
+type MockReviewRepo struct {
+	mock.Mock
+}
+
+var _ repository.ReviewRepo = (*MockReviewRepo)(nil)
+
+func (m *MockReviewRepo) Create(ctx context.Context, review *models.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepo) FindByID(ctx context.Context, id uint) (*models.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Review), args.Error(1)
+}
+
+func (m *MockReviewRepo) FindByUserID(ctx context.Context, userID uint, page, limit int) ([]models.Review, int64, error) {
+	args := m.Called(ctx, userID, page, limit)
+	return args.Get(0).([]models.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewRepo) FindAllPublic(ctx context.Context, filter repository.ReviewFilter) ([]models.Review, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewRepo) FindAll(ctx context.Context, filter repository.ReviewFilter) ([]models.Review, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewRepo) Update(ctx context.Context, review *models.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepo) UpdateStatus(ctx context.Context, id uint, status, rejectReason string) error {
+	args := m.Called(ctx, id, status, rejectReason)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepo) IncrementLikeCount(ctx context.Context, id uint, delta int) error {
+	args := m.Called(ctx, id, delta)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepo) CountImageReferences(ctx context.Context, url string) (int64, error) {
+	args := m.Called(ctx, url)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockCommentRepo is a testify mock for repository.CommentRepo.
+type MockCommentRepo struct {
+	mock.Mock
+}
+
+var _ repository.CommentRepo = (*MockCommentRepo)(nil)
+
+func (m *MockCommentRepo) Create(ctx context.Context, comment *models.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) FindByReviewID(ctx context.Context, reviewID, viewerID uint, page, limit int) ([]models.Comment, int64, error) {
+	args := m.Called(ctx, reviewID, viewerID, page, limit)
+	return args.Get(0).([]models.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepo) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepo) FindAll(ctx context.Context, filter repository.CommentFilter) ([]models.Comment, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepo) HasReplies(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCommentRepo) UpdateContent(ctx context.Context, comment *models.Comment, previous string) error {
+	args := m.Called(ctx, comment, previous)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) UpdateStatus(ctx context.Context, id uint, status, rejectReason string) error {
+	args := m.Called(ctx, id, status, rejectReason)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) SoftDelete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockReviewLikeRepo is a testify mock for repository.ReviewLikeRepo.
+type MockReviewLikeRepo struct {
+	mock.Mock
+}
+
+var _ repository.ReviewLikeRepo = (*MockReviewLikeRepo)(nil)
+
+func (m *MockReviewLikeRepo) Exists(ctx context.Context, userID, reviewID uint) (bool, error) {
+	args := m.Called(ctx, userID, reviewID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReviewLikeRepo) FindByUserAndReviewIDs(ctx context.Context, userID uint, reviewIDs []uint) ([]uint, error) {
+	args := m.Called(ctx, userID, reviewIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockReviewLikeRepo) Create(ctx context.Context, like *models.ReviewLike) error {
+	args := m.Called(ctx, like)
+	return args.Error(0)
+}
+
+func (m *MockReviewLikeRepo) Delete(ctx context.Context, userID, reviewID uint) error {
+	args := m.Called(ctx, userID, reviewID)
+	return args.Error(0)
+}
+
+// MockReportRepo is a testify mock for repository.ReportRepo.
+type MockReportRepo struct {
+	mock.Mock
+}
+
+var _ repository.ReportRepo = (*MockReportRepo)(nil)
+
+func (m *MockReportRepo) Create(ctx context.Context, report *models.Report) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockReportRepo) Exists(ctx context.Context, userID uint, target repository.ReportTarget) (bool, error) {
+	args := m.Called(ctx, userID, target)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReportRepo) CountOpen(ctx context.Context, target repository.ReportTarget) (int64, error) {
+	args := m.Called(ctx, target)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReportRepo) FindOpenGroups(ctx context.Context, page, limit int) ([]repository.ReportGroupRow, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]repository.ReportGroupRow), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReportRepo) FindOpenByTargets(ctx context.Context, targets []repository.ReportTarget) ([]models.Report, error) {
+	args := m.Called(ctx, targets)
+	return args.Get(0).([]models.Report), args.Error(1)
+}
+
+func (m *MockReportRepo) Resolve(ctx context.Context, target repository.ReportTarget, resolution string, adminID uint) error {
+	args := m.Called(ctx, target, resolution, adminID)
+	return args.Error(0)
+}
+
+// MockModerationRepo is a testify mock for repository.ModerationRepo.
+type MockModerationRepo struct {
+	mock.Mock
+}
+
+var _ repository.ModerationRepo = (*MockModerationRepo)(nil)
+
+func (m *MockModerationRepo) RecentContents(ctx context.Context, kind string, userID, excludeID uint, since time.Time) ([]string, error) {
+	args := m.Called(ctx, kind, userID, excludeID, since)
+	return args.Get(0).([]string), args.Error(1)
+}
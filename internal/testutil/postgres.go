@@ -0,0 +1,67 @@
+//go:build integration
+
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"sun-booking-tours/internal/database"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestDatabaseURLEnv names the PostgreSQL DSN integration tests run against.
+// The database is migrated once and emptied before every test, so it must
+// not hold data worth keeping.
+const TestDatabaseURLEnv = "TEST_DATABASE_URL"
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+// PostgresDB opens a connection to the test database with every table
+// emptied. The test is skipped when TEST_DATABASE_URL is not set.
+func PostgresDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(TestDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", TestDatabaseURLEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	migrateOnce.Do(func() { migrateErr = database.Migrate(db) })
+	require.NoError(t, migrateErr)
+	require.NoError(t, truncateTables(db))
+	return db
+}
+
+func truncateTables(db *gorm.DB) error {
+	var tables []string
+	if err := db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema()").
+		Scan(&tables).Error; err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = `"` + table + `"`
+	}
+	return db.Exec("TRUNCATE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE").Error
+}
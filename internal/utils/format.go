@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatPrice renders a VND amount with dot thousands separators,
+// e.g. 1500000 -> "1.500.000 ₫".
+func FormatPrice(p float64) string {
+	s := strconv.FormatInt(int64(math.Round(p)), 10)
+	n := len(s)
+	if n <= 3 {
+		return s + " ₫"
+	}
+	var parts []string
+	for n > 0 {
+		start := max(n-3, 0)
+		parts = append([]string{s[start:n]}, parts...)
+		n = start
+	}
+	return strings.Join(parts, ".") + " ₫"
+}
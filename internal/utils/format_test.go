@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPrice_GroupsThousands(t *testing.T) {
+	assert.Equal(t, "0 ₫", FormatPrice(0))
+	assert.Equal(t, "999 ₫", FormatPrice(999))
+	assert.Equal(t, "1.000 ₫", FormatPrice(1000))
+	assert.Equal(t, "1.500.000 ₫", FormatPrice(1499999.6))
+}
@@ -0,0 +1,44 @@
+package utils
+
+import "math"
+
+// EarthRadiusKm is the mean Earth radius used for great-circle distances.
+const EarthRadiusKm = 6371.0
+
+// HaversineKm returns the great-circle distance in kilometres between two
+// WGS84 points given in decimal degrees.
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// RadiusBounds returns a latitude/longitude box that contains every point
+// within radiusKm of (lat, lng). It is used to narrow a radius search with
+// plain range comparisons before the exact distance check. Near the poles
+// the box widens to every longitude.
+func RadiusBounds(lat, lng, radiusKm float64) (minLat, minLng, maxLat, maxLng float64) {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+	minLat, maxLat = math.Max(-90, lat-dLat), math.Min(90, lat+dLat)
+
+	cos := math.Cos(radians(lat))
+	if cos < 1e-6 || maxLat == 90 || minLat == -90 {
+		return minLat, -180, maxLat, 180
+	}
+	dLng := dLat / cos
+	if dLng >= 180 {
+		return minLat, -180, maxLat, 180
+	}
+	return minLat, lng - dLng, maxLat, lng + dLng
+}
+
+// ValidLatLng reports whether lat and lng are valid WGS84 coordinates.
+func ValidLatLng(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaversineKm_HanoiToHoChiMinhCity(t *testing.T) {
+	got := HaversineKm(21.0285, 105.8542, 10.8231, 106.6297)
+
+	assert.InDelta(t, 1137, got, 5)
+	assert.Zero(t, HaversineKm(16.0544, 108.2022, 16.0544, 108.2022))
+}
+
+func TestRadiusBounds_ContainsRadius(t *testing.T) {
+	minLat, minLng, maxLat, maxLng := RadiusBounds(16.0544, 108.2022, 50)
+
+	assert.InDelta(t, 50, HaversineKm(16.0544, 108.2022, maxLat, 108.2022), 0.01)
+	assert.InDelta(t, 50, HaversineKm(16.0544, 108.2022, minLat, 108.2022), 0.01)
+	assert.GreaterOrEqual(t, HaversineKm(16.0544, 108.2022, 16.0544, maxLng), 49.99)
+	assert.Less(t, minLng, 108.2022)
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sun-booking-tours/internal/constants"
+)
+
+// IsSupportedLocale reports whether locale is one the site is published in.
+func IsSupportedLocale(locale string) bool {
+	return slices.Contains(constants.SupportedLocales, locale)
+}
+
+// TranslatedLocales lists the supported locales other than the default,
+// i.e. the ones stored as translations.
+func TranslatedLocales() []string {
+	locales := make([]string, 0, len(constants.SupportedLocales)-1)
+	for _, l := range constants.SupportedLocales {
+		if l != constants.DefaultLocale {
+			locales = append(locales, l)
+		}
+	}
+	return locales
+}
+
+// MatchAcceptLanguage picks the supported locale the client prefers most
+// from an Accept-Language header, comparing primary language tags only
+// ("en-US" matches "en"). It returns "" when nothing matches.
+func MatchAcceptLanguage(header string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q > 0 && IsSupportedLocale(primary) {
+			candidates = append(candidates, candidate{tag: primary, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].tag
+}
+
+// LocalizedPath prefixes path with the locale segment used in public URLs,
+// e.g. "/en/tours/ha-long-bay".
+func LocalizedPath(locale, path string) string {
+	if path == "/" {
+		return "/" + locale + "/"
+	}
+	return "/" + locale + path
+}
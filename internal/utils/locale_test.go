@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchAcceptLanguage_PrefersHighestSupportedQuality(t *testing.T) {
+	assert.Equal(t, "en", MatchAcceptLanguage("fr-FR,fr;q=0.9,en-US;q=0.8,vi;q=0.5"))
+	assert.Equal(t, "vi", MatchAcceptLanguage("vi-VN, en;q=0.7"))
+	assert.Equal(t, "", MatchAcceptLanguage("de, fr;q=0.5"))
+	assert.Equal(t, "", MatchAcceptLanguage("en;q=0"))
+	assert.Equal(t, "", MatchAcceptLanguage(""))
+}
+
+func TestLocalizedPath_PrefixesLocaleSegment(t *testing.T) {
+	assert.Equal(t, "/vi/tours/ha-long", LocalizedPath("vi", "/tours/ha-long"))
+	assert.Equal(t, "/en/tours/ha-long-bay", LocalizedPath("en", "/tours/ha-long-bay"))
+	assert.Equal(t, "/en/", LocalizedPath("en", "/"))
+}
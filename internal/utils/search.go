@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"html/template"
+	"strings"
+	"unicode"
+)
+
+// maxSearchTerms caps how many words of a query reach the database.
+const maxSearchTerms = 8
+
+// SearchTerms splits a user query into accent-free, lowercase words.
+// "Vịnh Hạ Long" → ["vinh", "ha", "long"]. Duplicates are dropped.
+func SearchTerms(q string) []string {
+	words := strings.FieldsFunc(FoldVietnamese(q), func(r rune) bool {
+		return !isSearchRune(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// TSQuery builds a PostgreSQL to_tsquery expression that matches documents
+// containing every term as a word prefix: ["ha", "lo"] → "ha:* & lo:*".
+// Terms produced by SearchTerms only hold [a-z0-9], so no escaping is needed.
+// Returns "" when there is nothing to search for.
+func TSQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t != "" {
+			parts = append(parts, t+":*")
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Highlight HTML-escapes text and wraps every word that starts with one of
+// terms in <mark>. Matching ignores case and Vietnamese diacritics, so the
+// term "ha" highlights "Hạ". The result is safe to render unescaped.
+func Highlight(text string, terms []string) template.HTML {
+	if len(terms) == 0 || text == "" {
+		return template.HTML(template.HTMLEscapeString(text))
+	}
+
+	runes := []rune(text)
+	folded := []rune(FoldVietnamese(text))
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(folded); {
+		if !isSearchRune(folded[i]) {
+			i++
+			continue
+		}
+		end := i
+		for end < len(folded) && isSearchRune(folded[end]) {
+			end++
+		}
+		if matchesAnyPrefix(string(folded[i:end]), terms) {
+			b.WriteString(template.HTMLEscapeString(string(runes[last:i])))
+			b.WriteString("<mark>")
+			b.WriteString(template.HTMLEscapeString(string(runes[i:end])))
+			b.WriteString("</mark>")
+			last = end
+		}
+		i = end
+	}
+	b.WriteString(template.HTMLEscapeString(string(runes[last:])))
+	return template.HTML(b.String())
+}
+
+func isSearchRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func matchesAnyPrefix(word string, terms []string) bool {
+	for _, t := range terms {
+		if t != "" && strings.HasPrefix(word, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms_FoldsDiacriticsAndDedupes(t *testing.T) {
+	assert.Equal(t, []string{"vinh", "ha", "long"}, SearchTerms("Vịnh Hạ Long, hạ!"))
+	assert.Empty(t, SearchTerms(" -- "))
+}
+
+func TestTSQuery(t *testing.T) {
+	assert.Equal(t, "ha:* & long:*", TSQuery([]string{"ha", "long"}))
+	assert.Equal(t, "", TSQuery(nil))
+}
+
+func TestHighlight_AccentInsensitiveAndEscaped(t *testing.T) {
+	got := Highlight("Tour <Hạ Long> – Đà Nẵng", SearchTerms("ha da"))
+
+	assert.Equal(t, "Tour &lt;<mark>Hạ</mark> Long&gt; – <mark>Đà</mark> Nẵng", string(got))
+}
+
+func TestVietnameseFoldPairs_SameLength(t *testing.T) {
+	from, to := VietnameseFoldPairs()
+
+	assert.Equal(t, len([]rune(from)), len([]rune(to)))
+	assert.Equal(t, "ha long", FoldVietnamese("Hạ Long"))
+}